@@ -0,0 +1,237 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"sync"
+	"syscall"
+
+	"boilerplate/config"
+	"boilerplate/container"
+	identityModel "boilerplate/internal/identity/model"
+	inviteModel "boilerplate/internal/invite/model"
+	"boilerplate/internal/scheduler"
+	adminSearch "boilerplate/internal/search"
+	userModel "boilerplate/internal/user/model"
+	webhookModel "boilerplate/internal/webhook/model"
+	"boilerplate/pkg/events"
+	"boilerplate/pkg/fieldcrypt"
+	"boilerplate/pkg/logger"
+	"boilerplate/pkg/queue"
+	"boilerplate/pkg/search"
+
+	"github.com/sarulabs/di/v2"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const defaultWorkerConcurrency = 4
+
+// runCommand menjalankan subcommand CLI. keygen tidak memasang keyring default
+// agar dapat dipakai sebelum data key pertama dikonfigurasi.
+func runCommand(ctn di.Container, name string, args []string) error {
+	switch name {
+	case "worker":
+		return workerCommand(ctn, args)
+	case "reencrypt":
+		return reencryptCommand(ctn, args)
+	case "keygen":
+		return keygenCommand(ctn, args)
+	case "reindex":
+		return reindexCommand(ctn, args)
+	default:
+		return fmt.Errorf("unknown command %q (available: worker, reencrypt, keygen, reindex)", name)
+	}
+}
+
+// workerCommand menjalankan handler job, task terjadwal dan relay outbox sampai
+// menerima SIGINT/SIGTERM, lalu menunggu job dan task yang sedang berjalan selesai.
+// Scheduler dan relay aman dijalankan di setiap replica karena setiap tick dan event
+// hanya diklaim satu replica.
+func workerCommand(ctn di.Container, args []string) error {
+	cfg := ctn.Get(container.ConfigDefName).(config.Config)
+	flags := flag.NewFlagSet("worker", flag.ExitOnError)
+	concurrency := flags.Int("concurrency", cfg.WorkerConcurrency, "jobs processed in parallel (default WORKER_CONCURRENCY or 4)")
+	withScheduler := flags.Bool("scheduler", true, "also run scheduled tasks")
+	withRelay := flags.Bool("relay", true, "also publish domain events from the outbox")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *concurrency <= 0 {
+		*concurrency = defaultWorkerConcurrency
+	}
+
+	configureDefaults(ctn)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	worker := ctn.Get(container.WorkerDefName).(*queue.Worker)
+	appLogger := ctn.Get(container.LoggerDefName).(logger.Logger)
+	appLogger.WithFields(logrus.Fields{
+		"concurrency": *concurrency,
+		"job_types":   worker.Types(),
+		"scheduler":   *withScheduler,
+		"relay":       *withRelay,
+	}).Info("Worker berjalan")
+
+	var wg sync.WaitGroup
+	if *withScheduler {
+		schedulerService := ctn.Get(container.SchedulerServiceDefName).(*scheduler.SchedulerService)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			schedulerService.Start(ctx)
+		}()
+	}
+	if *withRelay {
+		relay := ctn.Get(container.OutboxRelayDefName).(*events.Relay)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			relay.Run(ctx)
+		}()
+	}
+	worker.Run(ctx, *concurrency)
+	wg.Wait()
+	appLogger.Info("Worker berhenti")
+	return nil
+}
+
+// reencryptCommand mengenkripsi ulang kolom terenkripsi dengan key aktif. Jalankan
+// setelah semua instance memakai keyring baru; baris lama tetap terbaca selama proses.
+func reencryptCommand(ctn di.Container, args []string) error {
+	flags := flag.NewFlagSet("reencrypt", flag.ExitOnError)
+	batch := flags.Int("batch", fieldcrypt.DefaultBatchSize, "rows per batch")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	configureDefaults(ctn)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	db := ctn.Get(container.DBDefName).(*gorm.DB)
+	appLogger := ctn.Get(container.LoggerDefName).(logger.Logger)
+	keyring := fieldcrypt.DefaultKeyring()
+
+	tables := []struct {
+		name         string
+		model        interface{}
+		extraColumns []string
+	}{
+		{name: "users", model: &userModel.User{}, extraColumns: []string{"email_index"}},
+		{name: "login_events", model: &userModel.LoginEvent{}, extraColumns: []string{"email_index"}},
+		{name: "invites", model: &inviteModel.Invite{}, extraColumns: []string{"email_index"}},
+		{name: "user_identities", model: &identityModel.UserIdentity{}},
+		{name: "webhooks", model: &webhookModel.Webhook{}},
+	}
+	for _, table := range tables {
+		result, err := fieldcrypt.Reencrypt(ctx, db, table.model, *batch, table.extraColumns...)
+		if err != nil {
+			return fmt.Errorf("reencrypt %s: %w", table.name, err)
+		}
+		appLogger.WithFields(logrus.Fields{
+			"table":       table.name,
+			"key_version": keyring.ActiveVersion(),
+			"scanned":     result.Scanned,
+			"updated":     result.Updated,
+			"skipped":     result.Skipped,
+		}).Info("Re-enkripsi selesai")
+	}
+	return nil
+}
+
+// reindexCommand membangun ulang index pencarian MySQL dari database, mis. setelah
+// restore database atau bila sinkronisasi tertinggal. Index embedded hanya ada di
+// memori server dan dibangun ulang setiap server dimulai.
+func reindexCommand(ctn di.Container, args []string) error {
+	flags := flag.NewFlagSet("reindex", flag.ExitOnError)
+	only := flags.String("type", "", "only rebuild one type (category or user)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	cfg := ctn.Get(container.ConfigDefName).(config.Config)
+	if cfg.SearchBackend == search.BackendEmbedded {
+		return fmt.Errorf("reindex: the embedded search index is rebuilt when the server starts")
+	}
+	var types []string
+	if *only != "" {
+		if !slices.Contains(adminSearch.Types, *only) {
+			return fmt.Errorf("reindex: unknown type %q (available: %v)", *only, adminSearch.Types)
+		}
+		types = []string{*only}
+	}
+
+	configureDefaults(ctn)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return rebuildSearchIndex(ctx, ctn, types...)
+}
+
+// buildEmbeddedSearchIndex mengisi index pencarian embedded saat server dimulai
+// karena index itu hanya ada di memori proses
+func buildEmbeddedSearchIndex(ctn di.Container) error {
+	cfg := ctn.Get(container.ConfigDefName).(config.Config)
+	if cfg.SearchBackend != search.BackendEmbedded {
+		return nil
+	}
+	return rebuildSearchIndex(context.Background(), ctn)
+}
+
+func rebuildSearchIndex(ctx context.Context, ctn di.Container, types ...string) error {
+	searchService := ctn.Get(container.SearchServiceDefName).(*adminSearch.SearchService)
+	appLogger := ctn.Get(container.LoggerDefName).(logger.Logger)
+
+	counts, err := searchService.Reindex(ctx, types...)
+	if err != nil {
+		return fmt.Errorf("reindex: %w", err)
+	}
+	appLogger.WithFields(logrus.Fields{
+		"documents": counts,
+	}).Info("Index pencarian dibangun ulang")
+	return nil
+}
+
+// keygenCommand mencetak data key baru yang dibungkus master key. Tanpa
+// ENCRYPTION_MASTER_KEY, master key baru ikut dibuat dan dicetak.
+func keygenCommand(ctn di.Container, args []string) error {
+	flags := flag.NewFlagSet("keygen", flag.ExitOnError)
+	version := flags.Uint("version", 1, "key version")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	cfg := ctn.Get(container.ConfigDefName).(config.Config)
+	var master []byte
+	if cfg.EncryptionMasterKey != "" {
+		key, err := base64.StdEncoding.DecodeString(cfg.EncryptionMasterKey)
+		if err != nil {
+			return fmt.Errorf("ENCRYPTION_MASTER_KEY: %w", err)
+		}
+		master = key
+	} else {
+		key, err := fieldcrypt.GenerateDataKey()
+		if err != nil {
+			return err
+		}
+		master = key
+		fmt.Printf("ENCRYPTION_MASTER_KEY=%s\n", base64.StdEncoding.EncodeToString(master))
+	}
+
+	dataKey, err := fieldcrypt.GenerateDataKey()
+	if err != nil {
+		return err
+	}
+	wrapped, err := fieldcrypt.WrapKey(master, dataKey, uint32(*version))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%d:%s\n", *version, wrapped)
+	return nil
+}
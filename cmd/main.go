@@ -3,14 +3,31 @@ package main
 import (
 	"fmt"
 	"log"
+	"os"
 
 	"boilerplate/config"
 	"boilerplate/container"
+	"boilerplate/internal/apikey"
+	"boilerplate/internal/cache"
 	"boilerplate/internal/category"
+	"boilerplate/internal/identity"
+	"boilerplate/internal/invite"
+	"boilerplate/internal/job"
+	"boilerplate/internal/oauth"
+	"boilerplate/internal/privacy"
+	"boilerplate/internal/scheduler"
+	"boilerplate/internal/search"
 	"boilerplate/internal/user"
+	"boilerplate/internal/webhook"
+	"boilerplate/pkg/fieldcrypt"
+	"boilerplate/pkg/logger"
+	"boilerplate/pkg/middleware"
+	"boilerplate/pkg/openapi"
+	"boilerplate/pkg/password"
 	"boilerplate/routes"
 
 	"github.com/labstack/echo/v4"
+	"github.com/sarulabs/di/v2"
 )
 
 func main() {
@@ -20,24 +37,66 @@ func main() {
 		log.Fatal("Cannot initialize container:", err)
 	}
 
+	// Subcommand, mis. "worker" atau "reencrypt"; tanpa argumen menjalankan server HTTP
+	if len(os.Args) > 1 {
+		if err := runCommand(ctn, os.Args[1], os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	configureDefaults(ctn)
+	if err := buildEmbeddedSearchIndex(ctn); err != nil {
+		log.Fatal("Cannot build search index:", err)
+	}
+
 	// Get echo instance
 	e := ctn.Get(container.EchoDefName).(*echo.Echo)
 
 	// Get handlers
 	userHandler := ctn.Get(container.UserHandlerDefName).(*user.UserHandler)
 	categoryHandler := ctn.Get(container.CategoryHandlerDefName).(*category.CategoryHandler)
+	apiKeyHandler := ctn.Get(container.APIKeyHandlerDefName).(*apikey.APIKeyHandler)
+	identityHandler := ctn.Get(container.IdentityHandlerDefName).(*identity.IdentityHandler)
+	oauthHandler := ctn.Get(container.OAuthHandlerDefName).(*oauth.OAuthHandler)
+	inviteHandler := ctn.Get(container.InviteHandlerDefName).(*invite.InviteHandler)
+	privacyHandler := ctn.Get(container.PrivacyHandlerDefName).(*privacy.PrivacyHandler)
+	jobHandler := ctn.Get(container.JobHandlerDefName).(*job.JobHandler)
+	schedulerHandler := ctn.Get(container.SchedulerHandlerDefName).(*scheduler.SchedulerHandler)
+	webhookHandler := ctn.Get(container.WebhookHandlerDefName).(*webhook.WebhookHandler)
+	cacheHandler := ctn.Get(container.CacheHandlerDefName).(*cache.CacheHandler)
+	searchHandler := ctn.Get(container.SearchHandlerDefName).(*search.SearchHandler)
 
 	// Get middleware
 	authMiddleware := ctn.Get(container.AuthMiddlewareDefName).(echo.MiddlewareFunc)
 	adminMiddleware := ctn.Get(container.AdminAuthMiddlewareDefName).(echo.MiddlewareFunc)
+	idempotencyMiddleware := ctn.Get(container.IdempotencyMiddlewareDefName).(echo.MiddlewareFunc)
 
 	// Setup routes
-	routes.SetupRoutes(e, userHandler, categoryHandler, authMiddleware, adminMiddleware)
+	doc, err := routes.SetupRoutes(e, userHandler, categoryHandler, apiKeyHandler, identityHandler, oauthHandler, inviteHandler, privacyHandler, jobHandler, schedulerHandler, webhookHandler, cacheHandler, searchHandler, authMiddleware, adminMiddleware, idempotencyMiddleware)
+	if err != nil {
+		log.Fatal("Cannot setup routes:", err)
+	}
 
-	// Get config and start server
+	// Validate requests and responses against the OpenAPI contract
 	cfg := ctn.Get(container.ConfigDefName).(config.Config)
+	contract, err := openapi.NewValidator(doc)
+	if err != nil {
+		log.Fatal("Cannot compile OpenAPI contract:", err)
+	}
+	appLogger := ctn.Get(container.LoggerDefName).(logger.Logger)
+	e.Use(middleware.ContractMiddleware(contract, cfg.ContractValidationMode(), appLogger))
+
+	// Start server
 	port := fmt.Sprintf(":%s", cfg.ServerPort)
 	if err := e.Start(port); err != nil {
 		log.Fatal("Cannot start server:", err)
 	}
 }
+
+// configureDefaults memasang default package-level. Model User meng-hash password
+// dan mengenkripsi email/nama dengan default ini, jadi panggil sebelum service dipakai.
+func configureDefaults(ctn di.Container) {
+	password.SetDefaultHasher(ctn.Get(container.PasswordHasherDefName).(*password.Hasher))
+	fieldcrypt.SetDefaultKeyring(ctn.Get(container.KeyringDefName).(*fieldcrypt.Keyring))
+}
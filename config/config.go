@@ -1,10 +1,23 @@
 package config
 
 import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"boilerplate/pkg/fieldcrypt"
+	"boilerplate/pkg/password"
+	"boilerplate/shared/constants"
+
 	"github.com/spf13/viper"
+	"golang.org/x/crypto/bcrypt"
 )
 
 type Config struct {
+	AppEnv     string `mapstructure:"APP_ENV"`
 	DBHost     string `mapstructure:"DB_HOST"`
 	DBPort     string `mapstructure:"DB_PORT"`
 	DBUser     string `mapstructure:"DB_USER"`
@@ -17,6 +30,120 @@ type Config struct {
 	RedisAddr     string `mapstructure:"REDIS_ADDR"`
 	RedisPassword string `mapstructure:"REDIS_PASSWORD"`
 	RedisDB       int    `mapstructure:"REDIS_DB"`
+
+	// DisableRegistration menonaktifkan POST /register dan pembuatan user otomatis lewat
+	// login OIDC; user baru hanya dapat bergabung lewat undangan admin
+	DisableRegistration bool `mapstructure:"DISABLE_REGISTRATION"`
+
+	// Password policy. Nilai 0 atau kosong memakai default password.DefaultPolicy.
+	PasswordMinLength int `mapstructure:"PASSWORD_MIN_LENGTH"`
+	PasswordMaxLength int `mapstructure:"PASSWORD_MAX_LENGTH"`
+	// PasswordRequiredClasses dipisah koma: lower, upper, digit, symbol
+	PasswordRequiredClasses string `mapstructure:"PASSWORD_REQUIRED_CLASSES"`
+	// PasswordHistory adalah jumlah password sebelumnya yang tidak boleh dipakai ulang
+	PasswordHistory int `mapstructure:"PASSWORD_HISTORY"`
+	// PasswordBreachFile adalah file daftar hash SHA-1 password bocor (maksimal 64 MiB) atau
+	// direktori file per prefix untuk dataset besar; kosong memakai daftar bawaan
+	PasswordBreachFile         string `mapstructure:"PASSWORD_BREACH_FILE"`
+	DisablePasswordBreachCheck bool   `mapstructure:"DISABLE_PASSWORD_BREACH_CHECK"`
+
+	// PasswordHashAlgorithm adalah argon2id (default) atau bcrypt. Hash dengan algoritma
+	// atau parameter lama di-hash ulang saat user login.
+	PasswordHashAlgorithm string `mapstructure:"PASSWORD_HASH_ALGORITHM"`
+	PasswordBcryptCost    int    `mapstructure:"PASSWORD_BCRYPT_COST"`
+	// PasswordArgon2Memory dalam KiB
+	PasswordArgon2Memory      uint32 `mapstructure:"PASSWORD_ARGON2_MEMORY"`
+	PasswordArgon2Iterations  uint32 `mapstructure:"PASSWORD_ARGON2_ITERATIONS"`
+	PasswordArgon2Parallelism uint8  `mapstructure:"PASSWORD_ARGON2_PARALLELISM"`
+
+	// SMTP untuk email notifikasi. Kosongkan SMTP_HOST untuk hanya mencatat email ke log.
+	SMTPHost     string `mapstructure:"SMTP_HOST"`
+	SMTPPort     int    `mapstructure:"SMTP_PORT"`
+	SMTPUsername string `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword string `mapstructure:"SMTP_PASSWORD"`
+	MailFrom     string `mapstructure:"MAIL_FROM"`
+
+	// Enkripsi email dan nama user di database. ENCRYPTION_MASTER_KEY (base64, 32 byte)
+	// membuka data key di ENCRYPTION_KEY_FILE (JSON) atau ENCRYPTION_KEYS ("1:<key>,2:<key>").
+	// Kosongkan master key untuk menyimpan plaintext.
+	EncryptionMasterKey string `mapstructure:"ENCRYPTION_MASTER_KEY"`
+	EncryptionKeyFile   string `mapstructure:"ENCRYPTION_KEY_FILE"`
+	EncryptionKeys      string `mapstructure:"ENCRYPTION_KEYS"`
+	EncryptionActiveKey uint32 `mapstructure:"ENCRYPTION_ACTIVE_KEY"`
+	// EncryptionBlindIndexKey (base64) default diturunkan dari master key. Mengganti key ini
+	// membutuhkan command reencrypt agar blind index dihitung ulang.
+	EncryptionBlindIndexKey string `mapstructure:"ENCRYPTION_BLIND_INDEX_KEY"`
+
+	// Antrian job di Redis. WorkerConcurrency adalah jumlah job yang diproses bersamaan
+	// oleh command worker; QueueVisibilityTimeout (mis. "5m") adalah batas satu percobaan
+	// sebelum job dianggap milik worker yang mati dan dikembalikan ke antrian.
+	WorkerConcurrency      int           `mapstructure:"WORKER_CONCURRENCY"`
+	QueueVisibilityTimeout time.Duration `mapstructure:"QUEUE_VISIBILITY_TIMEOUT"`
+	QueueMaxAttempts       int           `mapstructure:"QUEUE_MAX_ATTEMPTS"`
+
+	// EventStream adalah Redis Stream tujuan event dari outbox untuk consumer di luar
+	// aplikasi; kosong berarti event hanya diteruskan ke subscriber di proses worker.
+	// EventStreamMaxLen membatasi panjang stream (0 = 100000).
+	EventStream       string `mapstructure:"EVENT_STREAM"`
+	EventStreamMaxLen int64  `mapstructure:"EVENT_STREAM_MAXLEN"`
+
+	// CacheBackend adalah backend read-through cache: redis (default), memory (LRU per
+	// proses, hanya untuk satu instance) atau none. CacheTTL (mis. "5m") adalah masa
+	// berlaku entry; CacheMaxEntries membatasi jumlah entry backend memory.
+	CacheBackend    string        `mapstructure:"CACHE_BACKEND"`
+	CacheTTL        time.Duration `mapstructure:"CACHE_TTL"`
+	CacheMaxEntries int           `mapstructure:"CACHE_MAX_ENTRIES"`
+
+	// SearchBackend adalah backend pencarian admin: mysql (default, index FULLTEXT yang
+	// dipakai bersama) atau embedded (index Bleve di memori server, hanya untuk satu
+	// instance; diisi ulang setiap server dimulai)
+	SearchBackend string `mapstructure:"SEARCH_BACKEND"`
+
+	// WebhookAllowPrivateNetworks mengizinkan URL webhook yang mengarah ke localhost atau
+	// jaringan internal. Hanya untuk pengembangan lokal; default ditolak untuk mencegah SSRF.
+	WebhookAllowPrivateNetworks bool `mapstructure:"WEBHOOK_ALLOW_PRIVATE_NETWORKS"`
+
+	// IdempotencyTTL (mis. "24h") adalah masa simpan response request dengan header
+	// Idempotency-Key untuk replay; 0 memakai default 24 jam
+	IdempotencyTTL time.Duration `mapstructure:"IDEMPOTENCY_TTL"`
+
+	// ExportDir adalah direktori file export data user; kosong berarti direktori temp sistem
+	ExportDir string `mapstructure:"EXPORT_DIR"`
+
+	// RequireIfMatch mewajibkan header If-Match pada request PUT (428 jika tidak ada)
+	RequireIfMatch bool `mapstructure:"REQUIRE_IF_MATCH"`
+
+	// TrustedProxies adalah IP atau CIDR reverse proxy dipisah koma yang boleh menentukan
+	// IP client lewat X-Forwarded-For. Kosong berarti IP koneksi langsung yang dipakai.
+	TrustedProxies string `mapstructure:"TRUSTED_PROXIES"`
+
+	// ContractValidation: enforce, log atau off. Kosong berarti log di production, enforce di lainnya
+	ContractValidation string `mapstructure:"CONTRACT_VALIDATION"`
+
+	// OIDCProviders adalah daftar nama provider OIDC dipisah koma, mis. "google,keycloak".
+	// Setiap provider dikonfigurasi lewat OIDC_<NAMA>_ISSUER, _CLIENT_ID, _CLIENT_SECRET,
+	// _REDIRECT_URL dan _SCOPES (opsional, dipisah koma).
+	OIDCProviders string         `mapstructure:"OIDC_PROVIDERS"`
+	OIDC          []OIDCProvider `mapstructure:"-"`
+
+	// OAuthIssuer adalah URL publik aplikasi sebagai OIDC provider, default http://localhost:<SERVER_PORT>
+	OAuthIssuer string `mapstructure:"OAUTH_ISSUER"`
+	// OAuthAuthorizationURL adalah halaman frontend yang dibuka browser sebagai authorization
+	// endpoint: halaman menampilkan login dan consent lalu memanggil GET/POST /authorize dengan
+	// sesi user. Kosong berarti authorization_endpoint mengarah ke <OAUTH_ISSUER>/authorize.
+	OAuthAuthorizationURL string `mapstructure:"OAUTH_AUTHORIZATION_URL"`
+	// OAuthSigningKeyFile adalah private key RSA PEM untuk ID dan access token. Kosong berarti key sementara.
+	OAuthSigningKeyFile string `mapstructure:"OAUTH_SIGNING_KEY_FILE"`
+}
+
+// OIDCProvider adalah konfigurasi satu provider OpenID Connect eksternal
+type OIDCProvider struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
 }
 
 func LoadConfig() (config Config, err error) {
@@ -29,5 +156,176 @@ func LoadConfig() (config Config, err error) {
 	}
 
 	err = viper.Unmarshal(&config)
+	if err != nil {
+		return
+	}
+
+	config.OIDC = loadOIDCProviders(config.OIDCProviders)
 	return
 }
+
+// OAuthIssuerURL mengembalikan issuer yang dipakai di discovery document dan token
+func (c Config) OAuthIssuerURL() string {
+	if c.OAuthIssuer != "" {
+		return strings.TrimSuffix(c.OAuthIssuer, "/")
+	}
+	return "http://localhost:" + c.ServerPort
+}
+
+// CacheEntryTTL mengembalikan masa berlaku entry cache
+func (c Config) CacheEntryTTL() time.Duration {
+	if c.CacheTTL > 0 {
+		return c.CacheTTL
+	}
+	return constants.CacheTTL
+}
+
+// ExportDirectory mengembalikan direktori file export data user
+func (c Config) ExportDirectory() string {
+	if c.ExportDir != "" {
+		return c.ExportDir
+	}
+	return filepath.Join(os.TempDir(), "boilerplate-exports")
+}
+
+// loadOIDCProviders membaca konfigurasi per provider dari key OIDC_<NAMA>_*
+func loadOIDCProviders(names string) []OIDCProvider {
+	var providers []OIDCProvider
+	for _, name := range splitList(names) {
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		providers = append(providers, OIDCProvider{
+			Name:         strings.ToLower(name),
+			Issuer:       viper.GetString(prefix + "ISSUER"),
+			ClientID:     viper.GetString(prefix + "CLIENT_ID"),
+			ClientSecret: viper.GetString(prefix + "CLIENT_SECRET"),
+			RedirectURL:  viper.GetString(prefix + "REDIRECT_URL"),
+			Scopes:       splitList(viper.GetString(prefix + "SCOPES")),
+		})
+	}
+	return providers
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// PasswordPolicy menyusun password policy dari konfigurasi. Daftar password
+// bocor dimuat terpisah karena membutuhkan akses file.
+func (c Config) PasswordPolicy() (password.Policy, error) {
+	policy := password.DefaultPolicy()
+	if c.PasswordMinLength > 0 {
+		policy.MinLength = c.PasswordMinLength
+	}
+	if c.PasswordMaxLength > 0 {
+		policy.MaxLength = c.PasswordMaxLength
+	}
+	if policy.MaxLength < policy.MinLength {
+		return policy, fmt.Errorf("PASSWORD_MAX_LENGTH (%d) must not be less than PASSWORD_MIN_LENGTH (%d)", policy.MaxLength, policy.MinLength)
+	}
+	policy.HistorySize = c.PasswordHistory
+
+	for _, class := range splitList(c.PasswordRequiredClasses) {
+		switch class {
+		case password.ClassLowercase, password.ClassUppercase, password.ClassDigit, password.ClassSymbol:
+			policy.RequiredClasses = append(policy.RequiredClasses, class)
+		default:
+			return policy, fmt.Errorf("PASSWORD_REQUIRED_CLASSES: unknown class %q", class)
+		}
+	}
+	return policy, nil
+}
+
+// PasswordHasher menyusun hasher password. Algoritma yang tidak dipilih tetap
+// didaftarkan untuk verifikasi agar hash lama dapat dimigrasikan saat login.
+func (c Config) PasswordHasher() (*password.Hasher, error) {
+	bcryptHasher := password.BcryptHasher{Cost: constants.BcryptCost}
+	if c.PasswordBcryptCost > 0 {
+		if c.PasswordBcryptCost < bcrypt.MinCost || c.PasswordBcryptCost > bcrypt.MaxCost {
+			return nil, fmt.Errorf("PASSWORD_BCRYPT_COST must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+		}
+		bcryptHasher.Cost = c.PasswordBcryptCost
+	}
+
+	argon2Hasher := password.DefaultArgon2idHasher()
+	if c.PasswordArgon2Memory > 0 {
+		argon2Hasher.Memory = c.PasswordArgon2Memory
+	}
+	if c.PasswordArgon2Iterations > 0 {
+		argon2Hasher.Iterations = c.PasswordArgon2Iterations
+	}
+	if c.PasswordArgon2Parallelism > 0 {
+		argon2Hasher.Parallelism = c.PasswordArgon2Parallelism
+	}
+
+	switch c.PasswordHashAlgorithm {
+	case "", "argon2id":
+		return password.NewHasher(argon2Hasher, bcryptHasher), nil
+	case "bcrypt":
+		return password.NewHasher(bcryptHasher, argon2Hasher), nil
+	default:
+		return nil, fmt.Errorf("PASSWORD_HASH_ALGORITHM: unknown algorithm %q", c.PasswordHashAlgorithm)
+	}
+}
+
+// Keyring menyusun keyring enkripsi field. Data key dari ENCRYPTION_KEY_FILE
+// didahulukan dari ENCRYPTION_KEYS dan ENCRYPTION_ACTIVE_KEY.
+func (c Config) Keyring() (*fieldcrypt.Keyring, error) {
+	if c.EncryptionMasterKey == "" {
+		if c.EncryptionKeyFile != "" || c.EncryptionKeys != "" {
+			return nil, fmt.Errorf("ENCRYPTION_MASTER_KEY is required to unwrap the data keys")
+		}
+		return fieldcrypt.NewKeyring(0, nil, nil)
+	}
+	master, err := base64.StdEncoding.DecodeString(c.EncryptionMasterKey)
+	if err != nil {
+		return nil, fmt.Errorf("ENCRYPTION_MASTER_KEY: %w", err)
+	}
+
+	var blindIndexKey []byte
+	if c.EncryptionBlindIndexKey != "" {
+		blindIndexKey, err = base64.StdEncoding.DecodeString(c.EncryptionBlindIndexKey)
+		if err != nil {
+			return nil, fmt.Errorf("ENCRYPTION_BLIND_INDEX_KEY: %w", err)
+		}
+	}
+
+	var file fieldcrypt.KeyFile
+	if c.EncryptionKeyFile != "" {
+		file, err = fieldcrypt.ReadKeyFile(c.EncryptionKeyFile)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		keys, err := fieldcrypt.ParseKeyList(c.EncryptionKeys)
+		if err != nil {
+			return nil, fmt.Errorf("ENCRYPTION_KEYS: %w", err)
+		}
+		file = fieldcrypt.KeyFile{Active: c.EncryptionActiveKey, Keys: keys}
+	}
+	if len(file.Keys) == 0 {
+		return nil, fmt.Errorf("ENCRYPTION_MASTER_KEY is set but no data keys are configured")
+	}
+	return fieldcrypt.LoadKeyring(master, file, blindIndexKey)
+}
+
+// TrustedProxyList mengembalikan daftar IP atau CIDR trusted proxy
+func (c Config) TrustedProxyList() []string {
+	return splitList(c.TrustedProxies)
+}
+
+// ContractValidationMode menentukan mode validasi kontrak OpenAPI
+func (c Config) ContractValidationMode() string {
+	if c.ContractValidation != "" {
+		return c.ContractValidation
+	}
+	if c.AppEnv == "production" {
+		return "log"
+	}
+	return "enforce"
+}
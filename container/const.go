@@ -1,16 +1,51 @@
 package container
 
 const (
-	ConfigDefName              string = "config"
-	DBDefName                  string = "db"
-	LoggerDefName              string = "logger"
-	UserServiceDefName         string = "userService"
-	CategoryServiceDefName     string = "categoryService"
-	UserHandlerDefName         string = "userHandler"
-	CategoryHandlerDefName     string = "categoryHandler"
-	AuthMiddlewareDefName      string = "authMiddleware"
-	AdminAuthMiddlewareDefName string = "adminAuthMiddleware"
-	EchoDefName                string = "echo"
-	ValidatorDefName           string = "validator"
-	RedisClientDefName         string = "redisClient"
+	ConfigDefName                string = "config"
+	DBDefName                    string = "db"
+	LoggerDefName                string = "logger"
+	PasswordPolicyDefName        string = "passwordPolicy"
+	PasswordHasherDefName        string = "passwordHasher"
+	MailerDefName                string = "mailer"
+	MailDeliveryDefName          string = "mailDelivery"
+	QueueDefName                 string = "queue"
+	WorkerDefName                string = "worker"
+	JobServiceDefName            string = "jobService"
+	JobHandlerDefName            string = "jobHandler"
+	SchedulerServiceDefName      string = "schedulerService"
+	SchedulerHandlerDefName      string = "schedulerHandler"
+	WebhookServiceDefName        string = "webhookService"
+	WebhookHandlerDefName        string = "webhookHandler"
+	EventBusDefName              string = "eventBus"
+	OutboxRelayDefName           string = "outboxRelay"
+	KeyringDefName               string = "keyring"
+	CacheRegistryDefName         string = "cacheRegistry"
+	CacheServiceDefName          string = "cacheService"
+	CacheHandlerDefName          string = "cacheHandler"
+	SearcherDefName              string = "searcher"
+	SearchIndexerDefName         string = "searchIndexer"
+	SearchServiceDefName         string = "searchService"
+	SearchHandlerDefName         string = "searchHandler"
+	UserServiceDefName           string = "userService"
+	CategoryServiceDefName       string = "categoryService"
+	UserHandlerDefName           string = "userHandler"
+	CategoryHandlerDefName       string = "categoryHandler"
+	APIKeyServiceDefName         string = "apiKeyService"
+	APIKeyHandlerDefName         string = "apiKeyHandler"
+	IdentityServiceDefName       string = "identityService"
+	IdentityHandlerDefName       string = "identityHandler"
+	JWTSignerDefName             string = "jwtSigner"
+	OAuthServiceDefName          string = "oauthService"
+	OAuthHandlerDefName          string = "oauthHandler"
+	InviteServiceDefName         string = "inviteService"
+	InviteHandlerDefName         string = "inviteHandler"
+	PrivacyServiceDefName        string = "privacyService"
+	PrivacyHandlerDefName        string = "privacyHandler"
+	AuthMiddlewareDefName        string = "authMiddleware"
+	AdminAuthMiddlewareDefName   string = "adminAuthMiddleware"
+	IdempotencyMiddlewareDefName string = "idempotencyMiddleware"
+	EchoDefName                  string = "echo"
+	ValidatorDefName             string = "validator"
+	RedisClientDefName           string = "redisClient"
+	TranslatorDefName            string = "translator"
 )
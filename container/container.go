@@ -1,13 +1,38 @@
 package container
 
 import (
+	"context"
+	"fmt"
+	"time"
+
 	"boilerplate/config"
+	"boilerplate/internal/apikey"
+	cacheStats "boilerplate/internal/cache"
 	"boilerplate/internal/category"
+	"boilerplate/internal/identity"
+	"boilerplate/internal/invite"
+	"boilerplate/internal/job"
+	"boilerplate/internal/oauth"
+	"boilerplate/internal/privacy"
+	"boilerplate/internal/scheduler"
+	adminSearch "boilerplate/internal/search"
 	"boilerplate/internal/user"
+	"boilerplate/internal/webhook"
+	"boilerplate/pkg/cache"
 	"boilerplate/pkg/database"
+	"boilerplate/pkg/events"
+	"boilerplate/pkg/i18n"
+	"boilerplate/pkg/idempotency"
+	"boilerplate/pkg/jwt"
 	"boilerplate/pkg/logger"
+	"boilerplate/pkg/mailer"
 	"boilerplate/pkg/middleware"
+	"boilerplate/pkg/password"
+	"boilerplate/pkg/queue"
 	"boilerplate/pkg/redis"
+	"boilerplate/pkg/response"
+	"boilerplate/pkg/search"
+	"boilerplate/shared/constants"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
@@ -48,6 +73,114 @@ func NewContainer() (di.Container, error) {
 				return redis.NewRedisClient(cfg.RedisAddr, cfg.RedisPassword, cfg.RedisDB), nil
 			},
 		},
+		{
+			Name: QueueDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				cfg := ctn.Get(ConfigDefName).(config.Config)
+				redisClient := ctn.Get(RedisClientDefName).(*redis.RedisClient)
+				return queue.New(redisClient.Client(), queue.Config{
+					VisibilityTimeout: cfg.QueueVisibilityTimeout,
+					MaxAttempts:       cfg.QueueMaxAttempts,
+				}), nil
+			},
+		},
+		{
+			// Mailer mengantrikan email; pengiriman dilakukan command worker lewat MailDeliveryDefName
+			Name: MailerDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				return mailer.NewQueueMailer(ctn.Get(QueueDefName).(*queue.Queue)), nil
+			},
+		},
+		{
+			Name: MailDeliveryDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				cfg := ctn.Get(ConfigDefName).(config.Config)
+				if cfg.SMTPHost == "" {
+					return mailer.NewLogMailer(ctn.Get(LoggerDefName).(logger.Logger)), nil
+				}
+				port := cfg.SMTPPort
+				if port == 0 {
+					port = 587
+				}
+				return mailer.NewSMTPMailer(mailer.SMTPConfig{
+					Host:     cfg.SMTPHost,
+					Port:     port,
+					Username: cfg.SMTPUsername,
+					Password: cfg.SMTPPassword,
+					From:     cfg.MailFrom,
+				}), nil
+			},
+		},
+		{
+			// Worker menjalankan job; setiap modul mendaftarkan handler job-nya di sini
+			Name: WorkerDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				worker := queue.NewWorker(ctn.Get(QueueDefName).(*queue.Queue), ctn.Get(LoggerDefName).(logger.Logger))
+				mailer.RegisterJobs(worker, ctn.Get(MailDeliveryDefName).(mailer.Mailer))
+				ctn.Get(WebhookServiceDefName).(*webhook.WebhookService).RegisterJobs(worker)
+				ctn.Get(CategoryServiceDefName).(*category.CategoryService).RegisterJobs(worker)
+				return worker, nil
+			},
+		},
+		{
+			// Bus meneruskan event outbox di proses worker; setiap modul mendaftarkan subscriber-nya di sini
+			Name: EventBusDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				bus := events.NewBus()
+				ctn.Get(WebhookServiceDefName).(*webhook.WebhookService).Subscribe(bus)
+				ctn.Get(CategoryServiceDefName).(*category.CategoryService).Subscribe(bus)
+				ctn.Get(UserServiceDefName).(*user.UserService).Subscribe(bus)
+				// index embedded hanya ada di memori proses server sehingga tidak diperbarui dari worker
+				if cfg := ctn.Get(ConfigDefName).(config.Config); cfg.SearchBackend != search.BackendEmbedded {
+					ctn.Get(SearchServiceDefName).(*adminSearch.SearchService).Subscribe(bus)
+				}
+				return bus, nil
+			},
+		},
+		{
+			Name: OutboxRelayDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				cfg := ctn.Get(ConfigDefName).(config.Config)
+				db := ctn.Get(DBDefName).(*gorm.DB)
+				logger := ctn.Get(LoggerDefName).(logger.Logger)
+				bus := ctn.Get(EventBusDefName).(*events.Bus)
+
+				var broker events.Broker
+				if cfg.EventStream != "" {
+					redisClient := ctn.Get(RedisClientDefName).(*redis.RedisClient)
+					streams := events.NewRedisStreams(redisClient.Client(), cfg.EventStream, logger)
+					if cfg.EventStreamMaxLen > 0 {
+						streams.MaxLen = cfg.EventStreamMaxLen
+					}
+					broker = streams
+				}
+				return events.NewRelay(db, bus, broker, logger, events.RelayConfig{}), nil
+			},
+		},
+		{
+			Name: KeyringDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				cfg := ctn.Get(ConfigDefName).(config.Config)
+				return cfg.Keyring()
+			},
+		},
+		{
+			Name: PasswordPolicyDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				cfg := ctn.Get(ConfigDefName).(config.Config)
+				policy, err := cfg.PasswordPolicy()
+				if err != nil {
+					return nil, err
+				}
+				if !cfg.DisablePasswordBreachCheck {
+					policy.Breached, err = password.LoadBreachList(cfg.PasswordBreachFile)
+					if err != nil {
+						return nil, err
+					}
+				}
+				return policy, nil
+			},
+		},
 		{
 			Name: UserServiceDefName,
 			Build: func(ctn di.Container) (interface{}, error) {
@@ -55,28 +188,321 @@ func NewContainer() (di.Container, error) {
 				db := ctn.Get(DBDefName).(*gorm.DB)
 				logger := ctn.Get(LoggerDefName).(logger.Logger)
 				redisClient := ctn.Get(RedisClientDefName).(*redis.RedisClient)
-				return user.NewUserService(db, cfg.JWTSecret, logger, redisClient), nil
+				policy := ctn.Get(PasswordPolicyDefName).(password.Policy)
+				mailer := ctn.Get(MailerDefName).(mailer.Mailer)
+				// user memuat nama dan email sehingga entry cache dienkripsi seperti kolomnya
+				users := ctn.Get(CacheRegistryDefName).(*cache.Registry).
+					Loader("user", min(cfg.CacheEntryTTL(), constants.UserCacheTTL), cache.WithCodec(cache.Encrypted("cache:user")))
+				indexer := ctn.Get(SearchIndexerDefName).(*search.Indexer)
+				return user.NewUserService(db, cfg.JWTSecret, logger, redisClient, policy, mailer, users, indexer), nil
 			},
 		},
 		{
 			Name: CategoryServiceDefName,
 			Build: func(ctn di.Container) (interface{}, error) {
+				cfg := ctn.Get(ConfigDefName).(config.Config)
 				db := ctn.Get(DBDefName).(*gorm.DB)
-				return category.NewCategoryService(db), nil
+				categories := ctn.Get(CacheRegistryDefName).(*cache.Registry).Loader("category", cfg.CacheEntryTTL())
+				indexer := ctn.Get(SearchIndexerDefName).(*search.Indexer)
+				return category.NewCategoryService(db, categories, indexer, ctn.Get(QueueDefName).(*queue.Queue)), nil
+			},
+		},
+		{
+			Name: CacheRegistryDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				cfg := ctn.Get(ConfigDefName).(config.Config)
+				logger := ctn.Get(LoggerDefName).(logger.Logger)
+
+				var backend cache.Cache
+				switch cfg.CacheBackend {
+				case "", cache.BackendRedis:
+					redisClient := ctn.Get(RedisClientDefName).(*redis.RedisClient)
+					backend = cache.NewRedisCache(redisClient.Client(), "")
+				case cache.BackendMemory:
+					backend = cache.NewLRU(cfg.CacheMaxEntries)
+				case cache.BackendNone:
+				default:
+					return nil, fmt.Errorf("CACHE_BACKEND: unknown backend %q", cfg.CacheBackend)
+				}
+				return cache.NewRegistry(backend, logger), nil
+			},
+		},
+		{
+			Name: CacheServiceDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				registry := ctn.Get(CacheRegistryDefName).(*cache.Registry)
+				return cacheStats.NewCacheService(registry), nil
+			},
+		},
+		{
+			Name: CacheHandlerDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				cacheService := ctn.Get(CacheServiceDefName).(cacheStats.CacheServiceInterface)
+				return cacheStats.NewCacheHandler(cacheService), nil
+			},
+		},
+		{
+			Name: SearcherDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				cfg := ctn.Get(ConfigDefName).(config.Config)
+				switch cfg.SearchBackend {
+				case "", search.BackendMySQL:
+					return search.NewMySQLSearcher(ctn.Get(DBDefName).(*gorm.DB)), nil
+				case search.BackendEmbedded:
+					return search.NewEmbeddedSearcher()
+				default:
+					return nil, fmt.Errorf("SEARCH_BACKEND: unknown backend %q", cfg.SearchBackend)
+				}
+			},
+		},
+		{
+			Name: SearchIndexerDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				searcher := ctn.Get(SearcherDefName).(search.Searcher)
+				return search.NewIndexer(searcher, ctn.Get(LoggerDefName).(logger.Logger)), nil
+			},
+		},
+		{
+			Name: SearchServiceDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				db := ctn.Get(DBDefName).(*gorm.DB)
+				searcher := ctn.Get(SearcherDefName).(search.Searcher)
+				logger := ctn.Get(LoggerDefName).(logger.Logger)
+				return adminSearch.NewSearchService(db, searcher, logger), nil
+			},
+		},
+		{
+			Name: SearchHandlerDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				searchService := ctn.Get(SearchServiceDefName).(adminSearch.SearchServiceInterface)
+				return adminSearch.NewSearchHandler(searchService), nil
 			},
 		},
 		{
 			Name: UserHandlerDefName,
 			Build: func(ctn di.Container) (interface{}, error) {
+				cfg := ctn.Get(ConfigDefName).(config.Config)
 				userService := ctn.Get(UserServiceDefName).(user.UserServiceInterface)
-				return user.NewUserHandler(userService), nil
+				return user.NewUserHandler(userService, cfg.RequireIfMatch, cfg.DisableRegistration), nil
 			},
 		},
 		{
 			Name: CategoryHandlerDefName,
 			Build: func(ctn di.Container) (interface{}, error) {
+				cfg := ctn.Get(ConfigDefName).(config.Config)
 				categoryService := ctn.Get(CategoryServiceDefName).(category.CategoryServiceInterface)
-				return category.NewCategoryHandler(categoryService), nil
+				return category.NewCategoryHandler(categoryService, cfg.RequireIfMatch, ctn.Get(LoggerDefName).(logger.Logger)), nil
+			},
+		},
+		{
+			Name: APIKeyServiceDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				db := ctn.Get(DBDefName).(*gorm.DB)
+				logger := ctn.Get(LoggerDefName).(logger.Logger)
+				return apikey.NewAPIKeyService(db, logger), nil
+			},
+		},
+		{
+			Name: APIKeyHandlerDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				apiKeyService := ctn.Get(APIKeyServiceDefName).(apikey.APIKeyServiceInterface)
+				return apikey.NewAPIKeyHandler(apiKeyService), nil
+			},
+		},
+		{
+			Name: IdentityServiceDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				cfg := ctn.Get(ConfigDefName).(config.Config)
+				db := ctn.Get(DBDefName).(*gorm.DB)
+				logger := ctn.Get(LoggerDefName).(logger.Logger)
+				redisClient := ctn.Get(RedisClientDefName).(*redis.RedisClient)
+				userService := ctn.Get(UserServiceDefName).(user.UserServiceInterface)
+				indexer := ctn.Get(SearchIndexerDefName).(*search.Indexer)
+				return identity.NewIdentityService(db, logger, redisClient, userService, indexer, cfg.OIDC, cfg.DisableRegistration), nil
+			},
+		},
+		{
+			Name: IdentityHandlerDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				identityService := ctn.Get(IdentityServiceDefName).(identity.IdentityServiceInterface)
+				return identity.NewIdentityHandler(identityService), nil
+			},
+		},
+		{
+			Name: JWTSignerDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				cfg := ctn.Get(ConfigDefName).(config.Config)
+				if cfg.OAuthSigningKeyFile == "" {
+					logger := ctn.Get(LoggerDefName).(logger.Logger)
+					logger.Warn("OAUTH_SIGNING_KEY_FILE kosong, memakai signing key sementara")
+				}
+				return jwt.LoadSigner(cfg.OAuthSigningKeyFile)
+			},
+		},
+		{
+			Name: OAuthServiceDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				cfg := ctn.Get(ConfigDefName).(config.Config)
+				db := ctn.Get(DBDefName).(*gorm.DB)
+				logger := ctn.Get(LoggerDefName).(logger.Logger)
+				redisClient := ctn.Get(RedisClientDefName).(*redis.RedisClient)
+				userService := ctn.Get(UserServiceDefName).(user.UserServiceInterface)
+				signer := ctn.Get(JWTSignerDefName).(*jwt.Signer)
+				return oauth.NewOAuthService(db, logger, redisClient, userService, signer, cfg.OAuthIssuerURL(), cfg.OAuthAuthorizationURL), nil
+			},
+		},
+		{
+			Name: OAuthHandlerDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				oauthService := ctn.Get(OAuthServiceDefName).(oauth.OAuthServiceInterface)
+				return oauth.NewOAuthHandler(oauthService), nil
+			},
+		},
+		{
+			Name: InviteServiceDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				cfg := ctn.Get(ConfigDefName).(config.Config)
+				db := ctn.Get(DBDefName).(*gorm.DB)
+				logger := ctn.Get(LoggerDefName).(logger.Logger)
+				policy := ctn.Get(PasswordPolicyDefName).(password.Policy)
+				indexer := ctn.Get(SearchIndexerDefName).(*search.Indexer)
+				return invite.NewInviteService(db, logger, cfg.JWTSecret, policy, indexer), nil
+			},
+		},
+		{
+			Name: InviteHandlerDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				inviteService := ctn.Get(InviteServiceDefName).(invite.InviteServiceInterface)
+				return invite.NewInviteHandler(inviteService), nil
+			},
+		},
+		{
+			Name: PrivacyServiceDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				cfg := ctn.Get(ConfigDefName).(config.Config)
+				db := ctn.Get(DBDefName).(*gorm.DB)
+				logger := ctn.Get(LoggerDefName).(logger.Logger)
+				userService := ctn.Get(UserServiceDefName).(user.UserServiceInterface)
+				// Modul baru yang menyimpan data milik user menambahkan PrivacyHook-nya di sini
+				hooks := []privacy.Hook{
+					user.NewPrivacyHook(),
+					category.NewPrivacyHook(),
+					apikey.NewPrivacyHook(),
+					identity.NewPrivacyHook(),
+					oauth.NewPrivacyHook(),
+					invite.NewPrivacyHook(),
+					adminSearch.NewPrivacyHook(ctn.Get(SearcherDefName).(search.Searcher)),
+				}
+				return privacy.NewPrivacyService(db, logger, userService, cfg.JWTSecret, cfg.ExportDirectory(), hooks...), nil
+			},
+		},
+		{
+			Name: PrivacyHandlerDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				privacyService := ctn.Get(PrivacyServiceDefName).(privacy.PrivacyServiceInterface)
+				return privacy.NewPrivacyHandler(privacyService), nil
+			},
+		},
+		{
+			Name: JobServiceDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				return job.NewJobService(ctn.Get(QueueDefName).(*queue.Queue), ctn.Get(LoggerDefName).(logger.Logger)), nil
+			},
+		},
+		{
+			Name: JobHandlerDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				jobService := ctn.Get(JobServiceDefName).(job.JobServiceInterface)
+				return job.NewJobHandler(jobService), nil
+			},
+		},
+		{
+			Name: WebhookServiceDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				db := ctn.Get(DBDefName).(*gorm.DB)
+				logger := ctn.Get(LoggerDefName).(logger.Logger)
+				cfg := ctn.Get(ConfigDefName).(config.Config)
+				return webhook.NewWebhookService(db, ctn.Get(QueueDefName).(*queue.Queue), logger, cfg.WebhookAllowPrivateNetworks), nil
+			},
+		},
+		{
+			Name: WebhookHandlerDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				webhookService := ctn.Get(WebhookServiceDefName).(webhook.WebhookServiceInterface)
+				return webhook.NewWebhookHandler(webhookService), nil
+			},
+		},
+		{
+			// Scheduler menjalankan task periodik; setiap modul mendaftarkan task-nya di sini
+			Name: SchedulerServiceDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				db := ctn.Get(DBDefName).(*gorm.DB)
+				redisClient := ctn.Get(RedisClientDefName).(*redis.RedisClient)
+				logger := ctn.Get(LoggerDefName).(logger.Logger)
+				userService := ctn.Get(UserServiceDefName).(user.UserServiceInterface)
+				privacyService := ctn.Get(PrivacyServiceDefName).(privacy.PrivacyServiceInterface)
+				webhookService := ctn.Get(WebhookServiceDefName).(webhook.WebhookServiceInterface)
+
+				service := scheduler.NewSchedulerService(db, redisClient.Client(), logger)
+				tasks := []scheduler.Task{
+					{
+						Name:     "privacy.purge_expired_exports",
+						Schedule: "@hourly",
+						Run: func(ctx context.Context) error {
+							_, err := privacyService.PurgeExpiredExports(ctx)
+							return err
+						},
+					},
+					{
+						Name:     "user.purge_login_events",
+						Schedule: "30 3 * * *",
+						Run: func(ctx context.Context) error {
+							_, err := userService.PurgeLoginEvents(ctx, time.Now().Add(-constants.LoginEventRetention))
+							return err
+						},
+					},
+					{
+						Name:     "webhook.purge_deliveries",
+						Schedule: "15 4 * * *",
+						Run: func(ctx context.Context) error {
+							_, err := webhookService.PurgeDeliveries(ctx, time.Now().Add(-constants.WebhookDeliveryRetention))
+							return err
+						},
+					},
+					{
+						Name:     "events.purge_outbox",
+						Schedule: "0 4 * * *",
+						Run: func(ctx context.Context) error {
+							before := time.Now().Add(-constants.OutboxRetention)
+							if _, err := events.PurgePublished(ctx, db, before); err != nil {
+								return err
+							}
+							_, err := events.PurgeProcessed(ctx, db, before)
+							return err
+						},
+					},
+					{
+						Name:     "scheduler.purge_runs",
+						Schedule: "45 3 * * *",
+						Run: func(ctx context.Context) error {
+							_, err := service.PurgeRuns(ctx, time.Now().Add(-constants.SchedulerRunRetention))
+							return err
+						},
+					},
+				}
+				for _, task := range tasks {
+					if err := service.Register(task); err != nil {
+						return nil, err
+					}
+				}
+				return service, nil
+			},
+		},
+		{
+			Name: SchedulerHandlerDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				schedulerService := ctn.Get(SchedulerServiceDefName).(scheduler.SchedulerServiceInterface)
+				return scheduler.NewSchedulerHandler(schedulerService), nil
 			},
 		},
 		{
@@ -84,7 +510,9 @@ func NewContainer() (di.Container, error) {
 			Build: func(ctn di.Container) (interface{}, error) {
 				cfg := ctn.Get(ConfigDefName).(config.Config)
 				userService := ctn.Get(UserServiceDefName).(user.UserServiceInterface)
-				return middleware.AuthMiddleware(userService, cfg.JWTSecret), nil
+				apiKeyService := ctn.Get(APIKeyServiceDefName).(apikey.APIKeyServiceInterface)
+				logger := ctn.Get(LoggerDefName).(logger.Logger)
+				return middleware.AuthMiddleware(userService, apiKeyService, cfg.JWTSecret, logger), nil
 			},
 		},
 		{
@@ -93,18 +521,47 @@ func NewContainer() (di.Container, error) {
 				return middleware.AdminMiddleware(), nil
 			},
 		},
+		{
+			Name: IdempotencyMiddlewareDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				cfg := ctn.Get(ConfigDefName).(config.Config)
+				redisClient := ctn.Get(RedisClientDefName).(*redis.RedisClient)
+				logger := ctn.Get(LoggerDefName).(logger.Logger)
+				store := idempotency.NewStore(redisClient.Client(), cfg.IdempotencyTTL)
+				return middleware.IdempotencyMiddleware(store, logger), nil
+			},
+		},
 		{
 			Name: ValidatorDefName,
 			Build: func(ctn di.Container) (interface{}, error) {
-				return validator.New(), nil
+				validate := validator.New()
+				validate.RegisterTagNameFunc(i18n.JSONTagName)
+				return validate, nil
+			},
+		},
+		{
+			Name: TranslatorDefName,
+			Build: func(ctn di.Container) (interface{}, error) {
+				validate := ctn.Get(ValidatorDefName).(*validator.Validate)
+				return i18n.New(validate)
 			},
 		},
 		{
 			Name: EchoDefName,
 			Build: func(ctn di.Container) (interface{}, error) {
 				e := echo.New()
+				cfg := ctn.Get(ConfigDefName).(config.Config)
+				ipExtractor, err := middleware.NewIPExtractor(cfg.TrustedProxyList())
+				if err != nil {
+					return nil, err
+				}
+				e.IPExtractor = ipExtractor
 				validate := ctn.Get(ValidatorDefName).(*validator.Validate)
+				translator := ctn.Get(TranslatorDefName).(*i18n.Translator)
+				logger := ctn.Get(LoggerDefName).(logger.Logger)
 				e.Validator = &CustomValidator{validator: validate}
+				e.HTTPErrorHandler = response.NewHTTPErrorHandler(logger, translator)
+				e.Use(middleware.LocaleMiddleware(translator))
 				return e, nil
 			},
 		},
@@ -117,7 +574,9 @@ func NewContainer() (di.Container, error) {
 	return builder.Build(), nil
 }
 
-// CustomValidator adalah custom validator untuk Echo
+// CustomValidator adalah custom validator untuk Echo. Terjemahan en/id
+// didaftarkan ke validator oleh i18n.New dan dipilih sesuai Accept-Language
+// di error handler.
 type CustomValidator struct {
 	validator *validator.Validate
 }
@@ -0,0 +1,125 @@
+package apikey
+
+import (
+	"net/http"
+	"strconv"
+
+	"boilerplate/internal/apikey/model"
+	userModel "boilerplate/internal/user/model"
+	"boilerplate/pkg/response"
+	errs "boilerplate/shared/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+type APIKeyHandler struct {
+	apiKeyService APIKeyServiceInterface
+}
+
+func NewAPIKeyHandler(apiKeyService APIKeyServiceInterface) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+	}
+}
+
+func (h *APIKeyHandler) Create(c echo.Context) error {
+	user, exists := c.Get("user").(*userModel.User)
+	if !exists {
+		return errs.ErrUnauthorized
+	}
+
+	var input model.CreateAPIKeyInput
+	if err := c.Bind(&input); err != nil {
+		return errs.ErrInvalidPayload.Wrap(err)
+	}
+
+	if err := c.Validate(&input); err != nil {
+		return err
+	}
+
+	key, err := h.apiKeyService.Create(input, user)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, http.StatusCreated, "API key created successfully, store the secret now: it will not be shown again", key)
+}
+
+func (h *APIKeyHandler) GetAll(c echo.Context) error {
+	user, exists := c.Get("user").(*userModel.User)
+	if !exists {
+		return errs.ErrUnauthorized
+	}
+
+	keys, err := h.apiKeyService.List(user)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, http.StatusOK, "API keys retrieved successfully", keys)
+}
+
+func (h *APIKeyHandler) GetByID(c echo.Context) error {
+	user, exists := c.Get("user").(*userModel.User)
+	if !exists {
+		return errs.ErrUnauthorized
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return errs.ErrInvalidID.Wrap(err)
+	}
+
+	key, err := h.apiKeyService.GetByID(uint(id), user)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, http.StatusOK, "API key retrieved successfully", key)
+}
+
+func (h *APIKeyHandler) Update(c echo.Context) error {
+	user, exists := c.Get("user").(*userModel.User)
+	if !exists {
+		return errs.ErrUnauthorized
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return errs.ErrInvalidID.Wrap(err)
+	}
+
+	var input model.UpdateAPIKeyInput
+	if err := c.Bind(&input); err != nil {
+		return errs.ErrInvalidPayload.Wrap(err)
+	}
+
+	if err := c.Validate(&input); err != nil {
+		return err
+	}
+
+	key, err := h.apiKeyService.Update(uint(id), input, user)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, http.StatusOK, "API key updated successfully", key)
+}
+
+func (h *APIKeyHandler) Revoke(c echo.Context) error {
+	user, exists := c.Get("user").(*userModel.User)
+	if !exists {
+		return errs.ErrUnauthorized
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return errs.ErrInvalidID.Wrap(err)
+	}
+
+	if err := h.apiKeyService.Revoke(uint(id), user); err != nil {
+		return err
+	}
+
+	return response.Success(c, http.StatusOK, "API key revoked successfully", nil)
+}
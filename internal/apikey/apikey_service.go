@@ -0,0 +1,156 @@
+package apikey
+
+import (
+	"errors"
+	"time"
+
+	"boilerplate/internal/apikey/model"
+	userModel "boilerplate/internal/user/model"
+	"boilerplate/pkg/logger"
+	errs "boilerplate/shared/errors"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// lastUsedInterval membatasi penulisan last_used_at agar tidak terjadi di setiap request
+const lastUsedInterval = time.Minute
+
+// APIKeyServiceInterface mendefinisikan kontrak untuk APIKeyService
+type APIKeyServiceInterface interface {
+	Create(input model.CreateAPIKeyInput, owner *userModel.User) (*model.CreatedAPIKey, error)
+	List(owner *userModel.User) ([]model.APIKey, error)
+	GetByID(id uint, owner *userModel.User) (*model.APIKey, error)
+	Update(id uint, input model.UpdateAPIKeyInput, owner *userModel.User) (*model.APIKey, error)
+	Revoke(id uint, owner *userModel.User) error
+	Authenticate(rawKey, ip string) (*model.APIKey, error)
+}
+
+type APIKeyService struct {
+	db     *gorm.DB
+	logger logger.Logger
+}
+
+func NewAPIKeyService(db *gorm.DB, logger logger.Logger) *APIKeyService {
+	return &APIKeyService{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (s *APIKeyService) Create(input model.CreateAPIKeyInput, owner *userModel.User) (*model.CreatedAPIKey, error) {
+	if input.ServiceName != "" && !owner.IsAdmin() {
+		return nil, errs.ErrAdminRequired
+	}
+
+	key, secret, err := model.NewAPIKey(input, owner.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Create(key).Error; err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"user_id": owner.ID,
+			"error":   err.Error(),
+		}).Error("Gagal menyimpan API key")
+		return nil, err
+	}
+
+	return &model.CreatedAPIKey{APIKey: *key, Secret: secret}, nil
+}
+
+// List mengembalikan API key milik user; admin melihat semua API key
+func (s *APIKeyService) List(owner *userModel.User) ([]model.APIKey, error) {
+	query := s.db.Order("id")
+	if !owner.IsAdmin() {
+		query = query.Where("user_id = ?", owner.ID)
+	}
+
+	var keys []model.APIKey
+	if err := query.Find(&keys).Error; err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (s *APIKeyService) GetByID(id uint, owner *userModel.User) (*model.APIKey, error) {
+	var key model.APIKey
+	if err := s.db.First(&key, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrAPIKeyNotFound.Wrap(err)
+		}
+		return nil, err
+	}
+
+	// Sembunyikan keberadaan key milik user lain
+	if key.UserID != owner.ID && !owner.IsAdmin() {
+		return nil, errs.ErrAPIKeyNotFound
+	}
+	return &key, nil
+}
+
+func (s *APIKeyService) Update(id uint, input model.UpdateAPIKeyInput, owner *userModel.User) (*model.APIKey, error) {
+	key, err := s.GetByID(id, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	key.Update(input)
+	if err := s.db.Save(key).Error; err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (s *APIKeyService) Revoke(id uint, owner *userModel.User) error {
+	key, err := s.GetByID(id, owner)
+	if err != nil {
+		return err
+	}
+	if key.RevokedAt != nil {
+		return nil
+	}
+
+	now := time.Now()
+	return s.db.Model(key).Update("revoked_at", now).Error
+}
+
+// Authenticate memvalidasi API key mentah dari header dan mencatat pemakaian terakhir
+func (s *APIKeyService) Authenticate(rawKey, ip string) (*model.APIKey, error) {
+	keyID, secret, err := model.ParseKey(rawKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var key model.APIKey
+	if err := s.db.Where("key_id = ?", keyID).First(&key).Error; err != nil {
+		return nil, errs.ErrInvalidAPIKey.Wrap(err)
+	}
+
+	now := time.Now()
+	if !key.Matches(secret) || !key.IsActive(now) {
+		return nil, errs.ErrInvalidAPIKey
+	}
+
+	if !key.AllowsIP(ip) {
+		s.logger.WithFields(logrus.Fields{
+			"api_key_id": key.ID,
+			"ip":         ip,
+		}).Warn("API key dipakai dari IP yang tidak diizinkan")
+		return nil, errs.ErrAPIKeyIPNotAllowed
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedInterval || key.LastUsedIP != ip {
+		if err := s.db.Model(&key).UpdateColumns(map[string]interface{}{
+			"last_used_at": now,
+			"last_used_ip": ip,
+		}).Error; err != nil {
+			s.logger.WithFields(logrus.Fields{
+				"api_key_id": key.ID,
+				"error":      err.Error(),
+			}).Error("Gagal mencatat pemakaian API key")
+		}
+	}
+
+	return &key, nil
+}
@@ -0,0 +1,192 @@
+package model
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"math/big"
+	"net"
+	"strings"
+	"time"
+
+	errs "boilerplate/shared/errors"
+)
+
+const (
+	// KeyPrefix membuat API key mudah dicari (grep) jika bocor
+	KeyPrefix = "bpk_"
+
+	keyIDLength  = 12
+	secretLength = 32
+	alphabet     = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+)
+
+// Resource yang dapat dibatasi dengan scope "<resource>:read" dan "<resource>:write"
+const (
+	ResourceUsers      = "users"
+	ResourceCategories = "categories"
+)
+
+// Scope yang dapat diberikan ke API key
+const (
+	ScopeUsersRead       = "users:read"
+	ScopeUsersWrite      = "users:write"
+	ScopeCategoriesRead  = "categories:read"
+	ScopeCategoriesWrite = "categories:write"
+)
+
+type APIKey struct {
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	UserID      uint       `json:"user_id" gorm:"index"`
+	ServiceName string     `json:"service_name,omitempty" gorm:"size:100"`
+	Name        string     `json:"name" gorm:"size:100"`
+	KeyID       string     `json:"key_id" gorm:"size:32;uniqueIndex"`
+	SecretHash  string     `json:"-" gorm:"size:64"`
+	Scopes      []string   `json:"scopes" gorm:"serializer:json"`
+	AllowedIPs  []string   `json:"allowed_ips" gorm:"serializer:json"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	LastUsedAt  *time.Time `json:"last_used_at"`
+	LastUsedIP  string     `json:"last_used_ip" gorm:"size:45"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// CreatedAPIKey adalah response create; Secret hanya ditampilkan sekali
+type CreatedAPIKey struct {
+	APIKey
+	Secret string `json:"secret"`
+}
+
+// DTO: Create API key input. ServiceName hanya boleh diisi admin.
+type CreateAPIKeyInput struct {
+	Name        string     `json:"name" validate:"required,max=100"`
+	ServiceName string     `json:"service_name" validate:"omitempty,max=100"`
+	Scopes      []string   `json:"scopes" validate:"required,min=1,dive,oneof=users:read users:write categories:read categories:write"`
+	AllowedIPs  []string   `json:"allowed_ips" validate:"omitempty,dive,cidr|ip"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+}
+
+// DTO: Update API key input
+type UpdateAPIKeyInput struct {
+	Name       string     `json:"name" validate:"required,max=100"`
+	Scopes     []string   `json:"scopes" validate:"required,min=1,dive,oneof=users:read users:write categories:read categories:write"`
+	AllowedIPs []string   `json:"allowed_ips" validate:"omitempty,dive,cidr|ip"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+}
+
+// Factory: Create new API key and its plaintext secret
+func NewAPIKey(input CreateAPIKeyInput, userID uint) (*APIKey, string, error) {
+	keyID, err := randomString(keyIDLength)
+	if err != nil {
+		return nil, "", err
+	}
+	secret, err := randomString(secretLength)
+	if err != nil {
+		return nil, "", err
+	}
+
+	key := &APIKey{
+		UserID:      userID,
+		ServiceName: strings.TrimSpace(input.ServiceName),
+		Name:        strings.TrimSpace(input.Name),
+		KeyID:       keyID,
+		SecretHash:  hashSecret(secret),
+		Scopes:      input.Scopes,
+		AllowedIPs:  input.AllowedIPs,
+		ExpiresAt:   input.ExpiresAt,
+	}
+	return key, KeyPrefix + keyID + "_" + secret, nil
+}
+
+// ParseKey memecah "bpk_<key id>_<secret>" menjadi key id dan secret
+func ParseKey(raw string) (string, string, error) {
+	rest, ok := strings.CutPrefix(raw, KeyPrefix)
+	if !ok {
+		return "", "", errs.ErrInvalidAPIKey
+	}
+	keyID, secret, ok := strings.Cut(rest, "_")
+	if !ok || len(keyID) != keyIDLength || len(secret) != secretLength {
+		return "", "", errs.ErrInvalidAPIKey
+	}
+	return keyID, secret, nil
+}
+
+// Update applies editable fields
+func (k *APIKey) Update(input UpdateAPIKeyInput) {
+	k.Name = strings.TrimSpace(input.Name)
+	k.Scopes = input.Scopes
+	k.AllowedIPs = input.AllowedIPs
+	k.ExpiresAt = input.ExpiresAt
+}
+
+// Matches membandingkan secret dengan hash secara constant-time
+func (k *APIKey) Matches(secret string) bool {
+	return subtle.ConstantTimeCompare([]byte(k.SecretHash), []byte(hashSecret(secret))) == 1
+}
+
+// IsActive reports whether the key is neither revoked nor expired
+func (k *APIKey) IsActive(now time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
+
+// AllowsIP checks the allowlist; an empty allowlist allows every address
+func (k *APIKey) AllowsIP(ip string) bool {
+	if len(k.AllowedIPs) == 0 {
+		return true
+	}
+
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return false
+	}
+	for _, allowed := range k.AllowedIPs {
+		if _, network, err := net.ParseCIDR(allowed); err == nil {
+			if network.Contains(addr) {
+				return true
+			}
+			continue
+		}
+		if allowedIP := net.ParseIP(allowed); allowedIP != nil && allowedIP.Equal(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasScope checks whether the key was granted scope
+func (k *APIKey) HasScope(scope string) bool {
+	for _, granted := range k.Scopes {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// IsServiceKey reports whether the key belongs to a service instead of a person
+func (k *APIKey) IsServiceKey() bool {
+	return k.ServiceName != ""
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomString(length int) (string, error) {
+	max := big.NewInt(int64(len(alphabet)))
+	buf := make([]byte, length)
+	for i := range buf {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		buf[i] = alphabet[n.Int64()]
+	}
+	return string(buf), nil
+}
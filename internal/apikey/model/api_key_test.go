@@ -0,0 +1,116 @@
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	errs "boilerplate/shared/errors"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type APIKeyTestSuite struct {
+	suite.Suite
+}
+
+func TestAPIKeySuite(t *testing.T) {
+	suite.Run(t, new(APIKeyTestSuite))
+}
+
+func (s *APIKeyTestSuite) newKey(input CreateAPIKeyInput) (*APIKey, string) {
+	if input.Name == "" {
+		input.Name = "partner"
+	}
+	key, raw, err := NewAPIKey(input, 1)
+	s.Require().NoError(err)
+	return key, raw
+}
+
+func (s *APIKeyTestSuite) TestNewAPIKey() {
+	key, raw := s.newKey(CreateAPIKeyInput{Scopes: []string{ScopeCategoriesRead}})
+
+	s.True(strings.HasPrefix(raw, KeyPrefix))
+	s.NotContains(key.SecretHash, raw)
+
+	keyID, secret, err := ParseKey(raw)
+	s.Require().NoError(err)
+	s.Equal(key.KeyID, keyID)
+	s.True(key.Matches(secret))
+	s.False(key.Matches(strings.Repeat("x", secretLength)))
+}
+
+func (s *APIKeyTestSuite) TestParseKey() {
+	tests := []struct {
+		name string
+		raw  string
+	}{
+		{name: "missing prefix", raw: "abcdefghijkl_" + strings.Repeat("a", secretLength)},
+		{name: "missing separator", raw: KeyPrefix + strings.Repeat("a", keyIDLength+secretLength)},
+		{name: "short secret", raw: KeyPrefix + strings.Repeat("a", keyIDLength) + "_abc"},
+		{name: "empty", raw: ""},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			_, _, err := ParseKey(tt.raw)
+			s.ErrorIs(err, errs.ErrInvalidAPIKey)
+		})
+	}
+}
+
+func (s *APIKeyTestSuite) TestIsActive() {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	tests := []struct {
+		name      string
+		expiresAt *time.Time
+		revokedAt *time.Time
+		expected  bool
+	}{
+		{name: "no expiry", expected: true},
+		{name: "not yet expired", expiresAt: &future, expected: true},
+		{name: "expired", expiresAt: &past, expected: false},
+		{name: "revoked", revokedAt: &past, expected: false},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			key := &APIKey{ExpiresAt: tt.expiresAt, RevokedAt: tt.revokedAt}
+			s.Equal(tt.expected, key.IsActive(now))
+		})
+	}
+}
+
+func (s *APIKeyTestSuite) TestAllowsIP() {
+	tests := []struct {
+		name       string
+		allowedIPs []string
+		ip         string
+		expected   bool
+	}{
+		{name: "empty allowlist", ip: "203.0.113.7", expected: true},
+		{name: "exact match", allowedIPs: []string{"203.0.113.7"}, ip: "203.0.113.7", expected: true},
+		{name: "cidr match", allowedIPs: []string{"10.0.0.0/8"}, ip: "10.1.2.3", expected: true},
+		{name: "ipv6 cidr match", allowedIPs: []string{"2001:db8::/32"}, ip: "2001:db8::1", expected: true},
+		{name: "not allowed", allowedIPs: []string{"10.0.0.0/8", "203.0.113.7"}, ip: "192.168.1.1", expected: false},
+		{name: "invalid ip", allowedIPs: []string{"10.0.0.0/8"}, ip: "unknown", expected: false},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			key := &APIKey{AllowedIPs: tt.allowedIPs}
+			s.Equal(tt.expected, key.AllowsIP(tt.ip))
+		})
+	}
+}
+
+func (s *APIKeyTestSuite) TestHasScope() {
+	key, _ := s.newKey(CreateAPIKeyInput{Scopes: []string{ScopeCategoriesRead, ScopeUsersWrite}})
+
+	s.True(key.HasScope(ScopeCategoriesRead))
+	s.True(key.HasScope(ScopeUsersWrite))
+	s.False(key.HasScope(ScopeCategoriesWrite))
+}
@@ -0,0 +1,32 @@
+package apikey
+
+import (
+	"context"
+
+	"boilerplate/internal/apikey/model"
+
+	"gorm.io/gorm"
+)
+
+// PrivacyHook mengekspor metadata API key user (tanpa hash secret) dan menghapusnya saat erasure
+type PrivacyHook struct{}
+
+func NewPrivacyHook() *PrivacyHook {
+	return &PrivacyHook{}
+}
+
+func (h *PrivacyHook) Name() string {
+	return "api_keys"
+}
+
+func (h *PrivacyHook) Export(ctx context.Context, db *gorm.DB, userID uint) (interface{}, error) {
+	var keys []model.APIKey
+	if err := db.WithContext(ctx).Where("user_id = ?", userID).Order("id").Find(&keys).Error; err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (h *PrivacyHook) Erase(ctx context.Context, tx *gorm.DB, userID uint) error {
+	return tx.WithContext(ctx).Where("user_id = ?", userID).Delete(&model.APIKey{}).Error
+}
@@ -0,0 +1,23 @@
+package cache
+
+import (
+	"net/http"
+
+	"boilerplate/pkg/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+type CacheHandler struct {
+	cacheService CacheServiceInterface
+}
+
+func NewCacheHandler(cacheService CacheServiceInterface) *CacheHandler {
+	return &CacheHandler{
+		cacheService: cacheService,
+	}
+}
+
+func (h *CacheHandler) Stats(c echo.Context) error {
+	return response.Success(c, http.StatusOK, "Cache stats retrieved successfully", h.cacheService.Stats())
+}
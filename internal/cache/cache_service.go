@@ -0,0 +1,26 @@
+package cache
+
+import (
+	"boilerplate/pkg/cache"
+)
+
+// CacheServiceInterface mendefinisikan kontrak untuk CacheService
+type CacheServiceInterface interface {
+	Stats() []cache.Stats
+}
+
+// CacheService membaca statistik read-through cache. Hitungan disimpan di memori
+// sehingga hanya mencakup proses yang melayani request.
+type CacheService struct {
+	registry *cache.Registry
+}
+
+func NewCacheService(registry *cache.Registry) *CacheService {
+	return &CacheService{
+		registry: registry,
+	}
+}
+
+func (s *CacheService) Stats() []cache.Stats {
+	return s.registry.Stats()
+}
@@ -1,40 +1,59 @@
 package category
 
 import (
+	"bytes"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"time"
 
 	categoryModel "boilerplate/internal/category/model"
 	userModel "boilerplate/internal/user/model"
+	"boilerplate/pkg/etag"
+	"boilerplate/pkg/logger"
+	"boilerplate/pkg/patch"
 	"boilerplate/pkg/response"
+	"boilerplate/pkg/tabular"
+	"boilerplate/shared/constants"
+	errs "boilerplate/shared/errors"
 
 	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
 )
 
 type CategoryHandler struct {
 	categoryService CategoryServiceInterface
+	requireIfMatch  bool
+	logger          logger.Logger
 }
 
-func NewCategoryHandler(categoryService CategoryServiceInterface) *CategoryHandler {
+func NewCategoryHandler(categoryService CategoryServiceInterface, requireIfMatch bool, log logger.Logger) *CategoryHandler {
 	return &CategoryHandler{
 		categoryService: categoryService,
+		requireIfMatch:  requireIfMatch,
+		logger:          log,
 	}
 }
 
 func (h *CategoryHandler) Create(c echo.Context) error {
 	user, exists := c.Get("user").(*userModel.User)
 	if !exists {
-		return response.Unauthorized(c, "unauthorized", nil)
+		return errs.ErrUnauthorized
 	}
 
 	var input categoryModel.CreateCategoryInput
 	if err := c.Bind(&input); err != nil {
-		return response.BadRequest(c, "invalid request payload", err)
+		return errs.ErrInvalidPayload.Wrap(err)
+	}
+
+	if err := c.Validate(&input); err != nil {
+		return err
 	}
 
 	category, err := h.categoryService.Create(input, user)
 	if err != nil {
-		return response.BadRequest(c, "failed to create category", err)
+		return err
 	}
 
 	return response.Success(c, http.StatusCreated, "Category created successfully", category)
@@ -43,7 +62,7 @@ func (h *CategoryHandler) Create(c echo.Context) error {
 func (h *CategoryHandler) GetAll(c echo.Context) error {
 	categories, err := h.categoryService.GetAll()
 	if err != nil {
-		return response.Error(c, http.StatusInternalServerError, "failed to get categories", err)
+		return err
 	}
 
 	return response.Success(c, http.StatusOK, "Categories retrieved successfully", categories)
@@ -52,12 +71,18 @@ func (h *CategoryHandler) GetAll(c echo.Context) error {
 func (h *CategoryHandler) GetByID(c echo.Context) error {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		return response.BadRequest(c, "invalid category id", err)
+		return errs.ErrInvalidID.Wrap(err)
 	}
 
 	category, err := h.categoryService.GetByID(uint(id))
 	if err != nil {
-		return response.BadRequest(c, "category not found", err)
+		return err
+	}
+
+	tag := etag.Generate(category.ID, category.Version)
+	etag.Set(c, tag)
+	if etag.NotModified(c, tag) {
+		return c.NoContent(http.StatusNotModified)
 	}
 
 	return response.Success(c, http.StatusOK, "Category retrieved successfully", category)
@@ -66,41 +91,191 @@ func (h *CategoryHandler) GetByID(c echo.Context) error {
 func (h *CategoryHandler) Update(c echo.Context) error {
 	user, exists := c.Get("user").(*userModel.User)
 	if !exists {
-		return response.Unauthorized(c, "unauthorized", nil)
+		return errs.ErrUnauthorized
 	}
 
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		return response.BadRequest(c, "invalid category id", err)
+		return errs.ErrInvalidID.Wrap(err)
 	}
 
 	var input categoryModel.CreateCategoryInput
 	if err := c.Bind(&input); err != nil {
-		return response.BadRequest(c, "invalid request payload", err)
+		return errs.ErrInvalidPayload.Wrap(err)
+	}
+
+	if err := c.Validate(&input); err != nil {
+		return err
+	}
+
+	current, err := h.categoryService.GetForUpdate(uint(id))
+	if err != nil {
+		return err
+	}
+
+	if err := etag.CheckIfMatch(c, etag.Generate(current.ID, current.Version), h.requireIfMatch); err != nil {
+		return err
+	}
+
+	category, err := h.categoryService.Update(current, input, user)
+	if err != nil {
+		return etag.ConflictError(c, err)
+	}
+
+	etag.Set(c, etag.Generate(category.ID, category.Version))
+	return response.Success(c, http.StatusOK, "Category updated successfully", category)
+}
+
+func (h *CategoryHandler) Patch(c echo.Context) error {
+	user, exists := c.Get("user").(*userModel.User)
+	if !exists {
+		return errs.ErrUnauthorized
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return errs.ErrInvalidID.Wrap(err)
 	}
 
-	category, err := h.categoryService.Update(uint(id), input, user)
+	body, err := io.ReadAll(c.Request().Body)
 	if err != nil {
-		return response.BadRequest(c, "failed to update category", err)
+		return errs.ErrInvalidPayload.Wrap(err)
 	}
 
+	current, err := h.categoryService.GetForUpdate(uint(id))
+	if err != nil {
+		return err
+	}
+
+	if err := etag.CheckIfMatch(c, etag.Generate(current.ID, current.Version), h.requireIfMatch); err != nil {
+		return err
+	}
+
+	var input categoryModel.PatchCategoryInput
+	if err := patch.ApplyTo(c.Request().Header.Get(echo.HeaderContentType), current.PatchDocument(), body, &input); err != nil {
+		return err
+	}
+
+	if err := c.Validate(&input); err != nil {
+		return err
+	}
+
+	category, err := h.categoryService.Patch(current, input, user)
+	if err != nil {
+		return etag.ConflictError(c, err)
+	}
+
+	etag.Set(c, etag.Generate(category.ID, category.Version))
 	return response.Success(c, http.StatusOK, "Category updated successfully", category)
 }
 
 func (h *CategoryHandler) Delete(c echo.Context) error {
 	user, exists := c.Get("user").(*userModel.User)
 	if !exists {
-		return response.Unauthorized(c, "unauthorized", nil)
+		return errs.ErrUnauthorized
 	}
 
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		return response.BadRequest(c, "invalid category id", err)
+		return errs.ErrInvalidID.Wrap(err)
 	}
 
 	if err := h.categoryService.Delete(uint(id), user); err != nil {
-		return response.BadRequest(c, "failed to delete category", err)
+		return err
 	}
 
 	return response.Success(c, http.StatusOK, "Category deleted successfully", nil)
 }
+
+// Import mengimport category dari file CSV, JSON atau XLSX di body request. Format
+// diambil dari query format, Content-Type, lalu isi file.
+func (h *CategoryHandler) Import(c echo.Context) error {
+	user, exists := c.Get("user").(*userModel.User)
+	if !exists {
+		return errs.ErrUnauthorized
+	}
+
+	// body berupa file sehingga hanya query yang di-bind
+	var query categoryModel.ImportQuery
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, &query); err != nil {
+		return errs.ErrInvalidPayload.Wrap(err)
+	}
+
+	if err := c.Validate(&query); err != nil {
+		return err
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request().Body, constants.CategoryImportMaxSize+1))
+	if err != nil {
+		return errs.ErrInvalidPayload.Wrap(err)
+	}
+	if len(body) > constants.CategoryImportMaxSize {
+		return errs.ErrCategoryImportTooLarge.WithDetails(map[string]interface{}{"max_bytes": constants.CategoryImportMaxSize})
+	}
+
+	format := query.Format
+	if format == "" {
+		var ok bool
+		if format, ok = tabular.FormatOf(c.Request().Header.Get(echo.HeaderContentType)); !ok {
+			format = tabular.Sniff(body)
+		}
+	}
+
+	imp, err := h.categoryService.Import(format, bytes.NewReader(body), query, user)
+	if err != nil {
+		return err
+	}
+
+	if !imp.IsDone() {
+		return response.Success(c, http.StatusAccepted, "Import started, poll its status until it is completed", imp)
+	}
+	return response.Success(c, http.StatusOK, "Import completed", imp)
+}
+
+func (h *CategoryHandler) GetImport(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return errs.ErrInvalidID.Wrap(err)
+	}
+
+	imp, err := h.categoryService.GetImport(uint(id))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, http.StatusOK, "Import retrieved successfully", imp)
+}
+
+// Export mengirim semua category sebagai file CSV (default), JSON atau XLSX.
+// Baris ditulis langsung ke response tanpa dikumpulkan di memori. Error setelah
+// header terkirim hanya di-log dan koneksi diputus agar client tidak menerima file
+// terpotong sebagai download yang berhasil.
+func (h *CategoryHandler) Export(c echo.Context) error {
+	var query categoryModel.ExportQuery
+	if err := c.Bind(&query); err != nil {
+		return errs.ErrInvalidPayload.Wrap(err)
+	}
+
+	if err := c.Validate(&query); err != nil {
+		return err
+	}
+
+	format := query.Format
+	if format == "" {
+		format = tabular.FormatCSV
+	}
+
+	header := c.Response().Header()
+	header.Set(echo.HeaderContentType, tabular.ContentType(format))
+	header.Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", "categories-"+time.Now().UTC().Format("20060102")+"."+format))
+	err := h.categoryService.Export(c.Request().Context(), format, c.Response())
+	if err == nil || !c.Response().Committed {
+		return err
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"format": format,
+		"error":  err.Error(),
+	}).Error("Export category gagal di tengah stream")
+	panic(http.ErrAbortHandler)
+}
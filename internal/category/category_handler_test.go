@@ -0,0 +1,155 @@
+package category
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"boilerplate/internal/category/model"
+	"boilerplate/internal/testutil"
+	userModel "boilerplate/internal/user/model"
+	"boilerplate/pkg/etag"
+	"boilerplate/pkg/events"
+	"boilerplate/pkg/logger"
+	"boilerplate/shared/constants"
+	errs "boilerplate/shared/errors"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/suite"
+)
+
+// failingExportService menulis sebagian file lalu gagal di tengah stream
+type failingExportService struct {
+	CategoryServiceInterface
+}
+
+func (failingExportService) Export(_ context.Context, _ string, w io.Writer) error {
+	if _, err := io.WriteString(w, "id,name\n1,Books\n"); err != nil {
+		return err
+	}
+	return errors.New("connection reset")
+}
+
+type CategoryHandlerTestSuite struct {
+	suite.Suite
+	e        *echo.Echo
+	service  *CategoryService
+	admin    *userModel.User
+	category *model.Category
+}
+
+func TestCategoryHandlerSuite(t *testing.T) {
+	suite.Run(t, new(CategoryHandlerTestSuite))
+}
+
+func (s *CategoryHandlerTestSuite) SetupTest() {
+	db := testutil.NewDB(s.T(), &model.Category{}, &model.Import{}, &events.OutboxMessage{})
+
+	s.e = echo.New()
+	s.e.Validator = testutil.NewValidator()
+	s.service = NewCategoryService(db, nil, nil, nil)
+	s.admin = &userModel.User{ID: 1, Role: constants.RoleAdmin}
+
+	var err error
+	s.category, err = s.service.Create(model.CreateCategoryInput{Name: "Books"}, s.admin)
+	s.Require().NoError(err)
+}
+
+func (s *CategoryHandlerTestSuite) context(method, body string, headers map[string]string) (echo.Context, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(method, "/", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	rec := httptest.NewRecorder()
+	c := s.e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(strconv.FormatUint(uint64(s.category.ID), 10))
+	c.Set("user", s.admin)
+	return c, rec
+}
+
+func (s *CategoryHandlerTestSuite) TestGetByIDNotModified() {
+	handler := NewCategoryHandler(s.service, false, logger.NewLogger())
+	current := etag.Generate(s.category.ID, s.category.Version)
+
+	c, rec := s.context(http.MethodGet, "", map[string]string{etag.HeaderIfNoneMatch: current})
+	s.Require().NoError(handler.GetByID(c))
+	s.Equal(http.StatusNotModified, rec.Code)
+	s.Equal(current, rec.Header().Get(etag.HeaderETag))
+	s.Empty(rec.Body.String())
+
+	c, rec = s.context(http.MethodGet, "", map[string]string{etag.HeaderIfNoneMatch: `"0-0"`})
+	s.Require().NoError(handler.GetByID(c))
+	s.Equal(http.StatusOK, rec.Code)
+}
+
+func (s *CategoryHandlerTestSuite) TestUpdatePreconditions() {
+	tests := []struct {
+		name           string
+		requireIfMatch bool
+		ifMatch        string
+		expectedErr    error
+	}{
+		{name: "stale if-match", ifMatch: `"1-0"`, expectedErr: errs.ErrPreconditionFailed},
+		{name: "missing if-match when required", requireIfMatch: true, expectedErr: errs.ErrPreconditionRequired},
+		{name: "missing if-match when optional"},
+		{name: "current if-match", requireIfMatch: true, ifMatch: `"1-1"`},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			s.SetupTest()
+			handler := NewCategoryHandler(s.service, tt.requireIfMatch, logger.NewLogger())
+			headers := map[string]string{}
+			if tt.ifMatch != "" {
+				headers[etag.HeaderIfMatch] = tt.ifMatch
+			}
+
+			c, rec := s.context(http.MethodPut, `{"name":"Novels"}`, headers)
+			err := handler.Update(c)
+			if tt.expectedErr != nil {
+				s.ErrorIs(err, tt.expectedErr)
+				stored, getErr := s.service.GetByID(s.category.ID)
+				s.Require().NoError(getErr)
+				s.Equal("Books", stored.Name)
+				return
+			}
+			s.Require().NoError(err)
+			s.Equal(http.StatusOK, rec.Code)
+			s.Equal(`"1-2"`, rec.Header().Get(etag.HeaderETag))
+		})
+	}
+}
+
+func (s *CategoryHandlerTestSuite) TestPatchStaleIfMatch() {
+	handler := NewCategoryHandler(s.service, false, logger.NewLogger())
+	c, _ := s.context(http.MethodPatch, `{"name":"Novels"}`, map[string]string{
+		echo.HeaderContentType: "application/merge-patch+json",
+		etag.HeaderIfMatch:     `"1-0"`,
+	})
+	s.ErrorIs(handler.Patch(c), errs.ErrPreconditionFailed)
+}
+
+func (s *CategoryHandlerTestSuite) TestImportCompletedReturnsOK() {
+	handler := NewCategoryHandler(s.service, false, logger.NewLogger())
+	c, rec := s.context(http.MethodPost, "name\nNovels\n", map[string]string{echo.HeaderContentType: "text/csv"})
+	s.Require().NoError(handler.Import(c))
+	s.Equal(http.StatusOK, rec.Code)
+	s.Contains(rec.Body.String(), `"status":"completed"`)
+}
+
+func (s *CategoryHandlerTestSuite) TestExportAbortsOnStreamError() {
+	handler := NewCategoryHandler(failingExportService{}, false, logger.NewLogger())
+	c, rec := s.context(http.MethodGet, "", nil)
+
+	// error setelah header terkirim tidak boleh dikembalikan sebagai response biasa
+	s.PanicsWithValue(http.ErrAbortHandler, func() { _ = handler.Export(c) })
+	s.Equal(http.StatusOK, rec.Code)
+	s.True(c.Response().Committed)
+}
@@ -0,0 +1,367 @@
+package category
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	categoryModel "boilerplate/internal/category/model"
+	userModel "boilerplate/internal/user/model"
+	"boilerplate/pkg/database"
+	"boilerplate/pkg/events"
+	"boilerplate/pkg/queue"
+	"boilerplate/pkg/search"
+	"boilerplate/pkg/tabular"
+	"boilerplate/shared/constants"
+	errs "boilerplate/shared/errors"
+
+	"gorm.io/gorm"
+)
+
+// ImportJob adalah tipe job import category yang terlalu besar untuk diproses di request
+const ImportJob = "category.import"
+
+// exportBatchSize adalah jumlah category yang dibaca per batch saat export
+const exportBatchSize = 500
+
+// importSavepoint adalah savepoint per baris untuk import best effort
+const importSavepoint = "category_import_row"
+
+// errImportAborted membatalkan transaksi import atomic; penyebabnya ada di report
+var errImportAborted = errors.New("category import aborted")
+
+// RegisterJobs mendaftarkan handler job import ke worker
+func (s *CategoryService) RegisterJobs(worker *queue.Worker) {
+	worker.Handle(ImportJob, s.importJob)
+}
+
+// Import membaca file lalu mengimport setiap barisnya. File sampai
+// CategoryImportSyncRows baris diproses langsung dan dikembalikan dengan status
+// completed; file yang lebih besar disimpan sebagai job pending untuk worker.
+func (s *CategoryService) Import(format string, file io.Reader, query categoryModel.ImportQuery, user *userModel.User) (*categoryModel.Import, error) {
+	if !user.IsAdmin() {
+		return nil, errs.ErrAdminRequired
+	}
+
+	records, err := tabular.Read(file, format, constants.CategoryImportMaxRows)
+	switch {
+	case errors.Is(err, tabular.ErrTooManyRows):
+		return nil, errs.ErrCategoryImportTooLarge.WithDetails(map[string]interface{}{"max_rows": constants.CategoryImportMaxRows})
+	case errors.Is(err, tabular.ErrUnsupportedFormat):
+		return nil, errs.ErrUnsupportedMediaType
+	case err != nil:
+		return nil, errs.ErrCategoryImportInvalidFile.Wrap(err)
+	case len(records) == 0:
+		return nil, errs.ErrCategoryImportEmpty
+	}
+
+	rows := make([]categoryModel.ImportRow, len(records))
+	for i, record := range records {
+		rows[i] = categoryModel.ImportRow{
+			Line:        record.Line,
+			Name:        record.Get("name"),
+			Slug:        record.Get("slug"),
+			Description: record.Get("description"),
+		}
+	}
+	imp := categoryModel.NewImport(format, query, rows, user.ID)
+
+	if s.queue != nil && len(rows) > constants.CategoryImportSyncRows {
+		return imp, s.enqueueImport(imp)
+	}
+
+	report, err := s.runImport(context.Background(), imp)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	imp.Status = categoryModel.ImportStatusCompleted
+	imp.Report = report
+	imp.Rows = nil
+	imp.CompletedAt = &now
+	if err := s.db.Create(imp).Error; err != nil {
+		return nil, err
+	}
+	return imp, nil
+}
+
+// enqueueImport menyimpan import pending lalu menjadwalkan job-nya. Import dihapus
+// lagi bila job gagal dijadwalkan agar tidak ada import yang menggantung.
+func (s *CategoryService) enqueueImport(imp *categoryModel.Import) error {
+	ctx := context.Background()
+	if err := s.db.Create(imp).Error; err != nil {
+		return err
+	}
+
+	payload := categoryModel.ImportJob{ImportID: imp.ID}
+	if _, err := s.queue.Enqueue(ctx, ImportJob, payload, queue.Unique(ImportJob+":"+strconv.FormatUint(uint64(imp.ID), 10))); err != nil {
+		return errors.Join(err, s.db.Delete(imp).Error)
+	}
+	return nil
+}
+
+func (s *CategoryService) GetImport(id uint) (*categoryModel.Import, error) {
+	var imp categoryModel.Import
+	if err := s.db.Omit("pending_rows").First(&imp, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrCategoryImportNotFound.Wrap(err)
+		}
+		return nil, err
+	}
+	return &imp, nil
+}
+
+// importJob menjalankan import dari worker. Import yang sudah selesai dilewati
+// sehingga job yang terkirim ulang tidak mengimport dua kali; transaksi yang gagal
+// tidak menyimpan apa pun sehingga aman dicoba ulang.
+func (s *CategoryService) importJob(ctx context.Context, job *queue.Job) error {
+	var payload categoryModel.ImportJob
+	if err := job.Decode(&payload); err != nil {
+		return queue.Permanent(fmt.Errorf("decode payload: %w", err))
+	}
+
+	var imp categoryModel.Import
+	if err := s.db.WithContext(ctx).Limit(1).Find(&imp, payload.ImportID).Error; err != nil {
+		return err
+	}
+	if imp.ID == 0 || imp.IsDone() {
+		return nil
+	}
+	if err := s.db.WithContext(ctx).Model(&imp).Update("status", categoryModel.ImportStatusRunning).Error; err != nil {
+		return err
+	}
+
+	report, err := s.runImport(ctx, &imp)
+	if err != nil {
+		if job.Attempts >= job.MaxAttempts {
+			return errors.Join(err, s.db.WithContext(ctx).Model(&imp).Updates(map[string]interface{}{
+				"status": categoryModel.ImportStatusFailed,
+				"error":  "import failed, please try again",
+			}).Error)
+		}
+		return err
+	}
+
+	now := time.Now()
+	imp.Status = categoryModel.ImportStatusCompleted
+	imp.Report = report
+	imp.Rows = nil
+	imp.CompletedAt = &now
+	return s.db.WithContext(ctx).Model(&imp).
+		Select("status", "report", "pending_rows", "completed_at").
+		Updates(&imp).Error
+}
+
+// importStep adalah perubahan yang direncanakan untuk satu baris valid
+type importStep struct {
+	row      int
+	category *categoryModel.Category
+	changes  map[string]interface{}
+}
+
+// runImport memvalidasi setiap baris dengan aturan NewCategory, mencocokkannya
+// dengan category yang ada lalu menyimpan perubahannya dalam satu transaksi.
+// Error per baris dicatat di report; error yang dikembalikan berarti tidak ada
+// perubahan yang tersimpan.
+func (s *CategoryService) runImport(ctx context.Context, imp *categoryModel.Import) (*categoryModel.ImportReport, error) {
+	var categories []categoryModel.Category
+	if err := s.db.WithContext(ctx).Order("id").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	existing := make(map[string][]*categoryModel.Category, len(categories))
+	for i := range categories {
+		key := importKey(imp.Match, &categories[i])
+		existing[key] = append(existing[key], &categories[i])
+	}
+
+	results := make([]categoryModel.ImportRowResult, len(imp.Rows))
+	steps := make([]importStep, 0, len(imp.Rows))
+	seen := make(map[string]bool, len(imp.Rows))
+	failed := false
+	for i, row := range imp.Rows {
+		results[i] = categoryModel.ImportRowResult{Line: row.Line, Name: row.Name, Slug: categoryModel.Slugify(row.Name)}
+		step, err := planImportRow(imp, row, existing, seen)
+		if err != nil {
+			setRowError(&results[i], err)
+			failed = true
+			continue
+		}
+		step.row = i
+		results[i].CategoryID = step.category.ID
+		switch {
+		case step.category.ID == 0:
+			results[i].Action = categoryModel.ImportActionCreate
+		case len(step.changes) == 0:
+			results[i].Action = categoryModel.ImportActionUnchanged
+			continue
+		default:
+			results[i].Action = categoryModel.ImportActionUpdate
+		}
+		steps = append(steps, step)
+	}
+
+	report := &categoryModel.ImportReport{Rows: make([]categoryModel.ImportRowResult, 0, len(results))}
+	if imp.DryRun || (failed && imp.Mode == categoryModel.ImportModeAtomic) {
+		for _, result := range results {
+			report.Add(result)
+		}
+		return report, nil
+	}
+
+	var saved []importStep
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, step := range steps {
+			if imp.Mode == categoryModel.ImportModeBestEffort {
+				if err := tx.SavePoint(importSavepoint).Error; err != nil {
+					return err
+				}
+			}
+
+			err := applyImportStep(tx, step, imp.CreatedBy)
+			if _, ok := errs.As(err); ok {
+				setRowError(&results[step.row], err)
+				if imp.Mode == categoryModel.ImportModeAtomic {
+					return errImportAborted
+				}
+				if err := tx.RollbackTo(importSavepoint).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			saved = append(saved, step)
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errImportAborted) {
+		return nil, err
+	}
+
+	report.Applied = err == nil
+	if report.Applied {
+		for _, step := range saved {
+			results[step.row].CategoryID = step.category.ID
+		}
+		s.afterImport(ctx, saved)
+	}
+	for _, result := range results {
+		report.Add(result)
+	}
+	return report, nil
+}
+
+// planImportRow memvalidasi satu baris dan menentukan category yang dibuat atau diubah
+func planImportRow(imp *categoryModel.Import, row categoryModel.ImportRow, existing map[string][]*categoryModel.Category, seen map[string]bool) (importStep, error) {
+	category, err := categoryModel.NewCategory(row.Input(), imp.CreatedBy)
+	if err != nil {
+		return importStep{}, err
+	}
+
+	key := strings.ToLower(category.Name)
+	if imp.Match == categoryModel.ImportMatchSlug {
+		key = category.Slug()
+		if row.Slug != "" {
+			key = categoryModel.Slugify(row.Slug)
+		}
+		if key == "" {
+			return importStep{}, errs.ErrCategorySlugInvalid
+		}
+	}
+	if seen[key] {
+		return importStep{}, errs.ErrCategoryImportDuplicate
+	}
+	seen[key] = true
+
+	switch matches := existing[key]; len(matches) {
+	case 0:
+		return importStep{category: category}, nil
+	case 1:
+		current := *matches[0]
+		changes, err := current.ApplyPatch(categoryModel.PatchCategoryInput{Name: category.Name, Description: category.Description})
+		if err != nil {
+			return importStep{}, err
+		}
+		return importStep{category: &current, changes: changes}, nil
+	default:
+		return importStep{}, errs.ErrCategoryImportAmbiguous
+	}
+}
+
+// applyImportStep menyimpan satu baris dan mencatat event-nya dalam transaksi import
+func applyImportStep(tx *gorm.DB, step importStep, actorID uint) error {
+	category := step.category
+	if category.ID == 0 {
+		if err := tx.Create(category).Error; err != nil {
+			return err
+		}
+		return events.Record(tx, categoryModel.CategoryCreated(category.Event(actorID)))
+	}
+
+	category.UpdatedAt = time.Now()
+	step.changes["updated_at"] = category.UpdatedAt
+	if err := database.UpdateWithVersion(tx, &categoryModel.Category{}, category.ID, category.Version, step.changes); err != nil {
+		return err
+	}
+	category.Version++
+	return events.Record(tx, categoryModel.CategoryUpdated(category.Event(actorID)))
+}
+
+// afterImport menginvalidasi cache dan memperbarui index pencarian setelah commit
+func (s *CategoryService) afterImport(ctx context.Context, saved []importStep) {
+	if len(saved) == 0 {
+		return
+	}
+	tags := make([]string, 0, len(saved)+1)
+	tags = append(tags, listTag)
+	docs := make([]search.Document, len(saved))
+	for i, step := range saved {
+		tags = append(tags, idTag(step.category.ID))
+		docs[i] = step.category.SearchDocument()
+	}
+	_ = s.cache.Invalidate(ctx, tags...)
+	_ = s.indexer.Index(ctx, docs...)
+}
+
+func setRowError(result *categoryModel.ImportRowResult, err error) {
+	result.Action = categoryModel.ImportActionError
+	result.Error = &categoryModel.ImportRowError{Code: errs.ErrInternal.Code, Message: errs.ErrInternal.Message}
+	if appErr, ok := errs.As(err); ok {
+		result.Error = &categoryModel.ImportRowError{Code: appErr.Code, Message: appErr.Message}
+	}
+}
+
+// importKey adalah kunci pencocokan category yang sudah ada
+func importKey(match string, category *categoryModel.Category) string {
+	if match == categoryModel.ImportMatchSlug {
+		return category.Slug()
+	}
+	return strings.ToLower(category.Name)
+}
+
+// Export menulis semua category ke w secara streaming, dibaca per batch
+func (s *CategoryService) Export(ctx context.Context, format string, w io.Writer) error {
+	writer, err := tabular.NewWriter(w, format, categoryModel.ExportColumns)
+	if err != nil {
+		return err
+	}
+
+	var batch []categoryModel.Category
+	err = s.db.WithContext(ctx).Order("id").FindInBatches(&batch, exportBatchSize, func(_ *gorm.DB, _ int) error {
+		for _, category := range batch {
+			if err := writer.Write(category.ID, category.Name, category.Slug(), category.Description, category.Version, category.CreatedAt, category.UpdatedAt); err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+	if err != nil {
+		return err
+	}
+	return writer.Close()
+}
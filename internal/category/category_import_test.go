@@ -0,0 +1,223 @@
+package category
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"boilerplate/internal/category/model"
+	"boilerplate/internal/testutil"
+	userModel "boilerplate/internal/user/model"
+	"boilerplate/pkg/events"
+	"boilerplate/pkg/logger"
+	"boilerplate/pkg/queue"
+	"boilerplate/pkg/tabular"
+	"boilerplate/shared/constants"
+	errs "boilerplate/shared/errors"
+
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type CategoryImportTestSuite struct {
+	suite.Suite
+	ctx     context.Context
+	db      *gorm.DB
+	worker  *queue.Worker
+	service *CategoryService
+	admin   *userModel.User
+}
+
+func TestCategoryImportSuite(t *testing.T) {
+	suite.Run(t, new(CategoryImportTestSuite))
+}
+
+func (s *CategoryImportTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	db := testutil.NewDB(s.T(), &model.Category{}, &model.Import{}, &events.OutboxMessage{})
+	s.db = db
+
+	redisClient := testutil.NewRedisClient(s.T())
+	q := queue.New(redisClient.Client(), queue.Config{})
+	s.worker = queue.NewWorker(q, logger.NewLogger())
+	s.service = NewCategoryService(db, nil, nil, q)
+	s.service.RegisterJobs(s.worker)
+
+	s.admin = &userModel.User{ID: 1, Role: constants.RoleAdmin}
+}
+
+func (s *CategoryImportTestSuite) create(name, description string) *model.Category {
+	category, err := s.service.Create(model.CreateCategoryInput{Name: name, Description: description}, s.admin)
+	s.Require().NoError(err)
+	return category
+}
+
+func (s *CategoryImportTestSuite) importCSV(csv string, query model.ImportQuery) *model.Import {
+	imp, err := s.service.Import(tabular.FormatCSV, strings.NewReader(csv), query, s.admin)
+	s.Require().NoError(err)
+	return imp
+}
+
+func (s *CategoryImportTestSuite) names() []string {
+	var names []string
+	s.Require().NoError(s.db.Model(&model.Category{}).Order("id").Pluck("name", &names).Error)
+	return names
+}
+
+func (s *CategoryImportTestSuite) TestUpsertsByName() {
+	books := s.create("Books", "Old description")
+	toys := s.create("Toys", "Games")
+
+	imp := s.importCSV("name,description\nbooks,Novels\nToys,Games\nGarden,Plants\n", model.ImportQuery{})
+	s.Equal(model.ImportStatusCompleted, imp.Status)
+	s.NotZero(imp.ID)
+	report := imp.Report
+	s.True(report.Applied)
+	s.Equal(3, report.Total)
+	s.Equal(1, report.Created)
+	s.Equal(1, report.Updated)
+	s.Equal(1, report.Unchanged)
+
+	s.Equal(model.ImportRowResult{Line: 2, Name: "books", Slug: "books", Action: model.ImportActionUpdate, CategoryID: books.ID}, report.Rows[0])
+	s.Equal(toys.ID, report.Rows[1].CategoryID)
+	s.NotZero(report.Rows[2].CategoryID)
+	s.Equal([]string{"books", "Toys", "Garden"}, s.names())
+
+	updated, err := s.service.GetByID(books.ID)
+	s.Require().NoError(err)
+	s.Equal("Novels", updated.Description)
+	s.Equal(books.Version+1, updated.Version)
+
+	// setiap perubahan mencatat event seperti Create dan Update
+	var recorded int64
+	s.Require().NoError(s.db.Model(&events.OutboxMessage{}).Count(&recorded).Error)
+	s.Equal(int64(4), recorded)
+
+	stored, err := s.service.GetImport(imp.ID)
+	s.Require().NoError(err)
+	s.Equal(3, stored.Report.Total)
+	s.Empty(stored.Rows)
+}
+
+func (s *CategoryImportTestSuite) TestAtomicImportSavesNothingWhenARowFails() {
+	imp := s.importCSV("name,description\nBooks,Novels\n,Missing name\nbooks,Duplicate\n", model.ImportQuery{})
+	report := imp.Report
+	s.False(report.Applied)
+	s.Equal(1, report.Created)
+	s.Equal(2, report.Failed)
+	s.Equal(errs.ErrCategoryNameRequired.Code, report.Rows[1].Error.Code)
+	s.Equal(3, report.Rows[1].Line)
+	s.Equal(errs.ErrCategoryImportDuplicate.Code, report.Rows[2].Error.Code)
+	s.Zero(report.Rows[0].CategoryID)
+	s.Empty(s.names())
+}
+
+func (s *CategoryImportTestSuite) TestBestEffortImportSavesValidRows() {
+	s.create("Books", "")
+	s.create("Books", "")
+
+	imp := s.importCSV("name\nBooks\n\"\"\nToys\n", model.ImportQuery{Mode: model.ImportModeBestEffort})
+	report := imp.Report
+	s.True(report.Applied)
+	s.Equal(1, report.Created)
+	s.Equal(1, report.Failed)
+	s.Equal(errs.ErrCategoryImportAmbiguous.Code, report.Rows[0].Error.Code)
+	s.Equal([]string{"Books", "Books", "Toys"}, s.names())
+}
+
+func (s *CategoryImportTestSuite) TestBestEffortImportRollsBackFailedWrites() {
+	books := s.create("Books", "")
+	// versi berubah setelah import membaca category sehingga update bentrok
+	s.Require().NoError(s.db.Callback().Update().Before("gorm:update").Register("test:bump_version", func(tx *gorm.DB) {
+		tx.Exec("UPDATE categories SET version = version + 1 WHERE id = ?", books.ID)
+	}))
+	s.T().Cleanup(func() { _ = s.db.Callback().Update().Remove("test:bump_version") })
+
+	imp := s.importCSV("name,description\nBooks,Novels\nToys,\n", model.ImportQuery{Mode: model.ImportModeBestEffort})
+	report := imp.Report
+	s.True(report.Applied)
+	s.Equal(errs.ErrVersionConflict.Code, report.Rows[0].Error.Code)
+	s.Equal(model.ImportActionCreate, report.Rows[1].Action)
+	s.Equal([]string{"Books", "Toys"}, s.names())
+}
+
+func (s *CategoryImportTestSuite) TestDryRunReportsWithoutSaving() {
+	s.create("Books", "")
+
+	imp := s.importCSV("name,description\nBooks,Novels\nToys,\n", model.ImportQuery{DryRun: true})
+	s.True(imp.DryRun)
+	s.False(imp.Report.Applied)
+	s.Equal(1, imp.Report.Updated)
+	s.Equal(1, imp.Report.Created)
+	s.Equal([]string{"Books"}, s.names())
+}
+
+func (s *CategoryImportTestSuite) TestMatchBySlugRenames() {
+	home := s.create("Home & Garden", "")
+
+	imp := s.importCSV("name,slug\nHome and Garden,home-garden\n!!,\n", model.ImportQuery{Match: model.ImportMatchSlug, Mode: model.ImportModeBestEffort})
+	report := imp.Report
+	s.Equal(model.ImportActionUpdate, report.Rows[0].Action)
+	s.Equal(home.ID, report.Rows[0].CategoryID)
+	s.Equal("home-and-garden", report.Rows[0].Slug)
+	s.Equal(errs.ErrCategorySlugInvalid.Code, report.Rows[1].Error.Code)
+	s.Equal([]string{"Home and Garden"}, s.names())
+}
+
+func (s *CategoryImportTestSuite) TestLargeImportRunsAsJob() {
+	var csv strings.Builder
+	csv.WriteString("name\n")
+	for i := 0; i <= constants.CategoryImportSyncRows; i++ {
+		fmt.Fprintf(&csv, "Category %d\n", i)
+	}
+
+	imp := s.importCSV(csv.String(), model.ImportQuery{})
+	s.Equal(model.ImportStatusPending, imp.Status)
+	s.Nil(imp.Report)
+	s.Empty(s.names())
+
+	processed, err := s.worker.ProcessNext(s.ctx)
+	s.Require().NoError(err)
+	s.True(processed)
+
+	done, err := s.service.GetImport(imp.ID)
+	s.Require().NoError(err)
+	s.Equal(model.ImportStatusCompleted, done.Status)
+	s.NotNil(done.CompletedAt)
+	s.Equal(constants.CategoryImportSyncRows+1, done.Report.Created)
+	s.Len(s.names(), constants.CategoryImportSyncRows+1)
+
+	// baris file tidak disimpan lagi setelah import selesai
+	var pending int64
+	s.Require().NoError(s.db.Model(&model.Import{}).Where("id = ? AND pending_rows IS NULL", imp.ID).Count(&pending).Error)
+	s.Equal(int64(1), pending)
+}
+
+func (s *CategoryImportTestSuite) TestRejectsInvalidFiles() {
+	_, err := s.service.Import(tabular.FormatCSV, strings.NewReader("name\n"), model.ImportQuery{}, s.admin)
+	s.ErrorIs(err, errs.ErrCategoryImportEmpty)
+	_, err = s.service.Import(tabular.FormatJSON, strings.NewReader("{"), model.ImportQuery{}, s.admin)
+	s.ErrorIs(err, errs.ErrCategoryImportInvalidFile)
+	_, err = s.service.Import(tabular.FormatCSV, strings.NewReader("name\nBooks\n"), model.ImportQuery{}, &userModel.User{ID: 2, Role: constants.RoleUser})
+	s.ErrorIs(err, errs.ErrAdminRequired)
+	_, err = s.service.GetImport(99)
+	s.ErrorIs(err, errs.ErrCategoryImportNotFound)
+}
+
+func (s *CategoryImportTestSuite) TestExportCanBeImportedAgain() {
+	s.create("Books", "Novels, poems")
+	s.create("=cmd", "")
+
+	for _, format := range tabular.Formats {
+		s.Run(format, func() {
+			var buf bytes.Buffer
+			s.Require().NoError(s.service.Export(s.ctx, format, &buf))
+
+			imp, err := s.service.Import(format, &buf, model.ImportQuery{Match: model.ImportMatchSlug}, s.admin)
+			s.Require().NoError(err)
+			s.Equal(2, imp.Report.Unchanged)
+		})
+	}
+}
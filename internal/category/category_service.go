@@ -1,10 +1,20 @@
 package category
 
 import (
+	"context"
 	"errors"
+	"io"
+	"strconv"
+	"time"
 
 	categoryModel "boilerplate/internal/category/model"
 	userModel "boilerplate/internal/user/model"
+	"boilerplate/pkg/cache"
+	"boilerplate/pkg/database"
+	"boilerplate/pkg/events"
+	"boilerplate/pkg/queue"
+	"boilerplate/pkg/search"
+	errs "boilerplate/shared/errors"
 
 	"gorm.io/gorm"
 )
@@ -14,23 +24,55 @@ type CategoryServiceInterface interface {
 	Create(input categoryModel.CreateCategoryInput, user *userModel.User) (*categoryModel.Category, error)
 	GetAll() ([]categoryModel.Category, error)
 	GetByID(id uint) (*categoryModel.Category, error)
-	Update(id uint, input categoryModel.CreateCategoryInput, user *userModel.User) (*categoryModel.Category, error)
+	GetForUpdate(id uint) (*categoryModel.Category, error)
+	Update(category *categoryModel.Category, input categoryModel.CreateCategoryInput, user *userModel.User) (*categoryModel.Category, error)
+	Patch(category *categoryModel.Category, input categoryModel.PatchCategoryInput, user *userModel.User) (*categoryModel.Category, error)
 	Delete(id uint, user *userModel.User) error
+	Import(format string, file io.Reader, query categoryModel.ImportQuery, user *userModel.User) (*categoryModel.Import, error)
+	GetImport(id uint) (*categoryModel.Import, error)
+	Export(ctx context.Context, format string, w io.Writer) error
 }
 
+// listTag menandai cache daftar category; cache satu category ditandai ID-nya
+const listTag = "list"
+
 type CategoryService struct {
-	db *gorm.DB
+	db      *gorm.DB
+	cache   *cache.Loader
+	indexer *search.Indexer
+	queue   *queue.Queue
 }
 
-func NewCategoryService(db *gorm.DB) *CategoryService {
+// NewCategoryService membuat service category. cache nil menonaktifkan read-through
+// cache untuk GetAll dan GetByID; indexer nil menonaktifkan sinkronisasi index pencarian;
+// queue nil membuat semua import diproses langsung di request.
+func NewCategoryService(db *gorm.DB, cache *cache.Loader, indexer *search.Indexer, queue *queue.Queue) *CategoryService {
 	return &CategoryService{
-		db: db,
+		db:      db,
+		cache:   cache,
+		indexer: indexer,
+		queue:   queue,
 	}
 }
 
+// Subscribe menginvalidasi cache dari event outbox di proses worker. Service sudah
+// menginvalidasi setelah commit; event menjadi jaring pengaman jika invalidasi itu
+// gagal atau proses mati sebelum sempat menjalankannya.
+func (s *CategoryService) Subscribe(bus *events.Bus) {
+	events.Subscribe(bus, func(ctx context.Context, _ events.Envelope, _ categoryModel.CategoryCreated) error {
+		return s.cache.Invalidate(ctx, listTag)
+	})
+	events.Subscribe(bus, func(ctx context.Context, _ events.Envelope, event categoryModel.CategoryUpdated) error {
+		return s.cache.Invalidate(ctx, listTag, idTag(event.CategoryID))
+	})
+	events.Subscribe(bus, func(ctx context.Context, _ events.Envelope, event categoryModel.CategoryDeleted) error {
+		return s.cache.Invalidate(ctx, listTag, idTag(event.CategoryID))
+	})
+}
+
 func (s *CategoryService) Create(input categoryModel.CreateCategoryInput, user *userModel.User) (*categoryModel.Category, error) {
 	if !user.IsAdmin() {
-		return nil, errors.New("only admin can create category")
+		return nil, errs.ErrAdminRequired
 	}
 
 	category, err := categoryModel.NewCategory(input, user.ID)
@@ -38,44 +80,96 @@ func (s *CategoryService) Create(input categoryModel.CreateCategoryInput, user *
 		return nil, err
 	}
 
-	if err := s.db.Create(category).Error; err != nil {
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(category).Error; err != nil {
+			return err
+		}
+		return events.Record(tx, categoryModel.CategoryCreated(category.Event(user.ID)))
+	})
+	if err != nil {
 		return nil, err
 	}
+	s.invalidate(listTag)
+	s.index(category)
 
 	return category, nil
 }
 
 func (s *CategoryService) GetAll() ([]categoryModel.Category, error) {
-	var categories []categoryModel.Category
-	if err := s.db.Find(&categories).Error; err != nil {
-		return nil, err
-	}
-	return categories, nil
+	return cache.GetOrLoad(context.Background(), s.cache, listTag, func(ctx context.Context) ([]categoryModel.Category, error) {
+		var categories []categoryModel.Category
+		if err := s.db.WithContext(ctx).Find(&categories).Error; err != nil {
+			return nil, err
+		}
+		return categories, nil
+	}, listTag)
 }
 
 func (s *CategoryService) GetByID(id uint) (*categoryModel.Category, error) {
+	tag := idTag(id)
+	return cache.GetOrLoad(context.Background(), s.cache, tag, func(ctx context.Context) (*categoryModel.Category, error) {
+		return s.find(s.db.WithContext(ctx), id)
+	}, tag)
+}
+
+// GetForUpdate membaca category langsung dari database, tanpa cache, sebagai dasar
+// Update dan Patch
+func (s *CategoryService) GetForUpdate(id uint) (*categoryModel.Category, error) {
+	return s.find(s.db, id)
+}
+
+// find membaca category langsung dari database, tanpa cache
+func (s *CategoryService) find(db *gorm.DB, id uint) (*categoryModel.Category, error) {
 	var category categoryModel.Category
-	if err := s.db.First(&category, id).Error; err != nil {
+	if err := db.First(&category, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrCategoryNotFound.Wrap(err)
+		}
 		return nil, err
 	}
 	return &category, nil
 }
 
-func (s *CategoryService) Update(id uint, input categoryModel.CreateCategoryInput, user *userModel.User) (*categoryModel.Category, error) {
+// Update memperbarui category hasil GetForUpdate dengan optimistic locking;
+// ErrVersionConflict dikembalikan jika category berubah sejak dibaca.
+func (s *CategoryService) Update(category *categoryModel.Category, input categoryModel.CreateCategoryInput, user *userModel.User) (*categoryModel.Category, error) {
 	if !user.IsAdmin() {
-		return nil, errors.New("only admin can update category")
+		return nil, errs.ErrAdminRequired
 	}
 
-	category, err := s.GetByID(id)
-	if err != nil {
+	if err := category.Update(input); err != nil {
 		return nil, err
 	}
 
-	if err := category.Update(input); err != nil {
+	category.UpdatedAt = time.Now()
+	if err := s.saveChanges(category, user, map[string]interface{}{
+		"name":        category.Name,
+		"description": category.Description,
+		"updated_at":  category.UpdatedAt,
+	}); err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+// Patch hanya menyimpan kolom yang berubah dari hasil merge patch
+func (s *CategoryService) Patch(category *categoryModel.Category, input categoryModel.PatchCategoryInput, user *userModel.User) (*categoryModel.Category, error) {
+	if !user.IsAdmin() {
+		return nil, errs.ErrAdminRequired
+	}
+
+	changes, err := category.ApplyPatch(input)
+	if err != nil {
 		return nil, err
 	}
+	if len(changes) == 0 {
+		return category, nil
+	}
 
-	if err := s.db.Save(category).Error; err != nil {
+	category.UpdatedAt = time.Now()
+	changes["updated_at"] = category.UpdatedAt
+	if err := s.saveChanges(category, user, changes); err != nil {
 		return nil, err
 	}
 
@@ -84,8 +178,61 @@ func (s *CategoryService) Update(id uint, input categoryModel.CreateCategoryInpu
 
 func (s *CategoryService) Delete(id uint, user *userModel.User) error {
 	if !user.IsAdmin() {
-		return errors.New("only admin can delete category")
+		return errs.ErrAdminRequired
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var category categoryModel.Category
+		if err := tx.First(&category, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+		if err := tx.Delete(&category).Error; err != nil {
+			return err
+		}
+		return events.Record(tx, categoryModel.CategoryDeleted(category.Event(user.ID)))
+	})
+	if err != nil {
+		return err
 	}
+	s.invalidate(listTag, idTag(id))
+	_ = s.indexer.Delete(context.Background(), categoryModel.SearchType, id)
+	return nil
+}
+
+// saveChanges menyimpan perubahan dengan optimistic locking dan mencatat event
+// CategoryUpdated dalam transaksi yang sama
+func (s *CategoryService) saveChanges(category *categoryModel.Category, user *userModel.User, changes map[string]interface{}) error {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := database.UpdateWithVersion(tx, &categoryModel.Category{}, category.ID, category.Version, changes); err != nil {
+			return err
+		}
+		category.Version++
+		return events.Record(tx, categoryModel.CategoryUpdated(category.Event(user.ID)))
+	})
+	if err != nil {
+		return err
+	}
+	s.invalidate(listTag, idTag(category.ID))
+	s.index(category)
+	return nil
+}
+
+// invalidate menghapus cache setelah transaksi commit. Kegagalan sudah dicatat
+// loader dan tidak menggagalkan request karena perubahan sudah tersimpan; event di
+// outbox menginvalidasi ulang dari worker.
+func (s *CategoryService) invalidate(tags ...string) {
+	_ = s.cache.Invalidate(context.Background(), tags...)
+}
+
+// index memperbarui dokumen pencarian category setelah transaksi commit. Kegagalan
+// sudah dicatat indexer; event di outbox atau reindex memperbaikinya.
+func (s *CategoryService) index(category *categoryModel.Category) {
+	_ = s.indexer.Index(context.Background(), category.SearchDocument())
+}
 
-	return s.db.Delete(&categoryModel.Category{}, id).Error
+func idTag(id uint) string {
+	return strconv.FormatUint(uint64(id), 10)
 }
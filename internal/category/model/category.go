@@ -1,8 +1,9 @@
 package model
 
 import (
-	"errors"
 	"time"
+
+	errs "boilerplate/shared/errors"
 )
 
 type Category struct {
@@ -10,33 +11,67 @@ type Category struct {
 	Name        string    `json:"name"`
 	Description string    `json:"description"`
 	CreatedBy   uint      `json:"created_by"`
+	Version     uint      `json:"version" gorm:"not null;default:1"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
 
 type CreateCategoryInput struct {
-	Name        string `json:"name" binding:"required"`
+	Name        string `json:"name" validate:"required"`
+	Description string `json:"description"`
+}
+
+// PatchCategoryInput adalah hasil penerapan merge patch ke PatchDocument
+type PatchCategoryInput struct {
+	Name        string `json:"name" validate:"required"`
 	Description string `json:"description"`
 }
 
 func NewCategory(input CreateCategoryInput, userID uint) (*Category, error) {
 	if input.Name == "" {
-		return nil, errors.New("category name is required")
+		return nil, errs.ErrCategoryNameRequired
 	}
 
 	return &Category{
 		Name:        input.Name,
 		Description: input.Description,
 		CreatedBy:   userID,
+		Version:     1,
 	}, nil
 }
 
 func (c *Category) Update(input CreateCategoryInput) error {
 	if input.Name == "" {
-		return errors.New("category name is required")
+		return errs.ErrCategoryNameRequired
 	}
 
 	c.Name = input.Name
 	c.Description = input.Description
 	return nil
 }
+
+// PatchDocument mengembalikan representasi category yang dapat di-patch
+func (c *Category) PatchDocument() PatchCategoryInput {
+	return PatchCategoryInput{
+		Name:        c.Name,
+		Description: c.Description,
+	}
+}
+
+// ApplyPatch menerapkan patch yang sudah divalidasi dan mengembalikan kolom yang berubah saja
+func (c *Category) ApplyPatch(input PatchCategoryInput) (map[string]interface{}, error) {
+	if input.Name == "" {
+		return nil, errs.ErrCategoryNameRequired
+	}
+
+	changes := make(map[string]interface{})
+	if input.Name != c.Name {
+		c.Name = input.Name
+		changes["name"] = input.Name
+	}
+	if input.Description != c.Description {
+		c.Description = input.Description
+		changes["description"] = input.Description
+	}
+	return changes, nil
+}
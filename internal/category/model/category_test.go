@@ -0,0 +1,51 @@
+package model
+
+import (
+	"testing"
+
+	"boilerplate/pkg/patch"
+	errs "boilerplate/shared/errors"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type CategoryTestSuite struct {
+	suite.Suite
+}
+
+func TestCategorySuite(t *testing.T) {
+	suite.Run(t, new(CategoryTestSuite))
+}
+
+func (s *CategoryTestSuite) TestApplyPatch() {
+	tests := []struct {
+		name        string
+		patch       string
+		expected    map[string]interface{}
+		expectedErr error
+	}{
+		{name: "absent fields are unchanged", patch: `{}`, expected: map[string]interface{}{}},
+		{name: "same value is not a change", patch: `{"name":"Books"}`, expected: map[string]interface{}{}},
+		{name: "only changed column", patch: `{"description":"Novels"}`, expected: map[string]interface{}{"description": "Novels"}},
+		{name: "null clears the field", patch: `{"description":null}`, expected: map[string]interface{}{"description": ""}},
+		{name: "both columns", patch: `{"name":"Poems","description":""}`, expected: map[string]interface{}{"name": "Poems", "description": ""}},
+		{name: "null name is rejected", patch: `{"name":null}`, expectedErr: errs.ErrCategoryNameRequired},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			category := &Category{Name: "Books", Description: "Old"}
+			var input PatchCategoryInput
+			s.Require().NoError(patch.ApplyTo(patch.MergePatchContentType, category.PatchDocument(), []byte(tt.patch), &input))
+
+			changes, err := category.ApplyPatch(input)
+			if tt.expectedErr != nil {
+				s.Equal(tt.expectedErr, err)
+				s.Equal("Books", category.Name)
+				return
+			}
+			s.Require().NoError(err)
+			s.Equal(tt.expected, changes)
+		})
+	}
+}
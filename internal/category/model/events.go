@@ -0,0 +1,35 @@
+package model
+
+// CategoryEvent adalah payload bersama event category. ActorID adalah user yang
+// melakukan perubahan.
+type CategoryEvent struct {
+	CategoryID uint   `json:"category_id"`
+	Name       string `json:"name"`
+	Version    uint   `json:"version"`
+	ActorID    uint   `json:"actor_id"`
+}
+
+// CategoryCreated dicatat saat category dibuat
+type CategoryCreated CategoryEvent
+
+func (CategoryCreated) EventName() string { return "category.created" }
+
+// CategoryUpdated dicatat saat category diubah lewat PUT atau PATCH
+type CategoryUpdated CategoryEvent
+
+func (CategoryUpdated) EventName() string { return "category.updated" }
+
+// CategoryDeleted dicatat saat category dihapus
+type CategoryDeleted CategoryEvent
+
+func (CategoryDeleted) EventName() string { return "category.deleted" }
+
+// Event mengembalikan payload event untuk perubahan category oleh actorID
+func (c *Category) Event(actorID uint) CategoryEvent {
+	return CategoryEvent{
+		CategoryID: c.ID,
+		Name:       c.Name,
+		Version:    c.Version,
+		ActorID:    actorID,
+	}
+}
@@ -0,0 +1,194 @@
+package model
+
+import (
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Kolom yang dicocokkan untuk upsert saat import
+const (
+	ImportMatchName = "name"
+	ImportMatchSlug = "slug"
+)
+
+// Mode transaksi import
+const (
+	// ImportModeAtomic menyimpan semua baris atau tidak sama sekali bila ada baris yang gagal
+	ImportModeAtomic = "atomic"
+	// ImportModeBestEffort menyimpan baris yang valid dan melaporkan baris yang gagal
+	ImportModeBestEffort = "best_effort"
+)
+
+// Status job import
+const (
+	ImportStatusPending   = "pending"
+	ImportStatusRunning   = "running"
+	ImportStatusCompleted = "completed"
+	ImportStatusFailed    = "failed"
+)
+
+// Hasil per baris import
+const (
+	ImportActionCreate    = "create"
+	ImportActionUpdate    = "update"
+	ImportActionUnchanged = "unchanged"
+	ImportActionError     = "error"
+)
+
+// ExportColumns adalah kolom file export. File export dapat diimport ulang: name,
+// slug dan description dibaca, kolom lain diabaikan.
+var ExportColumns = []string{"id", "name", "slug", "description", "version", "created_at", "updated_at"}
+
+// DTO: Import query
+type ImportQuery struct {
+	Format string `query:"format" validate:"omitempty,oneof=csv json xlsx"`
+	Match  string `query:"match" validate:"omitempty,oneof=name slug"`
+	Mode   string `query:"mode" validate:"omitempty,oneof=atomic best_effort"`
+	DryRun bool   `query:"dry_run"`
+}
+
+// DTO: Export query
+type ExportQuery struct {
+	Format string `query:"format" validate:"omitempty,oneof=csv json xlsx"`
+}
+
+// ImportRow adalah satu baris file import. Slug hanya dipakai untuk mencocokkan
+// category dengan match=slug; slug category selalu diturunkan dari nama.
+type ImportRow struct {
+	Line        int    `json:"line"`
+	Name        string `json:"name"`
+	Slug        string `json:"slug,omitempty"`
+	Description string `json:"description"`
+}
+
+// Input mengubah baris menjadi input category agar divalidasi aturan yang sama
+// dengan Create dan Update
+func (r ImportRow) Input() CreateCategoryInput {
+	return CreateCategoryInput{Name: r.Name, Description: r.Description}
+}
+
+// ImportRowError adalah error satu baris dengan kode error aplikasi
+type ImportRowError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ImportRowResult adalah hasil satu baris. CategoryID kosong untuk baris baru yang
+// belum disimpan (dry run atau import yang dibatalkan).
+type ImportRowResult struct {
+	Line       int             `json:"line"`
+	Name       string          `json:"name"`
+	Slug       string          `json:"slug"`
+	Action     string          `json:"action"`
+	CategoryID uint            `json:"category_id,omitempty"`
+	Error      *ImportRowError `json:"error,omitempty"`
+}
+
+// ImportReport merangkum hasil import. Applied bernilai false untuk dry run dan
+// import atomic yang dibatalkan karena ada baris yang gagal; jumlah per aksi tetap
+// menunjukkan apa yang akan terjadi.
+type ImportReport struct {
+	Total     int               `json:"total"`
+	Created   int               `json:"created"`
+	Updated   int               `json:"updated"`
+	Unchanged int               `json:"unchanged"`
+	Failed    int               `json:"failed"`
+	Applied   bool              `json:"applied"`
+	Rows      []ImportRowResult `json:"rows"`
+}
+
+// Add mencatat hasil satu baris
+func (r *ImportReport) Add(result ImportRowResult) {
+	r.Total++
+	switch result.Action {
+	case ImportActionCreate:
+		r.Created++
+	case ImportActionUpdate:
+		r.Updated++
+	case ImportActionUnchanged:
+		r.Unchanged++
+	case ImportActionError:
+		r.Failed++
+	}
+	r.Rows = append(r.Rows, result)
+}
+
+// Import adalah job import category. Rows disimpan sampai job selesai agar file
+// besar dapat diproses worker; Report berisi hasil per baris.
+type Import struct {
+	ID          uint          `json:"id" gorm:"primaryKey"`
+	Format      string        `json:"format" gorm:"size:10"`
+	Match       string        `json:"match" gorm:"size:10"`
+	Mode        string        `json:"mode" gorm:"size:20"`
+	DryRun      bool          `json:"dry_run"`
+	Status      string        `json:"status" gorm:"size:20;index"`
+	RowCount    int           `json:"row_count"`
+	Rows        []ImportRow   `json:"-" gorm:"column:pending_rows;serializer:json;type:longtext"`
+	Report      *ImportReport `json:"report,omitempty" gorm:"serializer:json;type:longtext"`
+	Error       string        `json:"error,omitempty" gorm:"size:255"`
+	CreatedBy   uint          `json:"created_by" gorm:"index"`
+	CompletedAt *time.Time    `json:"completed_at"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+}
+
+func (Import) TableName() string {
+	return "category_imports"
+}
+
+// Factory: Create new pending import
+func NewImport(format string, query ImportQuery, rows []ImportRow, userID uint) *Import {
+	match := query.Match
+	if match == "" {
+		match = ImportMatchName
+	}
+	mode := query.Mode
+	if mode == "" {
+		mode = ImportModeAtomic
+	}
+	return &Import{
+		Format:    format,
+		Match:     match,
+		Mode:      mode,
+		DryRun:    query.DryRun,
+		Status:    ImportStatusPending,
+		RowCount:  len(rows),
+		Rows:      rows,
+		CreatedBy: userID,
+	}
+}
+
+// ImportJob adalah payload job import category yang diproses worker
+type ImportJob struct {
+	ImportID uint `json:"import_id"`
+}
+
+// IsDone menandakan import telah selesai, berhasil maupun gagal
+func (i *Import) IsDone() bool {
+	return i.Status == ImportStatusCompleted || i.Status == ImportStatusFailed
+}
+
+// Slug mengembalikan slug category yang diturunkan dari nama
+func (c *Category) Slug() string {
+	return Slugify(c.Name)
+}
+
+// Slugify membuat slug: huruf kecil, huruf dan angka dipertahankan, karakter lain
+// digabung menjadi satu tanda hubung, mis. "Home & Garden" menjadi "home-garden"
+func Slugify(value string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(value) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+	return b.String()
+}
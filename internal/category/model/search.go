@@ -0,0 +1,18 @@
+package model
+
+import "boilerplate/pkg/search"
+
+// SearchType adalah jenis dokumen category di index pencarian
+const SearchType = "category"
+
+// SearchDocument mengembalikan kolom category yang dapat dicari
+func (c *Category) SearchDocument() search.Document {
+	return search.Document{
+		Type: SearchType,
+		ID:   c.ID,
+		Fields: map[string]string{
+			"name":        c.Name,
+			"description": c.Description,
+		},
+	}
+}
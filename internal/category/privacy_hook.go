@@ -0,0 +1,34 @@
+package category
+
+import (
+	"context"
+
+	"boilerplate/internal/category/model"
+
+	"gorm.io/gorm"
+)
+
+// PrivacyHook mengekspor kategori yang dibuat user. Kategori tidak memuat data
+// pribadi sehingga tetap disimpan saat erasure; created_by tetap menunjuk ke baris
+// user yang sudah dianonimkan.
+type PrivacyHook struct{}
+
+func NewPrivacyHook() *PrivacyHook {
+	return &PrivacyHook{}
+}
+
+func (h *PrivacyHook) Name() string {
+	return "categories"
+}
+
+func (h *PrivacyHook) Export(ctx context.Context, db *gorm.DB, userID uint) (interface{}, error) {
+	var categories []model.Category
+	if err := db.WithContext(ctx).Where("created_by = ?", userID).Order("id").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+func (h *PrivacyHook) Erase(ctx context.Context, tx *gorm.DB, userID uint) error {
+	return nil
+}
@@ -0,0 +1,53 @@
+package identity
+
+import (
+	"net/http"
+
+	userModel "boilerplate/internal/user/model"
+	"boilerplate/pkg/response"
+	errs "boilerplate/shared/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+type IdentityHandler struct {
+	identityService IdentityServiceInterface
+}
+
+func NewIdentityHandler(identityService IdentityServiceInterface) *IdentityHandler {
+	return &IdentityHandler{
+		identityService: identityService,
+	}
+}
+
+// Login mengarahkan user ke halaman login provider OIDC
+func (h *IdentityHandler) Login(c echo.Context) error {
+	url, err := h.identityService.AuthCodeURL(c.Request().Context(), c.Param("provider"))
+	if err != nil {
+		return err
+	}
+
+	return c.Redirect(http.StatusFound, url)
+}
+
+// Callback menyelesaikan login OIDC dan mengembalikan token seperti /login
+func (h *IdentityHandler) Callback(c echo.Context) error {
+	if providerErr := c.QueryParam("error"); providerErr != "" {
+		return errs.ErrOIDCDenied.WithDetails(map[string]interface{}{
+			"error":             providerErr,
+			"error_description": c.QueryParam("error_description"),
+		})
+	}
+
+	token, err := h.identityService.Callback(c.Request().Context(), c.Param("provider"), c.QueryParam("state"), c.QueryParam("code"), userModel.ClientInfo{
+		IP:        c.RealIP(),
+		UserAgent: c.Request().UserAgent(),
+	})
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, http.StatusOK, "Login successful", map[string]string{
+		"token": token,
+	})
+}
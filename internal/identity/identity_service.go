@@ -0,0 +1,276 @@
+package identity
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"boilerplate/config"
+	"boilerplate/internal/identity/model"
+	"boilerplate/internal/user"
+	userModel "boilerplate/internal/user/model"
+	"boilerplate/pkg/events"
+	"boilerplate/pkg/logger"
+	"boilerplate/pkg/redis"
+	"boilerplate/pkg/search"
+	errs "boilerplate/shared/errors"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
+)
+
+// stateTTL adalah batas waktu antara redirect ke provider dan callback
+const stateTTL = 10 * time.Minute
+
+var defaultScopes = []string{oidc.ScopeOpenID, "email", "profile"}
+
+// IdentityServiceInterface mendefinisikan kontrak untuk IdentityService
+type IdentityServiceInterface interface {
+	AuthCodeURL(ctx context.Context, provider string) (string, error)
+	Callback(ctx context.Context, provider, state, code string, clientInfo userModel.ClientInfo) (string, error)
+}
+
+type IdentityService struct {
+	db          *gorm.DB
+	logger      logger.Logger
+	redisClient *redis.RedisClient
+	userService user.UserServiceInterface
+	indexer     *search.Indexer
+	providers   map[string]config.OIDCProvider
+	// registrationDisabled mencegah pembuatan user baru dari identitas yang belum dikenal
+	registrationDisabled bool
+
+	mu      sync.Mutex
+	clients map[string]*providerClient
+}
+
+// providerClient adalah provider yang sudah melalui discovery
+type providerClient struct {
+	oauth2   oauth2.Config
+	verifier *oidc.IDTokenVerifier
+}
+
+// loginState disimpan di Redis dengan key state sampai callback
+type loginState struct {
+	Provider string `json:"provider"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+}
+
+// NewIdentityService membuat service login OIDC. indexer (boleh nil) menambahkan
+// user yang dibuat dari identitas baru ke index pencarian.
+func NewIdentityService(db *gorm.DB, logger logger.Logger, redisClient *redis.RedisClient, userService user.UserServiceInterface, indexer *search.Indexer, providers []config.OIDCProvider, registrationDisabled bool) *IdentityService {
+	configured := make(map[string]config.OIDCProvider, len(providers))
+	for _, provider := range providers {
+		configured[provider.Name] = provider
+	}
+
+	return &IdentityService{
+		db:                   db,
+		logger:               logger,
+		redisClient:          redisClient,
+		userService:          userService,
+		indexer:              indexer,
+		providers:            configured,
+		registrationDisabled: registrationDisabled,
+		clients:              make(map[string]*providerClient),
+	}
+}
+
+// AuthCodeURL membuat state, nonce dan PKCE verifier lalu mengembalikan URL login provider
+func (s *IdentityService) AuthCodeURL(ctx context.Context, provider string) (string, error) {
+	client, err := s.client(ctx, provider)
+	if err != nil {
+		return "", err
+	}
+
+	state, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+	nonce, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+	verifier := oauth2.GenerateVerifier()
+
+	payload, err := json.Marshal(loginState{Provider: provider, Nonce: nonce, Verifier: verifier})
+	if err != nil {
+		return "", err
+	}
+	if err := s.redisClient.SetOIDCState(ctx, state, string(payload), stateTTL); err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"provider": provider,
+			"error":    err.Error(),
+		}).Error("Gagal menyimpan state OIDC di Redis")
+		return "", err
+	}
+
+	return client.oauth2.AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier)), nil
+}
+
+// Callback menukar authorization code, memverifikasi ID token, menautkan user
+// dan menerbitkan token aplikasi lewat jalur yang sama dengan login password,
+// termasuk riwayat login dan notifikasi perangkat baru
+func (s *IdentityService) Callback(ctx context.Context, provider, state, code string, clientInfo userModel.ClientInfo) (string, error) {
+	if state == "" || code == "" {
+		return "", errs.ErrOIDCInvalidState
+	}
+
+	payload, err := s.redisClient.TakeOIDCState(ctx, state)
+	if err != nil {
+		return "", errs.ErrOIDCInvalidState.Wrap(err)
+	}
+	var saved loginState
+	if err := json.Unmarshal([]byte(payload), &saved); err != nil || saved.Provider != provider {
+		return "", errs.ErrOIDCInvalidState
+	}
+
+	client, err := s.client(ctx, provider)
+	if err != nil {
+		return "", err
+	}
+
+	token, err := client.oauth2.Exchange(ctx, code, oauth2.VerifierOption(saved.Verifier))
+	if err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"provider": provider,
+			"error":    err.Error(),
+		}).Error("Gagal menukar authorization code OIDC")
+		return "", errs.ErrOIDCExchangeFailed.Wrap(err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return "", errs.ErrOIDCInvalidIDToken
+	}
+	idToken, err := client.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return "", errs.ErrOIDCInvalidIDToken.Wrap(err)
+	}
+	if idToken.Nonce != saved.Nonce {
+		return "", errs.ErrOIDCInvalidIDToken
+	}
+
+	var claims model.Claims
+	if err := idToken.Claims(&claims); err != nil {
+		return "", errs.ErrOIDCInvalidIDToken.Wrap(err)
+	}
+	if claims.Email == "" || !claims.EmailVerified {
+		return "", errs.ErrOIDCEmailNotVerified
+	}
+
+	linked, err := s.linkUser(provider, claims)
+	if err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"provider": provider,
+			"email":    claims.Email,
+			"error":    err.Error(),
+		}).Error("Gagal menautkan identitas OIDC ke user")
+		return "", err
+	}
+
+	return s.userService.IssueToken(linked, clientInfo)
+}
+
+// linkUser mencari user lewat identitas yang sudah tertaut, lalu lewat email
+// terverifikasi, dan membuat user baru jika keduanya tidak ditemukan dan
+// registrasi terbuka tidak dinonaktifkan
+func (s *IdentityService) linkUser(provider string, claims model.Claims) (*userModel.User, error) {
+	var linked userModel.User
+	created := false
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var identity model.UserIdentity
+		err := tx.Where("provider = ? AND subject = ?", provider, claims.Subject).First(&identity).Error
+		if err == nil {
+			return tx.First(&linked, identity.UserID).Error
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		email := strings.ToLower(strings.TrimSpace(claims.Email))
+		err = tx.Scopes(userModel.ByEmail(email)).First(&linked).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			if s.registrationDisabled {
+				return errs.ErrRegistrationDisabled
+			}
+			linked = *userModel.NewExternalUser(claims.DisplayName(), email)
+			if err = tx.Create(&linked).Error; err == nil {
+				err = events.Record(tx, userModel.NewUserRegistered(&linked, userModel.RegistrationSourceOIDC))
+			}
+			created = err == nil
+		}
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(model.NewUserIdentity(linked.ID, provider, claims)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	if created {
+		_ = s.indexer.Index(context.Background(), linked.SearchDocument())
+	}
+	return &linked, nil
+}
+
+// client menjalankan discovery provider sekali lalu menyimpannya, sehingga
+// aplikasi tetap bisa start walaupun provider sedang tidak tersedia
+func (s *IdentityService) client(ctx context.Context, name string) (*providerClient, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if client, ok := s.clients[name]; ok {
+		return client, nil
+	}
+
+	cfg, ok := s.providers[name]
+	if !ok {
+		return nil, errs.ErrOIDCProviderNotFound
+	}
+
+	provider, err := oidc.NewProvider(ctx, cfg.Issuer)
+	if err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"provider": name,
+			"issuer":   cfg.Issuer,
+			"error":    err.Error(),
+		}).Error("Gagal discovery provider OIDC")
+		return nil, errs.ErrOIDCExchangeFailed.Wrap(err)
+	}
+
+	scopes := cfg.Scopes
+	if len(scopes) == 0 {
+		scopes = defaultScopes
+	}
+
+	client := &providerClient{
+		oauth2: oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			RedirectURL:  cfg.RedirectURL,
+			Endpoint:     provider.Endpoint(),
+			Scopes:       scopes,
+		},
+		verifier: provider.Verifier(&oidc.Config{ClientID: cfg.ClientID}),
+	}
+	s.clients[name] = client
+	return client, nil
+}
+
+func randomToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
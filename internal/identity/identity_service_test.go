@@ -0,0 +1,415 @@
+package identity
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"boilerplate/config"
+	"boilerplate/internal/identity/model"
+	"boilerplate/internal/testutil"
+	"boilerplate/internal/user"
+	userModel "boilerplate/internal/user/model"
+	"boilerplate/pkg/events"
+	"boilerplate/pkg/logger"
+	"boilerplate/pkg/mailer"
+	"boilerplate/pkg/password"
+	"boilerplate/pkg/redis"
+	errs "boilerplate/shared/errors"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+const (
+	stubClientID     = "boilerplate"
+	stubClientSecret = "stub-secret"
+	stubKeyID        = "stub-key"
+)
+
+var (
+	browser = userModel.ClientInfo{
+		IP:        "203.0.113.10",
+		UserAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36",
+	}
+	phone = userModel.ClientInfo{
+		IP:        "198.51.100.7",
+		UserAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_5 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.5 Mobile/15E148 Safari/604.1",
+	}
+)
+
+// recordingMailer menangkap email yang dikirim service
+type recordingMailer struct {
+	sent []mailer.Message
+}
+
+func (m *recordingMailer) Send(_ context.Context, msg mailer.Message) error {
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+// stubProvider adalah server OIDC lokal sehingga test tidak membutuhkan jaringan
+type stubProvider struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu    sync.Mutex
+	codes map[string]stubAuthorization
+}
+
+// stubAuthorization adalah hasil login user di provider untuk satu authorization code
+type stubAuthorization struct {
+	challenge string
+	nonce     string
+	claims    map[string]interface{}
+}
+
+func newStubProvider(t *testing.T) *stubProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stub := &stubProvider{key: key, codes: make(map[string]stubAuthorization)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", stub.discovery)
+	mux.HandleFunc("/keys", stub.keys)
+	mux.HandleFunc("/token", stub.token)
+	stub.server = httptest.NewServer(mux)
+	t.Cleanup(stub.server.Close)
+	return stub
+}
+
+func (p *stubProvider) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                p.server.URL,
+		"authorization_endpoint":                p.server.URL + "/authorize",
+		"token_endpoint":                        p.server.URL + "/token",
+		"jwks_uri":                              p.server.URL + "/keys",
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+	})
+}
+
+func (p *stubProvider) keys(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"alg": "RS256",
+			"use": "sig",
+			"kid": stubKeyID,
+			"n":   base64.RawURLEncoding.EncodeToString(p.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(p.key.E)).Bytes()),
+		}},
+	})
+}
+
+// authorize mensimulasikan user yang login di provider lalu diarahkan kembali dengan code
+func (p *stubProvider) authorize(authURL string, claims map[string]interface{}) (state, code string) {
+	parsed, _ := url.Parse(authURL)
+	query := parsed.Query()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	code = "code-" + query.Get("state")
+	p.codes[code] = stubAuthorization{
+		challenge: query.Get("code_challenge"),
+		nonce:     query.Get("nonce"),
+		claims:    claims,
+	}
+	return query.Get("state"), code
+}
+
+func (p *stubProvider) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if clientID != stubClientID || clientSecret != stubClientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	p.mu.Lock()
+	authorization, ok := p.codes[r.PostForm.Get("code")]
+	delete(p.codes, r.PostForm.Get("code"))
+	p.mu.Unlock()
+	if !ok {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(sum[:]) != authorization.challenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	claims := jwt.MapClaims{
+		"iss":   p.server.URL,
+		"aud":   stubClientID,
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"iat":   time.Now().Unix(),
+		"nonce": authorization.nonce,
+	}
+	for name, value := range authorization.claims {
+		claims[name] = value
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = stubKeyID
+	idToken, err := token.SignedString(p.key)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": "stub-access-token",
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     idToken,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+type IdentityServiceTestSuite struct {
+	suite.Suite
+	ctx         context.Context
+	stub        *stubProvider
+	db          *gorm.DB
+	redisClient *redis.RedisClient
+	mailer      *recordingMailer
+	service     *IdentityService
+}
+
+func TestIdentityServiceSuite(t *testing.T) {
+	suite.Run(t, new(IdentityServiceTestSuite))
+}
+
+func (s *IdentityServiceTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.stub = newStubProvider(s.T())
+
+	db := testutil.NewDB(s.T(), &userModel.User{}, &userModel.LoginEvent{}, &model.UserIdentity{}, &events.OutboxMessage{})
+	s.db = db
+
+	s.redisClient = testutil.NewRedisClient(s.T())
+
+	log := logger.NewLogger()
+	s.mailer = &recordingMailer{}
+	userService := user.NewUserService(db, "test-secret", log, s.redisClient, password.DefaultPolicy(), s.mailer, nil, nil)
+	s.service = NewIdentityService(db, log, s.redisClient, userService, nil, []config.OIDCProvider{{
+		Name:         "stub",
+		Issuer:       s.stub.server.URL,
+		ClientID:     stubClientID,
+		ClientSecret: stubClientSecret,
+		RedirectURL:  "http://localhost/auth/stub/callback",
+	}}, false)
+}
+
+func (s *IdentityServiceTestSuite) login(claims map[string]interface{}) (string, error) {
+	return s.loginFrom(browser, claims)
+}
+
+func (s *IdentityServiceTestSuite) loginFrom(client userModel.ClientInfo, claims map[string]interface{}) (string, error) {
+	authURL, err := s.service.AuthCodeURL(s.ctx, "stub")
+	s.Require().NoError(err)
+
+	state, code := s.stub.authorize(authURL, claims)
+	return s.service.Callback(s.ctx, "stub", state, code, client)
+}
+
+func verifiedClaims(subject, email string) map[string]interface{} {
+	return map[string]interface{}{
+		"sub":            subject,
+		"email":          email,
+		"email_verified": true,
+		"name":           "Stub User",
+	}
+}
+
+func (s *IdentityServiceTestSuite) TestAuthCodeURLUsesPKCEAndNonce() {
+	authURL, err := s.service.AuthCodeURL(s.ctx, "stub")
+	s.Require().NoError(err)
+
+	parsed, err := url.Parse(authURL)
+	s.Require().NoError(err)
+	query := parsed.Query()
+	s.Equal(s.stub.server.URL+"/authorize", parsed.Scheme+"://"+parsed.Host+parsed.Path)
+	s.Equal("S256", query.Get("code_challenge_method"))
+	s.NotEmpty(query.Get("code_challenge"))
+	s.NotEmpty(query.Get("nonce"))
+	s.NotEmpty(query.Get("state"))
+	s.Equal(stubClientID, query.Get("client_id"))
+}
+
+func (s *IdentityServiceTestSuite) TestCallbackCreatesAndLinksUser() {
+	token, err := s.login(verifiedClaims("sub-1", "new@example.com"))
+	s.Require().NoError(err)
+
+	var created userModel.User
+	s.Require().NoError(s.db.Where("email = ?", "new@example.com").First(&created).Error)
+	s.Equal("Stub User", created.Name)
+	s.Empty(created.Password)
+
+	var identity model.UserIdentity
+	s.Require().NoError(s.db.Where("provider = ? AND subject = ?", "stub", "sub-1").First(&identity).Error)
+	s.Equal(created.ID, identity.UserID)
+
+	// Token diterbitkan lewat jalur yang sama dengan login password
+	stored, err := s.redisClient.GetToken(s.ctx, created.ID)
+	s.Require().NoError(err)
+	s.Equal(stored, token)
+}
+
+func (s *IdentityServiceTestSuite) TestCallbackLinksExistingUserByEmail() {
+	existing, err := userModel.NewUser(userModel.RegisterInput{Name: "Existing", Email: "existing@example.com", Password: "password123"})
+	s.Require().NoError(err)
+	s.Require().NoError(s.db.Create(existing).Error)
+
+	_, err = s.login(verifiedClaims("sub-2", "existing@example.com"))
+	s.Require().NoError(err)
+
+	var count int64
+	s.db.Model(&userModel.User{}).Count(&count)
+	s.EqualValues(1, count)
+
+	var identity model.UserIdentity
+	s.Require().NoError(s.db.Where("subject = ?", "sub-2").First(&identity).Error)
+	s.Equal(existing.ID, identity.UserID)
+}
+
+func (s *IdentityServiceTestSuite) TestCallbackReusesLinkedIdentity() {
+	_, err := s.login(verifiedClaims("sub-3", "first@example.com"))
+	s.Require().NoError(err)
+
+	// Email di provider berubah, user tetap dikenali lewat subject
+	_, err = s.login(verifiedClaims("sub-3", "changed@example.com"))
+	s.Require().NoError(err)
+
+	var count int64
+	s.db.Model(&userModel.User{}).Count(&count)
+	s.EqualValues(1, count)
+}
+
+func (s *IdentityServiceTestSuite) TestCallbackRespectsDisabledRegistration() {
+	s.service.registrationDisabled = true
+
+	_, err := s.login(verifiedClaims("sub-5", "stranger@example.com"))
+	s.ErrorIs(err, errs.ErrRegistrationDisabled)
+
+	existing, err := userModel.NewUser(userModel.RegisterInput{Name: "Existing", Email: "existing@example.com", Password: "password123"})
+	s.Require().NoError(err)
+	s.Require().NoError(s.db.Create(existing).Error)
+
+	_, err = s.login(verifiedClaims("sub-6", "existing@example.com"))
+	s.NoError(err)
+}
+
+func (s *IdentityServiceTestSuite) TestCallbackRecordsLoginHistory() {
+	_, err := s.login(verifiedClaims("sub-8", "device@example.com"))
+	s.Require().NoError(err)
+	_, err = s.login(verifiedClaims("sub-8", "device@example.com"))
+	s.Require().NoError(err)
+	s.Empty(s.mailer.sent)
+
+	// login OIDC dari perangkat baru mengirim notifikasi seperti login password
+	_, err = s.loginFrom(phone, verifiedClaims("sub-8", "device@example.com"))
+	s.Require().NoError(err)
+	s.Require().Len(s.mailer.sent, 1)
+	s.Equal("device@example.com", s.mailer.sent[0].To)
+	s.Contains(s.mailer.sent[0].Body, phone.IP)
+
+	var history []userModel.LoginEvent
+	s.Require().NoError(s.db.Order("id").Find(&history).Error)
+	s.Require().Len(history, 3)
+	for _, event := range history {
+		s.True(event.Success)
+		s.Equal("device@example.com", event.Email)
+		s.NotNil(event.UserID)
+	}
+	s.Equal(phone.IP, history[2].IP)
+}
+
+func (s *IdentityServiceTestSuite) TestCallbackRecordsDisabledAccount() {
+	_, err := s.login(verifiedClaims("sub-9", "disabled@example.com"))
+	s.Require().NoError(err)
+	s.Require().NoError(s.db.Model(&userModel.User{}).Where("1 = 1").Update("disabled_at", time.Now()).Error)
+
+	_, err = s.login(verifiedClaims("sub-9", "disabled@example.com"))
+	s.ErrorIs(err, errs.ErrAccountDisabled)
+
+	var failed userModel.LoginEvent
+	s.Require().NoError(s.db.Where("success = ?", false).First(&failed).Error)
+	s.Equal(userModel.LoginReasonAccountDisabled, failed.Reason)
+}
+
+func (s *IdentityServiceTestSuite) TestCallbackRejectsUnverifiedEmail() {
+	claims := verifiedClaims("sub-4", "unverified@example.com")
+	claims["email_verified"] = false
+
+	_, err := s.login(claims)
+	s.ErrorIs(err, errs.ErrOIDCEmailNotVerified)
+}
+
+func (s *IdentityServiceTestSuite) TestCallbackRejectsNonceMismatch() {
+	claims := verifiedClaims("sub-5", "nonce@example.com")
+	claims["nonce"] = "forged"
+
+	_, err := s.login(claims)
+	s.ErrorIs(err, errs.ErrOIDCInvalidIDToken)
+}
+
+func (s *IdentityServiceTestSuite) TestCallbackRejectsWrongPKCEVerifier() {
+	authURL, err := s.service.AuthCodeURL(s.ctx, "stub")
+	s.Require().NoError(err)
+	state, code := s.stub.authorize(authURL, verifiedClaims("sub-6", "pkce@example.com"))
+
+	// Challenge yang tercatat di provider tidak cocok dengan verifier milik state
+	s.stub.mu.Lock()
+	authorization := s.stub.codes[code]
+	authorization.challenge = "tampered"
+	s.stub.codes[code] = authorization
+	s.stub.mu.Unlock()
+
+	_, err = s.service.Callback(s.ctx, "stub", state, code, browser)
+	s.ErrorIs(err, errs.ErrOIDCExchangeFailed)
+}
+
+func (s *IdentityServiceTestSuite) TestCallbackRejectsReusedState() {
+	authURL, err := s.service.AuthCodeURL(s.ctx, "stub")
+	s.Require().NoError(err)
+	state, code := s.stub.authorize(authURL, verifiedClaims("sub-7", "state@example.com"))
+
+	_, err = s.service.Callback(s.ctx, "stub", state, code, browser)
+	s.Require().NoError(err)
+
+	_, err = s.service.Callback(s.ctx, "stub", state, code, browser)
+	s.ErrorIs(err, errs.ErrOIDCInvalidState)
+}
+
+func (s *IdentityServiceTestSuite) TestUnknownProvider() {
+	_, err := s.service.AuthCodeURL(s.ctx, "unknown")
+	s.ErrorIs(err, errs.ErrOIDCProviderNotFound)
+}
@@ -0,0 +1,59 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	// mendaftarkan serializer "encrypted" untuk kolom email
+	_ "boilerplate/pkg/fieldcrypt"
+)
+
+const (
+	nameMinLength = 2
+	nameMaxLength = 50
+)
+
+// UserIdentity menghubungkan user dengan akun di provider OIDC eksternal
+type UserIdentity struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"index"`
+	Provider  string    `json:"provider" gorm:"size:50;uniqueIndex:idx_identity_provider_subject"`
+	Subject   string    `json:"subject" gorm:"size:255;uniqueIndex:idx_identity_provider_subject"`
+	Email     string    `json:"email" gorm:"size:512;serializer:encrypted"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Claims adalah claim ID token yang dipakai untuk menautkan atau membuat user
+type Claims struct {
+	Subject       string `json:"sub"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Name          string `json:"name"`
+}
+
+// Factory: Create new identity link for a user
+func NewUserIdentity(userID uint, provider string, claims Claims) *UserIdentity {
+	return &UserIdentity{
+		UserID:   userID,
+		Provider: provider,
+		Subject:  claims.Subject,
+		Email:    strings.ToLower(strings.TrimSpace(claims.Email)),
+	}
+}
+
+// DisplayName mengembalikan nama yang memenuhi aturan User.Name; jika provider
+// tidak mengirim nama, bagian lokal email dipakai sebagai gantinya
+func (c Claims) DisplayName() string {
+	name := strings.TrimSpace(c.Name)
+	if len([]rune(name)) < nameMinLength {
+		name, _, _ = strings.Cut(strings.TrimSpace(c.Email), "@")
+	}
+	if runes := []rune(name); len(runes) > nameMaxLength {
+		name = string(runes[:nameMaxLength])
+	}
+	for len([]rune(name)) < nameMinLength {
+		name += "_"
+	}
+	return name
+}
@@ -0,0 +1,32 @@
+package identity
+
+import (
+	"context"
+
+	"boilerplate/internal/identity/model"
+
+	"gorm.io/gorm"
+)
+
+// PrivacyHook mengekspor identitas OIDC yang ditautkan user dan menghapusnya saat erasure
+type PrivacyHook struct{}
+
+func NewPrivacyHook() *PrivacyHook {
+	return &PrivacyHook{}
+}
+
+func (h *PrivacyHook) Name() string {
+	return "identities"
+}
+
+func (h *PrivacyHook) Export(ctx context.Context, db *gorm.DB, userID uint) (interface{}, error) {
+	var identities []model.UserIdentity
+	if err := db.WithContext(ctx).Where("user_id = ?", userID).Order("id").Find(&identities).Error; err != nil {
+		return nil, err
+	}
+	return identities, nil
+}
+
+func (h *PrivacyHook) Erase(ctx context.Context, tx *gorm.DB, userID uint) error {
+	return tx.WithContext(ctx).Where("user_id = ?", userID).Delete(&model.UserIdentity{}).Error
+}
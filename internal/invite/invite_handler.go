@@ -0,0 +1,82 @@
+package invite
+
+import (
+	"net/http"
+	"strconv"
+
+	"boilerplate/internal/invite/model"
+	"boilerplate/pkg/response"
+	errs "boilerplate/shared/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+type InviteHandler struct {
+	inviteService InviteServiceInterface
+}
+
+func NewInviteHandler(inviteService InviteServiceInterface) *InviteHandler {
+	return &InviteHandler{
+		inviteService: inviteService,
+	}
+}
+
+func (h *InviteHandler) Create(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+
+	var input model.CreateInviteInput
+	if err := c.Bind(&input); err != nil {
+		return errs.ErrInvalidPayload.Wrap(err)
+	}
+
+	if err := c.Validate(&input); err != nil {
+		return err
+	}
+
+	invite, err := h.inviteService.Create(input, userID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, http.StatusCreated, "Invite created successfully, send the token to the invitee: it will not be shown again", invite)
+}
+
+func (h *InviteHandler) GetAll(c echo.Context) error {
+	invites, err := h.inviteService.List()
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, http.StatusOK, "Invites retrieved successfully", invites)
+}
+
+func (h *InviteHandler) Revoke(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return errs.ErrInvalidID.Wrap(err)
+	}
+
+	if err := h.inviteService.Revoke(uint(id)); err != nil {
+		return err
+	}
+
+	return response.Success(c, http.StatusOK, "Invite revoked successfully", nil)
+}
+
+func (h *InviteHandler) Accept(c echo.Context) error {
+	var input model.AcceptInviteInput
+	if err := c.Bind(&input); err != nil {
+		return errs.ErrInvalidPayload.Wrap(err)
+	}
+
+	if err := c.Validate(&input); err != nil {
+		return err
+	}
+
+	user, err := h.inviteService.Accept(input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, http.StatusCreated, "Invite accepted, you can now log in", user)
+}
@@ -0,0 +1,173 @@
+package invite
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"boilerplate/internal/invite/model"
+	userModel "boilerplate/internal/user/model"
+	"boilerplate/pkg/events"
+	"boilerplate/pkg/logger"
+	"boilerplate/pkg/password"
+	"boilerplate/pkg/search"
+	errs "boilerplate/shared/errors"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// InviteServiceInterface mendefinisikan kontrak untuk InviteService
+type InviteServiceInterface interface {
+	Create(input model.CreateInviteInput, invitedBy uint) (*model.CreatedInvite, error)
+	List() ([]model.Invite, error)
+	Revoke(id uint) error
+	Accept(input model.AcceptInviteInput) (*userModel.User, error)
+}
+
+type InviteService struct {
+	db      *gorm.DB
+	logger  logger.Logger
+	secret  string
+	policy  password.Policy
+	indexer *search.Indexer
+}
+
+// NewInviteService membuat service invite; secret dipakai untuk menandatangani token
+// undangan dan indexer (boleh nil) menambahkan user baru ke index pencarian
+func NewInviteService(db *gorm.DB, logger logger.Logger, secret string, policy password.Policy, indexer *search.Indexer) *InviteService {
+	if secret == "" {
+		panic("invite secret is required")
+	}
+
+	return &InviteService{
+		db:      db,
+		logger:  logger,
+		secret:  secret,
+		policy:  policy,
+		indexer: indexer,
+	}
+}
+
+func (s *InviteService) Create(input model.CreateInviteInput, invitedBy uint) (*model.CreatedInvite, error) {
+	invite, err := model.NewInvite(input, invitedBy, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	var count int64
+	if err := s.db.Model(&userModel.User{}).Scopes(userModel.ByEmail(invite.Email)).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, errs.ErrEmailAlreadyRegistered
+	}
+
+	if err := s.db.Create(invite).Error; err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"email": invite.Email,
+			"error": err.Error(),
+		}).Error("Gagal menyimpan invite")
+		return nil, err
+	}
+
+	return &model.CreatedInvite{Invite: *invite, Token: invite.Token(s.secret)}, nil
+}
+
+func (s *InviteService) List() ([]model.Invite, error) {
+	var invites []model.Invite
+	if err := s.db.Order("id DESC").Find(&invites).Error; err != nil {
+		return nil, err
+	}
+	return invites, nil
+}
+
+func (s *InviteService) Revoke(id uint) error {
+	var invite model.Invite
+	if err := s.db.First(&invite, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errs.ErrInviteNotFound.Wrap(err)
+		}
+		return err
+	}
+	if invite.AcceptedAt != nil {
+		return errs.ErrInviteAlreadyAccepted
+	}
+	if invite.RevokedAt != nil {
+		return nil
+	}
+
+	return s.db.Model(&invite).Update("revoked_at", time.Now()).Error
+}
+
+// Accept memverifikasi token undangan lalu membuat user dengan role dari invite.
+// Invite ditandai terpakai dengan update bersyarat sehingga token hanya bisa dipakai sekali.
+func (s *InviteService) Accept(input model.AcceptInviteInput) (*userModel.User, error) {
+	code, signature, err := model.ParseToken(input.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	var user *userModel.User
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var invite model.Invite
+		if err := tx.Where("code = ?", code).First(&invite).Error; err != nil {
+			return errs.ErrInvalidInvite.Wrap(err)
+		}
+
+		now := time.Now()
+		if !invite.VerifySignature(signature, s.secret) || !invite.IsPending(now) {
+			return errs.ErrInvalidInvite
+		}
+
+		var count int64
+		if err := tx.Model(&userModel.User{}).Scopes(userModel.ByEmail(invite.Email)).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return errs.ErrEmailAlreadyRegistered
+		}
+
+		if err := s.policy.Validate(input.Password, invite.Email, input.Name); err != nil {
+			return err
+		}
+
+		user, err = userModel.NewUserWithRole(userModel.RegisterInput{
+			Name:     input.Name,
+			Email:    invite.Email,
+			Password: input.Password,
+		}, invite.Role)
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		if err := events.Record(tx, userModel.NewUserRegistered(user, userModel.RegistrationSourceInvite)); err != nil {
+			return err
+		}
+
+		result := tx.Model(&model.Invite{}).
+			Where("id = ? AND accepted_at IS NULL AND revoked_at IS NULL", invite.ID).
+			Updates(map[string]interface{}{
+				"accepted_at":      now,
+				"accepted_user_id": user.ID,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errs.ErrInvalidInvite
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"error": err.Error(),
+		}).Warn("Gagal menerima invite")
+		return nil, err
+	}
+	_ = s.indexer.Index(context.Background(), user.SearchDocument())
+
+	return user, nil
+}
@@ -0,0 +1,155 @@
+package invite
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"boilerplate/internal/invite/model"
+	"boilerplate/internal/testutil"
+	userModel "boilerplate/internal/user/model"
+	"boilerplate/pkg/events"
+	"boilerplate/pkg/fieldcrypt"
+	"boilerplate/pkg/logger"
+	"boilerplate/pkg/password"
+	"boilerplate/shared/constants"
+	errs "boilerplate/shared/errors"
+
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type InviteServiceTestSuite struct {
+	suite.Suite
+	db      *gorm.DB
+	service *InviteService
+}
+
+func TestInviteServiceSuite(t *testing.T) {
+	suite.Run(t, new(InviteServiceTestSuite))
+}
+
+func (s *InviteServiceTestSuite) SetupTest() {
+	db := testutil.NewDB(s.T(), &userModel.User{}, &model.Invite{}, &events.OutboxMessage{})
+	s.db = db
+
+	s.service = NewInviteService(db, logger.NewLogger(), "test-secret", password.DefaultPolicy(), nil)
+}
+
+func (s *InviteServiceTestSuite) accept(token string) (*userModel.User, error) {
+	return s.service.Accept(model.AcceptInviteInput{Token: token, Name: "Invited", Password: "password123"})
+}
+
+func (s *InviteServiceTestSuite) TestAcceptCreatesUserWithInvitedRole() {
+	created, err := s.service.Create(model.CreateInviteInput{Email: "ops@example.com", Role: constants.RoleAdmin}, 1)
+	s.Require().NoError(err)
+
+	user, err := s.accept(created.Token)
+	s.Require().NoError(err)
+	s.Equal("ops@example.com", user.Email)
+	s.Equal(constants.RoleAdmin, user.Role)
+	s.NoError(user.CheckPassword("password123"))
+
+	var invite model.Invite
+	s.Require().NoError(s.db.First(&invite, created.ID).Error)
+	s.NotNil(invite.AcceptedAt)
+	s.Equal(user.ID, *invite.AcceptedUserID)
+
+	// Token hanya bisa dipakai sekali
+	_, err = s.accept(created.Token)
+	s.ErrorIs(err, errs.ErrInvalidInvite)
+
+	s.ErrorIs(s.service.Revoke(created.ID), errs.ErrInviteAlreadyAccepted)
+}
+
+func (s *InviteServiceTestSuite) TestAcceptRejectsTamperedToken() {
+	created, err := s.service.Create(model.CreateInviteInput{Email: "ops@example.com", Role: constants.RoleUser}, 1)
+	s.Require().NoError(err)
+
+	_, err = s.accept(created.Invite.Code + ".forged")
+	s.ErrorIs(err, errs.ErrInvalidInvite)
+
+	_, err = s.accept("unknown." + created.Token[len(created.Invite.Code)+1:])
+	s.ErrorIs(err, errs.ErrInvalidInvite)
+}
+
+func (s *InviteServiceTestSuite) TestAcceptRejectsRevokedAndExpired() {
+	revoked, err := s.service.Create(model.CreateInviteInput{Email: "revoked@example.com", Role: constants.RoleUser}, 1)
+	s.Require().NoError(err)
+	s.Require().NoError(s.service.Revoke(revoked.ID))
+	_, err = s.accept(revoked.Token)
+	s.ErrorIs(err, errs.ErrInvalidInvite)
+
+	expiresAt := time.Now().Add(time.Second)
+	expired, err := s.service.Create(model.CreateInviteInput{Email: "expired@example.com", Role: constants.RoleUser, ExpiresAt: &expiresAt}, 1)
+	s.Require().NoError(err)
+	s.Require().NoError(s.db.Model(&model.Invite{}).Where("id = ?", expired.ID).Update("expires_at", time.Now().Add(-time.Minute)).Error)
+	var stored model.Invite
+	s.Require().NoError(s.db.First(&stored, expired.ID).Error)
+	_, err = s.accept(stored.Token("test-secret"))
+	s.ErrorIs(err, errs.ErrInvalidInvite)
+
+	invites, err := s.service.List()
+	s.Require().NoError(err)
+	s.Len(invites, 2)
+}
+
+func (s *InviteServiceTestSuite) TestCreateRejectsRegisteredEmail() {
+	user, err := userModel.NewUser(userModel.RegisterInput{Name: "Existing", Email: "existing@example.com", Password: "password123"})
+	s.Require().NoError(err)
+	s.Require().NoError(s.db.Create(user).Error)
+
+	_, err = s.service.Create(model.CreateInviteInput{Email: "Existing@example.com", Role: constants.RoleUser}, 1)
+	s.ErrorIs(err, errs.ErrEmailAlreadyRegistered)
+}
+
+func (s *InviteServiceTestSuite) TestRevokeUnknownInvite() {
+	s.ErrorIs(s.service.Revoke(42), errs.ErrInviteNotFound)
+}
+
+func (s *InviteServiceTestSuite) TestPrivacyHookWithEncryptedEmail() {
+	keyring, err := fieldcrypt.NewKeyring(1, map[uint32][]byte{1: bytes.Repeat([]byte{1}, fieldcrypt.KeySize)}, []byte("blind"))
+	s.Require().NoError(err)
+	previous := fieldcrypt.DefaultKeyring()
+	fieldcrypt.SetDefaultKeyring(keyring)
+	s.T().Cleanup(func() { fieldcrypt.SetDefaultKeyring(previous) })
+
+	pending, err := s.service.Create(model.CreateInviteInput{Email: "JANE@example.com", Role: constants.RoleUser}, 1)
+	s.Require().NoError(err)
+	_, err = s.service.Create(model.CreateInviteInput{Email: "other@example.com", Role: constants.RoleUser}, 1)
+	s.Require().NoError(err)
+	// user mendaftar sendiri tanpa memakai undangan
+	user, err := userModel.NewUser(userModel.RegisterInput{Name: "Jane", Email: "jane@example.com", Password: "password123"})
+	s.Require().NoError(err)
+	s.Require().NoError(s.db.Create(user).Error)
+
+	var email, index string
+	row := s.db.Raw("SELECT email, email_index FROM invites WHERE id = ?", pending.ID).Row()
+	s.Require().NoError(row.Scan(&email, &index))
+	s.True(strings.HasPrefix(email, "enc:v1:1:"))
+	s.Equal(keyring.BlindIndex("jane@example.com"), index)
+
+	hook := NewPrivacyHook()
+	exported, err := hook.Export(context.Background(), s.db, user.ID)
+	s.Require().NoError(err)
+	received := exported.(inviteExport).Received
+	s.Require().Len(received, 1)
+	s.Equal(pending.ID, received[0].ID)
+
+	s.Require().NoError(hook.Erase(context.Background(), s.db, user.ID))
+	var erased model.Invite
+	s.Require().NoError(s.db.First(&erased, pending.ID).Error)
+	s.Equal(userModel.ErasedEmail(user.ID), erased.Email)
+	s.NotNil(erased.RevokedAt)
+	row = s.db.Raw("SELECT email, email_index FROM invites WHERE id = ?", pending.ID).Row()
+	s.Require().NoError(row.Scan(&email, &index))
+	s.True(strings.HasPrefix(email, "enc:v1:1:"))
+	s.Equal(keyring.BlindIndex(userModel.ErasedEmail(user.ID)), index)
+
+	invites, err := s.service.List()
+	s.Require().NoError(err)
+	s.Len(invites, 2)
+	s.Equal("other@example.com", invites[0].Email)
+}
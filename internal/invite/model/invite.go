@@ -0,0 +1,148 @@
+package model
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"boilerplate/pkg/fieldcrypt"
+	"boilerplate/shared/constants"
+	errs "boilerplate/shared/errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DefaultTTL adalah masa berlaku invite jika expires_at tidak diisi
+const DefaultTTL = 7 * 24 * time.Hour
+
+const codeLength = 18
+
+type Invite struct {
+	ID             uint           `json:"id" gorm:"primaryKey"`
+	Code           string         `json:"-" gorm:"size:32;uniqueIndex"`
+	Email          string         `json:"email" gorm:"size:512;serializer:encrypted"`
+	EmailIndex     *string        `json:"-" gorm:"size:64;index"`
+	Role           constants.Role `json:"role" gorm:"size:20"`
+	InvitedBy      uint           `json:"invited_by"`
+	ExpiresAt      time.Time      `json:"expires_at"`
+	AcceptedAt     *time.Time     `json:"accepted_at"`
+	AcceptedUserID *uint          `json:"accepted_user_id"`
+	RevokedAt      *time.Time     `json:"revoked_at,omitempty"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+// encryptedColumns adalah kolom invites yang dienkripsi serializer fieldcrypt
+var encryptedColumns = []string{"email"}
+
+// CreatedInvite adalah response create; Token hanya ditampilkan sekali dan
+// dikirim ke calon user sebagai bagian dari link undangan
+type CreatedInvite struct {
+	Invite
+	Token string `json:"token"`
+}
+
+// DTO: Create invite input
+type CreateInviteInput struct {
+	Email     string         `json:"email" validate:"required,email"`
+	Role      constants.Role `json:"role" validate:"required,oneof=admin user"`
+	ExpiresAt *time.Time     `json:"expires_at"`
+}
+
+// DTO: Accept invite input
+type AcceptInviteInput struct {
+	Token    string `json:"token" validate:"required"`
+	Name     string `json:"name" validate:"required,min=2,max=50"`
+	Password string `json:"password" validate:"required,min=6"`
+}
+
+// Factory: Create new invite. Expiry defaults to DefaultTTL from now.
+func NewInvite(input CreateInviteInput, invitedBy uint, now time.Time) (*Invite, error) {
+	expiresAt := now.Add(DefaultTTL)
+	if input.ExpiresAt != nil {
+		if !input.ExpiresAt.After(now) {
+			return nil, errs.ErrInviteInvalidExpiry
+		}
+		expiresAt = *input.ExpiresAt
+	}
+
+	buf := make([]byte, codeLength)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+
+	return &Invite{
+		Code:      base64.RawURLEncoding.EncodeToString(buf),
+		Email:     strings.ToLower(strings.TrimSpace(input.Email)),
+		Role:      input.Role,
+		InvitedBy: invitedBy,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// ByEmail mencari invite berdasarkan blind index email
+func ByEmail(email string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(EmailCondition(email))
+	}
+}
+
+// EmailCondition adalah kondisi ByEmail untuk digabung dengan kondisi lain, mis. OR
+func EmailCondition(email string) clause.Expr {
+	return fieldcrypt.IndexCondition("invites.email", "invites.email_index", email)
+}
+
+// BeforeSave mengisi blind index email. Serializer GORM tidak berlaku untuk update
+// dengan map, mis. anonimisasi email saat erasure, sehingga dienkripsi di sini.
+func (i *Invite) BeforeSave(tx *gorm.DB) error {
+	changes, ok := tx.Statement.Dest.(map[string]interface{})
+	if !ok {
+		if i.Email != "" {
+			i.EmailIndex = fieldcrypt.IndexOf(i.Email)
+		}
+		return nil
+	}
+	return fieldcrypt.EncryptChanges(changes, encryptedColumns, map[string]string{"email": "email_index"})
+}
+
+// Token returns "<code>.<signature>". Signature mengikat code, email, role dan
+// expiry sehingga token tidak bisa dipalsukan atau dipakai setelah data invite berubah.
+func (i *Invite) Token(secret string) string {
+	return i.Code + "." + i.signature(secret)
+}
+
+// ParseToken memecah token menjadi code dan signature
+func ParseToken(token string) (string, string, error) {
+	code, signature, ok := strings.Cut(strings.TrimSpace(token), ".")
+	if !ok || code == "" || signature == "" {
+		return "", "", errs.ErrInvalidInvite
+	}
+	return code, signature, nil
+}
+
+// VerifySignature membandingkan signature token secara constant-time
+func (i *Invite) VerifySignature(signature, secret string) bool {
+	return hmac.Equal([]byte(signature), []byte(i.signature(secret)))
+}
+
+// IsPending reports whether the invite can still be accepted
+func (i *Invite) IsPending(now time.Time) bool {
+	return i.AcceptedAt == nil && i.RevokedAt == nil && now.Before(i.ExpiresAt)
+}
+
+func (i *Invite) signature(secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strings.Join([]string{
+		"invite",
+		i.Code,
+		i.Email,
+		string(i.Role),
+		strconv.FormatInt(i.ExpiresAt.Unix(), 10),
+	}, "|")))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,81 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"boilerplate/shared/constants"
+	errs "boilerplate/shared/errors"
+
+	"github.com/stretchr/testify/suite"
+)
+
+const testSecret = "test-secret"
+
+type InviteTestSuite struct {
+	suite.Suite
+	now time.Time
+}
+
+func TestInviteSuite(t *testing.T) {
+	suite.Run(t, new(InviteTestSuite))
+}
+
+func (s *InviteTestSuite) SetupTest() {
+	s.now = time.Now()
+}
+
+func (s *InviteTestSuite) newInvite() *Invite {
+	invite, err := NewInvite(CreateInviteInput{Email: " New@Example.com ", Role: constants.RoleAdmin}, 1, s.now)
+	s.Require().NoError(err)
+	return invite
+}
+
+func (s *InviteTestSuite) TestNewInviteDefaults() {
+	invite := s.newInvite()
+	s.Equal("new@example.com", invite.Email)
+	s.Equal(constants.RoleAdmin, invite.Role)
+	s.Equal(s.now.Add(DefaultTTL), invite.ExpiresAt)
+	s.NotEmpty(invite.Code)
+	s.True(invite.IsPending(s.now))
+}
+
+func (s *InviteTestSuite) TestNewInviteRejectsPastExpiry() {
+	past := s.now.Add(-time.Minute)
+	_, err := NewInvite(CreateInviteInput{Email: "new@example.com", Role: constants.RoleUser, ExpiresAt: &past}, 1, s.now)
+	s.ErrorIs(err, errs.ErrInviteInvalidExpiry)
+}
+
+func (s *InviteTestSuite) TestTokenRoundTrip() {
+	invite := s.newInvite()
+
+	code, signature, err := ParseToken(invite.Token(testSecret))
+	s.Require().NoError(err)
+	s.Equal(invite.Code, code)
+	s.True(invite.VerifySignature(signature, testSecret))
+	s.False(invite.VerifySignature(signature, "other-secret"))
+
+	// Signature terikat ke role; mengubah data invite membatalkan token lama
+	invite.Role = constants.RoleUser
+	s.False(invite.VerifySignature(signature, testSecret))
+}
+
+func (s *InviteTestSuite) TestParseTokenRejectsMalformed() {
+	for _, token := range []string{"", "no-signature", ".sig", "code."} {
+		_, _, err := ParseToken(token)
+		s.ErrorIs(err, errs.ErrInvalidInvite, token)
+	}
+}
+
+func (s *InviteTestSuite) TestIsPending() {
+	invite := s.newInvite()
+	s.False(invite.IsPending(invite.ExpiresAt))
+
+	accepted := s.newInvite()
+	accepted.AcceptedAt = &s.now
+	s.False(accepted.IsPending(s.now))
+
+	revoked := s.newInvite()
+	revoked.RevokedAt = &s.now
+	s.False(revoked.IsPending(s.now))
+}
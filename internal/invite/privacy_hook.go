@@ -0,0 +1,66 @@
+package invite
+
+import (
+	"context"
+	"time"
+
+	"boilerplate/internal/invite/model"
+	userModel "boilerplate/internal/user/model"
+
+	"gorm.io/gorm"
+)
+
+// PrivacyHook mengekspor undangan yang dikirim dan diterima user. Saat erasure,
+// email di undangan untuk user tersebut dianonimkan dan undangan yang belum
+// dipakai dicabut; undangan yang dikirim user tetap disimpan.
+type PrivacyHook struct{}
+
+func NewPrivacyHook() *PrivacyHook {
+	return &PrivacyHook{}
+}
+
+// inviteExport adalah data undangan di file export
+type inviteExport struct {
+	Sent     []model.Invite `json:"sent"`
+	Received []model.Invite `json:"received"`
+}
+
+func (h *PrivacyHook) Name() string {
+	return "invites"
+}
+
+func (h *PrivacyHook) Export(ctx context.Context, db *gorm.DB, userID uint) (interface{}, error) {
+	var user userModel.User
+	if err := db.WithContext(ctx).Select("id", "email").First(&user, userID).Error; err != nil {
+		return nil, err
+	}
+
+	var data inviteExport
+	if err := db.WithContext(ctx).Where("invited_by = ?", userID).Order("id").Find(&data.Sent).Error; err != nil {
+		return nil, err
+	}
+	if err := db.WithContext(ctx).
+		Where("accepted_user_id = ? OR ?", userID, model.EmailCondition(user.Email)).
+		Order("id").Find(&data.Received).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (h *PrivacyHook) Erase(ctx context.Context, tx *gorm.DB, userID uint) error {
+	var user userModel.User
+	if err := tx.WithContext(ctx).Select("id", "email").First(&user, userID).Error; err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if err := tx.WithContext(ctx).Model(&model.Invite{}).
+		Scopes(model.ByEmail(user.Email)).
+		Where("accepted_at IS NULL AND revoked_at IS NULL").
+		Update("revoked_at", now).Error; err != nil {
+		return err
+	}
+	return tx.WithContext(ctx).Model(&model.Invite{}).
+		Where("accepted_user_id = ? OR ?", userID, model.EmailCondition(user.Email)).
+		Update("email", userModel.ErasedEmail(userID)).Error
+}
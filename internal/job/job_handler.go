@@ -0,0 +1,66 @@
+package job
+
+import (
+	"net/http"
+
+	"boilerplate/internal/job/model"
+	"boilerplate/pkg/response"
+	errs "boilerplate/shared/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+type JobHandler struct {
+	jobService JobServiceInterface
+}
+
+func NewJobHandler(jobService JobServiceInterface) *JobHandler {
+	return &JobHandler{
+		jobService: jobService,
+	}
+}
+
+func (h *JobHandler) Stats(c echo.Context) error {
+	stats, err := h.jobService.Stats(c.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, http.StatusOK, "Queue stats retrieved successfully", stats)
+}
+
+func (h *JobHandler) GetFailed(c echo.Context) error {
+	var query model.FailedJobQuery
+	if err := c.Bind(&query); err != nil {
+		return errs.ErrInvalidPayload.Wrap(err)
+	}
+
+	if err := c.Validate(&query); err != nil {
+		return err
+	}
+
+	jobs, err := h.jobService.ListFailed(c.Request().Context(), query)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, http.StatusOK, "Failed jobs retrieved successfully", jobs)
+}
+
+func (h *JobHandler) GetByID(c echo.Context) error {
+	job, err := h.jobService.GetJob(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, http.StatusOK, "Job retrieved successfully", job)
+}
+
+func (h *JobHandler) Retry(c echo.Context) error {
+	job, err := h.jobService.Retry(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, http.StatusOK, "Job queued for retry", job)
+}
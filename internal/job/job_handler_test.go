@@ -0,0 +1,121 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"boilerplate/internal/testutil"
+	"boilerplate/pkg/logger"
+	"boilerplate/pkg/queue"
+	errs "boilerplate/shared/errors"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/suite"
+)
+
+type JobHandlerTestSuite struct {
+	suite.Suite
+	e       *echo.Echo
+	queue   *queue.Queue
+	handler *JobHandler
+	failed  *queue.Job
+}
+
+func TestJobHandlerSuite(t *testing.T) {
+	suite.Run(t, new(JobHandlerTestSuite))
+}
+
+func (s *JobHandlerTestSuite) SetupTest() {
+	client := testutil.NewRedis(s.T())
+	s.queue = queue.New(client, queue.Config{VisibilityTimeout: time.Minute, MaxAttempts: 3, BaseBackoff: time.Second, MaxBackoff: time.Minute})
+	s.handler = NewJobHandler(NewJobService(s.queue, logger.NewLogger()))
+	s.e = echo.New()
+	s.e.Validator = testutil.NewValidator()
+
+	worker := queue.NewWorker(s.queue, logger.NewLogger())
+	worker.Handle("email", func(context.Context, *queue.Job) error {
+		return queue.Permanent(errors.New("mailbox does not exist"))
+	})
+	var err error
+	s.failed, err = s.queue.Enqueue(context.Background(), "email", map[string]string{"to": "jane@example.com"})
+	s.Require().NoError(err)
+	_, err = worker.ProcessNext(context.Background())
+	s.Require().NoError(err)
+}
+
+func (s *JobHandlerTestSuite) context(method, target, id string) (echo.Context, *httptest.ResponseRecorder) {
+	rec := httptest.NewRecorder()
+	c := s.e.NewContext(httptest.NewRequest(method, target, nil), rec)
+	if id != "" {
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+	}
+	return c, rec
+}
+
+// data mendekode field data dari response sukses
+func (s *JobHandlerTestSuite) data(rec *httptest.ResponseRecorder, dest interface{}) {
+	var body struct {
+		Data json.RawMessage `json:"data"`
+	}
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &body))
+	s.Require().NoError(json.Unmarshal(body.Data, dest))
+}
+
+func (s *JobHandlerTestSuite) TestStats() {
+	c, rec := s.context(http.MethodGet, "/", "")
+	s.Require().NoError(s.handler.Stats(c))
+	s.Equal(http.StatusOK, rec.Code)
+
+	var stats queue.Stats
+	s.data(rec, &stats)
+	s.Equal(queue.Stats{Dead: 1}, stats)
+}
+
+func (s *JobHandlerTestSuite) TestGetFailed() {
+	c, rec := s.context(http.MethodGet, "/failed?limit=10", "")
+	s.Require().NoError(s.handler.GetFailed(c))
+	s.Equal(http.StatusOK, rec.Code)
+
+	var list struct {
+		Jobs  []queue.Job `json:"jobs"`
+		Total int64       `json:"total"`
+	}
+	s.data(rec, &list)
+	s.EqualValues(1, list.Total)
+	s.Require().Len(list.Jobs, 1)
+	s.Equal(s.failed.ID, list.Jobs[0].ID)
+
+	c, _ = s.context(http.MethodGet, "/failed?limit=500", "")
+	var validationErrs validator.ValidationErrors
+	s.ErrorAs(s.handler.GetFailed(c), &validationErrs)
+
+	c, _ = s.context(http.MethodGet, "/failed?offset=abc", "")
+	s.ErrorIs(s.handler.GetFailed(c), errs.ErrInvalidPayload)
+}
+
+func (s *JobHandlerTestSuite) TestGetByIDAndRetry() {
+	c, rec := s.context(http.MethodGet, "/", s.failed.ID)
+	s.Require().NoError(s.handler.GetByID(c))
+	s.Equal(http.StatusOK, rec.Code)
+	var job queue.Job
+	s.data(rec, &job)
+	s.Equal(queue.StateDead, job.State)
+
+	c, rec = s.context(http.MethodPost, "/", s.failed.ID)
+	s.Require().NoError(s.handler.Retry(c))
+	s.Equal(http.StatusOK, rec.Code)
+	s.data(rec, &job)
+	s.Equal(queue.StateReady, job.State)
+
+	c, _ = s.context(http.MethodPost, "/", s.failed.ID)
+	s.ErrorIs(s.handler.Retry(c), errs.ErrJobNotFailed)
+	c, _ = s.context(http.MethodGet, "/", "unknown")
+	s.ErrorIs(s.handler.GetByID(c), errs.ErrJobNotFound)
+}
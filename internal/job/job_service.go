@@ -0,0 +1,78 @@
+package job
+
+import (
+	"context"
+	"errors"
+
+	"boilerplate/internal/job/model"
+	"boilerplate/pkg/logger"
+	"boilerplate/pkg/queue"
+	errs "boilerplate/shared/errors"
+
+	"github.com/sirupsen/logrus"
+)
+
+// JobServiceInterface mendefinisikan kontrak untuk JobService
+type JobServiceInterface interface {
+	Stats(ctx context.Context) (queue.Stats, error)
+	ListFailed(ctx context.Context, query model.FailedJobQuery) (*model.FailedJobList, error)
+	GetJob(ctx context.Context, id string) (*queue.Job, error)
+	Retry(ctx context.Context, id string) (*queue.Job, error)
+}
+
+// JobService menyediakan inspeksi antrian job untuk admin
+type JobService struct {
+	queue  *queue.Queue
+	logger logger.Logger
+}
+
+func NewJobService(queue *queue.Queue, logger logger.Logger) *JobService {
+	return &JobService{
+		queue:  queue,
+		logger: logger,
+	}
+}
+
+func (s *JobService) Stats(ctx context.Context) (queue.Stats, error) {
+	return s.queue.Stats(ctx)
+}
+
+// ListFailed mengembalikan job di dead-letter queue, terbaru lebih dulu
+func (s *JobService) ListFailed(ctx context.Context, query model.FailedJobQuery) (*model.FailedJobList, error) {
+	jobs, total, err := s.queue.ListDead(ctx, query.Offset, model.FailedJobLimit(query.Limit))
+	if err != nil {
+		return nil, err
+	}
+	return &model.FailedJobList{Jobs: jobs, Total: total}, nil
+}
+
+func (s *JobService) GetJob(ctx context.Context, id string) (*queue.Job, error) {
+	job, err := s.queue.Get(ctx, id)
+	if errors.Is(err, queue.ErrNotFound) {
+		return nil, errs.ErrJobNotFound.Wrap(err)
+	}
+	return job, err
+}
+
+// Retry mengembalikan job gagal ke antrian dengan jumlah percobaan direset
+func (s *JobService) Retry(ctx context.Context, id string) (*queue.Job, error) {
+	job, err := s.queue.Retry(ctx, id)
+	switch {
+	case errors.Is(err, queue.ErrNotFound):
+		return nil, errs.ErrJobNotFound.Wrap(err)
+	case errors.Is(err, queue.ErrNotDead):
+		return nil, errs.ErrJobNotFailed
+	case err != nil:
+		s.logger.WithFields(logrus.Fields{
+			"job_id": id,
+			"error":  err.Error(),
+		}).Error("Gagal mencoba ulang job")
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"job_id":   job.ID,
+		"job_type": job.Type,
+	}).Info("Job gagal dicoba ulang oleh admin")
+	return job, nil
+}
@@ -0,0 +1,97 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"boilerplate/internal/job/model"
+	"boilerplate/internal/testutil"
+	"boilerplate/pkg/logger"
+	"boilerplate/pkg/queue"
+	errs "boilerplate/shared/errors"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type JobServiceTestSuite struct {
+	suite.Suite
+	ctx     context.Context
+	queue   *queue.Queue
+	worker  *queue.Worker
+	service *JobService
+}
+
+func TestJobServiceSuite(t *testing.T) {
+	suite.Run(t, new(JobServiceTestSuite))
+}
+
+func (s *JobServiceTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	client := testutil.NewRedis(s.T())
+
+	s.queue = queue.New(client, queue.Config{VisibilityTimeout: time.Minute, MaxAttempts: 3, BaseBackoff: time.Second, MaxBackoff: time.Minute})
+	s.worker = queue.NewWorker(s.queue, logger.NewLogger())
+	s.worker.Handle("email", func(context.Context, *queue.Job) error {
+		return queue.Permanent(errors.New("mailbox does not exist"))
+	})
+	s.service = NewJobService(s.queue, logger.NewLogger())
+}
+
+// dead memasukkan job ke dead-letter queue lewat worker
+func (s *JobServiceTestSuite) dead() *queue.Job {
+	job, err := s.queue.Enqueue(s.ctx, "email", map[string]string{"to": "jane@example.com"})
+	s.Require().NoError(err)
+	processed, err := s.worker.ProcessNext(s.ctx)
+	s.Require().NoError(err)
+	s.Require().True(processed)
+	return job
+}
+
+func (s *JobServiceTestSuite) TestListFailedAndGetJob() {
+	first := s.dead()
+	second := s.dead()
+	_, err := s.queue.Enqueue(s.ctx, "email", map[string]string{"to": "pending@example.com"}, queue.Delay(time.Hour))
+	s.Require().NoError(err)
+
+	stats, err := s.service.Stats(s.ctx)
+	s.Require().NoError(err)
+	s.Equal(queue.Stats{Scheduled: 1, Dead: 2}, stats)
+
+	page, err := s.service.ListFailed(s.ctx, model.FailedJobQuery{Limit: 1})
+	s.Require().NoError(err)
+	s.EqualValues(2, page.Total)
+	s.Require().Len(page.Jobs, 1)
+	next, err := s.service.ListFailed(s.ctx, model.FailedJobQuery{Offset: 1, Limit: 1})
+	s.Require().NoError(err)
+	s.Require().Len(next.Jobs, 1)
+	s.ElementsMatch([]string{first.ID, second.ID}, []string{page.Jobs[0].ID, next.Jobs[0].ID})
+
+	job, err := s.service.GetJob(s.ctx, first.ID)
+	s.Require().NoError(err)
+	s.Equal(queue.StateDead, job.State)
+	s.Equal("mailbox does not exist", job.LastError)
+
+	_, err = s.service.GetJob(s.ctx, "unknown")
+	s.ErrorIs(err, errs.ErrJobNotFound)
+}
+
+func (s *JobServiceTestSuite) TestRetry() {
+	failed := s.dead()
+
+	retried, err := s.service.Retry(s.ctx, failed.ID)
+	s.Require().NoError(err)
+	s.Equal(queue.StateReady, retried.State)
+	s.Zero(retried.Attempts)
+
+	stats, err := s.service.Stats(s.ctx)
+	s.Require().NoError(err)
+	s.Equal(queue.Stats{Ready: 1}, stats)
+
+	// job yang tidak berada di dead-letter queue tidak dapat dicoba ulang
+	_, err = s.service.Retry(s.ctx, failed.ID)
+	s.ErrorIs(err, errs.ErrJobNotFailed)
+	_, err = s.service.Retry(s.ctx, "unknown")
+	s.ErrorIs(err, errs.ErrJobNotFound)
+}
@@ -0,0 +1,31 @@
+package model
+
+import "boilerplate/pkg/queue"
+
+const (
+	DefaultFailedJobLimit = 50
+	MaxFailedJobLimit     = 200
+)
+
+// DTO: Query failed jobs
+type FailedJobQuery struct {
+	Offset int `query:"offset" validate:"omitempty,min=0"`
+	Limit  int `query:"limit" validate:"omitempty,min=1,max=200"`
+}
+
+// DTO: Failed jobs page
+type FailedJobList struct {
+	Jobs  []queue.Job `json:"jobs"`
+	Total int64       `json:"total"`
+}
+
+// FailedJobLimit returns limit clamped to the allowed range, or the default when unset
+func FailedJobLimit(limit int) int {
+	if limit <= 0 {
+		return DefaultFailedJobLimit
+	}
+	if limit > MaxFailedJobLimit {
+		return MaxFailedJobLimit
+	}
+	return limit
+}
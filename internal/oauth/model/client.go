@@ -0,0 +1,152 @@
+package model
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	errs "boilerplate/shared/errors"
+)
+
+// Grant type yang didukung
+const (
+	GrantAuthorizationCode = "authorization_code"
+	GrantClientCredentials = "client_credentials"
+)
+
+// Scope OpenID Connect; scope API key (users:read, ...) juga dapat diberikan ke client
+const (
+	ScopeOpenID  = "openid"
+	ScopeProfile = "profile"
+	ScopeEmail   = "email"
+)
+
+const (
+	clientIDBytes     = 16
+	clientSecretBytes = 32
+)
+
+// Client adalah aplikasi yang terdaftar untuk login atau mengambil token dari aplikasi ini
+type Client struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	ClientID     string    `json:"client_id" gorm:"size:64;uniqueIndex"`
+	SecretHash   string    `json:"-" gorm:"size:64"`
+	Name         string    `json:"name" gorm:"size:100"`
+	RedirectURIs []string  `json:"redirect_uris" gorm:"serializer:json"`
+	GrantTypes   []string  `json:"grant_types" gorm:"serializer:json"`
+	Scopes       []string  `json:"scopes" gorm:"serializer:json"`
+	Public       bool      `json:"public"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+func (Client) TableName() string {
+	return "oauth_clients"
+}
+
+// CreatedClient adalah response create; ClientSecret hanya ditampilkan sekali
+type CreatedClient struct {
+	Client
+	ClientSecret string `json:"client_secret,omitempty"`
+}
+
+// DTO: Create OAuth client input. Client public (SPA, mobile) tidak memiliki secret.
+type CreateClientInput struct {
+	Name         string   `json:"name" validate:"required,max=100"`
+	RedirectURIs []string `json:"redirect_uris" validate:"omitempty,dive,url"`
+	GrantTypes   []string `json:"grant_types" validate:"required,min=1,dive,oneof=authorization_code client_credentials"`
+	Scopes       []string `json:"scopes" validate:"required,min=1,dive,oneof=openid profile email users:read users:write categories:read categories:write"`
+	Public       bool     `json:"public"`
+}
+
+// Factory: Create new OAuth client and its plaintext secret
+func NewClient(input CreateClientInput) (*Client, string, error) {
+	client := &Client{
+		Name:         strings.TrimSpace(input.Name),
+		RedirectURIs: input.RedirectURIs,
+		GrantTypes:   input.GrantTypes,
+		Scopes:       input.Scopes,
+		Public:       input.Public,
+	}
+
+	if client.AllowsGrant(GrantAuthorizationCode) && len(client.RedirectURIs) == 0 {
+		return nil, "", errs.ErrOAuthInvalidClientConfig.WithDetails(map[string]interface{}{
+			"redirect_uris": "required for the authorization_code grant",
+		})
+	}
+	if client.Public && client.AllowsGrant(GrantClientCredentials) {
+		return nil, "", errs.ErrOAuthInvalidClientConfig.WithDetails(map[string]interface{}{
+			"grant_types": "client_credentials requires a confidential client",
+		})
+	}
+
+	clientID, err := randomString(clientIDBytes)
+	if err != nil {
+		return nil, "", err
+	}
+	client.ClientID = clientID
+
+	if client.Public {
+		return client, "", nil
+	}
+	secret, err := randomString(clientSecretBytes)
+	if err != nil {
+		return nil, "", err
+	}
+	client.SecretHash = hashSecret(secret)
+	return client, secret, nil
+}
+
+// Authenticate memeriksa secret client; client public tidak boleh mengirim secret
+func (c *Client) Authenticate(secret string) bool {
+	if c.Public {
+		return secret == ""
+	}
+	return subtle.ConstantTimeCompare([]byte(c.SecretHash), []byte(hashSecret(secret))) == 1
+}
+
+// AllowsGrant checks whether the client may use grant type
+func (c *Client) AllowsGrant(grantType string) bool {
+	return contains(c.GrantTypes, grantType)
+}
+
+// AllowsRedirectURI membandingkan redirect_uri secara persis (tanpa wildcard)
+func (c *Client) AllowsRedirectURI(uri string) bool {
+	return contains(c.RedirectURIs, uri)
+}
+
+// AllowsScopes checks that every requested scope was registered for the client
+func (c *Client) AllowsScopes(scopes []string) bool {
+	for _, scope := range scopes {
+		if !contains(c.Scopes, scope) {
+			return false
+		}
+	}
+	return true
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomString(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func contains(items []string, value string) bool {
+	for _, item := range items {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
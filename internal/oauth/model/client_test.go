@@ -0,0 +1,105 @@
+package model
+
+import (
+	"testing"
+
+	errs "boilerplate/shared/errors"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ClientTestSuite struct {
+	suite.Suite
+}
+
+func TestClientSuite(t *testing.T) {
+	suite.Run(t, new(ClientTestSuite))
+}
+
+func (s *ClientTestSuite) TestNewClient() {
+	tests := []struct {
+		name        string
+		input       CreateClientInput
+		expectedErr error
+		public      bool
+	}{
+		{
+			name: "confidential client",
+			input: CreateClientInput{
+				Name:         "Tool",
+				RedirectURIs: []string{"https://tool.example.com/cb"},
+				GrantTypes:   []string{GrantAuthorizationCode, GrantClientCredentials},
+				Scopes:       []string{ScopeOpenID},
+			},
+		},
+		{
+			name: "public client",
+			input: CreateClientInput{
+				Name:         "SPA",
+				RedirectURIs: []string{"https://spa.example.com/cb"},
+				GrantTypes:   []string{GrantAuthorizationCode},
+				Scopes:       []string{ScopeOpenID},
+				Public:       true,
+			},
+			public: true,
+		},
+		{
+			name: "authorization code without redirect uri",
+			input: CreateClientInput{
+				Name:       "Tool",
+				GrantTypes: []string{GrantAuthorizationCode},
+				Scopes:     []string{ScopeOpenID},
+			},
+			expectedErr: errs.ErrOAuthInvalidClientConfig,
+		},
+		{
+			name: "public client with client credentials",
+			input: CreateClientInput{
+				Name:       "CLI",
+				GrantTypes: []string{GrantClientCredentials},
+				Scopes:     []string{"users:read"},
+				Public:     true,
+			},
+			expectedErr: errs.ErrOAuthInvalidClientConfig,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			client, secret, err := NewClient(tt.input)
+			if tt.expectedErr != nil {
+				s.ErrorIs(err, tt.expectedErr)
+				return
+			}
+
+			s.Require().NoError(err)
+			s.NotEmpty(client.ClientID)
+			if tt.public {
+				s.Empty(secret)
+				s.True(client.Authenticate(""))
+				s.False(client.Authenticate("anything"))
+				return
+			}
+			s.NotEmpty(secret)
+			s.True(client.Authenticate(secret))
+			s.False(client.Authenticate(""))
+		})
+	}
+}
+
+func (s *ClientTestSuite) TestVerifyChallenge() {
+	// Contoh dari RFC 7636 lampiran B
+	code := AuthorizationCode{CodeChallenge: "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"}
+
+	s.True(code.VerifyChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"))
+	s.False(code.VerifyChallenge("wrong"))
+}
+
+func (s *ClientTestSuite) TestConsentGrant() {
+	consent := Consent{Scopes: []string{ScopeOpenID}}
+	s.False(consent.Covers([]string{ScopeOpenID, ScopeEmail}))
+
+	consent.Grant([]string{ScopeEmail, ScopeOpenID})
+	s.Equal([]string{ScopeOpenID, ScopeEmail}, consent.Scopes)
+	s.True(consent.Covers([]string{ScopeOpenID, ScopeEmail}))
+}
@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+// Consent mencatat scope yang sudah disetujui user untuk sebuah client
+type Consent struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"uniqueIndex:idx_consent_user_client"`
+	ClientID  string    `json:"client_id" gorm:"size:64;uniqueIndex:idx_consent_user_client"`
+	Scopes    []string  `json:"scopes" gorm:"serializer:json"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (Consent) TableName() string {
+	return "oauth_consents"
+}
+
+// Covers reports whether every scope was already approved
+func (c *Consent) Covers(scopes []string) bool {
+	for _, scope := range scopes {
+		if !contains(c.Scopes, scope) {
+			return false
+		}
+	}
+	return true
+}
+
+// Grant menambahkan scope baru ke consent yang sudah ada
+func (c *Consent) Grant(scopes []string) {
+	for _, scope := range scopes {
+		if !contains(c.Scopes, scope) {
+			c.Scopes = append(c.Scopes, scope)
+		}
+	}
+}
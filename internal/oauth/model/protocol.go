@@ -0,0 +1,143 @@
+package model
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/http"
+	"time"
+)
+
+// Kode error OAuth 2.0 (RFC 6749 bagian 4.1.2.1 dan 5.2)
+const (
+	ErrorInvalidRequest          = "invalid_request"
+	ErrorInvalidClient           = "invalid_client"
+	ErrorInvalidGrant            = "invalid_grant"
+	ErrorUnauthorizedClient      = "unauthorized_client"
+	ErrorUnsupportedGrantType    = "unsupported_grant_type"
+	ErrorUnsupportedResponseType = "unsupported_response_type"
+	ErrorInvalidScope            = "invalid_scope"
+	ErrorAccessDenied            = "access_denied"
+	ErrorInvalidToken            = "invalid_token"
+)
+
+// CodeChallengeS256 adalah satu-satunya metode PKCE yang diterima
+const CodeChallengeS256 = "S256"
+
+// ProtocolError adalah body error endpoint OAuth. Endpoint protokol tidak memakai
+// problem+json karena client OAuth mengharapkan format RFC 6749.
+type ProtocolError struct {
+	Status      int    `json:"-"`
+	Code        string `json:"error" validate:"required"`
+	Description string `json:"error_description,omitempty"`
+}
+
+func NewProtocolError(status int, code, description string) *ProtocolError {
+	return &ProtocolError{Status: status, Code: code, Description: description}
+}
+
+func (e *ProtocolError) Error() string {
+	if e.Description == "" {
+		return e.Code
+	}
+	return e.Code + ": " + e.Description
+}
+
+// DTO: Authorization request (query /authorize). Hanya client_id dan redirect_uri
+// yang divalidasi sebagai error biasa; error lain dikirim balik lewat redirect.
+type AuthorizeRequest struct {
+	ResponseType        string `json:"response_type" query:"response_type"`
+	ClientID            string `json:"client_id" query:"client_id" validate:"required"`
+	RedirectURI         string `json:"redirect_uri" query:"redirect_uri" validate:"required,url"`
+	Scope               string `json:"scope" query:"scope"`
+	State               string `json:"state" query:"state"`
+	Nonce               string `json:"nonce" query:"nonce"`
+	CodeChallenge       string `json:"code_challenge" query:"code_challenge"`
+	CodeChallengeMethod string `json:"code_challenge_method" query:"code_challenge_method"`
+}
+
+// DTO: Keputusan consent user untuk authorization request
+type ConsentInput struct {
+	AuthorizeRequest
+	Approve bool `json:"approve"`
+}
+
+// ClientInfo adalah data client yang ditampilkan di layar consent
+type ClientInfo struct {
+	ClientID string `json:"client_id"`
+	Name     string `json:"name"`
+}
+
+// AuthorizeResult berisi URL redirect ke client, atau permintaan consent
+type AuthorizeResult struct {
+	RedirectTo      string      `json:"redirect_to,omitempty"`
+	ConsentRequired bool        `json:"consent_required"`
+	Client          *ClientInfo `json:"client,omitempty"`
+	Scopes          []string    `json:"scopes,omitempty"`
+}
+
+// AuthorizationCode disimpan di Redis sampai ditukar di /token
+type AuthorizationCode struct {
+	ClientID      string    `json:"client_id"`
+	UserID        uint      `json:"user_id"`
+	RedirectURI   string    `json:"redirect_uri"`
+	Scopes        []string  `json:"scopes"`
+	Nonce         string    `json:"nonce,omitempty"`
+	CodeChallenge string    `json:"code_challenge"`
+	AuthTime      time.Time `json:"auth_time"`
+}
+
+// VerifyChallenge memeriksa code_verifier PKCE terhadap code_challenge S256
+func (a *AuthorizationCode) VerifyChallenge(verifier string) bool {
+	sum := sha256.Sum256([]byte(verifier))
+	challenge := base64.RawURLEncoding.EncodeToString(sum[:])
+	return subtle.ConstantTimeCompare([]byte(challenge), []byte(a.CodeChallenge)) == 1
+}
+
+// DTO: Token request (form /token)
+type TokenRequest struct {
+	GrantType    string `json:"grant_type" form:"grant_type"`
+	Code         string `json:"code" form:"code"`
+	RedirectURI  string `json:"redirect_uri" form:"redirect_uri"`
+	CodeVerifier string `json:"code_verifier" form:"code_verifier"`
+	ClientID     string `json:"client_id" form:"client_id"`
+	ClientSecret string `json:"client_secret" form:"client_secret"`
+	Scope        string `json:"scope" form:"scope"`
+}
+
+// TokenResponse adalah response sukses /token (RFC 6749 bagian 5.1)
+type TokenResponse struct {
+	AccessToken string `json:"access_token" validate:"required"`
+	TokenType   string `json:"token_type" validate:"required"`
+	ExpiresIn   int    `json:"expires_in"`
+	IDToken     string `json:"id_token,omitempty"`
+	Scope       string `json:"scope,omitempty"`
+}
+
+// UserInfo adalah response /userinfo; claim mengikuti scope access token
+type UserInfo struct {
+	Subject string `json:"sub" validate:"required"`
+	Email   string `json:"email,omitempty"`
+	Name    string `json:"name,omitempty"`
+}
+
+// Discovery adalah dokumen /.well-known/openid-configuration. AuthorizationEndpoint
+// adalah halaman frontend, atau GET /authorize jika halaman tersebut tidak dikonfigurasi.
+type Discovery struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	UserInfoEndpoint                  string   `json:"userinfo_endpoint"`
+	JWKSURI                           string   `json:"jwks_uri"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	GrantTypesSupported               []string `json:"grant_types_supported"`
+	SubjectTypesSupported             []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+	ScopesSupported                   []string `json:"scopes_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+	ClaimsSupported                   []string `json:"claims_supported"`
+}
+
+// ErrInvalidClient adalah error /token untuk client yang tidak dikenal atau secret salah
+var ErrInvalidClient = NewProtocolError(http.StatusUnauthorized, ErrorInvalidClient, "client authentication failed")
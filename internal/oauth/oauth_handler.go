@@ -0,0 +1,218 @@
+package oauth
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"boilerplate/internal/oauth/model"
+	userModel "boilerplate/internal/user/model"
+	"boilerplate/pkg/response"
+	errs "boilerplate/shared/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+type OAuthHandler struct {
+	oauthService OAuthServiceInterface
+}
+
+func NewOAuthHandler(oauthService OAuthServiceInterface) *OAuthHandler {
+	return &OAuthHandler{
+		oauthService: oauthService,
+	}
+}
+
+func (h *OAuthHandler) Discovery(c echo.Context) error {
+	return c.JSON(http.StatusOK, h.oauthService.Discovery())
+}
+
+func (h *OAuthHandler) JWKS(c echo.Context) error {
+	return c.JSON(http.StatusOK, h.oauthService.JWKS())
+}
+
+// Authorize dipanggil frontend dengan sesi user; hasilnya URL redirect ke client
+// atau data untuk menampilkan layar consent
+func (h *OAuthHandler) Authorize(c echo.Context) error {
+	user, exists := c.Get("user").(*userModel.User)
+	if !exists {
+		return errs.ErrUnauthorized
+	}
+
+	var req model.AuthorizeRequest
+	if err := c.Bind(&req); err != nil {
+		return errs.ErrInvalidPayload.Wrap(err)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	result, err := h.oauthService.Authorize(c.Request().Context(), user, req)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, http.StatusOK, "Authorization processed", result)
+}
+
+// Consent menyimpan keputusan user di layar consent
+func (h *OAuthHandler) Consent(c echo.Context) error {
+	user, exists := c.Get("user").(*userModel.User)
+	if !exists {
+		return errs.ErrUnauthorized
+	}
+
+	var input model.ConsentInput
+	if err := c.Bind(&input); err != nil {
+		return errs.ErrInvalidPayload.Wrap(err)
+	}
+
+	if err := c.Validate(&input); err != nil {
+		return err
+	}
+
+	result, err := h.oauthService.Consent(c.Request().Context(), user, input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, http.StatusOK, "Consent recorded", result)
+}
+
+// Token adalah token endpoint OAuth; client dapat mengirim kredensial lewat
+// HTTP Basic (client_secret_basic) atau body form (client_secret_post)
+func (h *OAuthHandler) Token(c echo.Context) error {
+	var req model.TokenRequest
+	if err := c.Bind(&req); err != nil {
+		return protocolError(c, model.NewProtocolError(http.StatusBadRequest, model.ErrorInvalidRequest, "invalid form body"))
+	}
+
+	if clientID, clientSecret, ok := c.Request().BasicAuth(); ok {
+		// RFC 6749 bagian 2.3.1: kredensial di-encode sebagai form
+		req.ClientID, _ = url.QueryUnescape(clientID)
+		req.ClientSecret, _ = url.QueryUnescape(clientSecret)
+	}
+
+	token, err := h.oauthService.Token(c.Request().Context(), req)
+	if err != nil {
+		return protocolError(c, err)
+	}
+
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+	c.Response().Header().Set("Pragma", "no-cache")
+	return c.JSON(http.StatusOK, token)
+}
+
+func (h *OAuthHandler) UserInfo(c echo.Context) error {
+	accessToken, ok := strings.CutPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+	if !ok || accessToken == "" {
+		return protocolError(c, model.NewProtocolError(http.StatusUnauthorized, model.ErrorInvalidToken, "bearer access token is required"))
+	}
+
+	info, err := h.oauthService.UserInfo(accessToken)
+	if err != nil {
+		return protocolError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, info)
+}
+
+func (h *OAuthHandler) CreateClient(c echo.Context) error {
+	var input model.CreateClientInput
+	if err := c.Bind(&input); err != nil {
+		return errs.ErrInvalidPayload.Wrap(err)
+	}
+
+	if err := c.Validate(&input); err != nil {
+		return err
+	}
+
+	client, err := h.oauthService.CreateClient(input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, http.StatusCreated, "OAuth client created successfully, store the secret now: it will not be shown again", client)
+}
+
+func (h *OAuthHandler) GetAllClients(c echo.Context) error {
+	clients, err := h.oauthService.ListClients()
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, http.StatusOK, "OAuth clients retrieved successfully", clients)
+}
+
+func (h *OAuthHandler) GetClient(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return errs.ErrInvalidID.Wrap(err)
+	}
+
+	client, err := h.oauthService.GetClient(uint(id))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, http.StatusOK, "OAuth client retrieved successfully", client)
+}
+
+func (h *OAuthHandler) DeleteClient(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return errs.ErrInvalidID.Wrap(err)
+	}
+
+	if err := h.oauthService.DeleteClient(uint(id)); err != nil {
+		return err
+	}
+
+	return response.Success(c, http.StatusOK, "OAuth client deleted successfully", nil)
+}
+
+func (h *OAuthHandler) GetMyConsents(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+
+	consents, err := h.oauthService.ListConsents(userID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, http.StatusOK, "Consents retrieved successfully", consents)
+}
+
+func (h *OAuthHandler) RevokeMyConsent(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return errs.ErrInvalidID.Wrap(err)
+	}
+
+	if err := h.oauthService.RevokeConsent(userID, uint(id)); err != nil {
+		return err
+	}
+
+	return response.Success(c, http.StatusOK, "Consent revoked successfully", nil)
+}
+
+// protocolError menulis error OAuth dalam format RFC 6749; error lain diteruskan ke error handler
+func protocolError(c echo.Context, err error) error {
+	var protocolErr *model.ProtocolError
+	if !errors.As(err, &protocolErr) {
+		return err
+	}
+
+	switch protocolErr.Code {
+	case model.ErrorInvalidClient:
+		c.Response().Header().Set(echo.HeaderWWWAuthenticate, `Basic realm="oauth"`)
+	case model.ErrorInvalidToken:
+		c.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer error="invalid_token"`)
+	}
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+	return c.JSON(protocolErr.Status, protocolErr)
+}
@@ -0,0 +1,507 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"boilerplate/internal/oauth/model"
+	"boilerplate/internal/user"
+	userModel "boilerplate/internal/user/model"
+	"boilerplate/pkg/jwt"
+	"boilerplate/pkg/logger"
+	"boilerplate/pkg/redis"
+	errs "boilerplate/shared/errors"
+
+	jwtlib "github.com/golang-jwt/jwt/v5"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	authorizationCodeTTL = 5 * time.Minute
+	accessTokenTTL       = time.Hour
+	idTokenTTL           = time.Hour
+)
+
+// OAuthServiceInterface mendefinisikan kontrak untuk OAuthService
+type OAuthServiceInterface interface {
+	Discovery() model.Discovery
+	JWKS() jwt.JSONWebKeySet
+	Authorize(ctx context.Context, user *userModel.User, req model.AuthorizeRequest) (*model.AuthorizeResult, error)
+	Consent(ctx context.Context, user *userModel.User, input model.ConsentInput) (*model.AuthorizeResult, error)
+	Token(ctx context.Context, req model.TokenRequest) (*model.TokenResponse, error)
+	UserInfo(accessToken string) (*model.UserInfo, error)
+	CreateClient(input model.CreateClientInput) (*model.CreatedClient, error)
+	ListClients() ([]model.Client, error)
+	GetClient(id uint) (*model.Client, error)
+	DeleteClient(id uint) error
+	ListConsents(userID uint) ([]model.Consent, error)
+	RevokeConsent(userID, id uint) error
+}
+
+type OAuthService struct {
+	db          *gorm.DB
+	logger      logger.Logger
+	redisClient *redis.RedisClient
+	userService user.UserServiceInterface
+	signer      *jwt.Signer
+	issuer      string
+	// authorizationURL adalah halaman frontend yang menjadi authorization endpoint
+	authorizationURL string
+}
+
+// NewOAuthService membuat OIDC provider. GET /authorize membutuhkan sesi Bearer dan
+// mengembalikan JSON sehingga tidak dapat dibuka langsung oleh browser; authorizationURL
+// adalah halaman frontend yang menjembatani alur tersebut. authorization_endpoint wajib
+// ada di discovery, sehingga tanpa halaman frontend yang diiklankan adalah <issuer>/authorize.
+func NewOAuthService(db *gorm.DB, logger logger.Logger, redisClient *redis.RedisClient, userService user.UserServiceInterface, signer *jwt.Signer, issuer, authorizationURL string) *OAuthService {
+	if authorizationURL == "" {
+		authorizationURL = issuer + "/authorize"
+	}
+	return &OAuthService{
+		db:          db,
+		logger:      logger,
+		redisClient: redisClient,
+		userService: userService,
+		signer:      signer,
+		issuer:      issuer,
+
+		authorizationURL: authorizationURL,
+	}
+}
+
+func (s *OAuthService) Discovery() model.Discovery {
+	return model.Discovery{
+		Issuer:                            s.issuer,
+		AuthorizationEndpoint:             s.authorizationURL,
+		TokenEndpoint:                     s.issuer + "/token",
+		UserInfoEndpoint:                  s.issuer + "/userinfo",
+		JWKSURI:                           s.issuer + "/.well-known/jwks.json",
+		ResponseTypesSupported:            []string{"code"},
+		GrantTypesSupported:               []string{model.GrantAuthorizationCode, model.GrantClientCredentials},
+		SubjectTypesSupported:             []string{"public"},
+		IDTokenSigningAlgValuesSupported:  []string{jwtlib.SigningMethodRS256.Alg()},
+		ScopesSupported:                   []string{model.ScopeOpenID, model.ScopeProfile, model.ScopeEmail},
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post", "none"},
+		CodeChallengeMethodsSupported:     []string{model.CodeChallengeS256},
+		ClaimsSupported:                   []string{"sub", "iss", "aud", "exp", "iat", "auth_time", "nonce", "email", "name"},
+	}
+}
+
+func (s *OAuthService) JWKS() jwt.JSONWebKeySet {
+	return s.signer.JWKS()
+}
+
+// Authorize memvalidasi authorization request untuk user yang sedang login.
+// Jika consent sudah ada, code langsung diterbitkan; jika belum, layar consent diminta.
+func (s *OAuthService) Authorize(ctx context.Context, user *userModel.User, req model.AuthorizeRequest) (*model.AuthorizeResult, error) {
+	client, scopes, redirect, err := s.validateAuthorize(req)
+	if err != nil {
+		return nil, err
+	}
+	if redirect != "" {
+		return &model.AuthorizeResult{RedirectTo: redirect}, nil
+	}
+
+	var consent model.Consent
+	err = s.db.Where("user_id = ? AND client_id = ?", user.ID, client.ClientID).First(&consent).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if err != nil || !consent.Covers(scopes) {
+		return &model.AuthorizeResult{
+			ConsentRequired: true,
+			Client:          &model.ClientInfo{ClientID: client.ClientID, Name: client.Name},
+			Scopes:          scopes,
+		}, nil
+	}
+
+	return s.issueCode(ctx, user, client, scopes, req)
+}
+
+// Consent menyimpan keputusan user lalu mengarahkan kembali ke client
+func (s *OAuthService) Consent(ctx context.Context, user *userModel.User, input model.ConsentInput) (*model.AuthorizeResult, error) {
+	req := input.AuthorizeRequest
+	client, scopes, redirect, err := s.validateAuthorize(req)
+	if err != nil {
+		return nil, err
+	}
+	if redirect != "" {
+		return &model.AuthorizeResult{RedirectTo: redirect}, nil
+	}
+
+	if !input.Approve {
+		return &model.AuthorizeResult{
+			RedirectTo: errorRedirect(req, model.ErrorAccessDenied, "the user denied the request"),
+		}, nil
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var consent model.Consent
+		err := tx.Where("user_id = ? AND client_id = ?", user.ID, client.ClientID).First(&consent).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			consent = model.Consent{UserID: user.ID, ClientID: client.ClientID}
+		} else if err != nil {
+			return err
+		}
+		consent.Grant(scopes)
+		return tx.Save(&consent).Error
+	})
+	if err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"user_id":   user.ID,
+			"client_id": client.ClientID,
+			"error":     err.Error(),
+		}).Error("Gagal menyimpan consent OAuth")
+		return nil, err
+	}
+
+	return s.issueCode(ctx, user, client, scopes, req)
+}
+
+// validateAuthorize mengembalikan error biasa jika client atau redirect_uri tidak
+// valid (tidak aman untuk redirect), dan URL redirect berisi error untuk kasus lain
+func (s *OAuthService) validateAuthorize(req model.AuthorizeRequest) (*model.Client, []string, string, error) {
+	client, err := s.findClient(req.ClientID)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	if !client.AllowsRedirectURI(req.RedirectURI) {
+		return nil, nil, "", errs.ErrOAuthInvalidRedirectURI
+	}
+
+	scopes := strings.Fields(req.Scope)
+	switch {
+	case req.ResponseType != "code":
+		return nil, nil, errorRedirect(req, model.ErrorUnsupportedResponseType, "only the code response type is supported"), nil
+	case !client.AllowsGrant(model.GrantAuthorizationCode):
+		return nil, nil, errorRedirect(req, model.ErrorUnauthorizedClient, "client may not use the authorization code grant"), nil
+	case len(scopes) == 0 || !client.AllowsScopes(scopes):
+		return nil, nil, errorRedirect(req, model.ErrorInvalidScope, "requested scope is not allowed for this client"), nil
+	case req.CodeChallenge == "" || req.CodeChallengeMethod != model.CodeChallengeS256:
+		return nil, nil, errorRedirect(req, model.ErrorInvalidRequest, "PKCE with the S256 method is required"), nil
+	}
+	return client, scopes, "", nil
+}
+
+func (s *OAuthService) issueCode(ctx context.Context, user *userModel.User, client *model.Client, scopes []string, req model.AuthorizeRequest) (*model.AuthorizeResult, error) {
+	code, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := json.Marshal(model.AuthorizationCode{
+		ClientID:      client.ClientID,
+		UserID:        user.ID,
+		RedirectURI:   req.RedirectURI,
+		Scopes:        scopes,
+		Nonce:         req.Nonce,
+		CodeChallenge: req.CodeChallenge,
+		AuthTime:      time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.redisClient.SetAuthorizationCode(ctx, code, string(payload), authorizationCodeTTL); err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"client_id": client.ClientID,
+			"error":     err.Error(),
+		}).Error("Gagal menyimpan authorization code di Redis")
+		return nil, err
+	}
+
+	params := url.Values{"code": {code}}
+	if req.State != "" {
+		params.Set("state", req.State)
+	}
+	return &model.AuthorizeResult{RedirectTo: withQuery(req.RedirectURI, params)}, nil
+}
+
+// Token menangani grant authorization_code dan client_credentials
+func (s *OAuthService) Token(ctx context.Context, req model.TokenRequest) (*model.TokenResponse, error) {
+	client, err := s.findClient(req.ClientID)
+	if err != nil {
+		if errors.Is(err, errs.ErrOAuthUnknownClient) {
+			return nil, model.ErrInvalidClient
+		}
+		return nil, err
+	}
+	if !client.Authenticate(req.ClientSecret) {
+		return nil, model.ErrInvalidClient
+	}
+	if !client.AllowsGrant(req.GrantType) {
+		if req.GrantType != model.GrantAuthorizationCode && req.GrantType != model.GrantClientCredentials {
+			return nil, model.NewProtocolError(http.StatusBadRequest, model.ErrorUnsupportedGrantType, "")
+		}
+		return nil, model.NewProtocolError(http.StatusBadRequest, model.ErrorUnauthorizedClient, "client may not use this grant type")
+	}
+
+	switch req.GrantType {
+	case model.GrantAuthorizationCode:
+		return s.exchangeCode(ctx, client, req)
+	default:
+		return s.clientCredentials(client, req)
+	}
+}
+
+func (s *OAuthService) exchangeCode(ctx context.Context, client *model.Client, req model.TokenRequest) (*model.TokenResponse, error) {
+	invalidGrant := model.NewProtocolError(http.StatusBadRequest, model.ErrorInvalidGrant, "authorization code is invalid, expired or already used")
+
+	payload, err := s.redisClient.TakeAuthorizationCode(ctx, req.Code)
+	if err != nil {
+		return nil, invalidGrant
+	}
+	var code model.AuthorizationCode
+	if err := json.Unmarshal([]byte(payload), &code); err != nil {
+		return nil, invalidGrant
+	}
+	if code.ClientID != client.ClientID || code.RedirectURI != req.RedirectURI || !code.VerifyChallenge(req.CodeVerifier) {
+		return nil, invalidGrant
+	}
+
+	authorized, err := s.userService.GetUserByID(code.UserID)
+	if err != nil || authorized.IsDisabled() {
+		return nil, invalidGrant
+	}
+
+	now := time.Now()
+	scope := strings.Join(code.Scopes, " ")
+	subject := strconv.FormatUint(uint64(authorized.ID), 10)
+	accessToken, err := s.signer.Sign(jwt.AccessTokenClaims{
+		ClientID:         client.ClientID,
+		Scope:            scope,
+		RegisteredClaims: s.registeredClaims(subject, s.issuer, now, accessTokenTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &model.TokenResponse{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(accessTokenTTL.Seconds()),
+		Scope:       scope,
+	}
+
+	if containsScope(code.Scopes, model.ScopeOpenID) {
+		claims := jwt.IDTokenClaims{
+			Nonce:            code.Nonce,
+			AuthTime:         jwtlib.NewNumericDate(code.AuthTime),
+			RegisteredClaims: s.registeredClaims(subject, client.ClientID, now, idTokenTTL),
+		}
+		if containsScope(code.Scopes, model.ScopeEmail) {
+			claims.Email = authorized.Email
+		}
+		if containsScope(code.Scopes, model.ScopeProfile) {
+			claims.Name = authorized.Name
+		}
+		if response.IDToken, err = s.signer.Sign(claims); err != nil {
+			return nil, err
+		}
+	}
+
+	return response, nil
+}
+
+func (s *OAuthService) clientCredentials(client *model.Client, req model.TokenRequest) (*model.TokenResponse, error) {
+	scopes := strings.Fields(req.Scope)
+	if len(scopes) == 0 {
+		// Tanpa scope, berikan semua scope client selain scope OpenID yang butuh user
+		for _, scope := range client.Scopes {
+			if scope != model.ScopeOpenID && scope != model.ScopeProfile && scope != model.ScopeEmail {
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+	if !client.AllowsScopes(scopes) || containsScope(scopes, model.ScopeOpenID) {
+		return nil, model.NewProtocolError(http.StatusBadRequest, model.ErrorInvalidScope, "requested scope is not allowed for this client")
+	}
+
+	scope := strings.Join(scopes, " ")
+	accessToken, err := s.signer.Sign(jwt.AccessTokenClaims{
+		ClientID:         client.ClientID,
+		Scope:            scope,
+		RegisteredClaims: s.registeredClaims(client.ClientID, s.issuer, time.Now(), accessTokenTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.TokenResponse{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(accessTokenTTL.Seconds()),
+		Scope:       scope,
+	}, nil
+}
+
+// UserInfo mengembalikan claim user untuk access token hasil grant authorization_code
+func (s *OAuthService) UserInfo(accessToken string) (*model.UserInfo, error) {
+	invalidToken := model.NewProtocolError(http.StatusUnauthorized, model.ErrorInvalidToken, "access token is invalid or expired")
+
+	var claims jwt.AccessTokenClaims
+	if err := s.signer.Parse(accessToken, &claims, jwtlib.WithIssuer(s.issuer), jwtlib.WithAudience(s.issuer)); err != nil {
+		return nil, invalidToken
+	}
+
+	scopes := strings.Fields(claims.Scope)
+	if !containsScope(scopes, model.ScopeOpenID) {
+		return nil, invalidToken
+	}
+	userID, err := strconv.ParseUint(claims.Subject, 10, 32)
+	if err != nil {
+		return nil, invalidToken
+	}
+	authorized, err := s.userService.GetUserByID(uint(userID))
+	if err != nil || authorized.IsDisabled() {
+		return nil, invalidToken
+	}
+
+	info := &model.UserInfo{Subject: claims.Subject}
+	if containsScope(scopes, model.ScopeEmail) {
+		info.Email = authorized.Email
+	}
+	if containsScope(scopes, model.ScopeProfile) {
+		info.Name = authorized.Name
+	}
+	return info, nil
+}
+
+func (s *OAuthService) CreateClient(input model.CreateClientInput) (*model.CreatedClient, error) {
+	client, secret, err := model.NewClient(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Create(client).Error; err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"name":  client.Name,
+			"error": err.Error(),
+		}).Error("Gagal menyimpan client OAuth")
+		return nil, err
+	}
+
+	return &model.CreatedClient{Client: *client, ClientSecret: secret}, nil
+}
+
+func (s *OAuthService) ListClients() ([]model.Client, error) {
+	var clients []model.Client
+	if err := s.db.Order("id").Find(&clients).Error; err != nil {
+		return nil, err
+	}
+	return clients, nil
+}
+
+func (s *OAuthService) GetClient(id uint) (*model.Client, error) {
+	var client model.Client
+	if err := s.db.First(&client, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrOAuthClientNotFound.Wrap(err)
+		}
+		return nil, err
+	}
+	return &client, nil
+}
+
+// DeleteClient menghapus client beserta semua consent untuk client tersebut
+func (s *OAuthService) DeleteClient(id uint) error {
+	client, err := s.GetClient(id)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("client_id = ?", client.ClientID).Delete(&model.Consent{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(client).Error
+	})
+}
+
+func (s *OAuthService) ListConsents(userID uint) ([]model.Consent, error) {
+	var consents []model.Consent
+	if err := s.db.Where("user_id = ?", userID).Order("id").Find(&consents).Error; err != nil {
+		return nil, err
+	}
+	return consents, nil
+}
+
+func (s *OAuthService) RevokeConsent(userID, id uint) error {
+	result := s.db.Where("id = ? AND user_id = ?", id, userID).Delete(&model.Consent{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errs.ErrOAuthConsentNotFound
+	}
+	return nil
+}
+
+func (s *OAuthService) findClient(clientID string) (*model.Client, error) {
+	var client model.Client
+	if err := s.db.Where("client_id = ?", clientID).First(&client).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrOAuthUnknownClient.Wrap(err)
+		}
+		return nil, err
+	}
+	return &client, nil
+}
+
+func (s *OAuthService) registeredClaims(subject, audience string, now time.Time, ttl time.Duration) jwtlib.RegisteredClaims {
+	return jwtlib.RegisteredClaims{
+		Issuer:    s.issuer,
+		Subject:   subject,
+		Audience:  jwtlib.ClaimStrings{audience},
+		ExpiresAt: jwtlib.NewNumericDate(now.Add(ttl)),
+		IssuedAt:  jwtlib.NewNumericDate(now),
+		NotBefore: jwtlib.NewNumericDate(now),
+	}
+}
+
+// errorRedirect membuat URL redirect error ke client (RFC 6749 bagian 4.1.2.1)
+func errorRedirect(req model.AuthorizeRequest, code, description string) string {
+	params := url.Values{"error": {code}, "error_description": {description}}
+	if req.State != "" {
+		params.Set("state", req.State)
+	}
+	return withQuery(req.RedirectURI, params)
+}
+
+func withQuery(rawURL string, params url.Values) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	query := parsed.Query()
+	for name, values := range params {
+		query[name] = values
+	}
+	parsed.RawQuery = query.Encode()
+	return parsed.String()
+}
+
+func containsScope(scopes []string, scope string) bool {
+	for _, granted := range scopes {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
+
+func randomToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -0,0 +1,284 @@
+package oauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"boilerplate/internal/oauth/model"
+	"boilerplate/internal/testutil"
+	"boilerplate/internal/user"
+	userModel "boilerplate/internal/user/model"
+	"boilerplate/pkg/jwt"
+	"boilerplate/pkg/logger"
+	"boilerplate/pkg/mailer"
+	"boilerplate/pkg/password"
+	errs "boilerplate/shared/errors"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+const (
+	testIssuer   = "https://sso.example.com"
+	redirectURI  = "https://tool.example.com/callback"
+	codeVerifier = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+)
+
+type OAuthServiceTestSuite struct {
+	suite.Suite
+	ctx     context.Context
+	db      *gorm.DB
+	signer  *jwt.Signer
+	service *OAuthService
+	user    *userModel.User
+	client  *model.CreatedClient
+}
+
+func TestOAuthServiceSuite(t *testing.T) {
+	suite.Run(t, new(OAuthServiceTestSuite))
+}
+
+func (s *OAuthServiceTestSuite) SetupTest() {
+	s.ctx = context.Background()
+
+	db := testutil.NewDB(s.T(), &userModel.User{}, &model.Client{}, &model.Consent{})
+	s.db = db
+
+	redisClient := testutil.NewRedisClient(s.T())
+	var err error
+	s.signer, err = jwt.LoadSigner("")
+	s.Require().NoError(err)
+
+	log := logger.NewLogger()
+	userService := user.NewUserService(db, "test-secret", log, redisClient, password.DefaultPolicy(), mailer.NewLogMailer(log), nil, nil)
+	s.service = NewOAuthService(db, log, redisClient, userService, s.signer, testIssuer, "")
+
+	s.user, err = userModel.NewUser(userModel.RegisterInput{Name: "Jane Doe", Email: "jane@example.com", Password: "password123"})
+	s.Require().NoError(err)
+	s.Require().NoError(db.Create(s.user).Error)
+
+	s.client, err = s.service.CreateClient(model.CreateClientInput{
+		Name:         "Internal tool",
+		RedirectURIs: []string{redirectURI},
+		GrantTypes:   []string{model.GrantAuthorizationCode, model.GrantClientCredentials},
+		Scopes:       []string{model.ScopeOpenID, model.ScopeEmail, model.ScopeProfile, "users:read"},
+	})
+	s.Require().NoError(err)
+}
+
+func challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func (s *OAuthServiceTestSuite) authorizeRequest() model.AuthorizeRequest {
+	return model.AuthorizeRequest{
+		ResponseType:        "code",
+		ClientID:            s.client.ClientID,
+		RedirectURI:         redirectURI,
+		Scope:               "openid email profile",
+		State:               "xyz",
+		Nonce:               "n-0S6_WzA2Mj",
+		CodeChallenge:       challenge(codeVerifier),
+		CodeChallengeMethod: model.CodeChallengeS256,
+	}
+}
+
+// approve menjalankan consent dan mengembalikan query redirect ke client
+func (s *OAuthServiceTestSuite) approve(req model.AuthorizeRequest) url.Values {
+	result, err := s.service.Consent(s.ctx, s.user, model.ConsentInput{AuthorizeRequest: req, Approve: true})
+	s.Require().NoError(err)
+	redirect, err := url.Parse(result.RedirectTo)
+	s.Require().NoError(err)
+	return redirect.Query()
+}
+
+func (s *OAuthServiceTestSuite) exchange(code, verifier string) (*model.TokenResponse, error) {
+	return s.service.Token(s.ctx, model.TokenRequest{
+		GrantType:    model.GrantAuthorizationCode,
+		Code:         code,
+		RedirectURI:  redirectURI,
+		CodeVerifier: verifier,
+		ClientID:     s.client.ClientID,
+		ClientSecret: s.client.ClientSecret,
+	})
+}
+
+func (s *OAuthServiceTestSuite) requireProtocolError(err error, code string) {
+	var protocolErr *model.ProtocolError
+	s.Require().ErrorAs(err, &protocolErr)
+	s.Equal(code, protocolErr.Code)
+}
+
+func (s *OAuthServiceTestSuite) TestAuthorizationCodeFlow() {
+	result, err := s.service.Authorize(s.ctx, s.user, s.authorizeRequest())
+	s.Require().NoError(err)
+	s.True(result.ConsentRequired)
+	s.Equal("Internal tool", result.Client.Name)
+
+	query := s.approve(s.authorizeRequest())
+	s.Equal("xyz", query.Get("state"))
+	s.Require().NotEmpty(query.Get("code"))
+
+	token, err := s.exchange(query.Get("code"), codeVerifier)
+	s.Require().NoError(err)
+	s.Equal("Bearer", token.TokenType)
+	s.Equal("openid email profile", token.Scope)
+
+	// ID token dapat diverifikasi relying party memakai JWKS
+	keySet := &oidc.StaticKeySet{PublicKeys: []crypto.PublicKey{s.signer.PublicKey()}}
+	verifier := oidc.NewVerifier(testIssuer, keySet, &oidc.Config{ClientID: s.client.ClientID})
+	idToken, err := verifier.Verify(s.ctx, token.IDToken)
+	s.Require().NoError(err)
+	s.Equal("n-0S6_WzA2Mj", idToken.Nonce)
+
+	var claims struct {
+		Email string `json:"email"`
+		Name  string `json:"name"`
+	}
+	s.Require().NoError(idToken.Claims(&claims))
+	s.Equal("jane@example.com", claims.Email)
+	s.Equal("Jane Doe", claims.Name)
+
+	info, err := s.service.UserInfo(token.AccessToken)
+	s.Require().NoError(err)
+	s.Equal(idToken.Subject, info.Subject)
+	s.Equal("jane@example.com", info.Email)
+
+	// Consent tersimpan, authorization berikutnya langsung redirect
+	result, err = s.service.Authorize(s.ctx, s.user, s.authorizeRequest())
+	s.Require().NoError(err)
+	s.False(result.ConsentRequired)
+	s.Contains(result.RedirectTo, "code=")
+}
+
+func (s *OAuthServiceTestSuite) TestCodeIsSingleUseAndBoundToVerifier() {
+	query := s.approve(s.authorizeRequest())
+
+	_, err := s.exchange(query.Get("code"), "wrong-verifier-wrong-verifier-wrong-verifier")
+	s.requireProtocolError(err, model.ErrorInvalidGrant)
+
+	// Code sudah terpakai walaupun penukaran pertama gagal
+	_, err = s.exchange(query.Get("code"), codeVerifier)
+	s.requireProtocolError(err, model.ErrorInvalidGrant)
+}
+
+func (s *OAuthServiceTestSuite) TestConsentDenied() {
+	result, err := s.service.Consent(s.ctx, s.user, model.ConsentInput{AuthorizeRequest: s.authorizeRequest()})
+	s.Require().NoError(err)
+
+	redirect, err := url.Parse(result.RedirectTo)
+	s.Require().NoError(err)
+	s.Equal(model.ErrorAccessDenied, redirect.Query().Get("error"))
+	s.Equal("xyz", redirect.Query().Get("state"))
+
+	var count int64
+	s.db.Model(&model.Consent{}).Count(&count)
+	s.Zero(count)
+}
+
+func (s *OAuthServiceTestSuite) TestAuthorizeValidation() {
+	req := s.authorizeRequest()
+	req.RedirectURI = "https://evil.example.com/callback"
+	_, err := s.service.Authorize(s.ctx, s.user, req)
+	s.ErrorIs(err, errs.ErrOAuthInvalidRedirectURI)
+
+	req = s.authorizeRequest()
+	req.ClientID = "unknown"
+	_, err = s.service.Authorize(s.ctx, s.user, req)
+	s.ErrorIs(err, errs.ErrOAuthUnknownClient)
+
+	tests := []struct {
+		name   string
+		mutate func(*model.AuthorizeRequest)
+		code   string
+	}{
+		{name: "missing PKCE", mutate: func(r *model.AuthorizeRequest) { r.CodeChallenge = "" }, code: model.ErrorInvalidRequest},
+		{name: "plain PKCE", mutate: func(r *model.AuthorizeRequest) { r.CodeChallengeMethod = "plain" }, code: model.ErrorInvalidRequest},
+		{name: "unregistered scope", mutate: func(r *model.AuthorizeRequest) { r.Scope = "openid categories:write" }, code: model.ErrorInvalidScope},
+		{name: "implicit flow", mutate: func(r *model.AuthorizeRequest) { r.ResponseType = "token" }, code: model.ErrorUnsupportedResponseType},
+	}
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			req := s.authorizeRequest()
+			tt.mutate(&req)
+			result, err := s.service.Authorize(s.ctx, s.user, req)
+			s.Require().NoError(err)
+
+			redirect, err := url.Parse(result.RedirectTo)
+			s.Require().NoError(err)
+			s.Equal(tt.code, redirect.Query().Get("error"))
+		})
+	}
+}
+
+func (s *OAuthServiceTestSuite) TestClientCredentials() {
+	token, err := s.service.Token(s.ctx, model.TokenRequest{
+		GrantType:    model.GrantClientCredentials,
+		ClientID:     s.client.ClientID,
+		ClientSecret: s.client.ClientSecret,
+	})
+	s.Require().NoError(err)
+	s.Equal("users:read", token.Scope)
+	s.Empty(token.IDToken)
+
+	var claims jwt.AccessTokenClaims
+	s.Require().NoError(s.signer.Parse(token.AccessToken, &claims))
+	s.Equal(s.client.ClientID, claims.Subject)
+
+	// Token tanpa user tidak bisa dipakai di /userinfo
+	_, err = s.service.UserInfo(token.AccessToken)
+	s.requireProtocolError(err, model.ErrorInvalidToken)
+
+	_, err = s.service.Token(s.ctx, model.TokenRequest{
+		GrantType:    model.GrantClientCredentials,
+		ClientID:     s.client.ClientID,
+		ClientSecret: s.client.ClientSecret,
+		Scope:        "openid",
+	})
+	s.requireProtocolError(err, model.ErrorInvalidScope)
+
+	_, err = s.service.Token(s.ctx, model.TokenRequest{
+		GrantType:    model.GrantClientCredentials,
+		ClientID:     s.client.ClientID,
+		ClientSecret: "wrong",
+	})
+	s.requireProtocolError(err, model.ErrorInvalidClient)
+
+	var protocolErr *model.ProtocolError
+	_, err = s.service.Token(s.ctx, model.TokenRequest{GrantType: "password", ClientID: s.client.ClientID, ClientSecret: s.client.ClientSecret})
+	s.Require().ErrorAs(err, &protocolErr)
+	s.Equal(model.ErrorUnsupportedGrantType, protocolErr.Code)
+	s.Equal(http.StatusBadRequest, protocolErr.Status)
+}
+
+func (s *OAuthServiceTestSuite) TestRevokeConsent() {
+	s.approve(s.authorizeRequest())
+
+	consents, err := s.service.ListConsents(s.user.ID)
+	s.Require().NoError(err)
+	s.Require().Len(consents, 1)
+
+	s.ErrorIs(s.service.RevokeConsent(s.user.ID+1, consents[0].ID), errs.ErrOAuthConsentNotFound)
+	s.Require().NoError(s.service.RevokeConsent(s.user.ID, consents[0].ID))
+
+	result, err := s.service.Authorize(s.ctx, s.user, s.authorizeRequest())
+	s.Require().NoError(err)
+	s.True(result.ConsentRequired)
+}
+
+func (s *OAuthServiceTestSuite) TestDiscoveryAdvertisesAuthorizationEndpoint() {
+	// tanpa halaman frontend, authorization_endpoint yang wajib ada mengarah ke /authorize
+	discovery := s.service.Discovery()
+	s.Equal(testIssuer+"/authorize", discovery.AuthorizationEndpoint)
+	s.Equal(testIssuer+"/token", discovery.TokenEndpoint)
+
+	service := NewOAuthService(s.db, logger.NewLogger(), nil, nil, s.signer, testIssuer, "https://app.example.com/oauth/authorize")
+	s.Equal("https://app.example.com/oauth/authorize", service.Discovery().AuthorizationEndpoint)
+}
@@ -0,0 +1,32 @@
+package oauth
+
+import (
+	"context"
+
+	"boilerplate/internal/oauth/model"
+
+	"gorm.io/gorm"
+)
+
+// PrivacyHook mengekspor consent OAuth user dan menghapusnya saat erasure
+type PrivacyHook struct{}
+
+func NewPrivacyHook() *PrivacyHook {
+	return &PrivacyHook{}
+}
+
+func (h *PrivacyHook) Name() string {
+	return "oauth_consents"
+}
+
+func (h *PrivacyHook) Export(ctx context.Context, db *gorm.DB, userID uint) (interface{}, error) {
+	var consents []model.Consent
+	if err := db.WithContext(ctx).Where("user_id = ?", userID).Order("id").Find(&consents).Error; err != nil {
+		return nil, err
+	}
+	return consents, nil
+}
+
+func (h *PrivacyHook) Erase(ctx context.Context, tx *gorm.DB, userID uint) error {
+	return tx.WithContext(ctx).Where("user_id = ?", userID).Delete(&model.Consent{}).Error
+}
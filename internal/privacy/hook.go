@@ -0,0 +1,20 @@
+package privacy
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// Hook adalah kontribusi satu modul ke export dan erasure data user. Modul baru
+// yang menyimpan data milik user cukup mengimplementasikan Hook di package-nya
+// sendiri lalu mendaftarkannya di container.
+type Hook interface {
+	// Name adalah key modul di file export, mis. "api_keys"
+	Name() string
+	// Export mengembalikan data user yang dapat di-marshal ke JSON
+	Export(ctx context.Context, db *gorm.DB, userID uint) (interface{}, error)
+	// Erase menghapus atau menganonimkan data user di dalam transaksi erasure.
+	// Baris yang masih direferensikan modul lain harus dianonimkan, bukan dihapus.
+	Erase(ctx context.Context, tx *gorm.DB, userID uint) error
+}
@@ -0,0 +1,98 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// Status export data user
+const (
+	ExportStatusPending   = "pending"
+	ExportStatusRunning   = "running"
+	ExportStatusCompleted = "completed"
+	ExportStatusFailed    = "failed"
+)
+
+// Format file export
+const (
+	ExportFormatJSON = "json"
+	ExportFormatZIP  = "zip"
+)
+
+// Export adalah job export seluruh data user (GDPR art. 15/20). File hasil export
+// disimpan di EXPORT_DIR dan dapat diunduh sampai ExpiresAt.
+type Export struct {
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	UserID      uint       `json:"user_id" gorm:"index"`
+	Format      string     `json:"format" gorm:"size:10"`
+	Status      string     `json:"status" gorm:"size:20;index"`
+	Path        string     `json:"-" gorm:"size:255"`
+	Size        int64      `json:"size"`
+	Error       string     `json:"error,omitempty" gorm:"size:255"`
+	CompletedAt *time.Time `json:"completed_at"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+func (Export) TableName() string {
+	return "privacy_exports"
+}
+
+// DTO: Request export input
+type ExportInput struct {
+	Format string `json:"format" validate:"omitempty,oneof=json zip"`
+}
+
+// Archive adalah isi file export; Modules berisi data per modul yang didaftarkan lewat hook
+type Archive struct {
+	UserID     uint                   `json:"user_id"`
+	ExportedAt time.Time              `json:"exported_at"`
+	Modules    map[string]interface{} `json:"modules"`
+}
+
+// Factory: Create new pending export
+func NewExport(userID uint, input ExportInput) *Export {
+	format := input.Format
+	if format == "" {
+		format = ExportFormatJSON
+	}
+	return &Export{
+		UserID: userID,
+		Format: format,
+		Status: ExportStatusPending,
+	}
+}
+
+// IsActive reports whether the export is still being produced
+func (e *Export) IsActive() bool {
+	return e.Status == ExportStatusPending || e.Status == ExportStatusRunning
+}
+
+// IsExpired reports whether the file may no longer be downloaded
+func (e *Export) IsExpired(now time.Time) bool {
+	return e.ExpiresAt != nil && !now.Before(*e.ExpiresAt)
+}
+
+// Complete menandai export selesai dengan file yang dapat diunduh sampai ttl habis
+func (e *Export) Complete(path string, size int64, now time.Time, ttl time.Duration) {
+	expiresAt := now.Add(ttl)
+	e.Status = ExportStatusCompleted
+	e.Path = path
+	e.Size = size
+	e.CompletedAt = &now
+	e.ExpiresAt = &expiresAt
+}
+
+// FileName adalah nama file saat diunduh
+func (e *Export) FileName() string {
+	return fmt.Sprintf("export-user-%d-%s.%s", e.UserID, e.CreatedAt.UTC().Format("20060102"), e.Format)
+}
+
+// ContentType adalah content type file saat diunduh
+func (e *Export) ContentType() string {
+	if e.Format == ExportFormatZIP {
+		return "application/zip"
+	}
+	return "application/json"
+}
@@ -0,0 +1,42 @@
+package model
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// Tombstone mencatat bahwa data user telah dihapus (right to erasure). Email tidak
+// disimpan; EmailHash memungkinkan pemeriksaan ulang, mis. setelah restore backup,
+// tanpa menyimpan PII.
+type Tombstone struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"uniqueIndex"`
+	EmailHash string    `json:"email_hash" gorm:"size:64;index"`
+	ErasedBy  uint      `json:"erased_by"`
+	Modules   []string  `json:"modules" gorm:"serializer:json"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (Tombstone) TableName() string {
+	return "erasure_tombstones"
+}
+
+// Factory: Create tombstone for an erased user
+func NewTombstone(userID uint, email, secret string, erasedBy uint, modules []string) *Tombstone {
+	return &Tombstone{
+		UserID:    userID,
+		EmailHash: HashEmail(email, secret),
+		ErasedBy:  erasedBy,
+		Modules:   modules,
+	}
+}
+
+// HashEmail returns the HMAC-SHA256 of the normalized email
+func HashEmail(email, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strings.ToLower(strings.TrimSpace(email))))
+	return hex.EncodeToString(mac.Sum(nil))
+}
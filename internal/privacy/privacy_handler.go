@@ -0,0 +1,104 @@
+package privacy
+
+import (
+	"net/http"
+	"strconv"
+
+	"boilerplate/internal/privacy/model"
+	"boilerplate/pkg/response"
+	errs "boilerplate/shared/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+type PrivacyHandler struct {
+	privacyService PrivacyServiceInterface
+}
+
+func NewPrivacyHandler(privacyService PrivacyServiceInterface) *PrivacyHandler {
+	return &PrivacyHandler{
+		privacyService: privacyService,
+	}
+}
+
+// RequestExport memulai export data user yang sedang login
+func (h *PrivacyHandler) RequestExport(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+
+	var input model.ExportInput
+	if err := c.Bind(&input); err != nil {
+		return errs.ErrInvalidPayload.Wrap(err)
+	}
+
+	if err := c.Validate(&input); err != nil {
+		return err
+	}
+
+	export, err := h.privacyService.RequestExport(userID, input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, http.StatusAccepted, "Export started, poll its status until it is completed", export)
+}
+
+func (h *PrivacyHandler) GetExport(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return errs.ErrInvalidID.Wrap(err)
+	}
+
+	export, err := h.privacyService.GetExport(userID, uint(id))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, http.StatusOK, "Export retrieved successfully", export)
+}
+
+func (h *PrivacyHandler) DownloadExport(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return errs.ErrInvalidID.Wrap(err)
+	}
+
+	export, err := h.privacyService.OpenExport(userID, uint(id))
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, export.ContentType())
+	return c.Attachment(export.Path, export.FileName())
+}
+
+// DeleteAccount menghapus data pribadi user yang sedang login
+func (h *PrivacyHandler) DeleteAccount(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+
+	if _, err := h.privacyService.Erase(userID, userID); err != nil {
+		return err
+	}
+
+	return response.Success(c, http.StatusOK, "Account deleted successfully", nil)
+}
+
+// EraseUser menghapus data pribadi user atas permintaan admin
+func (h *PrivacyHandler) EraseUser(c echo.Context) error {
+	adminID := c.Get("user_id").(uint)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return errs.ErrInvalidID.Wrap(err)
+	}
+
+	tombstone, err := h.privacyService.Erase(uint(id), adminID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, http.StatusOK, "User data erased successfully", tombstone)
+}
@@ -0,0 +1,355 @@
+package privacy
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"boilerplate/internal/privacy/model"
+	"boilerplate/internal/user"
+	userModel "boilerplate/internal/user/model"
+	"boilerplate/pkg/events"
+	"boilerplate/pkg/logger"
+	"boilerplate/shared/constants"
+	errs "boilerplate/shared/errors"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PrivacyServiceInterface mendefinisikan kontrak untuk PrivacyService
+type PrivacyServiceInterface interface {
+	RequestExport(userID uint, input model.ExportInput) (*model.Export, error)
+	GetExport(userID, exportID uint) (*model.Export, error)
+	OpenExport(userID, exportID uint) (*model.Export, error)
+	PurgeExpiredExports(ctx context.Context) (int, error)
+	Erase(userID, erasedBy uint) (*model.Tombstone, error)
+}
+
+type PrivacyService struct {
+	db          *gorm.DB
+	logger      logger.Logger
+	userService user.UserServiceInterface
+	secret      string
+	exportDir   string
+	hooks       []Hook
+	running     sync.WaitGroup
+}
+
+// NewPrivacyService membuat service export dan erasure data user. hooks dijalankan
+// berurutan; secret dipakai untuk hash email di tombstone.
+func NewPrivacyService(db *gorm.DB, logger logger.Logger, userService user.UserServiceInterface, secret, exportDir string, hooks ...Hook) *PrivacyService {
+	if secret == "" {
+		panic("privacy secret is required")
+	}
+	if exportDir == "" {
+		panic("export directory is required")
+	}
+
+	return &PrivacyService{
+		db:          db,
+		logger:      logger,
+		userService: userService,
+		secret:      secret,
+		exportDir:   exportDir,
+		hooks:       hooks,
+	}
+}
+
+// RequestExport membuat job export dan menjalankannya di background. Hanya satu
+// export per user yang dapat berjalan bersamaan.
+func (s *PrivacyService) RequestExport(userID uint, input model.ExportInput) (*model.Export, error) {
+	var active int64
+	if err := s.db.Model(&model.Export{}).
+		Where("user_id = ? AND status IN ?", userID, []string{model.ExportStatusPending, model.ExportStatusRunning}).
+		Count(&active).Error; err != nil {
+		return nil, err
+	}
+	if active > 0 {
+		return nil, errs.ErrExportInProgress
+	}
+
+	export := model.NewExport(userID, input)
+	if err := s.db.Create(export).Error; err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"user_id": userID,
+			"error":   err.Error(),
+		}).Error("Gagal menyimpan job export")
+		return nil, err
+	}
+
+	s.running.Add(1)
+	go func(export model.Export) {
+		defer s.running.Done()
+		s.runExport(context.Background(), &export)
+	}(*export)
+
+	return export, nil
+}
+
+func (s *PrivacyService) GetExport(userID, exportID uint) (*model.Export, error) {
+	var export model.Export
+	if err := s.db.Where("id = ? AND user_id = ?", exportID, userID).First(&export).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrExportNotFound.Wrap(err)
+		}
+		return nil, err
+	}
+	return &export, nil
+}
+
+// OpenExport mengembalikan export yang siap diunduh
+func (s *PrivacyService) OpenExport(userID, exportID uint) (*model.Export, error) {
+	export, err := s.GetExport(userID, exportID)
+	if err != nil {
+		return nil, err
+	}
+	if export.Status != model.ExportStatusCompleted {
+		return nil, errs.ErrExportNotReady
+	}
+	if export.IsExpired(time.Now()) {
+		return nil, errs.ErrExportExpired
+	}
+	return export, nil
+}
+
+// PurgeExpiredExports menghapus file export yang sudah kedaluwarsa. Baris export
+// tetap disimpan agar status expired dapat ditampilkan ke user.
+func (s *PrivacyService) PurgeExpiredExports(ctx context.Context) (int, error) {
+	var exports []model.Export
+	if err := s.db.WithContext(ctx).
+		Where("status = ? AND expires_at <= ? AND path <> ?", model.ExportStatusCompleted, time.Now(), "").
+		Find(&exports).Error; err != nil {
+		return 0, err
+	}
+
+	for i := range exports {
+		s.removeFile(exports[i].Path)
+		if err := s.db.WithContext(ctx).Model(&exports[i]).Update("path", "").Error; err != nil {
+			return i, err
+		}
+	}
+	return len(exports), nil
+}
+
+// Erase menghapus data pribadi user: hook setiap modul dijalankan, baris user
+// dianonimkan, tombstone dicatat dan sesi user dicabut. erasedBy adalah user yang
+// meminta erasure (user itu sendiri atau admin).
+func (s *PrivacyService) Erase(userID, erasedBy uint) (*model.Tombstone, error) {
+	ctx := context.Background()
+	var tombstone *model.Tombstone
+	var exports []model.Export
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var target userModel.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&target, userID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errs.ErrUserNotFound.Wrap(err)
+			}
+			return err
+		}
+		if target.IsErased() {
+			return errs.ErrUserErased
+		}
+		if target.IsAdmin() && !target.IsDisabled() {
+			var admins int64
+			if err := tx.Model(&userModel.User{}).
+				Where("role = ? AND disabled_at IS NULL AND id <> ?", constants.RoleAdmin, userID).
+				Count(&admins).Error; err != nil {
+				return err
+			}
+			if admins == 0 {
+				return errs.ErrLastAdmin
+			}
+		}
+
+		modules := make([]string, 0, len(s.hooks))
+		for _, hook := range s.hooks {
+			if err := hook.Erase(ctx, tx, userID); err != nil {
+				return fmt.Errorf("erase %s: %w", hook.Name(), err)
+			}
+			modules = append(modules, hook.Name())
+		}
+
+		// file export memuat data pribadi sehingga ikut dihapus
+		if err := tx.Where("user_id = ?", userID).Find(&exports).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&model.Export{}).Error; err != nil {
+			return err
+		}
+
+		tombstone = model.NewTombstone(userID, target.Email, s.secret, erasedBy, modules)
+		if err := tx.Model(&target).Updates(target.Anonymize(time.Now())).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(tombstone).Error; err != nil {
+			return err
+		}
+		return events.Record(tx, userModel.UserErased{UserID: userID, ErasedBy: erasedBy})
+	})
+	if err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"user_id":   userID,
+			"erased_by": erasedBy,
+			"error":     err.Error(),
+		}).Error("Gagal menghapus data user")
+		return nil, err
+	}
+
+	for _, export := range exports {
+		s.removeFile(export.Path)
+	}
+	// akun sudah nonaktif sehingga token yang gagal dicabut tetap ditolak middleware
+	if err := s.userService.Logout(userID); err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"user_id": userID,
+			"error":   err.Error(),
+		}).Error("Gagal mencabut token user yang dihapus")
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"user_id":   userID,
+		"erased_by": erasedBy,
+		"modules":   tombstone.Modules,
+	}).Info("Data user dihapus")
+	return tombstone, nil
+}
+
+// runExport mengumpulkan data dari setiap hook lalu menulis file export
+func (s *PrivacyService) runExport(ctx context.Context, export *model.Export) {
+	fields := logrus.Fields{"export_id": export.ID, "user_id": export.UserID}
+	if err := s.db.Model(export).Update("status", model.ExportStatusRunning).Error; err != nil {
+		fields["error"] = err.Error()
+		s.logger.WithFields(fields).Error("Gagal memulai job export")
+		return
+	}
+
+	path, size, err := s.writeExport(ctx, export)
+	if err != nil {
+		fields["error"] = err.Error()
+		s.logger.WithFields(fields).Error("Gagal membuat export data user")
+		if err := s.db.Model(export).Updates(map[string]interface{}{
+			"status": model.ExportStatusFailed,
+			"error":  "export failed, please try again",
+		}).Error; err != nil {
+			s.logger.WithFields(fields).Error("Gagal menandai job export gagal")
+		}
+		return
+	}
+
+	export.Complete(path, size, time.Now(), constants.ExportTTL)
+	if err := s.db.Model(export).Select("status", "path", "size", "completed_at", "expires_at").Updates(export).Error; err != nil {
+		fields["error"] = err.Error()
+		s.logger.WithFields(fields).Error("Gagal menyimpan hasil export")
+		s.removeFile(path)
+	}
+}
+
+func (s *PrivacyService) writeExport(ctx context.Context, export *model.Export) (string, int64, error) {
+	archive := model.Archive{
+		UserID:     export.UserID,
+		ExportedAt: time.Now().UTC(),
+		Modules:    make(map[string]interface{}, len(s.hooks)),
+	}
+	for _, hook := range s.hooks {
+		data, err := hook.Export(ctx, s.db, export.UserID)
+		if err != nil {
+			return "", 0, fmt.Errorf("export %s: %w", hook.Name(), err)
+		}
+		archive.Modules[hook.Name()] = data
+	}
+
+	if err := os.MkdirAll(s.exportDir, 0o700); err != nil {
+		return "", 0, err
+	}
+	// nama file acak agar file export tidak dapat ditebak dari ID
+	suffix := make([]byte, 16)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", 0, err
+	}
+	path := filepath.Join(s.exportDir, fmt.Sprintf("%d-%s.%s", export.ID, hex.EncodeToString(suffix), export.Format))
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return "", 0, err
+	}
+	if export.Format == model.ExportFormatZIP {
+		err = writeZIP(file, archive)
+	} else {
+		encoder := json.NewEncoder(file)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(archive)
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		s.removeFile(path)
+		return "", 0, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", 0, err
+	}
+	return path, info.Size(), nil
+}
+
+// writeZIP menulis manifest.json dan satu file JSON per modul
+func writeZIP(file *os.File, archive model.Archive) error {
+	zw := zip.NewWriter(file)
+
+	modules := make([]string, 0, len(archive.Modules))
+	for name := range archive.Modules {
+		modules = append(modules, name)
+	}
+	sort.Strings(modules)
+
+	manifest := map[string]interface{}{
+		"user_id":     archive.UserID,
+		"exported_at": archive.ExportedAt,
+		"modules":     modules,
+	}
+	if err := writeZIPEntry(zw, "manifest.json", manifest); err != nil {
+		return err
+	}
+	for _, name := range modules {
+		if err := writeZIPEntry(zw, name+".json", archive.Modules[name]); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func writeZIPEntry(zw *zip.Writer, name string, data interface{}) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(data)
+}
+
+func (s *PrivacyService) removeFile(path string) {
+	if path == "" {
+		return
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		s.logger.WithFields(logrus.Fields{
+			"path":  path,
+			"error": err.Error(),
+		}).Error("Gagal menghapus file export")
+	}
+}
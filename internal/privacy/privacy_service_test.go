@@ -0,0 +1,228 @@
+package privacy
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"boilerplate/internal/apikey"
+	apiKeyModel "boilerplate/internal/apikey/model"
+	"boilerplate/internal/category"
+	categoryModel "boilerplate/internal/category/model"
+	"boilerplate/internal/identity"
+	identityModel "boilerplate/internal/identity/model"
+	"boilerplate/internal/invite"
+	inviteModel "boilerplate/internal/invite/model"
+	"boilerplate/internal/oauth"
+	oauthModel "boilerplate/internal/oauth/model"
+	"boilerplate/internal/privacy/model"
+	"boilerplate/internal/testutil"
+	"boilerplate/internal/user"
+	userModel "boilerplate/internal/user/model"
+	"boilerplate/pkg/events"
+	"boilerplate/pkg/logger"
+	"boilerplate/pkg/mailer"
+	"boilerplate/pkg/password"
+	"boilerplate/shared/constants"
+	errs "boilerplate/shared/errors"
+
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type PrivacyServiceTestSuite struct {
+	suite.Suite
+	db          *gorm.DB
+	userService *user.UserService
+	service     *PrivacyService
+	exportDir   string
+	admin       *userModel.User
+	user        *userModel.User
+}
+
+func TestPrivacyServiceSuite(t *testing.T) {
+	suite.Run(t, new(PrivacyServiceTestSuite))
+}
+
+func (s *PrivacyServiceTestSuite) SetupTest() {
+	db := testutil.NewDB(s.T(),
+		&userModel.User{}, &userModel.PasswordHistory{}, &userModel.LoginEvent{},
+		&categoryModel.Category{}, &apiKeyModel.APIKey{}, &identityModel.UserIdentity{},
+		&oauthModel.Consent{}, &inviteModel.Invite{}, &model.Export{}, &model.Tombstone{}, &events.OutboxMessage{},
+	)
+	s.db = db
+
+	log := logger.NewLogger()
+	redisClient := testutil.NewRedisClient(s.T())
+	s.userService = user.NewUserService(db, "test-secret", log, redisClient, password.DefaultPolicy(), mailer.NewLogMailer(log), nil, nil)
+	s.exportDir = s.T().TempDir()
+	s.service = NewPrivacyService(db, log, s.userService, "test-secret", s.exportDir,
+		user.NewPrivacyHook(),
+		category.NewPrivacyHook(),
+		apikey.NewPrivacyHook(),
+		identity.NewPrivacyHook(),
+		oauth.NewPrivacyHook(),
+		invite.NewPrivacyHook(),
+	)
+
+	var err error
+	s.admin, err = s.userService.CreateUser(userModel.AdminCreateUserInput{
+		Name: "Admin", Email: "admin@example.com", Password: "password123", Role: constants.RoleAdmin,
+	})
+	s.Require().NoError(err)
+	s.user, err = s.userService.CreateUser(userModel.AdminCreateUserInput{
+		Name: "Jane Doe", Email: "jane@example.com", Password: "password123", Role: constants.RoleUser,
+	})
+	s.Require().NoError(err)
+
+	s.Require().NoError(db.Create(&categoryModel.Category{Name: "Books", CreatedBy: s.user.ID, Version: 1}).Error)
+	s.Require().NoError(db.Create(&apiKeyModel.APIKey{UserID: s.user.ID, Name: "ci", KeyID: "key1", SecretHash: "hash"}).Error)
+	s.Require().NoError(db.Create(&identityModel.UserIdentity{UserID: s.user.ID, Provider: "google", Subject: "sub-1", Email: "jane@example.com"}).Error)
+	s.Require().NoError(db.Create(&oauthModel.Consent{UserID: s.user.ID, ClientID: "app", Scopes: []string{"openid"}}).Error)
+	s.Require().NoError(db.Create(&inviteModel.Invite{Code: "code1", Email: "jane@example.com", Role: constants.RoleUser, InvitedBy: s.admin.ID, ExpiresAt: time.Now().Add(time.Hour)}).Error)
+}
+
+// export menjalankan export dan menunggu job background selesai
+func (s *PrivacyServiceTestSuite) export(format string) *model.Export {
+	started, err := s.service.RequestExport(s.user.ID, model.ExportInput{Format: format})
+	s.Require().NoError(err)
+	s.Equal(model.ExportStatusPending, started.Status)
+	s.service.running.Wait()
+
+	export, err := s.service.OpenExport(s.user.ID, started.ID)
+	s.Require().NoError(err)
+	s.Equal(model.ExportStatusCompleted, export.Status)
+	s.NotNil(export.ExpiresAt)
+	return export
+}
+
+func (s *PrivacyServiceTestSuite) TestExportJSON() {
+	_, err := s.userService.Login(userModel.LoginInput{Email: "jane@example.com", Password: "password123"}, userModel.ClientInfo{IP: "203.0.113.1"})
+	s.Require().NoError(err)
+
+	export := s.export(model.ExportFormatJSON)
+	raw, err := os.ReadFile(export.Path)
+	s.Require().NoError(err)
+	s.Equal(int64(len(raw)), export.Size)
+	s.NotContains(string(raw), s.user.Password)
+
+	var archive struct {
+		UserID  uint                       `json:"user_id"`
+		Modules map[string]json.RawMessage `json:"modules"`
+	}
+	s.Require().NoError(json.Unmarshal(raw, &archive))
+	s.Equal(s.user.ID, archive.UserID)
+	s.Len(archive.Modules, 6)
+
+	var userData struct {
+		Profile     userModel.User         `json:"profile"`
+		LoginEvents []userModel.LoginEvent `json:"login_events"`
+	}
+	s.Require().NoError(json.Unmarshal(archive.Modules["user"], &userData))
+	s.Equal("jane@example.com", userData.Profile.Email)
+	s.Len(userData.LoginEvents, 1)
+
+	var categories []categoryModel.Category
+	s.Require().NoError(json.Unmarshal(archive.Modules["categories"], &categories))
+	s.Len(categories, 1)
+}
+
+func (s *PrivacyServiceTestSuite) TestExportZIP() {
+	export := s.export(model.ExportFormatZIP)
+	s.Equal("application/zip", export.ContentType())
+
+	reader, err := zip.OpenReader(export.Path)
+	s.Require().NoError(err)
+	defer reader.Close()
+
+	var names []string
+	for _, file := range reader.File {
+		names = append(names, file.Name)
+	}
+	s.Equal([]string{
+		"manifest.json", "api_keys.json", "categories.json", "identities.json",
+		"invites.json", "oauth_consents.json", "user.json",
+	}, names)
+}
+
+func (s *PrivacyServiceTestSuite) TestExportStates() {
+	running := &model.Export{UserID: s.user.ID, Format: model.ExportFormatJSON, Status: model.ExportStatusRunning}
+	s.Require().NoError(s.db.Create(running).Error)
+
+	_, err := s.service.RequestExport(s.user.ID, model.ExportInput{})
+	s.ErrorIs(err, errs.ErrExportInProgress)
+	_, err = s.service.OpenExport(s.user.ID, running.ID)
+	s.ErrorIs(err, errs.ErrExportNotReady)
+	_, err = s.service.GetExport(s.admin.ID, running.ID)
+	s.ErrorIs(err, errs.ErrExportNotFound)
+
+	path := filepath.Join(s.exportDir, "expired.json")
+	s.Require().NoError(os.WriteFile(path, []byte("{}"), 0o600))
+	expired := &model.Export{UserID: s.admin.ID, Format: model.ExportFormatJSON}
+	expired.Complete(path, 2, time.Now().Add(-2*constants.ExportTTL), constants.ExportTTL)
+	s.Require().NoError(s.db.Create(expired).Error)
+
+	_, err = s.service.OpenExport(s.admin.ID, expired.ID)
+	s.ErrorIs(err, errs.ErrExportExpired)
+
+	purged, err := s.service.PurgeExpiredExports(context.Background())
+	s.Require().NoError(err)
+	s.Equal(1, purged)
+	s.NoFileExists(path)
+}
+
+func (s *PrivacyServiceTestSuite) TestErase() {
+	_, err := s.userService.Login(userModel.LoginInput{Email: "jane@example.com", Password: "password123"}, userModel.ClientInfo{})
+	s.Require().NoError(err)
+	export := s.export(model.ExportFormatJSON)
+
+	tombstone, err := s.service.Erase(s.user.ID, s.user.ID)
+	s.Require().NoError(err)
+	s.Equal(model.HashEmail("Jane@Example.com", "test-secret"), tombstone.EmailHash)
+	s.Equal([]string{"user", "categories", "api_keys", "identities", "oauth_consents", "invites"}, tombstone.Modules)
+
+	var erased userModel.User
+	s.Require().NoError(s.db.First(&erased, s.user.ID).Error)
+	s.True(erased.IsErased())
+	s.True(erased.IsDisabled())
+	s.Equal(userModel.ErasedName, erased.Name)
+	s.Equal(userModel.ErasedEmail(s.user.ID), erased.Email)
+	s.Empty(erased.Password)
+
+	// kategori tetap ada dan masih menunjuk ke baris user yang dianonimkan
+	var category categoryModel.Category
+	s.Require().NoError(s.db.Where("created_by = ?", s.user.ID).First(&category).Error)
+
+	for _, table := range []interface{}{&apiKeyModel.APIKey{}, &identityModel.UserIdentity{}, &oauthModel.Consent{}, &userModel.LoginEvent{}, &model.Export{}} {
+		var count int64
+		s.Require().NoError(s.db.Model(table).Where("user_id = ?", s.user.ID).Count(&count).Error)
+		s.Zero(count, "%T", table)
+	}
+	var invite inviteModel.Invite
+	s.Require().NoError(s.db.First(&invite).Error)
+	s.Equal(userModel.ErasedEmail(s.user.ID), invite.Email)
+	s.NotNil(invite.RevokedAt)
+
+	s.NoFileExists(export.Path)
+	_, err = s.userService.GetStoredToken(context.Background(), s.user.ID)
+	s.Error(err)
+	_, err = s.userService.Login(userModel.LoginInput{Email: "jane@example.com", Password: "password123"}, userModel.ClientInfo{})
+	s.ErrorIs(err, errs.ErrInvalidCredentials)
+	_, err = s.userService.EnableUser(s.user.ID)
+	s.ErrorIs(err, errs.ErrUserErased)
+
+	_, err = s.service.Erase(s.user.ID, s.admin.ID)
+	s.ErrorIs(err, errs.ErrUserErased)
+}
+
+func (s *PrivacyServiceTestSuite) TestEraseLastAdmin() {
+	_, err := s.service.Erase(s.admin.ID, s.admin.ID)
+	s.ErrorIs(err, errs.ErrLastAdmin)
+
+	_, err = s.service.Erase(9999, s.admin.ID)
+	s.ErrorIs(err, errs.ErrUserNotFound)
+}
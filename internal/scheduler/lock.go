@@ -0,0 +1,92 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Hasil acquire selain fencing token
+const (
+	tickClaimed int64 = 0
+	lockBusy    int64 = -1
+)
+
+// acquireScript mengklaim satu tick task. Tick yang sudah diklaim replica lain
+// dilewati; lock (SET NX PX) mencegah eksekusi yang tumpang tindih. Fencing token
+// naik monoton setiap eksekusi.
+// KEYS lock, last tick, fence; ARGV owner, ttl ms, tick
+var acquireScript = redis.NewScript(`
+local last = tonumber(redis.call("GET", KEYS[2]) or "0")
+if last >= tonumber(ARGV[3]) then
+	return 0
+end
+if not redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+	return -1
+end
+redis.call("SET", KEYS[2], ARGV[3])
+return redis.call("INCR", KEYS[3])
+`)
+
+// renewScript: KEYS lock; ARGV owner, ttl ms
+var renewScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// releaseScript: KEYS lock; ARGV owner
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+func lockKey(task string) string {
+	return "scheduler:lock:" + task
+}
+
+func lastTickKey(task string) string {
+	return "scheduler:last:" + task
+}
+
+func fenceKey(task string) string {
+	return "scheduler:fence:" + task
+}
+
+// acquire mengembalikan fencing token, tickClaimed atau lockBusy
+func (s *SchedulerService) acquire(ctx context.Context, task, owner string, tick time.Time) (int64, error) {
+	return acquireScript.Run(ctx, s.redis,
+		[]string{lockKey(task), lastTickKey(task), fenceKey(task)},
+		owner, s.lockTTL.Milliseconds(), tick.Unix(),
+	).Int64()
+}
+
+func (s *SchedulerService) renew(ctx context.Context, task, owner string) (bool, error) {
+	renewed, err := renewScript.Run(ctx, s.redis, []string{lockKey(task)}, owner, s.lockTTL.Milliseconds()).Int()
+	return renewed == 1, err
+}
+
+func (s *SchedulerService) release(task, owner string) {
+	// memakai context baru agar lock tetap dilepas walau scheduler sedang berhenti
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = releaseScript.Run(ctx, s.redis, []string{lockKey(task)}, owner).Err()
+}
+
+type fencingTokenKey struct{}
+
+func withFencingToken(ctx context.Context, token int64) context.Context {
+	return context.WithValue(ctx, fencingTokenKey{}, token)
+}
+
+// FencingToken mengembalikan fencing token eksekusi task. Token selalu lebih besar
+// dari eksekusi sebelumnya sehingga sistem hilir dapat menolak penulisan dari
+// eksekusi lama yang kehilangan lock.
+func FencingToken(ctx context.Context) int64 {
+	token, _ := ctx.Value(fencingTokenKey{}).(int64)
+	return token
+}
@@ -0,0 +1,89 @@
+package model
+
+import (
+	"time"
+)
+
+// Status satu eksekusi task
+const (
+	RunStatusRunning   = "running"
+	RunStatusSucceeded = "succeeded"
+	RunStatusFailed    = "failed"
+)
+
+const (
+	DefaultRunLimit = 20
+	MaxRunLimit     = 200
+)
+
+// Run adalah riwayat satu eksekusi task terjadwal. Tick adalah waktu jadwal cron,
+// FencingToken adalah token monoton dari lock Redis untuk eksekusi ini.
+type Run struct {
+	ID           uint       `json:"id" gorm:"primaryKey"`
+	Task         string     `json:"task" gorm:"size:100;index:idx_scheduler_runs_task_tick"`
+	Tick         time.Time  `json:"tick" gorm:"index:idx_scheduler_runs_task_tick"`
+	Instance     string     `json:"instance" gorm:"size:255"`
+	FencingToken int64      `json:"fencing_token"`
+	Status       string     `json:"status" gorm:"size:20"`
+	Error        string     `json:"error,omitempty" gorm:"size:1000"`
+	StartedAt    time.Time  `json:"started_at"`
+	FinishedAt   *time.Time `json:"finished_at"`
+	DurationMs   int64      `json:"duration_ms"`
+}
+
+func (Run) TableName() string {
+	return "scheduler_runs"
+}
+
+// TaskStatus adalah status task untuk endpoint admin
+type TaskStatus struct {
+	Name     string    `json:"name"`
+	Schedule string    `json:"schedule"`
+	Timeout  string    `json:"timeout"`
+	NextRun  time.Time `json:"next_run"`
+	// Running berarti lock task sedang dipegang salah satu replica
+	Running bool `json:"running"`
+	LastRun *Run `json:"last_run,omitempty"`
+}
+
+// DTO: Query task runs
+type RunQuery struct {
+	Limit int `query:"limit" validate:"omitempty,min=1,max=200"`
+}
+
+// Factory: Create running task run
+func NewRun(task string, tick time.Time, instance string, token int64, now time.Time) *Run {
+	return &Run{
+		Task:         task,
+		Tick:         tick,
+		Instance:     instance,
+		FencingToken: token,
+		Status:       RunStatusRunning,
+		StartedAt:    now,
+	}
+}
+
+// Finish mencatat hasil eksekusi
+func (r *Run) Finish(err error, now time.Time) {
+	r.FinishedAt = &now
+	r.DurationMs = now.Sub(r.StartedAt).Milliseconds()
+	r.Status = RunStatusSucceeded
+	if err != nil {
+		r.Status = RunStatusFailed
+		r.Error = err.Error()
+		if len(r.Error) > 1000 {
+			r.Error = r.Error[:1000]
+		}
+	}
+}
+
+// RunLimit returns limit clamped to the allowed range, or the default when unset
+func RunLimit(limit int) int {
+	if limit <= 0 {
+		return DefaultRunLimit
+	}
+	if limit > MaxRunLimit {
+		return MaxRunLimit
+	}
+	return limit
+}
@@ -0,0 +1,48 @@
+package scheduler
+
+import (
+	"net/http"
+
+	"boilerplate/internal/scheduler/model"
+	"boilerplate/pkg/response"
+	errs "boilerplate/shared/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+type SchedulerHandler struct {
+	schedulerService SchedulerServiceInterface
+}
+
+func NewSchedulerHandler(schedulerService SchedulerServiceInterface) *SchedulerHandler {
+	return &SchedulerHandler{
+		schedulerService: schedulerService,
+	}
+}
+
+func (h *SchedulerHandler) GetTasks(c echo.Context) error {
+	tasks, err := h.schedulerService.Status(c.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, http.StatusOK, "Scheduled tasks retrieved successfully", tasks)
+}
+
+func (h *SchedulerHandler) GetRuns(c echo.Context) error {
+	var query model.RunQuery
+	if err := c.Bind(&query); err != nil {
+		return errs.ErrInvalidPayload.Wrap(err)
+	}
+
+	if err := c.Validate(&query); err != nil {
+		return err
+	}
+
+	runs, err := h.schedulerService.GetRuns(c.Request().Context(), c.Param("name"), query.Limit)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, http.StatusOK, "Task runs retrieved successfully", runs)
+}
@@ -0,0 +1,336 @@
+package scheduler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"boilerplate/internal/scheduler/model"
+	"boilerplate/pkg/logger"
+	errs "boilerplate/shared/errors"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/robfig/cron/v3"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultTaskTimeout membatasi satu eksekusi task
+	DefaultTaskTimeout = 30 * time.Minute
+	// DefaultLockTTL adalah masa berlaku lock task; lock diperpanjang setiap sepertiga TTL
+	// selama task berjalan sehingga replica yang mati melepaskan lock setelah TTL
+	DefaultLockTTL = 30 * time.Second
+)
+
+// TaskFunc adalah pekerjaan periodik. ctx dibatalkan saat timeout tercapai atau
+// lock hilang; FencingToken(ctx) mengembalikan token eksekusi ini.
+type TaskFunc func(ctx context.Context) error
+
+// Task adalah pekerjaan periodik yang didaftarkan modul
+type Task struct {
+	Name string
+	// Schedule adalah ekspresi cron 5 field (menit jam tanggal bulan hari) atau
+	// deskriptor seperti @hourly, @daily dan @every 10m. @every diselaraskan ke
+	// epoch Unix (@every 10m berjalan di menit kelipatan 10) agar semua replica
+	// menghasilkan tick yang sama.
+	Schedule string
+	Timeout  time.Duration
+	Run      TaskFunc
+}
+
+type registeredTask struct {
+	Task
+	schedule cron.Schedule
+}
+
+// everySchedule menggantikan jadwal @every bawaan cron yang menghitung interval dari
+// waktu start scheduler. Replica yang start pada waktu berbeda akan menghasilkan tick
+// berbeda sehingga klaim tick di Redis tidak mencegah task berjalan di setiap replica.
+type everySchedule struct {
+	interval time.Duration
+}
+
+func (e everySchedule) Next(t time.Time) time.Time {
+	interval := e.interval.Nanoseconds()
+	return time.Unix(0, (t.UnixNano()/interval+1)*interval).In(t.Location())
+}
+
+// SchedulerServiceInterface mendefinisikan kontrak untuk SchedulerService
+type SchedulerServiceInterface interface {
+	Status(ctx context.Context) ([]model.TaskStatus, error)
+	GetRuns(ctx context.Context, task string, limit int) ([]model.Run, error)
+}
+
+// SchedulerService menjalankan task sesuai jadwal cron di setiap replica. Setiap
+// tick diklaim lewat lock Redis (SET NX) sehingga hanya dijalankan satu replica,
+// dan setiap eksekusi dicatat di tabel scheduler_runs.
+type SchedulerService struct {
+	db       *gorm.DB
+	redis    *redis.Client
+	logger   logger.Logger
+	tasks    map[string]*registeredTask
+	instance string
+	lockTTL  time.Duration
+	now      func() time.Time
+}
+
+func NewSchedulerService(db *gorm.DB, redisClient *redis.Client, logger logger.Logger) *SchedulerService {
+	hostname, _ := os.Hostname()
+	return &SchedulerService{
+		db:       db,
+		redis:    redisClient,
+		logger:   logger,
+		tasks:    make(map[string]*registeredTask),
+		instance: fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+		lockTTL:  DefaultLockTTL,
+		now:      time.Now,
+	}
+}
+
+// Register menambahkan task; dipanggil saat startup sebelum Start
+func (s *SchedulerService) Register(task Task) error {
+	if _, exists := s.tasks[task.Name]; exists {
+		return fmt.Errorf("scheduler: task %q already registered", task.Name)
+	}
+	schedule, err := cron.ParseStandard(task.Schedule)
+	if err != nil {
+		return fmt.Errorf("scheduler: task %q: %w", task.Name, err)
+	}
+	if every, ok := schedule.(cron.ConstantDelaySchedule); ok {
+		schedule = everySchedule{interval: every.Delay}
+	}
+	if task.Timeout <= 0 {
+		task.Timeout = DefaultTaskTimeout
+	}
+	s.tasks[task.Name] = &registeredTask{Task: task, schedule: schedule}
+	return nil
+}
+
+// Start menjalankan task yang jatuh tempo sampai ctx dibatalkan, lalu menunggu
+// task yang sedang berjalan selesai
+func (s *SchedulerService) Start(ctx context.Context) {
+	var running sync.WaitGroup
+	defer running.Wait()
+
+	next := make(map[string]time.Time, len(s.tasks))
+	for name, task := range s.tasks {
+		next[name] = task.schedule.Next(s.now())
+	}
+
+	for {
+		earliest := time.Time{}
+		for _, at := range next {
+			if earliest.IsZero() || at.Before(earliest) {
+				earliest = at
+			}
+		}
+		if earliest.IsZero() {
+			<-ctx.Done()
+			return
+		}
+
+		timer := time.NewTimer(time.Until(earliest))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		now := s.now()
+		for name, tick := range next {
+			if tick.After(now) {
+				continue
+			}
+			task := s.tasks[name]
+			next[name] = task.schedule.Next(now)
+
+			running.Add(1)
+			go func(tick time.Time) {
+				defer running.Done()
+				s.execute(ctx, task, tick)
+			}(tick)
+		}
+	}
+}
+
+// execute menjalankan satu tick task jika replica ini berhasil mengklaimnya
+func (s *SchedulerService) execute(ctx context.Context, task *registeredTask, tick time.Time) *model.Run {
+	fields := logrus.Fields{
+		"task": task.Name,
+		"tick": tick,
+	}
+
+	var token int64
+	owner, err := newOwner(s.instance)
+	if err == nil {
+		token, err = s.acquire(ctx, task.Name, owner, tick)
+	}
+	if err != nil {
+		fields["error"] = err.Error()
+		s.logger.WithFields(fields).Error("Gagal mengambil lock task terjadwal")
+		return nil
+	}
+	switch token {
+	case tickClaimed:
+		return nil
+	case lockBusy:
+		s.logger.WithFields(fields).Warn("Task terjadwal dilewati karena eksekusi sebelumnya belum selesai")
+		return nil
+	}
+	defer s.release(task.Name, owner)
+
+	run := model.NewRun(task.Name, tick, s.instance, token, s.now())
+	if err := s.db.Create(run).Error; err != nil {
+		fields["error"] = err.Error()
+		s.logger.WithFields(fields).Error("Gagal mencatat riwayat task terjadwal")
+	}
+
+	// task tetap diselesaikan walau scheduler berhenti; batasnya timeout task
+	runCtx, cancel := context.WithTimeout(withFencingToken(context.WithoutCancel(ctx), token), task.Timeout)
+	defer cancel()
+	stopRenewal := s.keepLock(runCtx, cancel, task.Name, owner)
+
+	runErr := runSafely(runCtx, task.Run)
+	stopRenewal()
+	run.Finish(runErr, s.now())
+
+	if err := s.db.Select("status", "error", "finished_at", "duration_ms").Updates(run).Error; err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"task":  task.Name,
+			"error": err.Error(),
+		}).Error("Gagal mencatat riwayat task terjadwal")
+	}
+
+	fields["fencing_token"] = token
+	fields["duration_ms"] = run.DurationMs
+	if runErr != nil {
+		fields["error"] = runErr.Error()
+		s.logger.WithFields(fields).Error("Task terjadwal gagal")
+	} else {
+		s.logger.WithFields(fields).Info("Task terjadwal selesai")
+	}
+	return run
+}
+
+// keepLock memperpanjang lock selama task berjalan. Jika lock hilang (mis. Redis
+// tidak dapat dihubungi lebih lama dari TTL) task dibatalkan agar tidak berjalan
+// bersamaan dengan replica lain.
+func (s *SchedulerService) keepLock(ctx context.Context, cancel context.CancelFunc, name, owner string) func() {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(s.lockTTL / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				renewed, err := s.renew(ctx, name, owner)
+				if err == nil && renewed {
+					continue
+				}
+				fields := logrus.Fields{"task": name}
+				if err != nil {
+					fields["error"] = err.Error()
+				}
+				s.logger.WithFields(fields).Error("Lock task terjadwal hilang, task dibatalkan")
+				cancel()
+				return
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+func runSafely(ctx context.Context, fn TaskFunc) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return fn(ctx)
+}
+
+// Status mengembalikan jadwal, eksekusi terakhir dan status lock setiap task
+func (s *SchedulerService) Status(ctx context.Context) ([]model.TaskStatus, error) {
+	names := make([]string, 0, len(s.tasks))
+	for name := range s.tasks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	now := s.now()
+	statuses := make([]model.TaskStatus, 0, len(names))
+	for _, name := range names {
+		task := s.tasks[name]
+		status := model.TaskStatus{
+			Name:     name,
+			Schedule: task.Schedule,
+			Timeout:  task.Timeout.String(),
+			NextRun:  task.schedule.Next(now),
+		}
+
+		held, err := s.redis.Exists(ctx, lockKey(name)).Result()
+		if err != nil {
+			return nil, err
+		}
+		status.Running = held > 0
+
+		var last model.Run
+		err = s.db.WithContext(ctx).Where("task = ?", name).Order("id DESC").First(&last).Error
+		switch {
+		case err == nil:
+			status.LastRun = &last
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, err
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// GetRuns mengembalikan riwayat eksekusi task, terbaru lebih dulu
+func (s *SchedulerService) GetRuns(ctx context.Context, task string, limit int) ([]model.Run, error) {
+	if _, ok := s.tasks[task]; !ok {
+		return nil, errs.ErrTaskNotFound
+	}
+
+	var runs []model.Run
+	if err := s.db.WithContext(ctx).
+		Where("task = ?", task).
+		Order("id DESC").
+		Limit(model.RunLimit(limit)).
+		Find(&runs).Error; err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
+
+// PurgeRuns menghapus riwayat eksekusi yang lebih lama dari before
+func (s *SchedulerService) PurgeRuns(ctx context.Context, before time.Time) (int64, error) {
+	result := s.db.WithContext(ctx).Where("started_at < ?", before).Delete(&model.Run{})
+	return result.RowsAffected, result.Error
+}
+
+func newOwner(instance string) (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return instance + ":" + hex.EncodeToString(b), nil
+}
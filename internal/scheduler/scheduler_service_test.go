@@ -0,0 +1,264 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"boilerplate/internal/scheduler/model"
+	"boilerplate/internal/testutil"
+	"boilerplate/pkg/logger"
+	"boilerplate/pkg/redis"
+	errs "boilerplate/shared/errors"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type SchedulerServiceTestSuite struct {
+	suite.Suite
+	db      *gorm.DB
+	redis   *miniredis.Miniredis
+	service *SchedulerService
+}
+
+func TestSchedulerServiceSuite(t *testing.T) {
+	suite.Run(t, new(SchedulerServiceTestSuite))
+}
+
+func (s *SchedulerServiceTestSuite) SetupTest() {
+	db := testutil.NewDB(s.T(), &model.Run{})
+
+	s.db = db
+	s.redis = miniredis.RunT(s.T())
+	s.service = s.newReplica("replica-a")
+}
+
+// newReplica membuat scheduler lain yang berbagi database dan Redis
+func (s *SchedulerServiceTestSuite) newReplica(instance string) *SchedulerService {
+	service := NewSchedulerService(s.db, redis.NewRedisClient(s.redis.Addr(), "", 0).Client(), logger.NewLogger())
+	service.instance = instance
+	return service
+}
+
+func (s *SchedulerServiceTestSuite) register(service *SchedulerService, fn TaskFunc) *registeredTask {
+	s.Require().NoError(service.Register(Task{Name: "cleanup", Schedule: "*/5 * * * *", Run: fn}))
+	return service.tasks["cleanup"]
+}
+
+func (s *SchedulerServiceTestSuite) TestRegisterValidatesTasks() {
+	s.Error(s.service.Register(Task{Name: "bad", Schedule: "every minute", Run: func(context.Context) error { return nil }}))
+	s.NoError(s.service.Register(Task{Name: "hourly", Schedule: "@hourly", Run: func(context.Context) error { return nil }}))
+	s.Error(s.service.Register(Task{Name: "hourly", Schedule: "@daily", Run: func(context.Context) error { return nil }}))
+	s.Equal(DefaultTaskTimeout, s.service.tasks["hourly"].Timeout)
+}
+
+func (s *SchedulerServiceTestSuite) TestTickRunsOnExactlyOneReplica() {
+	var runs atomic.Int32
+	count := func(context.Context) error {
+		runs.Add(1)
+		return nil
+	}
+	replicas := []*SchedulerService{s.service, s.newReplica("replica-b"), s.newReplica("replica-c")}
+	tasks := make([]*registeredTask, len(replicas))
+	for i, replica := range replicas {
+		tasks[i] = s.register(replica, count)
+	}
+
+	tick := time.Date(2024, 1, 1, 12, 5, 0, 0, time.UTC)
+	var wg sync.WaitGroup
+	for i := range replicas {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			replicas[i].execute(context.Background(), tasks[i], tick)
+		}(i)
+	}
+	wg.Wait()
+	s.Equal(int32(1), runs.Load())
+
+	// replica yang terlambat untuk tick yang sama tetap dilewati
+	s.Nil(replicas[1].execute(context.Background(), tasks[1], tick))
+
+	next := replicas[2].execute(context.Background(), tasks[2], tick.Add(5*time.Minute))
+	s.Require().NotNil(next)
+	s.Equal(int64(2), next.FencingToken)
+	s.Equal("replica-c", next.Instance)
+
+	var history []model.Run
+	s.Require().NoError(s.db.Order("id").Find(&history).Error)
+	s.Require().Len(history, 2)
+	s.Equal(int64(1), history[0].FencingToken)
+	s.Equal(model.RunStatusSucceeded, history[0].Status)
+	s.NotNil(history[0].FinishedAt)
+}
+
+func (s *SchedulerServiceTestSuite) TestEveryScheduleRunsOnExactlyOneReplica() {
+	var runs atomic.Int32
+	count := func(context.Context) error {
+		runs.Add(1)
+		return nil
+	}
+
+	// replica start pada waktu berbeda tetapi menghasilkan tick yang sama
+	replicas := []*SchedulerService{s.service, s.newReplica("replica-b")}
+	started := []time.Time{
+		time.Date(2024, 1, 1, 12, 3, 17, 0, time.UTC),
+		time.Date(2024, 1, 1, 12, 7, 42, 0, time.UTC),
+	}
+	expected := time.Date(2024, 1, 1, 12, 10, 0, 0, time.UTC)
+	for i, replica := range replicas {
+		s.Require().NoError(replica.Register(Task{Name: "sync", Schedule: "@every 10m", Run: count}))
+		task := replica.tasks["sync"]
+		tick := task.schedule.Next(started[i])
+		s.Equal(expected, tick)
+		replica.execute(context.Background(), task, tick)
+	}
+	s.Equal(int32(1), runs.Load())
+
+	// tick berikutnya dihitung dari tick sebelumnya, termasuk tepat di batas interval
+	s.Equal(expected.Add(10*time.Minute), replicas[1].tasks["sync"].schedule.Next(expected))
+}
+
+func (s *SchedulerServiceTestSuite) TestOverlappingRunIsSkipped() {
+	var runs atomic.Int32
+	task := s.register(s.service, func(context.Context) error {
+		runs.Add(1)
+		return nil
+	})
+	s.redis.Set(lockKey("cleanup"), "replica-b:still-running")
+
+	s.Nil(s.service.execute(context.Background(), task, time.Now()))
+	s.Equal(int32(0), runs.Load())
+}
+
+func (s *SchedulerServiceTestSuite) TestFailuresAreRecorded() {
+	fail := s.register(s.service, func(context.Context) error {
+		return errors.New("database unavailable")
+	})
+	run := s.service.execute(context.Background(), fail, time.Unix(1000, 0))
+	s.Require().NotNil(run)
+	s.Equal(model.RunStatusFailed, run.Status)
+	s.Equal("database unavailable", run.Error)
+
+	fail.Run = func(context.Context) error { panic("boom") }
+	run = s.service.execute(context.Background(), fail, time.Unix(2000, 0))
+	s.Require().NotNil(run)
+	s.Equal("panic: boom", run.Error)
+
+	var stored model.Run
+	s.Require().NoError(s.db.First(&stored, run.ID).Error)
+	s.Equal(model.RunStatusFailed, stored.Status)
+	s.False(s.redis.Exists(lockKey("cleanup")), "lock is released after a failure")
+}
+
+func (s *SchedulerServiceTestSuite) TestFencingTokenInContext() {
+	var token int64
+	task := s.register(s.service, func(ctx context.Context) error {
+		token = FencingToken(ctx)
+		return nil
+	})
+	s.service.execute(context.Background(), task, time.Unix(1000, 0))
+	s.service.execute(context.Background(), task, time.Unix(2000, 0))
+	s.Equal(int64(2), token)
+	s.Equal(int64(0), FencingToken(context.Background()))
+}
+
+func (s *SchedulerServiceTestSuite) TestRenewAndReleaseRequireOwnership() {
+	ctx := context.Background()
+	token, err := s.service.acquire(ctx, "cleanup", "owner-a", time.Unix(1000, 0))
+	s.Require().NoError(err)
+	s.Equal(int64(1), token)
+
+	s.redis.FastForward(20 * time.Second)
+	renewed, err := s.service.renew(ctx, "cleanup", "owner-a")
+	s.Require().NoError(err)
+	s.True(renewed)
+	s.Equal(DefaultLockTTL, s.redis.TTL(lockKey("cleanup")))
+
+	renewed, err = s.service.renew(ctx, "cleanup", "owner-b")
+	s.Require().NoError(err)
+	s.False(renewed)
+
+	s.service.release("cleanup", "owner-b")
+	s.True(s.redis.Exists(lockKey("cleanup")))
+	s.service.release("cleanup", "owner-a")
+	s.False(s.redis.Exists(lockKey("cleanup")))
+
+	// lock yang kedaluwarsa dapat diambil replica lain untuk tick berikutnya
+	token, err = s.service.acquire(ctx, "cleanup", "owner-b", time.Unix(2000, 0))
+	s.Require().NoError(err)
+	s.Equal(int64(2), token)
+}
+
+func (s *SchedulerServiceTestSuite) TestLostLockCancelsTask() {
+	s.service.lockTTL = 30 * time.Millisecond
+	task := s.register(s.service, func(ctx context.Context) error {
+		// lock kedaluwarsa, mis. karena Redis tidak dapat dihubungi
+		s.redis.Del(lockKey("cleanup"))
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	run := s.service.execute(context.Background(), task, time.Unix(1000, 0))
+	s.Require().NotNil(run)
+	s.Equal(model.RunStatusFailed, run.Status)
+	s.Contains(run.Error, "context canceled")
+}
+
+func (s *SchedulerServiceTestSuite) TestStatusAndRuns() {
+	task := s.register(s.service, func(context.Context) error { return nil })
+	now := time.Date(2024, 1, 1, 12, 1, 0, 0, time.UTC)
+	s.service.now = func() time.Time { return now }
+
+	statuses, err := s.service.Status(context.Background())
+	s.Require().NoError(err)
+	s.Require().Len(statuses, 1)
+	s.Equal("cleanup", statuses[0].Name)
+	s.Equal(now.Add(4*time.Minute), statuses[0].NextRun)
+	s.Nil(statuses[0].LastRun)
+	s.False(statuses[0].Running)
+
+	s.service.execute(context.Background(), task, now)
+	statuses, err = s.service.Status(context.Background())
+	s.Require().NoError(err)
+	s.Require().NotNil(statuses[0].LastRun)
+	s.Equal(model.RunStatusSucceeded, statuses[0].LastRun.Status)
+
+	runs, err := s.service.GetRuns(context.Background(), "cleanup", 0)
+	s.Require().NoError(err)
+	s.Len(runs, 1)
+	_, err = s.service.GetRuns(context.Background(), "unknown", 0)
+	s.ErrorIs(err, errs.ErrTaskNotFound)
+
+	purged, err := s.service.PurgeRuns(context.Background(), now.Add(time.Hour))
+	s.Require().NoError(err)
+	s.Equal(int64(1), purged)
+}
+
+func (s *SchedulerServiceTestSuite) TestStartRunsDueTasks() {
+	var runs atomic.Int32
+	s.Require().NoError(s.service.Register(Task{Name: "tick", Schedule: "@every 1s", Run: func(context.Context) error {
+		runs.Add(1)
+		return nil
+	}}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.service.Start(ctx)
+		close(done)
+	}()
+
+	s.Eventually(func() bool { return runs.Load() >= 1 }, 3*time.Second, 50*time.Millisecond)
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		s.Fail("scheduler did not stop")
+	}
+}
@@ -0,0 +1,31 @@
+package model
+
+// SearchQuery adalah parameter pencarian admin. Type kosong mencari semua jenis.
+type SearchQuery struct {
+	Q     string `query:"q" validate:"required,max=200"`
+	Type  string `query:"type" validate:"omitempty,oneof=category user"`
+	Limit int    `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+// SearchResult adalah hasil pencarian yang dikelompokkan per jenis entitas. Group
+// diurutkan dari skor hit tertinggi di dalamnya.
+type SearchResult struct {
+	Query  string        `json:"query"`
+	Total  int           `json:"total"`
+	Groups []SearchGroup `json:"groups"`
+}
+
+// SearchGroup berisi hit satu jenis entitas, terurut dari skor tertinggi
+type SearchGroup struct {
+	Type string      `json:"type"`
+	Hits []SearchHit `json:"hits"`
+}
+
+// SearchHit adalah satu entitas yang cocok. Highlights berisi potongan nilai kolom
+// dengan kata yang cocok dibungkus <mark>; teks lain sudah di-escape HTML.
+type SearchHit struct {
+	ID         uint                `json:"id"`
+	Title      string              `json:"title"`
+	Score      float64             `json:"score"`
+	Highlights map[string][]string `json:"highlights"`
+}
@@ -0,0 +1,34 @@
+package search
+
+import (
+	"context"
+
+	userModel "boilerplate/internal/user/model"
+	"boilerplate/pkg/search"
+
+	"gorm.io/gorm"
+)
+
+// PrivacyHook menghapus user dari index pencarian saat erasure. Index hanya memuat
+// salinan nama dan email yang sudah diekspor modul user sehingga tidak ada yang
+// diekspor. Dokumen dihapus sebelum transaksi erasure commit; bila erasure batal,
+// user kembali dapat dicari setelah reindex.
+type PrivacyHook struct {
+	searcher search.Searcher
+}
+
+func NewPrivacyHook(searcher search.Searcher) *PrivacyHook {
+	return &PrivacyHook{searcher: searcher}
+}
+
+func (h *PrivacyHook) Name() string {
+	return "search"
+}
+
+func (h *PrivacyHook) Export(ctx context.Context, db *gorm.DB, userID uint) (interface{}, error) {
+	return nil, nil
+}
+
+func (h *PrivacyHook) Erase(ctx context.Context, tx *gorm.DB, userID uint) error {
+	return h.searcher.Delete(ctx, userModel.SearchType, userID)
+}
@@ -0,0 +1,40 @@
+package search
+
+import (
+	"net/http"
+
+	"boilerplate/internal/search/model"
+	"boilerplate/pkg/response"
+	errs "boilerplate/shared/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+type SearchHandler struct {
+	searchService SearchServiceInterface
+}
+
+func NewSearchHandler(searchService SearchServiceInterface) *SearchHandler {
+	return &SearchHandler{
+		searchService: searchService,
+	}
+}
+
+// Search mencari category dan user; hanya untuk admin
+func (h *SearchHandler) Search(c echo.Context) error {
+	var query model.SearchQuery
+	if err := c.Bind(&query); err != nil {
+		return errs.ErrInvalidPayload.Wrap(err)
+	}
+
+	if err := c.Validate(&query); err != nil {
+		return err
+	}
+
+	result, err := h.searchService.Search(c.Request().Context(), query)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, http.StatusOK, "Search results retrieved successfully", result)
+}
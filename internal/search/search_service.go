@@ -0,0 +1,238 @@
+package search
+
+import (
+	"context"
+	"fmt"
+
+	categoryModel "boilerplate/internal/category/model"
+	"boilerplate/internal/search/model"
+	userModel "boilerplate/internal/user/model"
+	"boilerplate/pkg/events"
+	"boilerplate/pkg/logger"
+	"boilerplate/pkg/search"
+	errs "boilerplate/shared/errors"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// reindexBatchSize adalah jumlah baris yang dibaca dan diindex per batch
+const reindexBatchSize = 500
+
+// Types adalah jenis entitas yang dapat dicari, sesuai urutan reindex
+var Types = []string{categoryModel.SearchType, userModel.SearchType}
+
+// SearchServiceInterface mendefinisikan kontrak untuk SearchService
+type SearchServiceInterface interface {
+	Search(ctx context.Context, query model.SearchQuery) (*model.SearchResult, error)
+	Reindex(ctx context.Context, types ...string) (map[string]int, error)
+}
+
+// SearchService mencari category dan user lewat index pencarian. Service lain
+// memperbarui index setelah commit; SearchService membangun ulang index dan
+// menyinkronkannya dari event outbox.
+type SearchService struct {
+	db       *gorm.DB
+	searcher search.Searcher
+	logger   logger.Logger
+}
+
+func NewSearchService(db *gorm.DB, searcher search.Searcher, logger logger.Logger) *SearchService {
+	return &SearchService{
+		db:       db,
+		searcher: searcher,
+		logger:   logger,
+	}
+}
+
+// Subscribe memperbarui index dari event outbox di proses worker. Event menjadi
+// jaring pengaman bila sinkronisasi setelah commit gagal, sehingga hanya berguna
+// untuk index yang dipakai bersama semua proses.
+func (s *SearchService) Subscribe(bus *events.Bus) {
+	events.Subscribe(bus, func(ctx context.Context, _ events.Envelope, event categoryModel.CategoryCreated) error {
+		return s.indexCategory(ctx, event.CategoryID)
+	})
+	events.Subscribe(bus, func(ctx context.Context, _ events.Envelope, event categoryModel.CategoryUpdated) error {
+		return s.indexCategory(ctx, event.CategoryID)
+	})
+	events.Subscribe(bus, func(ctx context.Context, _ events.Envelope, event categoryModel.CategoryDeleted) error {
+		return s.searcher.Delete(ctx, categoryModel.SearchType, event.CategoryID)
+	})
+	events.Subscribe(bus, func(ctx context.Context, _ events.Envelope, event userModel.UserRegistered) error {
+		return s.indexUser(ctx, event.UserID)
+	})
+	events.Subscribe(bus, func(ctx context.Context, _ events.Envelope, event userModel.UserErased) error {
+		return s.searcher.Delete(ctx, userModel.SearchType, event.UserID)
+	})
+}
+
+// Search mencari entitas lalu memuat datanya dari database. Hit untuk entitas yang
+// sudah dihapus dilewati. Highlight dibuat dari data asli bila backend tidak
+// menyediakannya.
+func (s *SearchService) Search(ctx context.Context, query model.SearchQuery) (*model.SearchResult, error) {
+	terms := search.Terms(query.Q)
+	if len(terms) == 0 {
+		return nil, errs.ErrSearchQueryTooShort
+	}
+
+	request := search.Query{Text: query.Q, Limit: query.Limit}
+	if query.Type != "" {
+		request.Types = []string{query.Type}
+	}
+	hits, err := s.searcher.Search(ctx, request)
+	if err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"error": err.Error(),
+		}).Error("Gagal menjalankan pencarian")
+		return nil, err
+	}
+
+	fields, err := s.load(ctx, hits)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.SearchResult{Query: query.Q, Groups: []model.SearchGroup{}}
+	groups := make(map[string]int)
+	for _, hit := range hits {
+		values, found := fields[hit.Type][hit.ID]
+		if !found {
+			continue
+		}
+		highlights := hit.Highlights
+		if len(highlights) == 0 {
+			highlights = search.Highlight(values, terms)
+		}
+
+		index, found := groups[hit.Type]
+		if !found {
+			index = len(result.Groups)
+			groups[hit.Type] = index
+			result.Groups = append(result.Groups, model.SearchGroup{Type: hit.Type})
+		}
+		result.Groups[index].Hits = append(result.Groups[index].Hits, model.SearchHit{
+			ID:         hit.ID,
+			Title:      values["name"],
+			Score:      hit.Score,
+			Highlights: highlights,
+		})
+		result.Total++
+	}
+	return result, nil
+}
+
+// load membaca kolom yang dapat dicari untuk setiap hit, dikelompokkan per jenis lalu ID
+func (s *SearchService) load(ctx context.Context, hits []search.Hit) (map[string]map[uint]map[string]string, error) {
+	ids := make(map[string][]uint)
+	for _, hit := range hits {
+		ids[hit.Type] = append(ids[hit.Type], hit.ID)
+	}
+
+	fields := make(map[string]map[uint]map[string]string)
+	if len(ids[categoryModel.SearchType]) > 0 {
+		var categories []categoryModel.Category
+		if err := s.db.WithContext(ctx).Find(&categories, ids[categoryModel.SearchType]).Error; err != nil {
+			return nil, err
+		}
+		documents := make(map[uint]map[string]string, len(categories))
+		for i := range categories {
+			documents[categories[i].ID] = categories[i].SearchDocument().Fields
+		}
+		fields[categoryModel.SearchType] = documents
+	}
+	if len(ids[userModel.SearchType]) > 0 {
+		var users []userModel.User
+		if err := s.db.WithContext(ctx).Where("erased_at IS NULL").Find(&users, ids[userModel.SearchType]).Error; err != nil {
+			return nil, err
+		}
+		documents := make(map[uint]map[string]string, len(users))
+		for i := range users {
+			documents[users[i].ID] = users[i].SearchDocument().Fields
+		}
+		fields[userModel.SearchType] = documents
+	}
+	return fields, nil
+}
+
+// Reindex membangun ulang index untuk jenis yang diminta, atau semua jenis bila
+// kosong, dan mengembalikan jumlah dokumen per jenis. Dokumen satu jenis tidak
+// dapat dicari selama jenis itu dibangun ulang.
+func (s *SearchService) Reindex(ctx context.Context, types ...string) (map[string]int, error) {
+	if len(types) == 0 {
+		types = Types
+	}
+
+	counts := make(map[string]int, len(types))
+	for _, docType := range types {
+		var count int
+		var err error
+		switch docType {
+		case categoryModel.SearchType:
+			count, err = reindex[categoryModel.Category](ctx, s, docType, s.db)
+		case userModel.SearchType:
+			count, err = reindex[userModel.User](ctx, s, docType, s.db.Where("erased_at IS NULL"))
+		default:
+			return counts, fmt.Errorf("unknown search type %q", docType)
+		}
+		if err != nil {
+			s.logger.WithFields(logrus.Fields{
+				"type":  docType,
+				"error": err.Error(),
+			}).Error("Gagal membangun ulang index pencarian")
+			return counts, err
+		}
+		counts[docType] = count
+	}
+	return counts, nil
+}
+
+// document adalah entitas yang dapat diindex
+type document interface {
+	SearchDocument() search.Document
+}
+
+// reindex mengosongkan index satu jenis lalu mengisinya dari query per batch
+func reindex[T any, PT interface {
+	*T
+	document
+}](ctx context.Context, s *SearchService, docType string, query *gorm.DB) (int, error) {
+	if err := s.searcher.Clear(ctx, docType); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	var rows []T
+	err := query.WithContext(ctx).FindInBatches(&rows, reindexBatchSize, func(tx *gorm.DB, _ int) error {
+		docs := make([]search.Document, len(rows))
+		for i := range rows {
+			docs[i] = PT(&rows[i]).SearchDocument()
+		}
+		count += len(docs)
+		return s.searcher.Index(ctx, docs...)
+	}).Error
+	return count, err
+}
+
+func (s *SearchService) indexCategory(ctx context.Context, id uint) error {
+	var category categoryModel.Category
+	err := s.db.WithContext(ctx).Limit(1).Find(&category, id).Error
+	if err != nil {
+		return err
+	}
+	if category.ID == 0 {
+		return s.searcher.Delete(ctx, categoryModel.SearchType, id)
+	}
+	return s.searcher.Index(ctx, category.SearchDocument())
+}
+
+func (s *SearchService) indexUser(ctx context.Context, id uint) error {
+	var user userModel.User
+	err := s.db.WithContext(ctx).Limit(1).Find(&user, id).Error
+	if err != nil {
+		return err
+	}
+	if user.ID == 0 || user.IsErased() {
+		return s.searcher.Delete(ctx, userModel.SearchType, id)
+	}
+	return s.searcher.Index(ctx, user.SearchDocument())
+}
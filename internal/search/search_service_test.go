@@ -0,0 +1,231 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"boilerplate/internal/category"
+	categoryModel "boilerplate/internal/category/model"
+	"boilerplate/internal/search/model"
+	"boilerplate/internal/testutil"
+	"boilerplate/internal/user"
+	userModel "boilerplate/internal/user/model"
+	"boilerplate/pkg/events"
+	"boilerplate/pkg/fieldcrypt"
+	"boilerplate/pkg/logger"
+	"boilerplate/pkg/mailer"
+	"boilerplate/pkg/password"
+	"boilerplate/pkg/search"
+	"boilerplate/shared/constants"
+	errs "boilerplate/shared/errors"
+
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type SearchServiceTestSuite struct {
+	suite.Suite
+	ctx        context.Context
+	db         *gorm.DB
+	searcher   *search.EmbeddedSearcher
+	service    *SearchService
+	categories *category.CategoryService
+	users      *user.UserService
+	admin      *userModel.User
+}
+
+func TestSearchServiceSuite(t *testing.T) {
+	suite.Run(t, new(SearchServiceTestSuite))
+}
+
+func (s *SearchServiceTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	keyring, err := fieldcrypt.NewKeyring(1, map[uint32][]byte{1: bytes.Repeat([]byte{1}, fieldcrypt.KeySize)}, []byte("blind"))
+	s.Require().NoError(err)
+	previous := fieldcrypt.DefaultKeyring()
+	fieldcrypt.SetDefaultKeyring(keyring)
+	s.T().Cleanup(func() { fieldcrypt.SetDefaultKeyring(previous) })
+
+	db := testutil.NewDB(s.T(), &userModel.User{}, &userModel.PasswordHistory{}, &categoryModel.Category{}, &events.OutboxMessage{})
+	s.db = db
+
+	s.searcher, err = search.NewEmbeddedSearcher()
+	s.Require().NoError(err)
+	s.T().Cleanup(func() { _ = s.searcher.Close() })
+
+	log := logger.NewLogger()
+	indexer := search.NewIndexer(s.searcher, log)
+	redisClient := testutil.NewRedisClient(s.T())
+	s.service = NewSearchService(db, s.searcher, log)
+	s.categories = category.NewCategoryService(db, nil, indexer, nil)
+	s.users = user.NewUserService(db, "test-secret", log, redisClient, password.DefaultPolicy(), mailer.NewLogMailer(log), nil, indexer)
+
+	s.admin = &userModel.User{Name: "Admin", Email: "admin@example.com", Role: constants.RoleAdmin, Version: 1}
+	s.Require().NoError(db.Create(s.admin).Error)
+}
+
+func (s *SearchServiceTestSuite) search(q string) *model.SearchResult {
+	result, err := s.service.Search(s.ctx, model.SearchQuery{Q: q})
+	s.Require().NoError(err)
+	return result
+}
+
+func (s *SearchServiceTestSuite) register(name, email string) *userModel.User {
+	registered, err := s.users.Register(userModel.RegisterInput{Name: name, Email: email, Password: "Sup3r-secret-pass"})
+	s.Require().NoError(err)
+	return registered
+}
+
+func (s *SearchServiceTestSuite) TestGroupsRankedHitsByType() {
+	registered := s.register("John Smith", "john@example.com")
+	created, err := s.categories.Create(categoryModel.CreateCategoryInput{Name: "Books", Description: "Novels by John Grisham"}, s.admin)
+	s.Require().NoError(err)
+
+	result := s.search("john")
+	s.Equal("john", result.Query)
+	s.Equal(2, result.Total)
+	s.Require().Len(result.Groups, 2)
+
+	groups := map[string]model.SearchGroup{}
+	for _, group := range result.Groups {
+		groups[group.Type] = group
+	}
+	s.Require().Len(groups[userModel.SearchType].Hits, 1)
+	hit := groups[userModel.SearchType].Hits[0]
+	s.Equal(registered.ID, hit.ID)
+	s.Equal("John Smith", hit.Title)
+	s.Contains(hit.Highlights["name"][0], "<mark>John</mark>")
+
+	s.Require().Len(groups[categoryModel.SearchType].Hits, 1)
+	s.Equal(created.ID, groups[categoryModel.SearchType].Hits[0].ID)
+	s.Equal("Books", groups[categoryModel.SearchType].Hits[0].Title)
+
+	// group diurutkan dari skor hit tertinggi
+	s.GreaterOrEqual(result.Groups[0].Hits[0].Score, result.Groups[1].Hits[0].Score)
+}
+
+func (s *SearchServiceTestSuite) TestToleratesTyposAndFiltersByType() {
+	s.register("Jane Smyth", "jane@example.com")
+	_, err := s.categories.Create(categoryModel.CreateCategoryInput{Name: "Smith tools"}, s.admin)
+	s.Require().NoError(err)
+
+	result := s.search("smith")
+	s.Equal(2, result.Total)
+
+	result, err = s.service.Search(s.ctx, model.SearchQuery{Q: "smith", Type: userModel.SearchType})
+	s.Require().NoError(err)
+	s.Require().Len(result.Groups, 1)
+	s.Equal(userModel.SearchType, result.Groups[0].Type)
+	s.Contains(result.Groups[0].Hits[0].Highlights["name"][0], "<mark>Smyth</mark>")
+}
+
+func (s *SearchServiceTestSuite) TestCategoryWritesKeepIndexInSync() {
+	created, err := s.categories.Create(categoryModel.CreateCategoryInput{Name: "Electronics"}, s.admin)
+	s.Require().NoError(err)
+	s.Equal(1, s.search("electronics").Total)
+
+	current, err := s.categories.GetForUpdate(created.ID)
+	s.Require().NoError(err)
+	current, err = s.categories.Update(current, categoryModel.CreateCategoryInput{Name: "Gadgets"}, s.admin)
+	s.Require().NoError(err)
+	s.Zero(s.search("electronics").Total)
+	s.Equal(1, s.search("gadgets").Total)
+
+	_, err = s.categories.Patch(current, categoryModel.PatchCategoryInput{Name: "Gadgets", Description: "Phones"}, s.admin)
+	s.Require().NoError(err)
+	s.Equal(1, s.search("phones").Total)
+
+	s.Require().NoError(s.categories.Delete(created.ID, s.admin))
+	s.Zero(s.search("gadgets").Total)
+}
+
+func (s *SearchServiceTestSuite) TestProfileUpdateReindexesUser() {
+	registered := s.register("Alice Cooper", "alice@example.com")
+
+	current, err := s.users.GetUserForUpdate(registered.ID)
+	s.Require().NoError(err)
+	_, err = s.users.UpdateProfile(current, userModel.UpdateProfileInput{Name: "Alice Walker"})
+	s.Require().NoError(err)
+	s.Zero(s.search("cooper").Total)
+	s.Equal(1, s.search("walker").Total)
+}
+
+func (s *SearchServiceTestSuite) TestSkipsHitsForDeletedEntities() {
+	s.Require().NoError(s.searcher.Index(s.ctx, search.Document{Type: categoryModel.SearchType, ID: 999, Fields: map[string]string{"name": "Ghost"}}))
+
+	result := s.search("ghost")
+	s.Zero(result.Total)
+	s.Empty(result.Groups)
+}
+
+func (s *SearchServiceTestSuite) TestRejectsQueryWithoutWords() {
+	_, err := s.service.Search(s.ctx, model.SearchQuery{Q: "a !"})
+	s.ErrorIs(err, errs.ErrSearchQueryTooShort)
+}
+
+func (s *SearchServiceTestSuite) TestReindexRebuildsFromDatabase() {
+	// baris yang ditulis tanpa service belum ada di index
+	s.Require().NoError(s.db.Create(&categoryModel.Category{Name: "Garden", Version: 1}).Error)
+	erasedAt := time.Now()
+	s.Require().NoError(s.db.Create(&userModel.User{Name: "Garden Gnome", Email: "gnome@example.com", Version: 1, ErasedAt: &erasedAt}).Error)
+	s.Require().NoError(s.searcher.Index(s.ctx, search.Document{Type: categoryModel.SearchType, ID: 999, Fields: map[string]string{"name": "Stale"}}))
+	s.Zero(s.search("garden").Total)
+
+	counts, err := s.service.Reindex(s.ctx)
+	s.Require().NoError(err)
+	s.Equal(map[string]int{categoryModel.SearchType: 1, userModel.SearchType: 1}, counts)
+
+	// user yang sudah dihapus datanya tidak diindex
+	result := s.search("garden")
+	s.Equal(1, result.Total)
+	s.Equal(categoryModel.SearchType, result.Groups[0].Type)
+	hits, err := s.searcher.Search(s.ctx, search.Query{Text: "stale"})
+	s.Require().NoError(err)
+	s.Empty(hits)
+
+	_, err = s.service.Reindex(s.ctx, "unknown")
+	s.Error(err)
+}
+
+func (s *SearchServiceTestSuite) TestEventsUpdateIndex() {
+	bus := events.NewBus()
+	s.service.Subscribe(bus)
+	publish := func(event events.Event) {
+		envelope, err := events.NewEnvelope(event)
+		s.Require().NoError(err)
+		s.Require().NoError(bus.Publish(s.ctx, envelope))
+	}
+
+	created := &categoryModel.Category{Name: "Imported", Version: 1}
+	s.Require().NoError(s.db.Create(created).Error)
+	publish(categoryModel.CategoryCreated(created.Event(s.admin.ID)))
+	s.Equal(1, s.search("imported").Total)
+
+	s.Require().NoError(s.db.Delete(created).Error)
+	publish(categoryModel.CategoryDeleted(created.Event(s.admin.ID)))
+	hits, err := s.searcher.Search(s.ctx, search.Query{Text: "imported"})
+	s.Require().NoError(err)
+	s.Empty(hits)
+
+	invited := &userModel.User{Name: "Invited Person", Email: "invited@example.com", Version: 1}
+	s.Require().NoError(s.db.Create(invited).Error)
+	publish(userModel.NewUserRegistered(invited, userModel.RegistrationSourceInvite))
+	s.Equal(1, s.search("invited").Total)
+
+	publish(userModel.UserErased{UserID: invited.ID, ErasedBy: s.admin.ID})
+	hits, err = s.searcher.Search(s.ctx, search.Query{Text: "invited"})
+	s.Require().NoError(err)
+	s.Empty(hits)
+}
+
+func (s *SearchServiceTestSuite) TestPrivacyHookRemovesErasedUser() {
+	registered := s.register("Erin Erased", "erin@example.com")
+	s.Equal(1, s.search("erin").Total)
+
+	s.Require().NoError(NewPrivacyHook(s.searcher).Erase(s.ctx, s.db, registered.ID))
+	hits, err := s.searcher.Search(s.ctx, search.Query{Text: "erin"})
+	s.Require().NoError(err)
+	s.Empty(hits)
+}
@@ -0,0 +1,66 @@
+// Package testutil berisi setup bersama untuk test: database sqlite in-memory,
+// Redis dari miniredis dan validator Echo. Semua resource ditutup lewat t.Cleanup.
+package testutil
+
+import (
+	"testing"
+
+	pkgRedis "boilerplate/pkg/redis"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/glebarez/sqlite"
+	"github.com/go-playground/validator/v10"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// NewDB membuka database sqlite in-memory dan menjalankan AutoMigrate untuk models.
+// Koneksi dibatasi satu karena setiap koneksi file::memory: memiliki database sendiri.
+func NewDB(t testing.TB, models ...interface{}) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if len(models) > 0 {
+		require.NoError(t, db.AutoMigrate(models...))
+	}
+	return db
+}
+
+// NewRedis mengembalikan client go-redis ke miniredis baru
+func NewRedis(t testing.TB) *redis.Client {
+	t.Helper()
+
+	client := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// NewRedisClient mengembalikan RedisClient aplikasi ke miniredis baru
+func NewRedisClient(t testing.TB) *pkgRedis.RedisClient {
+	t.Helper()
+
+	client := pkgRedis.NewRedisClient(miniredis.RunT(t).Addr(), "", 0)
+	t.Cleanup(func() { client.Client().Close() })
+	return client
+}
+
+// Validator adalah echo.Validator untuk test handler, setara CustomValidator di container
+type Validator struct {
+	validator *validator.Validate
+}
+
+// NewValidator membuat Validator dengan aturan bawaan go-playground/validator
+func NewValidator() *Validator {
+	return &Validator{validator: validator.New()}
+}
+
+func (v *Validator) Validate(i interface{}) error {
+	return v.validator.Struct(i)
+}
@@ -0,0 +1,34 @@
+package model
+
+import "boilerplate/shared/constants"
+
+// Sumber pembuatan akun pada event UserRegistered
+const (
+	RegistrationSourceSignup = "signup"
+	RegistrationSourceAdmin  = "admin"
+	RegistrationSourceInvite = "invite"
+	RegistrationSourceOIDC   = "oidc"
+)
+
+// UserRegistered dicatat saat akun baru dibuat. Payload tidak memuat data pribadi;
+// subscriber membaca user dari database bila membutuhkan nama atau email.
+type UserRegistered struct {
+	UserID uint           `json:"user_id"`
+	Role   constants.Role `json:"role"`
+	Source string         `json:"source"`
+}
+
+func (UserRegistered) EventName() string { return "user.registered" }
+
+// UserErased dicatat saat data pribadi user dihapus
+type UserErased struct {
+	UserID   uint `json:"user_id"`
+	ErasedBy uint `json:"erased_by"`
+}
+
+func (UserErased) EventName() string { return "user.erased" }
+
+// NewUserRegistered membuat event untuk user yang baru disimpan
+func NewUserRegistered(user *User, source string) UserRegistered {
+	return UserRegistered{UserID: user.ID, Role: user.Role, Source: source}
+}
@@ -0,0 +1,111 @@
+package model
+
+import (
+	"time"
+
+	"boilerplate/pkg/fieldcrypt"
+	"boilerplate/pkg/useragent"
+
+	"gorm.io/gorm"
+)
+
+// Alasan kegagalan login yang dicatat di login_events
+const (
+	LoginReasonUnknownEmail    = "unknown_email"
+	LoginReasonInvalidPassword = "invalid_password"
+	LoginReasonAccountDisabled = "account_disabled"
+)
+
+const (
+	DefaultLoginEventLimit = 50
+	MaxLoginEventLimit     = 200
+)
+
+// LoginEvent mencatat satu percobaan login dengan password atau OIDC. UserID kosong
+// jika email tidak terdaftar.
+type LoginEvent struct {
+	ID     uint   `json:"id" gorm:"primaryKey"`
+	UserID *uint  `json:"user_id" gorm:"index"`
+	Email  string `json:"email" gorm:"size:512;serializer:encrypted"`
+	// EmailIndex adalah blind index email untuk filter login gagal per email
+	EmailIndex  *string   `json:"-" gorm:"size:64;index"`
+	Success     bool      `json:"success" gorm:"not null;default:false"`
+	Reason      string    `json:"reason,omitempty" gorm:"size:50"`
+	IP          string    `json:"ip" gorm:"size:45"`
+	UserAgent   string    `json:"user_agent" gorm:"size:512"`
+	Browser     string    `json:"browser" gorm:"size:50"`
+	OS          string    `json:"os" gorm:"size:50"`
+	DeviceType  string    `json:"device_type" gorm:"size:20"`
+	Fingerprint string    `json:"-" gorm:"size:64;index"`
+	CreatedAt   time.Time `json:"created_at" gorm:"index"`
+}
+
+// ClientInfo adalah informasi koneksi client yang diteruskan handler ke service
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+// DTO: Query login events
+type LoginEventQuery struct {
+	Limit int `query:"limit" validate:"omitempty,min=1,max=200"`
+}
+
+// DTO: Admin query failed logins
+type FailedLoginQuery struct {
+	Email string     `query:"email" validate:"omitempty,max=255"`
+	IP    string     `query:"ip" validate:"omitempty,ip"`
+	Since *time.Time `query:"since"`
+	Limit int        `query:"limit" validate:"omitempty,min=1,max=200"`
+}
+
+// Factory: Create login event from the client connection
+func NewLoginEvent(email string, client ClientInfo, now time.Time) *LoginEvent {
+	userAgent := client.UserAgent
+	if len(userAgent) > 512 {
+		userAgent = userAgent[:512]
+	}
+	device := useragent.Parse(userAgent)
+
+	return &LoginEvent{
+		Email:       email,
+		IP:          client.IP,
+		UserAgent:   userAgent,
+		Browser:     device.Browser,
+		OS:          device.OS,
+		DeviceType:  device.Type,
+		Fingerprint: device.Fingerprint(),
+		CreatedAt:   now,
+	}
+}
+
+// BeforeSave mengisi blind index email; login event hanya ditulis sebagai struct
+func (e *LoginEvent) BeforeSave(*gorm.DB) error {
+	if e.Email != "" {
+		e.EmailIndex = fieldcrypt.IndexOf(e.Email)
+	}
+	return nil
+}
+
+// LoginEventByEmail mencari login event berdasarkan blind index email
+func LoginEventByEmail(email string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(fieldcrypt.IndexCondition("login_events.email", "login_events.email_index", email))
+	}
+}
+
+// Device returns the parsed device of the event
+func (e *LoginEvent) Device() useragent.Device {
+	return useragent.Device{Browser: e.Browser, OS: e.OS, Type: e.DeviceType}
+}
+
+// LoginEventLimit returns limit clamped to the allowed range, or the default when unset
+func LoginEventLimit(limit int) int {
+	if limit <= 0 {
+		return DefaultLoginEventLimit
+	}
+	if limit > MaxLoginEventLimit {
+		return MaxLoginEventLimit
+	}
+	return limit
+}
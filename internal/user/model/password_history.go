@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+
+	"boilerplate/pkg/password"
+)
+
+// PasswordHistory menyimpan hash password lama untuk aturan riwayat password policy
+type PasswordHistory struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"index"`
+	Hash      string    `json:"-" gorm:"size:255"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Matches reports whether plain produced this historical hash
+func (h PasswordHistory) Matches(plain string) bool {
+	ok, err := password.Verify(h.Hash, plain)
+	return err == nil && ok
+}
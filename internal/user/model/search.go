@@ -0,0 +1,20 @@
+package model
+
+import "boilerplate/pkg/search"
+
+// SearchType adalah jenis dokumen user di index pencarian
+const SearchType = "user"
+
+// SearchDocument mengembalikan nama dan email user. Dokumen ditandai sensitif karena
+// kedua kolom terenkripsi di database.
+func (u *User) SearchDocument() search.Document {
+	return search.Document{
+		Type: SearchType,
+		ID:   u.ID,
+		Fields: map[string]string{
+			"name":  u.Name,
+			"email": u.Email,
+		},
+		Sensitive: true,
+	}
+}
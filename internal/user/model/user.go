@@ -1,24 +1,41 @@
 package model
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
+	"boilerplate/pkg/fieldcrypt"
+	"boilerplate/pkg/password"
 	"boilerplate/shared/constants"
 	errs "boilerplate/shared/errors"
 
-	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 type User struct {
-	ID        uint           `json:"id" gorm:"primaryKey"`
-	Name      string         `json:"name" gorm:"size:50" validate:"required,min=2,max=50"`
-	Email     string         `json:"email" gorm:"unique" validate:"required,email"`
-	Password  string         `json:"-"`
-	Role      constants.Role `json:"role"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
+	ID    uint   `json:"id" gorm:"primaryKey"`
+	Name  string `json:"name" gorm:"size:512;serializer:encrypted" validate:"required,min=2,max=50"`
+	Email string `json:"email" gorm:"size:512;serializer:encrypted" validate:"required,email"`
+	// EmailIndex adalah blind index (HMAC) email untuk pencarian dan satu-satunya sumber
+	// keunikan email karena ciphertext email berbeda untuk plaintext yang sama.
+	EmailIndex            *string        `json:"-" gorm:"size:64;uniqueIndex"`
+	Password              string         `json:"-"`
+	Role                  constants.Role `json:"role"`
+	Version               uint           `json:"version" gorm:"not null;default:1"`
+	DisabledAt            *time.Time     `json:"disabled_at"`
+	PasswordResetRequired bool           `json:"password_reset_required" gorm:"not null;default:false"`
+	ErasedAt              *time.Time     `json:"erased_at,omitempty"`
+	CreatedAt             time.Time      `json:"created_at"`
+	UpdatedAt             time.Time      `json:"updated_at"`
 }
 
+// encryptedColumns adalah kolom users yang dienkripsi serializer fieldcrypt
+var encryptedColumns = []string{"name", "email"}
+
+// ErasedName menggantikan nama user yang datanya telah dihapus
+const ErasedName = "Deleted user"
+
 // DTO: Register input
 type RegisterInput struct {
 	Name     string `json:"name" validate:"required,min=2,max=50"`
@@ -39,16 +56,49 @@ type UpdateProfileInput struct {
 	Password string `json:"password,omitempty" validate:"omitempty,min=6"`
 }
 
+// DTO: Admin create user input
+type AdminCreateUserInput struct {
+	Name     string         `json:"name" validate:"required,min=2,max=50"`
+	Email    string         `json:"email" validate:"required,email"`
+	Password string         `json:"password" validate:"required,min=6"`
+	Role     constants.Role `json:"role" validate:"required,oneof=admin user"`
+}
+
+// DTO: Change role input
+type ChangeRoleInput struct {
+	Role constants.Role `json:"role" validate:"required,oneof=admin user"`
+}
+
+// DTO: Impersonation result
+type ImpersonationResult struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+	User      *User     `json:"user"`
+}
+
+// DTO: Patch profile input, hasil penerapan merge patch ke PatchDocument
+type PatchProfileInput struct {
+	Name     string `json:"name" validate:"required,min=2,max=50"`
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password,omitempty" validate:"omitempty,min=6"`
+}
+
 // Factory: Create new user from register input
 func NewUser(input RegisterInput) (*User, error) {
+	return NewUserWithRole(input, constants.RoleUser)
+}
+
+// Factory: Create new user with an explicit role (admin create, invites)
+func NewUserWithRole(input RegisterInput, role constants.Role) (*User, error) {
 	if len(input.Password) < constants.PasswordMinLength {
 		return nil, errs.ErrShortPassword
 	}
 
 	user := &User{
-		Name:  input.Name,
-		Email: input.Email,
-		Role:  constants.RoleUser,
+		Name:    input.Name,
+		Email:   input.Email,
+		Role:    role,
+		Version: 1,
 	}
 
 	if err := user.SetPassword(input.Password); err != nil {
@@ -58,26 +108,135 @@ func NewUser(input RegisterInput) (*User, error) {
 	return user, nil
 }
 
-// Password setter with hashing
-func (u *User) SetPassword(password string) error {
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), constants.BcryptCost)
+// Factory: Create new user from an external identity provider. Password
+// dibiarkan kosong sehingga login dengan password tidak mungkin dilakukan.
+func NewExternalUser(name, email string) *User {
+	return &User{
+		Name:    name,
+		Email:   email,
+		Role:    constants.RoleUser,
+		Version: 1,
+	}
+}
+
+// ByEmail mencari user berdasarkan blind index email. Baris plaintext yang belum
+// di-enkripsi ulang dicocokkan dengan kolom email sampai command reencrypt dijalankan.
+func ByEmail(email string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(fieldcrypt.IndexCondition("users.email", "users.email_index", email))
+	}
+}
+
+// BeforeSave mengisi blind index email. Serializer GORM tidak berlaku untuk update
+// dengan map, sehingga kolom terenkripsi pada map dienkripsi di sini.
+func (u *User) BeforeSave(tx *gorm.DB) error {
+	changes, ok := tx.Statement.Dest.(map[string]interface{})
+	if !ok {
+		if u.Email != "" {
+			u.EmailIndex = fieldcrypt.IndexOf(u.Email)
+		}
+		return nil
+	}
+	return fieldcrypt.EncryptChanges(changes, encryptedColumns, map[string]string{"email": "email_index"})
+}
+
+// Password setter with hashing, memakai hasher yang dikonfigurasi saat startup
+func (u *User) SetPassword(plain string) error {
+	hashedPassword, err := password.Hash(plain)
 	if err != nil {
 		return err
 	}
-	u.Password = string(hashedPassword)
+	u.Password = hashedPassword
 	return nil
 }
 
 // Password checker
-func (u *User) CheckPassword(password string) error {
-	err := bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(password))
-	if err != nil {
+func (u *User) CheckPassword(plain string) error {
+	if ok, err := password.Verify(u.Password, plain); err != nil || !ok {
 		return errs.ErrInvalidPassword
 	}
 	return nil
 }
 
+// PasswordNeedsRehash menandakan hash tersimpan memakai algoritma atau parameter lama
+func (u *User) PasswordNeedsRehash() bool {
+	return u.Password != "" && password.NeedsRehash(u.Password)
+}
+
+// PatchDocument mengembalikan representasi user yang dapat di-patch
+func (u *User) PatchDocument() PatchProfileInput {
+	return PatchProfileInput{
+		Name:  u.Name,
+		Email: u.Email,
+	}
+}
+
+// ApplyPatch menerapkan patch yang sudah divalidasi dan mengembalikan kolom yang berubah saja
+func (u *User) ApplyPatch(input PatchProfileInput) (map[string]interface{}, error) {
+	changes := make(map[string]interface{})
+
+	if name := strings.TrimSpace(input.Name); name != u.Name {
+		u.Name = name
+		changes["name"] = name
+	}
+	if email := strings.TrimSpace(input.Email); email != u.Email {
+		u.Email = email
+		changes["email"] = email
+	}
+	if input.Password != "" {
+		if err := u.SetPassword(strings.TrimSpace(input.Password)); err != nil {
+			return nil, errs.ErrHashingPassword
+		}
+		changes["password"] = u.Password
+		if u.PasswordResetRequired {
+			u.PasswordResetRequired = false
+			changes["password_reset_required"] = false
+		}
+	}
+
+	return changes, nil
+}
+
 // Role checker
 func (u *User) IsAdmin() bool {
 	return u.Role == constants.RoleAdmin
 }
+
+// IsDisabled menandakan akun telah dinonaktifkan admin
+func (u *User) IsDisabled() bool {
+	return u.DisabledAt != nil
+}
+
+// IsErased menandakan data pribadi user telah dihapus
+func (u *User) IsErased() bool {
+	return u.ErasedAt != nil
+}
+
+// ErasedEmail adalah placeholder unik yang menggantikan email user yang datanya telah dihapus
+func ErasedEmail(userID uint) string {
+	return fmt.Sprintf("erased-%d@erased.invalid", userID)
+}
+
+// Anonymize mengganti data pribadi dengan placeholder dan menonaktifkan akun.
+// Baris user tetap ada agar referensi dari modul lain (mis. categories.created_by) tetap valid.
+func (u *User) Anonymize(now time.Time) map[string]interface{} {
+	u.Name = ErasedName
+	u.Email = ErasedEmail(u.ID)
+	u.Password = ""
+	u.PasswordResetRequired = false
+	u.ErasedAt = &now
+	if u.DisabledAt == nil {
+		u.DisabledAt = &now
+	}
+	u.Version++
+
+	return map[string]interface{}{
+		"name":                    u.Name,
+		"email":                   u.Email,
+		"password":                u.Password,
+		"password_reset_required": false,
+		"erased_at":               u.ErasedAt,
+		"disabled_at":             u.DisabledAt,
+		"version":                 u.Version,
+	}
+}
@@ -3,6 +3,7 @@ package model
 import (
 	"testing"
 
+	"boilerplate/pkg/patch"
 	"boilerplate/shared/constants"
 	errs "boilerplate/shared/errors"
 
@@ -177,3 +178,36 @@ func (s *UserTestSuite) TestIsAdmin() {
 		})
 	}
 }
+
+func (s *UserTestSuite) TestApplyPatch() {
+	tests := []struct {
+		name          string
+		patch         string
+		resetRequired bool
+		expected      map[string]interface{}
+	}{
+		{name: "absent fields are unchanged", patch: `{}`, expected: map[string]interface{}{}},
+		{name: "same value is not a change", patch: `{"name":"Jane Doe"}`, expected: map[string]interface{}{}},
+		{name: "only changed column", patch: `{"name":"Jane Roe"}`, expected: map[string]interface{}{"name": "Jane Roe"}},
+		{name: "email is trimmed", patch: `{"email":" roe@example.com "}`, expected: map[string]interface{}{"email": "roe@example.com"}},
+		{name: "null clears the field", patch: `{"name":null}`, expected: map[string]interface{}{"name": ""}},
+		{name: "password clears reset flag", patch: `{"password":"newpassword123"}`, resetRequired: true, expected: map[string]interface{}{"password_reset_required": false}},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			user := &User{Name: "Jane Doe", Email: "jane@example.com", PasswordResetRequired: tt.resetRequired}
+			var input PatchProfileInput
+			s.Require().NoError(patch.ApplyTo(patch.MergePatchContentType, user.PatchDocument(), []byte(tt.patch), &input))
+
+			changes, err := user.ApplyPatch(input)
+			s.Require().NoError(err)
+			if hash, ok := changes["password"]; ok {
+				s.NoError(user.CheckPassword("newpassword123"))
+				s.Equal(user.Password, hash)
+				delete(changes, "password")
+			}
+			s.Equal(tt.expected, changes)
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package user
+
+import (
+	"context"
+
+	"boilerplate/internal/user/model"
+
+	"gorm.io/gorm"
+)
+
+// PrivacyHook mengekspor profil dan riwayat login user serta menghapus riwayat
+// password dan login saat erasure. Anonimisasi baris user dilakukan oleh modul privacy.
+type PrivacyHook struct{}
+
+func NewPrivacyHook() *PrivacyHook {
+	return &PrivacyHook{}
+}
+
+// userExport adalah data user di file export
+type userExport struct {
+	Profile     model.User         `json:"profile"`
+	LoginEvents []model.LoginEvent `json:"login_events"`
+}
+
+func (h *PrivacyHook) Name() string {
+	return "user"
+}
+
+func (h *PrivacyHook) Export(ctx context.Context, db *gorm.DB, userID uint) (interface{}, error) {
+	var data userExport
+	if err := db.WithContext(ctx).First(&data.Profile, userID).Error; err != nil {
+		return nil, err
+	}
+	if err := db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at").Find(&data.LoginEvents).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (h *PrivacyHook) Erase(ctx context.Context, tx *gorm.DB, userID uint) error {
+	if err := tx.WithContext(ctx).Where("user_id = ?", userID).Delete(&model.PasswordHistory{}).Error; err != nil {
+		return err
+	}
+	return tx.WithContext(ctx).Where("user_id = ?", userID).Delete(&model.LoginEvent{}).Error
+}
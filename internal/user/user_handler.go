@@ -1,37 +1,51 @@
 package user
 
 import (
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"boilerplate/internal/user/model"
+	"boilerplate/pkg/etag"
+	"boilerplate/pkg/patch"
 	"boilerplate/pkg/response"
+	errs "boilerplate/shared/errors"
 
 	"github.com/labstack/echo/v4"
 )
 
 type UserHandler struct {
-	userService UserServiceInterface
+	userService          UserServiceInterface
+	requireIfMatch       bool
+	registrationDisabled bool
 }
 
-func NewUserHandler(userService UserServiceInterface) *UserHandler {
+func NewUserHandler(userService UserServiceInterface, requireIfMatch, registrationDisabled bool) *UserHandler {
 	return &UserHandler{
-		userService: userService,
+		userService:          userService,
+		requireIfMatch:       requireIfMatch,
+		registrationDisabled: registrationDisabled,
 	}
 }
 
 func (h *UserHandler) Register(c echo.Context) error {
+	if h.registrationDisabled {
+		return errs.ErrRegistrationDisabled
+	}
+
 	var input model.RegisterInput
 	if err := c.Bind(&input); err != nil {
-		return response.BadRequest(c, "invalid request payload", err)
+		return errs.ErrInvalidPayload.Wrap(err)
 	}
 
 	if err := c.Validate(&input); err != nil {
-		return response.ValidationError(c, err)
+		return err
 	}
 
 	user, err := h.userService.Register(input)
 	if err != nil {
-		return response.BadRequest(c, "registration failed", err)
+		return err
 	}
 
 	return response.Success(c, http.StatusCreated, "User registered successfully", user)
@@ -40,16 +54,19 @@ func (h *UserHandler) Register(c echo.Context) error {
 func (h *UserHandler) Login(c echo.Context) error {
 	var input model.LoginInput
 	if err := c.Bind(&input); err != nil {
-		return response.BadRequest(c, "invalid request payload", err)
+		return errs.ErrInvalidPayload.Wrap(err)
 	}
 
 	if err := c.Validate(&input); err != nil {
-		return response.ValidationError(c, err)
+		return err
 	}
 
-	token, err := h.userService.Login(input)
+	token, err := h.userService.Login(input, model.ClientInfo{
+		IP:        c.RealIP(),
+		UserAgent: c.Request().UserAgent(),
+	})
 	if err != nil {
-		return response.Unauthorized(c, "invalid credentials", err)
+		return err
 	}
 
 	return response.Success(c, http.StatusOK, "Login successful", map[string]string{
@@ -58,10 +75,18 @@ func (h *UserHandler) Login(c echo.Context) error {
 }
 
 func (h *UserHandler) Logout(c echo.Context) error {
+	// logout dari sesi impersonasi hanya mengakhiri impersonasi, bukan sesi user
+	if tokenID, ok := c.Get("impersonation_id").(string); ok {
+		if err := h.userService.EndImpersonation(tokenID); err != nil {
+			return err
+		}
+		return response.Success(c, http.StatusOK, "Impersonation ended", nil)
+	}
+
 	userID := c.Get("user_id").(uint)
 
 	if err := h.userService.Logout(userID); err != nil {
-		return response.InternalServerError(c, "logout failed", err)
+		return err
 	}
 
 	return response.Success(c, http.StatusOK, "Logout successful", nil)
@@ -72,7 +97,13 @@ func (h *UserHandler) GetMe(c echo.Context) error {
 
 	user, err := h.userService.GetUserByID(userID)
 	if err != nil {
-		return response.NotFound(c, "user not found", err)
+		return err
+	}
+
+	tag := etag.Generate(user.ID, user.Version)
+	etag.Set(c, tag)
+	if etag.NotModified(c, tag) {
+		return c.NoContent(http.StatusNotModified)
 	}
 
 	return response.Success(c, http.StatusOK, "User profile retrieved successfully", user)
@@ -83,36 +114,242 @@ func (h *UserHandler) UpdateProfile(c echo.Context) error {
 
 	var input model.UpdateProfileInput
 	if err := c.Bind(&input); err != nil {
-		return response.BadRequest(c, "invalid request payload", err)
+		return errs.ErrInvalidPayload.Wrap(err)
 	}
 
 	if err := c.Validate(&input); err != nil {
-		return response.ValidationError(c, err)
+		return err
 	}
 
-	user, err := h.userService.UpdateProfile(userID, input)
+	current, err := h.userService.GetUserForUpdate(userID)
 	if err != nil {
-		return response.BadRequest(c, "failed to update profile", err)
+		return err
 	}
 
+	if impersonated(c) && changesCredentials(current, input.Email, input.Password) {
+		return errs.ErrImpersonationForbidden
+	}
+
+	if err := etag.CheckIfMatch(c, etag.Generate(current.ID, current.Version), h.requireIfMatch); err != nil {
+		return err
+	}
+
+	user, err := h.userService.UpdateProfile(current, input)
+	if err != nil {
+		return etag.ConflictError(c, err)
+	}
+
+	etag.Set(c, etag.Generate(user.ID, user.Version))
 	return response.Success(c, http.StatusOK, "Profile updated successfully", user)
 }
 
-func (h *UserHandler) DeleteAccount(c echo.Context) error {
+func (h *UserHandler) PatchProfile(c echo.Context) error {
 	userID := c.Get("user_id").(uint)
 
-	if err := h.userService.DeleteAccount(userID); err != nil {
-		return response.InternalServerError(c, "failed to delete account", err)
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return errs.ErrInvalidPayload.Wrap(err)
+	}
+
+	current, err := h.userService.GetUserForUpdate(userID)
+	if err != nil {
+		return err
+	}
+
+	if err := etag.CheckIfMatch(c, etag.Generate(current.ID, current.Version), h.requireIfMatch); err != nil {
+		return err
+	}
+
+	var input model.PatchProfileInput
+	if err := patch.ApplyTo(c.Request().Header.Get(echo.HeaderContentType), current.PatchDocument(), body, &input); err != nil {
+		return err
+	}
+
+	if err := c.Validate(&input); err != nil {
+		return err
+	}
+
+	if impersonated(c) && changesCredentials(current, input.Email, input.Password) {
+		return errs.ErrImpersonationForbidden
+	}
+
+	user, err := h.userService.PatchProfile(current, input)
+	if err != nil {
+		return etag.ConflictError(c, err)
 	}
 
-	return response.Success(c, http.StatusOK, "Account deleted successfully", nil)
+	etag.Set(c, etag.Generate(user.ID, user.Version))
+	return response.Success(c, http.StatusOK, "Profile updated successfully", user)
 }
 
 func (h *UserHandler) GetAllUsers(c echo.Context) error {
 	users, err := h.userService.GetAllUsers()
 	if err != nil {
-		return response.InternalServerError(c, "failed to get all users", err)
+		return err
 	}
 
 	return response.Success(c, http.StatusOK, "Users retrieved successfully", users)
 }
+
+// GetMyLogins mengembalikan riwayat login user yang sedang login
+func (h *UserHandler) GetMyLogins(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+
+	var query model.LoginEventQuery
+	if err := c.Bind(&query); err != nil {
+		return errs.ErrInvalidPayload.Wrap(err)
+	}
+
+	if err := c.Validate(&query); err != nil {
+		return err
+	}
+
+	events, err := h.userService.GetLoginHistory(userID, query.Limit)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, http.StatusOK, "Login history retrieved successfully", events)
+}
+
+// GetFailedLogins mengembalikan login gagal dari semua user; hanya untuk admin
+func (h *UserHandler) GetFailedLogins(c echo.Context) error {
+	var query model.FailedLoginQuery
+	if err := c.Bind(&query); err != nil {
+		return errs.ErrInvalidPayload.Wrap(err)
+	}
+
+	if err := c.Validate(&query); err != nil {
+		return err
+	}
+
+	events, err := h.userService.GetFailedLogins(query)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, http.StatusOK, "Failed logins retrieved successfully", events)
+}
+
+func (h *UserHandler) CreateUser(c echo.Context) error {
+	var input model.AdminCreateUserInput
+	if err := c.Bind(&input); err != nil {
+		return errs.ErrInvalidPayload.Wrap(err)
+	}
+
+	if err := c.Validate(&input); err != nil {
+		return err
+	}
+
+	user, err := h.userService.CreateUser(input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, http.StatusCreated, "User created successfully", user)
+}
+
+func (h *UserHandler) ChangeRole(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return errs.ErrInvalidID.Wrap(err)
+	}
+
+	var input model.ChangeRoleInput
+	if err := c.Bind(&input); err != nil {
+		return errs.ErrInvalidPayload.Wrap(err)
+	}
+
+	if err := c.Validate(&input); err != nil {
+		return err
+	}
+
+	user, err := h.userService.ChangeRole(uint(id), input.Role)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, http.StatusOK, "User role updated successfully", user)
+}
+
+func (h *UserHandler) DisableUser(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return errs.ErrInvalidID.Wrap(err)
+	}
+
+	user, err := h.userService.DisableUser(uint(id))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, http.StatusOK, "User disabled successfully", user)
+}
+
+func (h *UserHandler) EnableUser(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return errs.ErrInvalidID.Wrap(err)
+	}
+
+	user, err := h.userService.EnableUser(uint(id))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, http.StatusOK, "User enabled successfully", user)
+}
+
+func (h *UserHandler) ForcePasswordReset(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return errs.ErrInvalidID.Wrap(err)
+	}
+
+	user, err := h.userService.ForcePasswordReset(uint(id))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, http.StatusOK, "Password reset required on next login", user)
+}
+
+func (h *UserHandler) RevokeTokens(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return errs.ErrInvalidID.Wrap(err)
+	}
+
+	if err := h.userService.RevokeTokens(uint(id)); err != nil {
+		return err
+	}
+
+	return response.Success(c, http.StatusOK, "User tokens revoked successfully", nil)
+}
+
+func (h *UserHandler) Impersonate(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return errs.ErrInvalidID.Wrap(err)
+	}
+
+	actor := c.Get("user").(*model.User)
+	result, err := h.userService.Impersonate(actor, uint(id))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, http.StatusCreated, "Impersonation started", result)
+}
+
+// impersonated menandakan request memakai token impersonasi
+func impersonated(c echo.Context) bool {
+	return c.Get("actor") != nil
+}
+
+// changesCredentials menandakan input mengganti password atau email user. Keduanya
+// dapat dipakai mengambil alih akun sehingga ditolak selama impersonasi.
+func changesCredentials(current *model.User, email, password string) bool {
+	email = strings.TrimSpace(email)
+	return password != "" || (email != "" && email != current.Email)
+}
@@ -0,0 +1,113 @@
+package user
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"boilerplate/internal/testutil"
+	"boilerplate/internal/user/model"
+	"boilerplate/pkg/events"
+	"boilerplate/pkg/logger"
+	"boilerplate/pkg/mailer"
+	"boilerplate/pkg/password"
+	"boilerplate/pkg/patch"
+	"boilerplate/shared/constants"
+	errs "boilerplate/shared/errors"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/suite"
+)
+
+type UserHandlerTestSuite struct {
+	suite.Suite
+	e       *echo.Echo
+	handler *UserHandler
+	service *UserService
+	user    *model.User
+	admin   *model.User
+}
+
+func TestUserHandlerSuite(t *testing.T) {
+	suite.Run(t, new(UserHandlerTestSuite))
+}
+
+func (s *UserHandlerTestSuite) SetupTest() {
+	db := testutil.NewDB(s.T(), &model.User{}, &model.PasswordHistory{}, &model.LoginEvent{}, &events.OutboxMessage{})
+	log := logger.NewLogger()
+	s.service = NewUserService(db, "test-secret", log, testutil.NewRedisClient(s.T()), password.DefaultPolicy(), mailer.NewLogMailer(log), nil, nil)
+	s.handler = NewUserHandler(s.service, false, false)
+	s.e = echo.New()
+	s.e.Validator = testutil.NewValidator()
+
+	var err error
+	s.admin, err = s.service.CreateUser(model.AdminCreateUserInput{
+		Name: "Admin", Email: "admin@example.com", Password: "password123", Role: constants.RoleAdmin,
+	})
+	s.Require().NoError(err)
+	s.user, err = s.service.CreateUser(model.AdminCreateUserInput{
+		Name: "Jane Doe", Email: "jane@example.com", Password: "password123", Role: constants.RoleUser,
+	})
+	s.Require().NoError(err)
+}
+
+// context membuat request atas nama user; actor diisi seperti AuthMiddleware saat impersonasi
+func (s *UserHandlerTestSuite) context(method, contentType, body string, actor *model.User) (echo.Context, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(method, "/", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, contentType)
+	rec := httptest.NewRecorder()
+	c := s.e.NewContext(req, rec)
+	c.Set("user_id", s.user.ID)
+	if actor != nil {
+		c.Set("actor", actor)
+	}
+	return c, rec
+}
+
+func (s *UserHandlerTestSuite) TestImpersonationCannotChangeCredentials() {
+	tests := []struct {
+		name          string
+		put           string
+		patch         string
+		impersonating bool
+		expectedErr   error
+	}{
+		{name: "email while impersonating", put: `{"name":"Jane Doe","email":"attacker@example.com"}`, patch: `{"email":"attacker@example.com"}`, impersonating: true, expectedErr: errs.ErrImpersonationForbidden},
+		{name: "password while impersonating", put: `{"name":"Jane Doe","password":"attacker-secret"}`, patch: `{"password":"attacker-secret"}`, impersonating: true, expectedErr: errs.ErrImpersonationForbidden},
+		{name: "name while impersonating", put: `{"name":"Jane Smith","email":"jane@example.com"}`, patch: `{"name":"Jane Smith"}`, impersonating: true},
+		{name: "email by the user", put: `{"name":"Jane Doe","email":"jane.doe@example.com"}`, patch: `{"email":"jane.doe@example.com"}`},
+	}
+
+	for _, tt := range tests {
+		var actor *model.User
+		if tt.impersonating {
+			actor = s.admin
+		}
+
+		s.Run("PUT "+tt.name, func() {
+			s.SetupTest()
+			c, rec := s.context(http.MethodPut, echo.MIMEApplicationJSON, tt.put, actor)
+			s.assertUpdate(s.handler.UpdateProfile(c), rec, tt.expectedErr)
+		})
+		s.Run("PATCH "+tt.name, func() {
+			s.SetupTest()
+			c, rec := s.context(http.MethodPatch, patch.MergePatchContentType, tt.patch, actor)
+			s.assertUpdate(s.handler.PatchProfile(c), rec, tt.expectedErr)
+		})
+	}
+}
+
+func (s *UserHandlerTestSuite) assertUpdate(err error, rec *httptest.ResponseRecorder, expectedErr error) {
+	if expectedErr == nil {
+		s.Require().NoError(err)
+		s.Equal(http.StatusOK, rec.Code)
+		return
+	}
+
+	s.ErrorIs(err, expectedErr)
+	stored, getErr := s.service.GetUserForUpdate(s.user.ID)
+	s.Require().NoError(getErr)
+	s.Equal("jane@example.com", stored.Email)
+	s.NoError(stored.CheckPassword("password123"))
+}
@@ -2,30 +2,55 @@ package user
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
+	apiKeyModel "boilerplate/internal/apikey/model"
 	"boilerplate/internal/user/model"
+	"boilerplate/pkg/cache"
+	"boilerplate/pkg/database"
+	"boilerplate/pkg/events"
 	"boilerplate/pkg/jwt"
 	"boilerplate/pkg/logger"
+	"boilerplate/pkg/mailer"
+	"boilerplate/pkg/password"
 	"boilerplate/pkg/redis"
+	"boilerplate/pkg/search"
+	"boilerplate/shared/constants"
 	userErr "boilerplate/shared/errors"
 
 	"github.com/sirupsen/logrus"
-	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // UserServiceInterface mendefinisikan kontrak untuk UserService
 type UserServiceInterface interface {
 	Register(input model.RegisterInput) (*model.User, error)
-	Login(input model.LoginInput) (string, error)
+	Login(input model.LoginInput, client model.ClientInfo) (string, error)
+	IssueToken(user *model.User, client model.ClientInfo) (string, error)
 	GetUserByID(userID uint) (*model.User, error)
 	GetStoredToken(ctx context.Context, userID uint) (string, error)
 	Logout(userID uint) error
-	UpdateProfile(userID uint, input model.UpdateProfileInput) (*model.User, error)
-	DeleteAccount(userID uint) error
+	GetUserForUpdate(userID uint) (*model.User, error)
+	UpdateProfile(user *model.User, input model.UpdateProfileInput) (*model.User, error)
+	PatchProfile(user *model.User, input model.PatchProfileInput) (*model.User, error)
 	GetAllUsers() ([]model.User, error)
+	CreateUser(input model.AdminCreateUserInput) (*model.User, error)
+	ChangeRole(userID uint, role constants.Role) (*model.User, error)
+	DisableUser(userID uint) (*model.User, error)
+	EnableUser(userID uint) (*model.User, error)
+	ForcePasswordReset(userID uint) (*model.User, error)
+	RevokeTokens(userID uint) error
+	Impersonate(actor *model.User, userID uint) (*model.ImpersonationResult, error)
+	GetImpersonationToken(ctx context.Context, tokenID string) (string, error)
+	EndImpersonation(tokenID string) error
+	GetLoginHistory(userID uint, limit int) ([]model.LoginEvent, error)
+	GetFailedLogins(query model.FailedLoginQuery) ([]model.LoginEvent, error)
+	PurgeLoginEvents(ctx context.Context, before time.Time) (int64, error)
 }
 
 type UserService struct {
@@ -33,9 +58,15 @@ type UserService struct {
 	jwtSecret   string
 	logger      logger.Logger
 	redisClient *redis.RedisClient
+	policy      password.Policy
+	mailer      mailer.Mailer
+	cache       *cache.Loader
+	indexer     *search.Indexer
 }
 
-func NewUserService(db *gorm.DB, jwtSecret string, logger logger.Logger, redisClient *redis.RedisClient) *UserService {
+// NewUserService membuat service user. cache nil menonaktifkan cache GetUserByID;
+// indexer nil menonaktifkan sinkronisasi index pencarian.
+func NewUserService(db *gorm.DB, jwtSecret string, logger logger.Logger, redisClient *redis.RedisClient, policy password.Policy, mailer mailer.Mailer, cache *cache.Loader, indexer *search.Indexer) *UserService {
 	if db == nil {
 		panic("database connection is required")
 	}
@@ -48,18 +79,33 @@ func NewUserService(db *gorm.DB, jwtSecret string, logger logger.Logger, redisCl
 	if jwtSecret == "" {
 		panic("jwt secret is required")
 	}
+	if mailer == nil {
+		panic("mailer is required")
+	}
 
 	return &UserService{
 		db:          db,
 		jwtSecret:   jwtSecret,
 		logger:      logger,
 		redisClient: redisClient,
+		policy:      policy,
+		mailer:      mailer,
+		cache:       cache,
+		indexer:     indexer,
 	}
 }
 
+// Subscribe menginvalidasi cache user yang dihapus lewat modul privacy, yang
+// mengubah baris user di luar service ini
+func (s *UserService) Subscribe(bus *events.Bus) {
+	events.Subscribe(bus, func(ctx context.Context, _ events.Envelope, event model.UserErased) error {
+		return s.cache.Invalidate(ctx, userTag(event.UserID))
+	})
+}
+
 func (s *UserService) Register(input model.RegisterInput) (*model.User, error) {
 	var existingUser model.User
-	if err := s.db.Where("email = ?", input.Email).First(&existingUser).Error; err == nil {
+	if err := s.db.Scopes(model.ByEmail(input.Email)).First(&existingUser).Error; err == nil {
 		s.logger.WithFields(logrus.Fields{
 			"email": input.Email,
 			"error": userErr.ErrEmailAlreadyRegistered.Error(),
@@ -67,6 +113,10 @@ func (s *UserService) Register(input model.RegisterInput) (*model.User, error) {
 		return nil, userErr.ErrEmailAlreadyRegistered
 	}
 
+	if err := s.policy.Validate(input.Password, input.Email, input.Name); err != nil {
+		return nil, err
+	}
+
 	user, err := model.NewUser(input)
 	if err != nil {
 		s.logger.WithFields(logrus.Fields{
@@ -76,7 +126,7 @@ func (s *UserService) Register(input model.RegisterInput) (*model.User, error) {
 		return nil, err
 	}
 
-	if err := s.db.Create(user).Error; err != nil {
+	if err := s.createUser(user, model.RegistrationSourceSignup); err != nil {
 		s.logger.WithFields(logrus.Fields{
 			"email": input.Email,
 			"error": err.Error(),
@@ -87,24 +137,210 @@ func (s *UserService) Register(input model.RegisterInput) (*model.User, error) {
 	return user, nil
 }
 
-func (s *UserService) Login(input model.LoginInput) (string, error) {
+// createUser menyimpan user baru beserta event UserRegistered dalam satu transaksi
+func (s *UserService) createUser(user *model.User, source string) error {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		return events.Record(tx, model.NewUserRegistered(user, source))
+	})
+	if err != nil {
+		return err
+	}
+	s.index(user)
+	return nil
+}
+
+func (s *UserService) Login(input model.LoginInput, client model.ClientInfo) (string, error) {
+	event := model.NewLoginEvent(input.Email, client, time.Now())
+
 	var user model.User
-	if err := s.db.Where("email = ?", input.Email).First(&user).Error; err != nil {
+	if err := s.db.Scopes(model.ByEmail(input.Email)).First(&user).Error; err != nil {
 		s.logger.WithFields(logrus.Fields{
 			"email": input.Email,
 			"error": userErr.ErrInvalidCredentials.Error(),
 		}).Error("Kredensial login tidak valid")
+		s.recordLoginFailure(event, model.LoginReasonUnknownEmail)
 		return "", userErr.ErrInvalidCredentials
 	}
-
 	if err := user.CheckPassword(input.Password); err != nil {
 		s.logger.WithFields(logrus.Fields{
 			"email": input.Email,
 			"error": userErr.ErrInvalidCredentials.Error(),
 		}).Error("Password tidak valid")
+		event.UserID = &user.ID
+		s.recordLoginFailure(event, model.LoginReasonInvalidPassword)
 		return "", userErr.ErrInvalidCredentials
 	}
 
+	if user.PasswordNeedsRehash() {
+		s.rehashPassword(&user, input.Password)
+	}
+
+	return s.issueToken(&user, event)
+}
+
+// recordLoginFailure mencatat login gagal. Kegagalan menyimpan hanya dicatat di log
+// agar riwayat login tidak mengubah hasil login.
+func (s *UserService) recordLoginFailure(event *model.LoginEvent, reason string) {
+	event.Reason = reason
+	if err := s.db.Create(event).Error; err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"email": event.Email,
+			"error": err.Error(),
+		}).Error("Gagal menyimpan riwayat login")
+	}
+}
+
+// recordLoginSuccess mencatat login berhasil dan mengirim notifikasi jika perangkat
+// belum pernah dipakai. Login pertama user tidak dianggap perangkat baru.
+func (s *UserService) recordLoginSuccess(user *model.User, event *model.LoginEvent) {
+	event.Success = true
+
+	var previous, sameDevice int64
+	err := s.db.Model(&model.LoginEvent{}).
+		Where("user_id = ? AND success = ?", user.ID, true).
+		Count(&previous).Error
+	if err == nil && previous > 0 {
+		err = s.db.Model(&model.LoginEvent{}).
+			Where("user_id = ? AND success = ? AND fingerprint = ?", user.ID, true, event.Fingerprint).
+			Count(&sameDevice).Error
+	}
+	if err == nil {
+		err = s.db.Create(event).Error
+	}
+	if err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"user_id": user.ID,
+			"error":   err.Error(),
+		}).Error("Gagal menyimpan riwayat login")
+		return
+	}
+
+	if previous > 0 && sameDevice == 0 {
+		s.notifyNewDevice(user, event)
+	}
+}
+
+// notifyNewDevice mengantrikan email peringatan login dari perangkat baru. Mailer
+// hanya menyimpan job ke queue sehingga dipanggil langsung di request login dan
+// pengirimannya tetap berjalan walaupun instance berhenti setelah response dikirim.
+func (s *UserService) notifyNewDevice(user *model.User, event *model.LoginEvent) {
+	ctx, cancel := context.WithTimeout(context.Background(), constants.MailTimeout)
+	defer cancel()
+
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Login dari perangkat baru",
+		Body: fmt.Sprintf(
+			"Halo %s,\n\nAkun Anda baru saja login dari perangkat yang belum pernah dipakai sebelumnya.\n\n"+
+				"Perangkat: %s\nAlamat IP: %s\nWaktu: %s\n\n"+
+				"Jika ini bukan Anda, segera ganti password dan hubungi administrator.\n",
+			user.Name, event.Device(), event.IP, event.CreatedAt.UTC().Format(time.RFC1123),
+		),
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"user_id": user.ID,
+			"error":   err.Error(),
+		}).Error("Gagal mengirim notifikasi perangkat baru")
+	}
+}
+
+// GetLoginHistory mengambil riwayat login user, terbaru lebih dulu
+func (s *UserService) GetLoginHistory(userID uint, limit int) ([]model.LoginEvent, error) {
+	var events []model.LoginEvent
+	if err := s.db.Where("user_id = ?", userID).
+		Order("created_at DESC, id DESC").
+		Limit(model.LoginEventLimit(limit)).
+		Find(&events).Error; err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"user_id": userID,
+			"error":   err.Error(),
+		}).Error("Gagal mengambil riwayat login")
+		return nil, err
+	}
+	return events, nil
+}
+
+// GetFailedLogins mengambil login gagal dari semua user; hanya untuk admin
+func (s *UserService) GetFailedLogins(query model.FailedLoginQuery) ([]model.LoginEvent, error) {
+	db := s.db.Where("success = ?", false)
+	if query.Email != "" {
+		db = db.Scopes(model.LoginEventByEmail(query.Email))
+	}
+	if query.IP != "" {
+		db = db.Where("ip = ?", query.IP)
+	}
+	if query.Since != nil {
+		db = db.Where("created_at >= ?", *query.Since)
+	}
+
+	var events []model.LoginEvent
+	if err := db.Order("created_at DESC, id DESC").
+		Limit(model.LoginEventLimit(query.Limit)).
+		Find(&events).Error; err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"error": err.Error(),
+		}).Error("Gagal mengambil daftar login gagal")
+		return nil, err
+	}
+	return events, nil
+}
+
+// PurgeLoginEvents menghapus riwayat login sebelum before (retensi). Perangkat yang
+// tidak dipakai sejak saat itu akan kembali dianggap perangkat baru.
+func (s *UserService) PurgeLoginEvents(ctx context.Context, before time.Time) (int64, error) {
+	result := s.db.WithContext(ctx).Where("created_at < ?", before).Delete(&model.LoginEvent{})
+	if result.Error != nil {
+		s.logger.WithFields(logrus.Fields{
+			"error": result.Error.Error(),
+		}).Error("Gagal menghapus riwayat login lama")
+	}
+	return result.RowsAffected, result.Error
+}
+
+// rehashPassword meng-hash ulang password dengan algoritma dan parameter saat ini.
+// Kegagalan hanya dicatat karena password lama tetap valid.
+func (s *UserService) rehashPassword(user *model.User, plain string) {
+	previousHash := user.Password
+	if err := user.SetPassword(plain); err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"user_id": user.ID,
+			"error":   err.Error(),
+		}).Error("Gagal meng-hash ulang password")
+		user.Password = previousHash
+		return
+	}
+
+	// hanya ganti hash yang sama dengan yang diverifikasi agar tidak menimpa penggantian password yang bersamaan
+	if err := s.db.Model(&model.User{}).
+		Where("id = ? AND password = ?", user.ID, previousHash).
+		UpdateColumn("password", user.Password).Error; err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"user_id": user.ID,
+			"error":   err.Error(),
+		}).Error("Gagal menyimpan hasil hash ulang password")
+	}
+}
+
+// IssueToken membuat JWT, menyimpannya di Redis dan mencatat login di riwayat login
+// (termasuk notifikasi perangkat baru); dipakai oleh semua jalur login
+func (s *UserService) IssueToken(user *model.User, client model.ClientInfo) (string, error) {
+	return s.issueToken(user, model.NewLoginEvent(user.Email, client, time.Now()))
+}
+
+func (s *UserService) issueToken(user *model.User, event *model.LoginEvent) (string, error) {
+	event.UserID = &user.ID
+	if user.IsDisabled() {
+		s.logger.WithFields(logrus.Fields{
+			"user_id": user.ID,
+		}).Warn("Login ditolak karena akun dinonaktifkan")
+		s.recordLoginFailure(event, model.LoginReasonAccountDisabled)
+		return "", userErr.ErrAccountDisabled
+	}
+
 	token, err := jwt.GenerateToken(user.ID, s.jwtSecret)
 	if err != nil {
 		s.logger.WithFields(logrus.Fields{
@@ -124,15 +360,41 @@ func (s *UserService) Login(input model.LoginInput) (string, error) {
 		return "", err
 	}
 
+	s.recordLoginSuccess(user, event)
 	return token, nil
 }
 
+// GetUserByID dipanggil AuthMiddleware di setiap request sehingga hasilnya di-cache.
+// User dari cache tidak memuat hash password; baca langsung dari database untuk
+// memverifikasi password.
 func (s *UserService) GetUserByID(userID uint) (*model.User, error) {
-	var user model.User
-	if err := s.db.First(&user, userID).Error; err != nil {
-		return nil, err
-	}
-	return &user, nil
+	tag := userTag(userID)
+	return cache.GetOrLoad(context.Background(), s.cache, tag, func(ctx context.Context) (*model.User, error) {
+		var user model.User
+		if err := s.db.WithContext(ctx).First(&user, userID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, userErr.ErrUserNotFound.Wrap(err)
+			}
+			return nil, err
+		}
+		return &user, nil
+	}, tag)
+}
+
+// invalidate menghapus cache user setelah perubahan di-commit. Kegagalan sudah
+// dicatat loader; entry lama kedaluwarsa sesuai TTL cache user.
+func (s *UserService) invalidate(userID uint) {
+	_ = s.cache.Invalidate(context.Background(), userTag(userID))
+}
+
+// index memperbarui dokumen pencarian user setelah perubahan nama atau email
+// di-commit. Kegagalan sudah dicatat indexer.
+func (s *UserService) index(user *model.User) {
+	_ = s.indexer.Index(context.Background(), user.SearchDocument())
+}
+
+func userTag(userID uint) string {
+	return strconv.FormatUint(uint64(userID), 10)
 }
 
 func (s *UserService) GetStoredToken(ctx context.Context, userID uint) (string, error) {
@@ -142,8 +404,11 @@ func (s *UserService) GetStoredToken(ctx context.Context, userID uint) (string,
 	return s.redisClient.GetToken(ctx, userID)
 }
 
+// Logout mencabut token sesi user. Cache user ikut dihapus agar perubahan akun oleh
+// modul lain yang mencabut sesi, mis. erasure, langsung terlihat di middleware.
 func (s *UserService) Logout(userID uint) error {
 	ctx := context.Background()
+	s.invalidate(userID)
 	if err := s.redisClient.DeleteToken(ctx, userID); err != nil {
 		s.logger.WithFields(logrus.Fields{
 			"user_id": userID,
@@ -154,34 +419,113 @@ func (s *UserService) Logout(userID uint) error {
 	return nil
 }
 
-func (s *UserService) UpdateProfile(userID uint, input model.UpdateProfileInput) (*model.User, error) {
+// GetUserForUpdate membaca user langsung dari database, tanpa cache, sebagai dasar
+// UpdateProfile dan PatchProfile. Cache tidak menyimpan hash password yang dibutuhkan
+// pengecekan riwayat password.
+func (s *UserService) GetUserForUpdate(userID uint) (*model.User, error) {
 	var user model.User
 	if err := s.db.First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, userErr.ErrUserNotFound.Wrap(err)
+		}
 		return nil, err
 	}
+	return &user, nil
+}
 
+// UpdateProfile memperbarui user hasil GetUserForUpdate dengan optimistic locking;
+// ErrVersionConflict dikembalikan jika user berubah sejak dibaca.
+func (s *UserService) UpdateProfile(user *model.User, input model.UpdateProfileInput) (*model.User, error) {
 	// Update fields
 	user.Name = strings.TrimSpace(input.Name)
 	if input.Email != "" {
 		user.Email = strings.TrimSpace(input.Email)
 	}
+	previousHash := user.Password
 	if input.Password != "" {
-		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(strings.TrimSpace(input.Password)), bcrypt.DefaultCost)
-		if err != nil {
+		newPassword := strings.TrimSpace(input.Password)
+		if err := s.validateNewPassword(user, newPassword); err != nil {
 			return nil, err
 		}
-		user.Password = string(hashedPassword)
+		if err := user.SetPassword(newPassword); err != nil {
+			return nil, userErr.ErrHashingPassword
+		}
+		user.PasswordResetRequired = false
 	}
 
-	if err := s.db.Save(&user).Error; err != nil {
+	user.UpdatedAt = time.Now()
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := database.UpdateWithVersion(tx, &model.User{}, user.ID, user.Version, map[string]interface{}{
+			"name":                    user.Name,
+			"email":                   user.Email,
+			"password":                user.Password,
+			"password_reset_required": user.PasswordResetRequired,
+			"updated_at":              user.UpdatedAt,
+		}); err != nil {
+			return err
+		}
+		if user.Password == previousHash {
+			return nil
+		}
+		return s.recordPasswordHistory(tx, user.ID, previousHash)
+	})
+	if err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"user_id": user.ID,
+			"error":   err.Error(),
+		}).Error("Gagal memperbarui profile user")
 		return nil, err
 	}
+	user.Version++
+	s.invalidate(user.ID)
+	s.index(user)
 
-	return &user, nil
+	return user, nil
 }
 
-func (s *UserService) DeleteAccount(userID uint) error {
-	return s.db.Delete(&model.User{}, userID).Error
+// PatchProfile hanya menyimpan kolom yang berubah dari hasil merge patch
+func (s *UserService) PatchProfile(user *model.User, input model.PatchProfileInput) (*model.User, error) {
+	previousHash := user.Password
+	if input.Password != "" {
+		candidate := *user
+		candidate.Name = strings.TrimSpace(input.Name)
+		candidate.Email = strings.TrimSpace(input.Email)
+		if err := s.validateNewPassword(&candidate, strings.TrimSpace(input.Password)); err != nil {
+			return nil, err
+		}
+	}
+
+	changes, err := user.ApplyPatch(input)
+	if err != nil {
+		return nil, err
+	}
+	if len(changes) == 0 {
+		return user, nil
+	}
+
+	user.UpdatedAt = time.Now()
+	changes["updated_at"] = user.UpdatedAt
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := database.UpdateWithVersion(tx, &model.User{}, user.ID, user.Version, changes); err != nil {
+			return err
+		}
+		if _, changed := changes["password"]; !changed {
+			return nil
+		}
+		return s.recordPasswordHistory(tx, user.ID, previousHash)
+	})
+	if err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"user_id": user.ID,
+			"error":   err.Error(),
+		}).Error("Gagal menerapkan patch profile user")
+		return nil, err
+	}
+	user.Version++
+	s.invalidate(user.ID)
+	s.index(user)
+
+	return user, nil
 }
 
 // GetAllUsers mengambil semua user tanpa password
@@ -201,3 +545,285 @@ func (s *UserService) GetAllUsers() ([]model.User, error) {
 
 	return users, nil
 }
+
+// CreateUser membuat user dengan role tertentu; hanya untuk admin
+func (s *UserService) CreateUser(input model.AdminCreateUserInput) (*model.User, error) {
+	var existingUser model.User
+	if err := s.db.Scopes(model.ByEmail(input.Email)).First(&existingUser).Error; err == nil {
+		return nil, userErr.ErrEmailAlreadyRegistered
+	}
+
+	if err := s.policy.Validate(input.Password, input.Email, input.Name); err != nil {
+		return nil, err
+	}
+
+	user, err := model.NewUserWithRole(model.RegisterInput{
+		Name:     input.Name,
+		Email:    input.Email,
+		Password: input.Password,
+	}, input.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.createUser(user, model.RegistrationSourceAdmin); err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"email": input.Email,
+			"error": err.Error(),
+		}).Error("Gagal menyimpan user ke database")
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// ChangeRole mengubah role user; admin aktif terakhir tidak boleh diturunkan
+func (s *UserService) ChangeRole(userID uint, role constants.Role) (*model.User, error) {
+	return s.adminUpdate(userID, func(tx *gorm.DB, user *model.User) (map[string]interface{}, error) {
+		if user.Role == role {
+			return nil, nil
+		}
+		if user.IsAdmin() && !user.IsDisabled() {
+			if err := s.ensureAnotherAdmin(tx, user.ID); err != nil {
+				return nil, err
+			}
+		}
+		user.Role = role
+		return map[string]interface{}{"role": role}, nil
+	})
+}
+
+// DisableUser menonaktifkan akun dan mencabut token yang sedang aktif
+func (s *UserService) DisableUser(userID uint) (*model.User, error) {
+	user, err := s.adminUpdate(userID, func(tx *gorm.DB, user *model.User) (map[string]interface{}, error) {
+		if user.IsDisabled() {
+			return nil, nil
+		}
+		if user.IsAdmin() {
+			if err := s.ensureAnotherAdmin(tx, user.ID); err != nil {
+				return nil, err
+			}
+		}
+		now := time.Now()
+		user.DisabledAt = &now
+		return map[string]interface{}{"disabled_at": now}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user, s.RevokeTokens(userID)
+}
+
+func (s *UserService) EnableUser(userID uint) (*model.User, error) {
+	return s.adminUpdate(userID, func(tx *gorm.DB, user *model.User) (map[string]interface{}, error) {
+		if !user.IsDisabled() {
+			return nil, nil
+		}
+		user.DisabledAt = nil
+		return map[string]interface{}{"disabled_at": nil}, nil
+	})
+}
+
+// ForcePasswordReset mewajibkan user mengganti password dan mencabut sesi yang sedang aktif
+func (s *UserService) ForcePasswordReset(userID uint) (*model.User, error) {
+	user, err := s.adminUpdate(userID, func(tx *gorm.DB, user *model.User) (map[string]interface{}, error) {
+		if user.PasswordResetRequired {
+			return nil, nil
+		}
+		user.PasswordResetRequired = true
+		return map[string]interface{}{"password_reset_required": true}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user, s.RevokeTokens(userID)
+}
+
+// RevokeTokens mencabut semua token user: sesi login sehingga user harus login ulang,
+// API key milik user, dan token impersonasi yang sedang bertindak sebagai user
+func (s *UserService) RevokeTokens(userID uint) error {
+	if _, err := s.GetUserByID(userID); err != nil {
+		return err
+	}
+
+	if err := s.db.Model(&apiKeyModel.APIKey{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error; err != nil {
+		return err
+	}
+
+	if err := s.redisClient.DeleteUserImpersonationTokens(context.Background(), userID); err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"user_id": userID,
+			"error":   err.Error(),
+		}).Error("Gagal menghapus token impersonasi dari Redis")
+		return err
+	}
+	return s.Logout(userID)
+}
+
+// adminUpdate memuat user dalam transaksi, menerapkan perubahan dari apply dan
+// menyimpannya dengan optimistic locking. apply mengembalikan nil jika tidak ada perubahan.
+func (s *UserService) adminUpdate(userID uint, apply func(tx *gorm.DB, user *model.User) (map[string]interface{}, error)) (*model.User, error) {
+	var user model.User
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&user, userID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return userErr.ErrUserNotFound.Wrap(err)
+			}
+			return err
+		}
+		if user.IsErased() {
+			return userErr.ErrUserErased
+		}
+
+		changes, err := apply(tx, &user)
+		if err != nil || changes == nil {
+			return err
+		}
+
+		user.UpdatedAt = time.Now()
+		changes["updated_at"] = user.UpdatedAt
+		if err := database.UpdateWithVersion(tx, &model.User{}, user.ID, user.Version, changes); err != nil {
+			return err
+		}
+		user.Version++
+		return nil
+	})
+	if err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"user_id": userID,
+			"error":   err.Error(),
+		}).Error("Gagal memperbarui user oleh admin")
+		return nil, err
+	}
+	s.invalidate(user.ID)
+
+	return &user, nil
+}
+
+// ensureAnotherAdmin memastikan masih ada admin aktif lain selain userID.
+// Row admin dikunci agar dua admin tidak saling menurunkan secara bersamaan.
+func (s *UserService) ensureAnotherAdmin(tx *gorm.DB, userID uint) error {
+	var adminIDs []uint
+	if err := tx.Model(&model.User{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("role = ? AND disabled_at IS NULL", constants.RoleAdmin).
+		Pluck("id", &adminIDs).Error; err != nil {
+		return err
+	}
+
+	for _, id := range adminIDs {
+		if id != userID {
+			return nil
+		}
+	}
+	return userErr.ErrLastAdmin
+}
+
+// Impersonate menerbitkan token berumur pendek agar admin dapat bertindak sebagai
+// user. Token disimpan terpisah sehingga sesi user sendiri tetap berlaku.
+func (s *UserService) Impersonate(actor *model.User, userID uint) (*model.ImpersonationResult, error) {
+	user, err := s.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// admin lain tidak boleh diimpersonasi agar impersonasi tidak bisa dipakai untuk eskalasi
+	if user.ID == actor.ID || user.IsAdmin() {
+		return nil, userErr.ErrImpersonationNotAllowed
+	}
+	if user.IsDisabled() {
+		return nil, userErr.ErrAccountDisabled
+	}
+
+	token, tokenID, err := jwt.GenerateImpersonationToken(user.ID, actor.ID, constants.ImpersonationTTL, s.jwtSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.redisClient.SetImpersonationToken(context.Background(), user.ID, tokenID, token, constants.ImpersonationTTL); err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"user_id":  user.ID,
+			"actor_id": actor.ID,
+			"error":    err.Error(),
+		}).Error("Gagal menyimpan token impersonasi di Redis")
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"user_id":  user.ID,
+		"actor_id": actor.ID,
+		"token_id": tokenID,
+	}).Warn("Admin memulai impersonasi user")
+
+	return &model.ImpersonationResult{
+		Token:     token,
+		ExpiresAt: time.Now().Add(constants.ImpersonationTTL),
+		User:      user,
+	}, nil
+}
+
+func (s *UserService) GetImpersonationToken(ctx context.Context, tokenID string) (string, error) {
+	return s.redisClient.GetImpersonationToken(ctx, tokenID)
+}
+
+// EndImpersonation mencabut token impersonasi tanpa menyentuh sesi user
+func (s *UserService) EndImpersonation(tokenID string) error {
+	if err := s.redisClient.DeleteImpersonationToken(context.Background(), tokenID); err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"token_id": tokenID,
+			"error":    err.Error(),
+		}).Error("Gagal menghapus token impersonasi dari Redis")
+		return err
+	}
+	return nil
+}
+
+// validateNewPassword menerapkan password policy terhadap email dan nama user
+// (yang baru jika ikut diubah) serta menolak password saat ini dan riwayatnya
+func (s *UserService) validateNewPassword(user *model.User, newPassword string) error {
+	if err := s.policy.Validate(newPassword, user.Email, user.Name); err != nil {
+		return err
+	}
+	if s.policy.HistorySize <= 0 {
+		return nil
+	}
+
+	if user.Password != "" && user.CheckPassword(newPassword) == nil {
+		return password.Error([]password.Violation{s.policy.HistoryViolation()})
+	}
+
+	var history []model.PasswordHistory
+	if err := s.db.Where("user_id = ?", user.ID).Order("id DESC").Limit(s.policy.HistorySize).Find(&history).Error; err != nil {
+		return err
+	}
+	for _, previous := range history {
+		if previous.Matches(newPassword) {
+			return password.Error([]password.Violation{s.policy.HistoryViolation()})
+		}
+	}
+	return nil
+}
+
+// recordPasswordHistory menyimpan hash lama dan hanya menyisakan HistorySize entri terbaru
+func (s *UserService) recordPasswordHistory(tx *gorm.DB, userID uint, previousHash string) error {
+	if s.policy.HistorySize <= 0 || previousHash == "" {
+		return nil
+	}
+
+	if err := tx.Create(&model.PasswordHistory{UserID: userID, Hash: previousHash}).Error; err != nil {
+		return err
+	}
+
+	var ids []uint
+	if err := tx.Model(&model.PasswordHistory{}).Where("user_id = ?", userID).Order("id DESC").Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+	if len(ids) <= s.policy.HistorySize {
+		return nil
+	}
+	return tx.Delete(&model.PasswordHistory{}, ids[s.policy.HistorySize:]).Error
+}
@@ -0,0 +1,564 @@
+package user
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	apiKeyModel "boilerplate/internal/apikey/model"
+	"boilerplate/internal/testutil"
+	"boilerplate/internal/user/model"
+	"boilerplate/pkg/cache"
+	"boilerplate/pkg/events"
+	"boilerplate/pkg/fieldcrypt"
+	"boilerplate/pkg/jwt"
+	"boilerplate/pkg/logger"
+	"boilerplate/pkg/mailer"
+	"boilerplate/pkg/password"
+	"boilerplate/shared/constants"
+	errs "boilerplate/shared/errors"
+
+	"github.com/stretchr/testify/suite"
+)
+
+var (
+	desktop = model.ClientInfo{
+		IP:        "203.0.113.10",
+		UserAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36",
+	}
+	phone = model.ClientInfo{
+		IP:        "198.51.100.7",
+		UserAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_5 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.5 Mobile/15E148 Safari/604.1",
+	}
+)
+
+// recordingMailer menangkap email yang dikirim service
+type recordingMailer struct {
+	sent chan mailer.Message
+}
+
+func (m *recordingMailer) Send(_ context.Context, msg mailer.Message) error {
+	m.sent <- msg
+	return nil
+}
+
+type UserServiceTestSuite struct {
+	suite.Suite
+	service *UserService
+	mailer  *recordingMailer
+	admin   *model.User
+}
+
+func TestUserServiceSuite(t *testing.T) {
+	suite.Run(t, new(UserServiceTestSuite))
+}
+
+func (s *UserServiceTestSuite) SetupTest() {
+	db := testutil.NewDB(s.T(), &model.User{}, &model.PasswordHistory{}, &model.LoginEvent{}, &apiKeyModel.APIKey{}, &events.OutboxMessage{})
+
+	redisClient := testutil.NewRedisClient(s.T())
+	s.mailer = &recordingMailer{sent: make(chan mailer.Message, 10)}
+	s.service = NewUserService(db, "test-secret", logger.NewLogger(), redisClient, password.DefaultPolicy(), s.mailer, nil, nil)
+
+	var err error
+	s.admin, err = s.service.CreateUser(model.AdminCreateUserInput{
+		Name:     "Admin",
+		Email:    "admin@example.com",
+		Password: "password123",
+		Role:     constants.RoleAdmin,
+	})
+	s.Require().NoError(err)
+}
+
+func (s *UserServiceTestSuite) createUser(email string, role constants.Role) *model.User {
+	user, err := s.service.CreateUser(model.AdminCreateUserInput{
+		Name:     "Someone",
+		Email:    email,
+		Password: "password123",
+		Role:     role,
+	})
+	s.Require().NoError(err)
+	return user
+}
+
+func (s *UserServiceTestSuite) forUpdate(userID uint) *model.User {
+	user, err := s.service.GetUserForUpdate(userID)
+	s.Require().NoError(err)
+	return user
+}
+
+func (s *UserServiceTestSuite) TestCreateUserDuplicateEmail() {
+	_, err := s.service.CreateUser(model.AdminCreateUserInput{
+		Name:     "Admin",
+		Email:    "admin@example.com",
+		Password: "password123",
+		Role:     constants.RoleUser,
+	})
+	s.ErrorIs(err, errs.ErrEmailAlreadyRegistered)
+}
+
+func (s *UserServiceTestSuite) TestLastAdminCannotBeDemotedOrDisabled() {
+	_, err := s.service.ChangeRole(s.admin.ID, constants.RoleUser)
+	s.ErrorIs(err, errs.ErrLastAdmin)
+
+	_, err = s.service.DisableUser(s.admin.ID)
+	s.ErrorIs(err, errs.ErrLastAdmin)
+
+	second := s.createUser("second@example.com", constants.RoleAdmin)
+	demoted, err := s.service.ChangeRole(s.admin.ID, constants.RoleUser)
+	s.Require().NoError(err)
+	s.Equal(constants.RoleUser, demoted.Role)
+	s.Equal(s.admin.Version+1, demoted.Version)
+
+	_, err = s.service.DisableUser(second.ID)
+	s.ErrorIs(err, errs.ErrLastAdmin)
+}
+
+func (s *UserServiceTestSuite) TestDisabledAdminDoesNotCount() {
+	second := s.createUser("second@example.com", constants.RoleAdmin)
+	_, err := s.service.DisableUser(second.ID)
+	s.Require().NoError(err)
+
+	_, err = s.service.ChangeRole(s.admin.ID, constants.RoleUser)
+	s.ErrorIs(err, errs.ErrLastAdmin)
+}
+
+func (s *UserServiceTestSuite) TestDisableUserBlocksLoginAndRevokesToken() {
+	user := s.createUser("user@example.com", constants.RoleUser)
+	_, err := s.service.Login(model.LoginInput{Email: "user@example.com", Password: "password123"}, desktop)
+	s.Require().NoError(err)
+
+	disabled, err := s.service.DisableUser(user.ID)
+	s.Require().NoError(err)
+	s.True(disabled.IsDisabled())
+
+	_, err = s.service.GetStoredToken(context.Background(), user.ID)
+	s.Error(err)
+
+	_, err = s.service.Login(model.LoginInput{Email: "user@example.com", Password: "password123"}, desktop)
+	s.ErrorIs(err, errs.ErrAccountDisabled)
+
+	enabled, err := s.service.EnableUser(user.ID)
+	s.Require().NoError(err)
+	s.False(enabled.IsDisabled())
+
+	_, err = s.service.Login(model.LoginInput{Email: "user@example.com", Password: "password123"}, desktop)
+	s.NoError(err)
+}
+
+func (s *UserServiceTestSuite) TestForcePasswordResetClearedByPasswordChange() {
+	user := s.createUser("user@example.com", constants.RoleUser)
+
+	flagged, err := s.service.ForcePasswordReset(user.ID)
+	s.Require().NoError(err)
+	s.True(flagged.PasswordResetRequired)
+
+	updated, err := s.service.PatchProfile(s.forUpdate(user.ID), model.PatchProfileInput{
+		Name:     flagged.Name,
+		Email:    flagged.Email,
+		Password: "newpassword123",
+	})
+	s.Require().NoError(err)
+	s.False(updated.PasswordResetRequired)
+
+	reloaded, err := s.service.GetUserByID(user.ID)
+	s.Require().NoError(err)
+	s.False(reloaded.PasswordResetRequired)
+}
+
+func (s *UserServiceTestSuite) TestAdminActionsOnUnknownUser() {
+	_, err := s.service.DisableUser(999)
+	s.ErrorIs(err, errs.ErrUserNotFound)
+	s.ErrorIs(s.service.RevokeTokens(999), errs.ErrUserNotFound)
+}
+
+func (s *UserServiceTestSuite) TestRevokeTokens() {
+	user := s.createUser("user@example.com", constants.RoleUser)
+	other := s.createUser("other@example.com", constants.RoleUser)
+	_, err := s.service.Login(model.LoginInput{Email: "user@example.com", Password: "password123"}, desktop)
+	s.Require().NoError(err)
+
+	keys := []*apiKeyModel.APIKey{
+		{UserID: user.ID, Name: "ci", KeyID: "userkey1"},
+		{UserID: user.ID, Name: "backup", KeyID: "userkey2"},
+		{UserID: other.ID, Name: "ci", KeyID: "otherkey"},
+	}
+	for _, key := range keys {
+		s.Require().NoError(s.service.db.Create(key).Error)
+	}
+
+	var tokenIDs []string
+	for range 2 {
+		result, err := s.service.Impersonate(s.admin, user.ID)
+		s.Require().NoError(err)
+		claims, err := jwt.ValidateToken(result.Token, "test-secret")
+		s.Require().NoError(err)
+		tokenIDs = append(tokenIDs, claims.ID)
+	}
+	otherResult, err := s.service.Impersonate(s.admin, other.ID)
+	s.Require().NoError(err)
+	otherClaims, err := jwt.ValidateToken(otherResult.Token, "test-secret")
+	s.Require().NoError(err)
+
+	s.Require().NoError(s.service.RevokeTokens(user.ID))
+
+	s.Run("session", func() {
+		_, err := s.service.GetStoredToken(context.Background(), user.ID)
+		s.Error(err)
+	})
+	s.Run("api keys", func() {
+		for _, key := range keys {
+			var stored apiKeyModel.APIKey
+			s.Require().NoError(s.service.db.First(&stored, key.ID).Error)
+			if key.UserID == user.ID {
+				s.NotNil(stored.RevokedAt, key.KeyID)
+			} else {
+				s.Nil(stored.RevokedAt, key.KeyID)
+			}
+		}
+	})
+	s.Run("impersonation", func() {
+		for _, tokenID := range tokenIDs {
+			_, err := s.service.GetImpersonationToken(context.Background(), tokenID)
+			s.Error(err)
+		}
+		_, err := s.service.GetImpersonationToken(context.Background(), otherClaims.ID)
+		s.NoError(err)
+	})
+}
+
+func (s *UserServiceTestSuite) TestImpersonate() {
+	user := s.createUser("user@example.com", constants.RoleUser)
+	session, err := s.service.Login(model.LoginInput{Email: "user@example.com", Password: "password123"}, desktop)
+	s.Require().NoError(err)
+
+	result, err := s.service.Impersonate(s.admin, user.ID)
+	s.Require().NoError(err)
+	s.Equal(user.ID, result.User.ID)
+
+	claims, err := jwt.ValidateToken(result.Token, "test-secret")
+	s.Require().NoError(err)
+	s.Equal(user.ID, claims.UserID)
+	s.Equal(s.admin.ID, claims.Act.UserID)
+
+	stored, err := s.service.GetImpersonationToken(context.Background(), claims.ID)
+	s.Require().NoError(err)
+	s.Equal(result.Token, stored)
+
+	// sesi user sendiri tidak tersentuh, baik saat mulai maupun saat impersonasi diakhiri
+	s.Require().NoError(s.service.EndImpersonation(claims.ID))
+	_, err = s.service.GetImpersonationToken(context.Background(), claims.ID)
+	s.Error(err)
+	own, err := s.service.GetStoredToken(context.Background(), user.ID)
+	s.Require().NoError(err)
+	s.Equal(session, own)
+}
+
+func (s *UserServiceTestSuite) TestImpersonateNotAllowed() {
+	other := s.createUser("second@example.com", constants.RoleAdmin)
+	_, err := s.service.Impersonate(s.admin, other.ID)
+	s.ErrorIs(err, errs.ErrImpersonationNotAllowed)
+
+	_, err = s.service.Impersonate(s.admin, s.admin.ID)
+	s.ErrorIs(err, errs.ErrImpersonationNotAllowed)
+
+	user := s.createUser("user@example.com", constants.RoleUser)
+	_, err = s.service.DisableUser(user.ID)
+	s.Require().NoError(err)
+	_, err = s.service.Impersonate(s.admin, user.ID)
+	s.ErrorIs(err, errs.ErrAccountDisabled)
+}
+
+func (s *UserServiceTestSuite) TestRegisterAppliesPasswordPolicy() {
+	_, err := s.service.Register(model.RegisterInput{Name: "Jane Doe", Email: "jane@example.com", Password: "jane-secret"})
+	s.ErrorIs(err, errs.ErrPasswordPolicy)
+
+	s.service.policy.Breached, err = password.ParseBreachList(strings.NewReader("CBFDAC6008F9CAB4083784CBD1874F76618D2A97\n"))
+	s.Require().NoError(err)
+	_, err = s.service.Register(model.RegisterInput{Name: "Jane Doe", Email: "jane@example.com", Password: "password123"})
+	s.ErrorIs(err, errs.ErrPasswordPolicy)
+
+	_, err = s.service.Register(model.RegisterInput{Name: "Jane Doe", Email: "jane@example.com", Password: "correct-horse"})
+	s.NoError(err)
+}
+
+func (s *UserServiceTestSuite) TestRegisterRecordsUserRegisteredEvent() {
+	user, err := s.service.Register(model.RegisterInput{Name: "Jane Doe", Email: "jane@example.com", Password: "correct-horse"})
+	s.Require().NoError(err)
+
+	var messages []events.OutboxMessage
+	s.Require().NoError(s.service.db.Where("name = ?", "user.registered").Order("id").Find(&messages).Error)
+	s.Require().Len(messages, 2)
+
+	var registered model.UserRegistered
+	s.Require().NoError(messages[0].Envelope().Decode(&registered))
+	s.Equal(model.UserRegistered{UserID: s.admin.ID, Role: constants.RoleAdmin, Source: model.RegistrationSourceAdmin}, registered)
+
+	s.Require().NoError(messages[1].Envelope().Decode(&registered))
+	s.Equal(model.UserRegistered{UserID: user.ID, Role: constants.RoleUser, Source: model.RegistrationSourceSignup}, registered)
+	s.NotContains(string(messages[1].Payload), "jane@example.com")
+}
+
+func (s *UserServiceTestSuite) TestPasswordHistory() {
+	s.service.policy.HistorySize = 2
+	user := s.createUser("user@example.com", constants.RoleUser)
+
+	change := func(newPassword string) error {
+		current := s.forUpdate(user.ID)
+		_, err := s.service.UpdateProfile(current, model.UpdateProfileInput{Name: current.Name, Password: newPassword})
+		return err
+	}
+
+	s.ErrorIs(change("password123"), errs.ErrPasswordPolicy, "current password")
+	s.Require().NoError(change("second-pass"))
+	s.Require().NoError(change("third-pass"))
+	s.ErrorIs(change("password123"), errs.ErrPasswordPolicy, "still in history")
+	s.Require().NoError(change("fourth-pass"))
+
+	// hanya HistorySize hash terakhir yang disimpan, password pertama boleh dipakai lagi
+	var count int64
+	s.Require().NoError(s.service.db.Model(&model.PasswordHistory{}).Where("user_id = ?", user.ID).Count(&count).Error)
+	s.EqualValues(2, count)
+	s.NoError(change("password123"))
+}
+
+func (s *UserServiceTestSuite) TestPatchProfileChecksNewEmailAgainstPassword() {
+	user := s.createUser("user@example.com", constants.RoleUser)
+
+	_, err := s.service.PatchProfile(s.forUpdate(user.ID), model.PatchProfileInput{
+		Name:     user.Name,
+		Email:    "wonderland@example.com",
+		Password: "wonderland-1",
+	})
+	s.ErrorIs(err, errs.ErrPasswordPolicy)
+}
+
+func (s *UserServiceTestSuite) TestLoginRehashesLegacyPassword() {
+	user := s.createUser("user@example.com", constants.RoleUser)
+	s.True(strings.HasPrefix(user.Password, "$2a$"))
+
+	previous := password.DefaultHasher()
+	password.SetDefaultHasher(password.NewHasher(
+		password.Argon2idHasher{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32},
+		password.BcryptHasher{Cost: constants.BcryptCost},
+	))
+	s.T().Cleanup(func() { password.SetDefaultHasher(previous) })
+
+	_, err := s.service.Login(model.LoginInput{Email: "user@example.com", Password: "password123"}, desktop)
+	s.Require().NoError(err)
+
+	var stored model.User
+	s.Require().NoError(s.service.db.First(&stored, user.ID).Error)
+	s.True(strings.HasPrefix(stored.Password, "$argon2id$"))
+	s.NoError(stored.CheckPassword("password123"))
+
+	_, err = s.service.Login(model.LoginInput{Email: "user@example.com", Password: "password123"}, desktop)
+	s.NoError(err)
+}
+
+func (s *UserServiceTestSuite) TestPersonalDataEncryptedAtRest() {
+	keyring, err := fieldcrypt.NewKeyring(1, map[uint32][]byte{1: bytes.Repeat([]byte{1}, fieldcrypt.KeySize)}, []byte("blind"))
+	s.Require().NoError(err)
+	previous := fieldcrypt.DefaultKeyring()
+	fieldcrypt.SetDefaultKeyring(keyring)
+	s.T().Cleanup(func() { fieldcrypt.SetDefaultKeyring(previous) })
+
+	user, err := s.service.Register(model.RegisterInput{Name: "Jane Doe", Email: "jane@example.com", Password: "password123"})
+	s.Require().NoError(err)
+
+	raw := func() (name, email, index string) {
+		row := s.service.db.Raw("SELECT name, email, email_index FROM users WHERE id = ?", user.ID).Row()
+		s.Require().NoError(row.Scan(&name, &email, &index))
+		return
+	}
+	name, email, index := raw()
+	s.True(strings.HasPrefix(name, "enc:v1:1:"))
+	s.True(strings.HasPrefix(email, "enc:v1:1:"))
+	s.Equal(keyring.BlindIndex("jane@example.com"), index)
+
+	// lookup lewat blind index tidak peka huruf besar
+	_, err = s.service.Register(model.RegisterInput{Name: "Jane", Email: "JANE@example.com", Password: "password123"})
+	s.ErrorIs(err, errs.ErrEmailAlreadyRegistered)
+	_, err = s.service.Login(model.LoginInput{Email: "jane@example.com", Password: "password123"}, desktop)
+	s.Require().NoError(err)
+
+	// update dengan map juga dienkripsi dan memperbarui blind index
+	patched, err := s.service.PatchProfile(s.forUpdate(user.ID), model.PatchProfileInput{Name: "Jane Roe", Email: "roe@example.com"})
+	s.Require().NoError(err)
+	s.Equal("roe@example.com", patched.Email)
+	name, email, index = raw()
+	s.True(strings.HasPrefix(name, "enc:v1:1:"))
+	s.True(strings.HasPrefix(email, "enc:v1:1:"))
+	s.Equal(keyring.BlindIndex("roe@example.com"), index)
+
+	stored, err := s.service.GetUserByID(user.ID)
+	s.Require().NoError(err)
+	s.Equal("Jane Roe", stored.Name)
+	_, err = s.service.Login(model.LoginInput{Email: "roe@example.com", Password: "password123"}, desktop)
+	s.NoError(err)
+
+	// admin dibuat sebelum enkripsi aktif dan tetap dapat login lewat kolom plaintext
+	_, err = s.service.Login(model.LoginInput{Email: "admin@example.com", Password: "password123"}, desktop)
+	s.NoError(err)
+}
+
+func (s *UserServiceTestSuite) TestGetUserByIDCacheIsInvalidatedOnWrites() {
+	s.service.cache = cache.NewLoader(cache.NewLRU(0), "user", time.Minute, logger.NewLogger())
+	user := s.createUser("cached@example.com", constants.RoleUser)
+
+	cached, err := s.service.GetUserByID(user.ID)
+	s.Require().NoError(err)
+	s.False(cached.IsDisabled())
+
+	_, err = s.service.DisableUser(user.ID)
+	s.Require().NoError(err)
+	cached, err = s.service.GetUserByID(user.ID)
+	s.Require().NoError(err)
+	s.True(cached.IsDisabled())
+
+	_, err = s.service.PatchProfile(s.forUpdate(user.ID), model.PatchProfileInput{Name: "Renamed", Email: user.Email})
+	s.Require().NoError(err)
+	cached, err = s.service.GetUserByID(user.ID)
+	s.Require().NoError(err)
+	s.Equal("Renamed", cached.Name)
+
+	// baris user diubah modul lain: terlihat setelah event UserErased atau Logout
+	s.Require().NoError(s.service.db.Model(&model.User{}).Where("id = ?", user.ID).Update("role", constants.RoleAdmin).Error)
+	cached, err = s.service.GetUserByID(user.ID)
+	s.Require().NoError(err)
+	s.Equal(constants.RoleUser, cached.Role)
+
+	bus := events.NewBus()
+	s.service.Subscribe(bus)
+	envelope, err := events.NewEnvelope(model.UserErased{UserID: user.ID})
+	s.Require().NoError(err)
+	s.Require().NoError(bus.Publish(context.Background(), envelope))
+	cached, err = s.service.GetUserByID(user.ID)
+	s.Require().NoError(err)
+	s.Equal(constants.RoleAdmin, cached.Role)
+
+	s.Equal(uint64(1), s.service.cache.Stats().Hits)
+}
+
+func (s *UserServiceTestSuite) TestLoginHistory() {
+	user := s.createUser("user@example.com", constants.RoleUser)
+
+	_, err := s.service.Login(model.LoginInput{Email: "user@example.com", Password: "wrong-password"}, desktop)
+	s.ErrorIs(err, errs.ErrInvalidCredentials)
+	_, err = s.service.Login(model.LoginInput{Email: "user@example.com", Password: "password123"}, desktop)
+	s.Require().NoError(err)
+
+	events, err := s.service.GetLoginHistory(user.ID, 0)
+	s.Require().NoError(err)
+	s.Require().Len(events, 2)
+	s.True(events[0].Success)
+	s.Equal("Chrome", events[0].Browser)
+	s.Equal("Windows", events[0].OS)
+	s.Equal("desktop", events[0].DeviceType)
+	s.Equal(desktop.IP, events[0].IP)
+	s.False(events[1].Success)
+	s.Equal(model.LoginReasonInvalidPassword, events[1].Reason)
+
+	limited, err := s.service.GetLoginHistory(user.ID, 1)
+	s.Require().NoError(err)
+	s.Len(limited, 1)
+}
+
+func (s *UserServiceTestSuite) TestFailedLogins() {
+	user := s.createUser("user@example.com", constants.RoleUser)
+	_, err := s.service.DisableUser(user.ID)
+	s.Require().NoError(err)
+
+	_, err = s.service.Login(model.LoginInput{Email: "ghost@example.com", Password: "password123"}, phone)
+	s.ErrorIs(err, errs.ErrInvalidCredentials)
+	_, err = s.service.Login(model.LoginInput{Email: "user@example.com", Password: "password123"}, desktop)
+	s.ErrorIs(err, errs.ErrAccountDisabled)
+	_, err = s.service.Login(model.LoginInput{Email: "admin@example.com", Password: "password123"}, desktop)
+	s.Require().NoError(err)
+
+	failed, err := s.service.GetFailedLogins(model.FailedLoginQuery{})
+	s.Require().NoError(err)
+	s.Require().Len(failed, 2)
+	s.Equal(model.LoginReasonAccountDisabled, failed[0].Reason)
+	s.Equal(user.ID, *failed[0].UserID)
+	s.Equal(model.LoginReasonUnknownEmail, failed[1].Reason)
+	s.Nil(failed[1].UserID)
+
+	byIP, err := s.service.GetFailedLogins(model.FailedLoginQuery{IP: phone.IP})
+	s.Require().NoError(err)
+	s.Require().Len(byIP, 1)
+	s.Equal("ghost@example.com", byIP[0].Email)
+
+	future := time.Now().Add(time.Hour)
+	none, err := s.service.GetFailedLogins(model.FailedLoginQuery{Since: &future})
+	s.Require().NoError(err)
+	s.Empty(none)
+}
+
+func (s *UserServiceTestSuite) TestFailedLoginsByEncryptedEmail() {
+	// login gagal sebelum enkripsi aktif tetap dapat dicari lewat kolom plaintext
+	_, err := s.service.Login(model.LoginInput{Email: "ghost@example.com", Password: "password123"}, phone)
+	s.ErrorIs(err, errs.ErrInvalidCredentials)
+
+	keyring, err := fieldcrypt.NewKeyring(1, map[uint32][]byte{1: bytes.Repeat([]byte{1}, fieldcrypt.KeySize)}, []byte("blind"))
+	s.Require().NoError(err)
+	previous := fieldcrypt.DefaultKeyring()
+	fieldcrypt.SetDefaultKeyring(keyring)
+	s.T().Cleanup(func() { fieldcrypt.SetDefaultKeyring(previous) })
+
+	_, err = s.service.Login(model.LoginInput{Email: "Ghost@example.com", Password: "password123"}, desktop)
+	s.ErrorIs(err, errs.ErrInvalidCredentials)
+	_, err = s.service.Login(model.LoginInput{Email: "other@example.com", Password: "password123"}, desktop)
+	s.ErrorIs(err, errs.ErrInvalidCredentials)
+
+	var email, index string
+	row := s.service.db.Raw("SELECT email, email_index FROM login_events WHERE ip = ?", desktop.IP).Row()
+	s.Require().NoError(row.Scan(&email, &index))
+	s.True(strings.HasPrefix(email, "enc:v1:1:"))
+	s.Equal(keyring.BlindIndex("ghost@example.com"), index)
+
+	failed, err := s.service.GetFailedLogins(model.FailedLoginQuery{Email: "ghost@example.com"})
+	s.Require().NoError(err)
+	s.Require().Len(failed, 2)
+	s.Equal("Ghost@example.com", failed[0].Email)
+	s.Equal("ghost@example.com", failed[1].Email)
+}
+
+func (s *UserServiceTestSuite) TestPurgeLoginEvents() {
+	old := model.NewLoginEvent("old@example.com", desktop, time.Now().Add(-100*24*time.Hour))
+	recent := model.NewLoginEvent("recent@example.com", desktop, time.Now())
+	s.Require().NoError(s.service.db.Create([]*model.LoginEvent{old, recent}).Error)
+
+	purged, err := s.service.PurgeLoginEvents(context.Background(), time.Now().Add(-constants.LoginEventRetention))
+	s.Require().NoError(err)
+	s.Equal(int64(1), purged)
+
+	var remaining []model.LoginEvent
+	s.Require().NoError(s.service.db.Find(&remaining).Error)
+	s.Require().Len(remaining, 1)
+	s.Equal("recent@example.com", remaining[0].Email)
+}
+
+func (s *UserServiceTestSuite) TestNewDeviceNotification() {
+	s.createUser("user@example.com", constants.RoleUser)
+	login := model.LoginInput{Email: "user@example.com", Password: "password123"}
+
+	// login pertama dan perangkat yang sudah dikenal tidak mengirim notifikasi
+	_, err := s.service.Login(login, desktop)
+	s.Require().NoError(err)
+	_, err = s.service.Login(login, desktop)
+	s.Require().NoError(err)
+
+	s.Empty(s.mailer.sent)
+
+	// notifikasi sudah diantrikan saat Login kembali
+	_, err = s.service.Login(login, phone)
+	s.Require().NoError(err)
+	s.Require().Len(s.mailer.sent, 1)
+	msg := <-s.mailer.sent
+	s.Equal("user@example.com", msg.To)
+	s.Contains(msg.Body, "Safari on iOS (mobile)")
+	s.Contains(msg.Body, phone.IP)
+}
@@ -0,0 +1,64 @@
+package webhook
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// errBlockedAddress dikembalikan saat URL webhook mengarah ke jaringan internal
+var errBlockedAddress = errors.New("webhook: receiver address is not a public IP")
+
+// newClient membuat HTTP client pengiriman webhook. Tanpa allowPrivate, koneksi ke
+// IP loopback, private, link-local, multicast atau unspecified ditolak setelah DNS
+// di-resolve sehingga URL admin tidak dapat dipakai menjangkau layanan internal (SSRF),
+// termasuk lewat hostname yang mengarah ke IP internal.
+func newClient(allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: deliveryTimeout, KeepAlive: 30 * time.Second}
+	if !allowPrivate {
+		dialer.Control = publicOnly
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	// proxy dari environment akan membuat alamat yang dicek adalah alamat proxy
+	transport.Proxy = nil
+
+	return &http.Client{
+		Timeout:   deliveryTimeout,
+		Transport: transport,
+		// redirect tidak diikuti agar payload bertanda tangan hanya dikirim ke URL terdaftar
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// publicOnly dipanggil untuk setiap alamat hasil resolve tepat sebelum connect
+func publicOnly(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !isPublic(ip) {
+		return errBlockedAddress
+	}
+	return nil
+}
+
+func isPublic(ip net.IP) bool {
+	if ip4 := ip.To4(); ip4 != nil && ip4[0] == 0 {
+		// 0.0.0.0/8 terhubung ke host lokal di Linux
+		return false
+	}
+	return !ip.IsLoopback() &&
+		!ip.IsPrivate() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast() &&
+		!ip.IsUnspecified()
+}
@@ -0,0 +1,66 @@
+package model
+
+import (
+	"time"
+
+	"boilerplate/pkg/events"
+)
+
+const (
+	DefaultDeliveryLimit = 50
+	MaxDeliveryLimit     = 200
+
+	// MaxSnapshotSize membatasi body request dan response yang disimpan di log pengiriman
+	MaxSnapshotSize = 4096
+)
+
+// Delivery mencatat satu percobaan pengiriman beserta snapshot request dan response
+type Delivery struct {
+	ID              uint              `json:"id" gorm:"primaryKey"`
+	WebhookID       uint              `json:"webhook_id" gorm:"index"`
+	EventID         string            `json:"event_id" gorm:"size:32;index"`
+	Event           string            `json:"event" gorm:"size:100"`
+	Attempt         int               `json:"attempt"`
+	Test            bool              `json:"test"`
+	URL             string            `json:"url" gorm:"size:2048"`
+	RequestHeaders  map[string]string `json:"request_headers" gorm:"serializer:json"`
+	RequestBody     string            `json:"request_body" gorm:"type:text"`
+	ResponseStatus  int               `json:"response_status"`
+	ResponseHeaders map[string]string `json:"response_headers" gorm:"serializer:json"`
+	ResponseBody    string            `json:"response_body" gorm:"type:text"`
+	Error           string            `json:"error,omitempty" gorm:"type:text"`
+	Success         bool              `json:"success"`
+	DurationMs      int64             `json:"duration_ms"`
+	CreatedAt       time.Time         `json:"created_at" gorm:"index"`
+}
+
+// DeliveryJob adalah payload job pengiriman event ke satu webhook
+type DeliveryJob struct {
+	WebhookID uint            `json:"webhook_id"`
+	Event     events.Envelope `json:"event"`
+}
+
+// DTO: Query delivery log
+type DeliveryQuery struct {
+	Limit  int `query:"limit" validate:"omitempty,min=1,max=200"`
+	Offset int `query:"offset" validate:"omitempty,min=0"`
+}
+
+// DeliveryLimit returns limit clamped to the allowed range, or the default when unset
+func DeliveryLimit(limit int) int {
+	if limit <= 0 {
+		return DefaultDeliveryLimit
+	}
+	if limit > MaxDeliveryLimit {
+		return MaxDeliveryLimit
+	}
+	return limit
+}
+
+// Truncate memotong snapshot body ke MaxSnapshotSize
+func Truncate(body []byte) string {
+	if len(body) > MaxSnapshotSize {
+		return string(body[:MaxSnapshotSize])
+	}
+	return string(body)
+}
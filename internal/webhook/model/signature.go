@@ -0,0 +1,82 @@
+package model
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Header yang dikirim bersama setiap payload
+const (
+	SignatureHeader = "X-Signature"
+	EventHeader     = "X-Webhook-Event"
+	EventIDHeader   = "X-Webhook-ID"
+	AttemptHeader   = "X-Webhook-Attempt"
+)
+
+// SignatureTolerance adalah selisih waktu maksimal yang disarankan bagi receiver
+// sebelum menolak payload sebagai replay
+const SignatureTolerance = 5 * time.Minute
+
+var (
+	ErrInvalidSignature = errors.New("webhook: invalid signature")
+	ErrSignatureExpired = errors.New("webhook: signature timestamp outside tolerance")
+)
+
+// Sign mengembalikan nilai header X-Signature: "t=<unix>,v1=<hex HMAC-SHA256>", dengan
+// HMAC dihitung dari "<unix>.<body>" sehingga timestamp ikut ditandatangani
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	unix := timestamp.Unix()
+	return fmt.Sprintf("t=%d,v1=%s", unix, signature(secret, unix, body))
+}
+
+// VerifySignature memeriksa header X-Signature dan menolak timestamp yang berselisih
+// lebih dari tolerance dari now. Dipakai receiver yang ditulis dengan Go dan di test.
+func VerifySignature(secret, header string, body []byte, now time.Time, tolerance time.Duration) error {
+	var unix int64
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			parsed, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return ErrInvalidSignature
+			}
+			unix = parsed
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	if unix == 0 || len(signatures) == 0 {
+		return ErrInvalidSignature
+	}
+
+	if diff := now.Sub(time.Unix(unix, 0)); diff > tolerance || diff < -tolerance {
+		return ErrSignatureExpired
+	}
+
+	expected := signature(secret, unix, body)
+	for _, candidate := range signatures {
+		if hmac.Equal([]byte(candidate), []byte(expected)) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+func signature(secret string, unix int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(unix, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,139 @@
+package model
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
+
+	// mendaftarkan serializer "encrypted" untuk kolom secret
+	_ "boilerplate/pkg/fieldcrypt"
+)
+
+// Event yang dapat dilanggani webhook; daftar ini sama dengan tag validasi input
+const (
+	EventCategoryCreated = "category.created"
+	EventCategoryUpdated = "category.updated"
+	EventCategoryDeleted = "category.deleted"
+)
+
+const (
+	// SecretPrefix membuat signing secret mudah dicari (grep) jika bocor
+	SecretPrefix = "whsec_"
+	secretLength = 24
+
+	// DeliveryAttempts adalah jumlah percobaan pengiriman satu event sebelum masuk
+	// dead-letter queue
+	DeliveryAttempts = 8
+	// DisableAfterFailures adalah jumlah kegagalan berturut-turut sebelum webhook
+	// dinonaktifkan otomatis
+	DisableAfterFailures = 20
+)
+
+type Webhook struct {
+	ID          uint     `json:"id" gorm:"primaryKey"`
+	URL         string   `json:"url" gorm:"size:2048;not null"`
+	Description string   `json:"description" gorm:"size:255"`
+	Events      []string `json:"events" gorm:"serializer:json"`
+	// Secret dipakai untuk menandatangani payload; disimpan terenkripsi karena harus
+	// dapat dibaca kembali saat pengiriman
+	Secret              string     `json:"-" gorm:"size:512;serializer:encrypted"`
+	ConsecutiveFailures int        `json:"consecutive_failures" gorm:"not null;default:0"`
+	LastDeliveryAt      *time.Time `json:"last_delivery_at"`
+	DisabledAt          *time.Time `json:"disabled_at"`
+	DisabledReason      string     `json:"disabled_reason,omitempty" gorm:"size:255"`
+	CreatedBy           uint       `json:"created_by"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+// CreatedWebhook adalah response create; SigningSecret hanya ditampilkan sekali
+type CreatedWebhook struct {
+	Webhook
+	SigningSecret string `json:"secret"`
+}
+
+// DTO: Create webhook input. Secret kosong berarti dibuatkan secara acak.
+type CreateWebhookInput struct {
+	URL         string   `json:"url" validate:"required,http_url,max=2048"`
+	Description string   `json:"description" validate:"omitempty,max=255"`
+	Events      []string `json:"events" validate:"required,min=1,dive,oneof=category.created category.updated category.deleted"`
+	Secret      string   `json:"secret" validate:"omitempty,min=16,max=255"`
+}
+
+// DTO: Update webhook input
+type UpdateWebhookInput struct {
+	URL         string   `json:"url" validate:"required,http_url,max=2048"`
+	Description string   `json:"description" validate:"omitempty,max=255"`
+	Events      []string `json:"events" validate:"required,min=1,dive,oneof=category.created category.updated category.deleted"`
+}
+
+// Payload adalah body JSON yang dikirim ke receiver
+type Payload struct {
+	ID        string          `json:"id"`
+	Event     string          `json:"event"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// TestEvent dikirim oleh endpoint test untuk memeriksa receiver
+type TestEvent struct {
+	WebhookID uint   `json:"webhook_id"`
+	Message   string `json:"message"`
+}
+
+func (TestEvent) EventName() string { return "webhook.test" }
+
+// Factory: Create new webhook and its plaintext signing secret
+func NewWebhook(input CreateWebhookInput, createdBy uint) (*Webhook, error) {
+	secret := input.Secret
+	if secret == "" {
+		generated, err := NewSecret()
+		if err != nil {
+			return nil, err
+		}
+		secret = generated
+	}
+
+	return &Webhook{
+		URL:         strings.TrimSpace(input.URL),
+		Description: strings.TrimSpace(input.Description),
+		Events:      uniqueEvents(input.Events),
+		Secret:      secret,
+		CreatedBy:   createdBy,
+	}, nil
+}
+
+// NewSecret membuat signing secret acak
+func NewSecret() (string, error) {
+	buf := make([]byte, secretLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return SecretPrefix + base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// Update applies editable fields
+func (w *Webhook) Update(input UpdateWebhookInput) {
+	w.URL = strings.TrimSpace(input.URL)
+	w.Description = strings.TrimSpace(input.Description)
+	w.Events = uniqueEvents(input.Events)
+}
+
+// Subscribes reports whether the webhook should receive the event
+func (w *Webhook) Subscribes(event string) bool {
+	return slices.Contains(w.Events, event)
+}
+
+// IsDisabled reports whether the webhook was disabled after repeated failures
+func (w *Webhook) IsDisabled() bool {
+	return w.DisabledAt != nil
+}
+
+func uniqueEvents(events []string) []string {
+	unique := slices.Clone(events)
+	slices.Sort(unique)
+	return slices.Compact(unique)
+}
@@ -0,0 +1,152 @@
+package webhook
+
+import (
+	"net/http"
+	"strconv"
+
+	"boilerplate/internal/webhook/model"
+	"boilerplate/pkg/response"
+	errs "boilerplate/shared/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+type WebhookHandler struct {
+	webhookService WebhookServiceInterface
+}
+
+func NewWebhookHandler(webhookService WebhookServiceInterface) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+func (h *WebhookHandler) Create(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+
+	var input model.CreateWebhookInput
+	if err := c.Bind(&input); err != nil {
+		return errs.ErrInvalidPayload.Wrap(err)
+	}
+
+	if err := c.Validate(&input); err != nil {
+		return err
+	}
+
+	webhook, err := h.webhookService.Create(input, userID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, http.StatusCreated, "Webhook created successfully, store the secret: it will not be shown again", webhook)
+}
+
+func (h *WebhookHandler) GetAll(c echo.Context) error {
+	webhooks, err := h.webhookService.List()
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, http.StatusOK, "Webhooks retrieved successfully", webhooks)
+}
+
+func (h *WebhookHandler) GetByID(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return errs.ErrInvalidID.Wrap(err)
+	}
+
+	webhook, err := h.webhookService.GetByID(uint(id))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, http.StatusOK, "Webhook retrieved successfully", webhook)
+}
+
+func (h *WebhookHandler) Update(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return errs.ErrInvalidID.Wrap(err)
+	}
+
+	var input model.UpdateWebhookInput
+	if err := c.Bind(&input); err != nil {
+		return errs.ErrInvalidPayload.Wrap(err)
+	}
+
+	if err := c.Validate(&input); err != nil {
+		return err
+	}
+
+	webhook, err := h.webhookService.Update(uint(id), input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, http.StatusOK, "Webhook updated successfully", webhook)
+}
+
+func (h *WebhookHandler) Delete(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return errs.ErrInvalidID.Wrap(err)
+	}
+
+	if err := h.webhookService.Delete(uint(id)); err != nil {
+		return err
+	}
+
+	return response.Success(c, http.StatusOK, "Webhook deleted successfully", nil)
+}
+
+func (h *WebhookHandler) Enable(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return errs.ErrInvalidID.Wrap(err)
+	}
+
+	webhook, err := h.webhookService.Enable(uint(id))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, http.StatusOK, "Webhook enabled successfully", webhook)
+}
+
+func (h *WebhookHandler) GetDeliveries(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return errs.ErrInvalidID.Wrap(err)
+	}
+
+	var query model.DeliveryQuery
+	if err := c.Bind(&query); err != nil {
+		return errs.ErrInvalidPayload.Wrap(err)
+	}
+
+	if err := c.Validate(&query); err != nil {
+		return err
+	}
+
+	deliveries, err := h.webhookService.GetDeliveries(uint(id), query)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, http.StatusOK, "Webhook deliveries retrieved successfully", deliveries)
+}
+
+func (h *WebhookHandler) SendTest(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return errs.ErrInvalidID.Wrap(err)
+	}
+
+	delivery, err := h.webhookService.SendTest(c.Request().Context(), uint(id))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, http.StatusOK, "Test event sent", delivery)
+}
@@ -0,0 +1,361 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"boilerplate/internal/webhook/model"
+	"boilerplate/pkg/events"
+	"boilerplate/pkg/logger"
+	"boilerplate/pkg/queue"
+	errs "boilerplate/shared/errors"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// DeliverJob adalah tipe job pengiriman event ke satu webhook
+const DeliverJob = "webhook.deliver"
+
+const (
+	// deliveryTimeout membatasi satu request ke receiver
+	deliveryTimeout = 10 * time.Second
+	userAgent       = "boilerplate-webhooks/1.0"
+)
+
+// WebhookServiceInterface mendefinisikan kontrak untuk WebhookService
+type WebhookServiceInterface interface {
+	Create(input model.CreateWebhookInput, createdBy uint) (*model.CreatedWebhook, error)
+	List() ([]model.Webhook, error)
+	GetByID(id uint) (*model.Webhook, error)
+	Update(id uint, input model.UpdateWebhookInput) (*model.Webhook, error)
+	Delete(id uint) error
+	Enable(id uint) (*model.Webhook, error)
+	GetDeliveries(id uint, query model.DeliveryQuery) ([]model.Delivery, error)
+	SendTest(ctx context.Context, id uint) (*model.Delivery, error)
+	PurgeDeliveries(ctx context.Context, before time.Time) (int64, error)
+}
+
+// WebhookService mengelola langganan webhook dan mengirim domain event ke receiver.
+// Event dari outbox diteruskan ke antrian job (satu job per webhook) sehingga retry
+// dengan backoff ditangani worker.
+type WebhookService struct {
+	db     *gorm.DB
+	queue  *queue.Queue
+	logger logger.Logger
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewWebhookService membuat service webhook. allowPrivate mengizinkan receiver di
+// jaringan internal atau localhost; hanya untuk pengembangan lokal.
+func NewWebhookService(db *gorm.DB, queue *queue.Queue, logger logger.Logger, allowPrivate bool) *WebhookService {
+	return &WebhookService{
+		db:     db,
+		queue:  queue,
+		logger: logger,
+		client: newClient(allowPrivate),
+		now:    time.Now,
+	}
+}
+
+// Subscribe mendaftarkan pengiriman webhook untuk setiap event yang dapat dilanggani
+func (s *WebhookService) Subscribe(bus *events.Bus) {
+	for _, event := range []string{model.EventCategoryCreated, model.EventCategoryUpdated, model.EventCategoryDeleted} {
+		bus.Subscribe(event, s.Dispatch)
+	}
+}
+
+// RegisterJobs mendaftarkan handler job pengiriman ke worker
+func (s *WebhookService) RegisterJobs(worker *queue.Worker) {
+	worker.Handle(DeliverJob, s.deliverJob)
+}
+
+func (s *WebhookService) Create(input model.CreateWebhookInput, createdBy uint) (*model.CreatedWebhook, error) {
+	webhook, err := model.NewWebhook(input, createdBy)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Create(webhook).Error; err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"url":   webhook.URL,
+			"error": err.Error(),
+		}).Error("Gagal menyimpan webhook")
+		return nil, err
+	}
+
+	return &model.CreatedWebhook{Webhook: *webhook, SigningSecret: webhook.Secret}, nil
+}
+
+func (s *WebhookService) List() ([]model.Webhook, error) {
+	var webhooks []model.Webhook
+	if err := s.db.Order("id").Find(&webhooks).Error; err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+func (s *WebhookService) GetByID(id uint) (*model.Webhook, error) {
+	var webhook model.Webhook
+	if err := s.db.First(&webhook, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrWebhookNotFound.Wrap(err)
+		}
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+func (s *WebhookService) Update(id uint, input model.UpdateWebhookInput) (*model.Webhook, error) {
+	webhook, err := s.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook.Update(input)
+	if err := s.db.Model(webhook).Select("url", "description", "events").Updates(webhook).Error; err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// Delete menghapus webhook beserta log pengirimannya. Job yang masih di antrian
+// diabaikan saat dijalankan.
+func (s *WebhookService) Delete(id uint) error {
+	webhook, err := s.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("webhook_id = ?", webhook.ID).Delete(&model.Delivery{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(webhook).Error
+	})
+}
+
+// Enable mengaktifkan kembali webhook yang dinonaktifkan otomatis
+func (s *WebhookService) Enable(id uint) (*model.Webhook, error) {
+	webhook, err := s.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook.DisabledAt = nil
+	webhook.DisabledReason = ""
+	webhook.ConsecutiveFailures = 0
+	if err := s.db.Model(webhook).Select("disabled_at", "disabled_reason", "consecutive_failures").Updates(webhook).Error; err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// GetDeliveries mengembalikan log pengiriman webhook, terbaru lebih dulu
+func (s *WebhookService) GetDeliveries(id uint, query model.DeliveryQuery) ([]model.Delivery, error) {
+	if _, err := s.GetByID(id); err != nil {
+		return nil, err
+	}
+
+	var deliveries []model.Delivery
+	if err := s.db.Where("webhook_id = ?", id).
+		Order("id DESC").
+		Offset(query.Offset).
+		Limit(model.DeliveryLimit(query.Limit)).
+		Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// SendTest mengirim event webhook.test sekali secara langsung, juga ke webhook yang
+// sedang nonaktif. Hasilnya dicatat di log tetapi tidak memengaruhi hitungan kegagalan.
+func (s *WebhookService) SendTest(ctx context.Context, id uint) (*model.Delivery, error) {
+	webhook, err := s.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	envelope, err := events.NewEnvelope(model.TestEvent{
+		WebhookID: webhook.ID,
+		Message:   "This is a test event",
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.deliver(ctx, webhook, envelope, 1, true)
+}
+
+// PurgeDeliveries menghapus log pengiriman yang lebih lama dari before
+func (s *WebhookService) PurgeDeliveries(ctx context.Context, before time.Time) (int64, error) {
+	result := s.db.WithContext(ctx).Where("created_at < ?", before).Delete(&model.Delivery{})
+	return result.RowsAffected, result.Error
+}
+
+// Dispatch mengantrikan satu job pengiriman untuk setiap webhook aktif yang
+// melanggani event. Event yang dikirim ulang relay tidak diantrikan dua kali selama
+// job sebelumnya belum selesai.
+func (s *WebhookService) Dispatch(ctx context.Context, envelope events.Envelope) error {
+	var webhooks []model.Webhook
+	if err := s.db.WithContext(ctx).Where("disabled_at IS NULL").Find(&webhooks).Error; err != nil {
+		return err
+	}
+
+	var failed []error
+	for _, webhook := range webhooks {
+		if !webhook.Subscribes(envelope.Name) {
+			continue
+		}
+		_, err := s.queue.Enqueue(ctx, DeliverJob, model.DeliveryJob{WebhookID: webhook.ID, Event: envelope},
+			queue.MaxAttempts(model.DeliveryAttempts),
+			queue.Unique(fmt.Sprintf("webhook:%d:%s", webhook.ID, envelope.ID)),
+		)
+		if err != nil && !errors.Is(err, queue.ErrDuplicate) {
+			failed = append(failed, err)
+		}
+	}
+	return errors.Join(failed...)
+}
+
+// deliverJob mengirim event ke webhook. Kegagalan dicoba ulang oleh antrian; webhook
+// dinonaktifkan setelah DisableAfterFailures kegagalan berturut-turut.
+func (s *WebhookService) deliverJob(ctx context.Context, job *queue.Job) error {
+	var payload model.DeliveryJob
+	if err := job.Decode(&payload); err != nil {
+		return queue.Permanent(fmt.Errorf("decode payload: %w", err))
+	}
+
+	fields := logrus.Fields{"webhook_id": payload.WebhookID, "event_id": payload.Event.ID, "event": payload.Event.Name}
+	webhook, err := s.GetByID(payload.WebhookID)
+	if errors.Is(err, errs.ErrWebhookNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if webhook.IsDisabled() {
+		s.logger.WithFields(fields).Warn("Webhook nonaktif, pengiriman dilewati")
+		return nil
+	}
+
+	delivery, err := s.deliver(ctx, webhook, payload.Event, job.Attempts, false)
+	if err != nil {
+		return err
+	}
+	if delivery.Success {
+		return s.db.Model(webhook).UpdateColumns(map[string]interface{}{
+			"consecutive_failures": 0,
+			"last_delivery_at":     delivery.CreatedAt,
+		}).Error
+	}
+
+	failure := errors.New(delivery.Error)
+	if err := s.db.Model(webhook).UpdateColumn("consecutive_failures", gorm.Expr("consecutive_failures + 1")).Error; err != nil {
+		return errors.Join(failure, err)
+	}
+	result := s.db.Model(&model.Webhook{}).
+		Where("id = ? AND disabled_at IS NULL AND consecutive_failures >= ?", webhook.ID, model.DisableAfterFailures).
+		UpdateColumns(map[string]interface{}{
+			"disabled_at":     s.now(),
+			"disabled_reason": fmt.Sprintf("disabled after %d consecutive failed deliveries", model.DisableAfterFailures),
+		})
+	if result.Error != nil {
+		return errors.Join(failure, result.Error)
+	}
+	if result.RowsAffected > 0 {
+		s.logger.WithFields(fields).Warn("Webhook dinonaktifkan setelah pengiriman gagal berturut-turut")
+		return queue.Permanent(failure)
+	}
+	return failure
+}
+
+// deliver mengirim satu request bertanda tangan dan mencatat hasilnya. Error hanya
+// dikembalikan jika log tidak dapat disimpan; kegagalan receiver tercatat di Delivery.
+func (s *WebhookService) deliver(ctx context.Context, webhook *model.Webhook, envelope events.Envelope, attempt int, test bool) (*model.Delivery, error) {
+	body, err := json.Marshal(model.Payload{
+		ID:        envelope.ID,
+		Event:     envelope.Name,
+		CreatedAt: envelope.OccurredAt,
+		Data:      envelope.Payload,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	headers := map[string]string{
+		"Content-Type":        "application/json",
+		"User-Agent":          userAgent,
+		model.EventHeader:     envelope.Name,
+		model.EventIDHeader:   envelope.ID,
+		model.AttemptHeader:   strconv.Itoa(attempt),
+		model.SignatureHeader: model.Sign(webhook.Secret, now, body),
+	}
+	delivery := &model.Delivery{
+		WebhookID:      webhook.ID,
+		EventID:        envelope.ID,
+		Event:          envelope.Name,
+		Attempt:        attempt,
+		Test:           test,
+		URL:            webhook.URL,
+		RequestHeaders: headers,
+		RequestBody:    model.Truncate(body),
+		CreatedAt:      now,
+	}
+
+	start := time.Now()
+	s.send(ctx, delivery, headers, body)
+	delivery.DurationMs = time.Since(start).Milliseconds()
+
+	if err := s.db.Create(delivery).Error; err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"webhook_id": webhook.ID,
+			"event_id":   envelope.ID,
+			"error":      err.Error(),
+		}).Error("Gagal menyimpan log pengiriman webhook")
+		return nil, err
+	}
+	return delivery, nil
+}
+
+func (s *WebhookService) send(ctx context.Context, delivery *model.Delivery, headers map[string]string, body []byte) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(body))
+	if err != nil {
+		delivery.Error = err.Error()
+		return
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		delivery.Error = err.Error()
+		return
+	}
+	defer resp.Body.Close()
+
+	snapshot, err := io.ReadAll(io.LimitReader(resp.Body, model.MaxSnapshotSize))
+	if err != nil {
+		delivery.Error = err.Error()
+	}
+	delivery.ResponseStatus = resp.StatusCode
+	delivery.ResponseBody = model.Truncate(snapshot)
+	delivery.ResponseHeaders = make(map[string]string, len(resp.Header))
+	for name := range resp.Header {
+		delivery.ResponseHeaders[name] = resp.Header.Get(name)
+	}
+
+	delivery.Success = err == nil && resp.StatusCode >= 200 && resp.StatusCode < 300
+	if !delivery.Success && delivery.Error == "" {
+		delivery.Error = fmt.Sprintf("receiver responded with status %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,312 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"boilerplate/internal/testutil"
+	"boilerplate/internal/webhook/model"
+	"boilerplate/pkg/events"
+	"boilerplate/pkg/logger"
+	"boilerplate/pkg/queue"
+	errs "boilerplate/shared/errors"
+
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+// receiver adalah endpoint webhook lokal yang memverifikasi signature setiap request
+type receiver struct {
+	mu       sync.Mutex
+	secret   string
+	status   int
+	requests []model.Payload
+	headers  []http.Header
+	errors   []error
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := model.VerifySignature(r.secret, req.Header.Get(model.SignatureHeader), body, time.Now(), model.SignatureTolerance); err != nil {
+		r.errors = append(r.errors, err)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var payload model.Payload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		r.errors = append(r.errors, err)
+	}
+	r.requests = append(r.requests, payload)
+	r.headers = append(r.headers, req.Header.Clone())
+	w.Header().Set("X-Receiver", "test")
+	w.WriteHeader(r.status)
+	_, _ = w.Write([]byte(`{"received":true}`))
+}
+
+type WebhookServiceTestSuite struct {
+	suite.Suite
+	ctx      context.Context
+	db       *gorm.DB
+	queue    *queue.Queue
+	worker   *queue.Worker
+	service  *WebhookService
+	receiver *receiver
+	server   *httptest.Server
+}
+
+func TestWebhookServiceSuite(t *testing.T) {
+	suite.Run(t, new(WebhookServiceTestSuite))
+}
+
+func (s *WebhookServiceTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	db := testutil.NewDB(s.T(), &model.Webhook{}, &model.Delivery{})
+	s.db = db
+
+	redisClient := testutil.NewRedisClient(s.T())
+	s.queue = queue.New(redisClient.Client(), queue.Config{})
+	s.worker = queue.NewWorker(s.queue, logger.NewLogger())
+	// receiver test berjalan di localhost
+	s.service = NewWebhookService(db, s.queue, logger.NewLogger(), true)
+	s.service.RegisterJobs(s.worker)
+
+	s.receiver = &receiver{status: http.StatusOK}
+	s.server = httptest.NewServer(s.receiver)
+	s.T().Cleanup(s.server.Close)
+}
+
+func (s *WebhookServiceTestSuite) createWebhook(events ...string) *model.CreatedWebhook {
+	created, err := s.service.Create(model.CreateWebhookInput{
+		URL:    s.server.URL + "/hooks",
+		Events: events,
+	}, 1)
+	s.Require().NoError(err)
+	s.receiver.secret = created.SigningSecret
+	return created
+}
+
+func (s *WebhookServiceTestSuite) categoryEvent(name string) events.Envelope {
+	envelope, err := events.NewEnvelope(testCategoryEvent{name: name, CategoryID: 42})
+	s.Require().NoError(err)
+	return envelope
+}
+
+type testCategoryEvent struct {
+	name       string
+	CategoryID uint `json:"category_id"`
+}
+
+func (e testCategoryEvent) EventName() string { return e.name }
+
+func (s *WebhookServiceTestSuite) stats() queue.Stats {
+	stats, err := s.queue.Stats(s.ctx)
+	s.Require().NoError(err)
+	return stats
+}
+
+func (s *WebhookServiceTestSuite) process() {
+	processed, err := s.worker.ProcessNext(s.ctx)
+	s.Require().NoError(err)
+	s.Require().True(processed)
+}
+
+func (s *WebhookServiceTestSuite) TestCreateReturnsSecretOnce() {
+	created := s.createWebhook(model.EventCategoryUpdated, model.EventCategoryCreated, model.EventCategoryUpdated)
+	s.Contains(created.SigningSecret, model.SecretPrefix)
+	s.Equal([]string{model.EventCategoryCreated, model.EventCategoryUpdated}, created.Events)
+
+	data, err := json.Marshal(created.Webhook)
+	s.Require().NoError(err)
+	s.NotContains(string(data), created.SigningSecret)
+
+	stored, err := s.service.GetByID(created.ID)
+	s.Require().NoError(err)
+	s.Equal(created.SigningSecret, stored.Secret)
+}
+
+func (s *WebhookServiceTestSuite) TestDispatchDeliversSignedPayloadToSubscribers() {
+	subscribed := s.createWebhook(model.EventCategoryUpdated)
+	s.createWebhook(model.EventCategoryDeleted)
+	s.receiver.secret = subscribed.SigningSecret
+
+	envelope := s.categoryEvent(model.EventCategoryUpdated)
+	s.Require().NoError(s.service.Dispatch(s.ctx, envelope))
+	// event yang dikirim ulang relay tidak diantrikan dua kali
+	s.Require().NoError(s.service.Dispatch(s.ctx, envelope))
+	s.Equal(int64(1), s.stats().Ready)
+
+	s.process()
+	s.Empty(s.receiver.errors)
+	s.Require().Len(s.receiver.requests, 1)
+	s.Equal(envelope.ID, s.receiver.requests[0].ID)
+	s.Equal(model.EventCategoryUpdated, s.receiver.requests[0].Event)
+	s.JSONEq(`{"category_id":42}`, string(s.receiver.requests[0].Data))
+	s.Equal(envelope.ID, s.receiver.headers[0].Get(model.EventIDHeader))
+	s.Equal("1", s.receiver.headers[0].Get(model.AttemptHeader))
+
+	deliveries, err := s.service.GetDeliveries(subscribed.ID, model.DeliveryQuery{})
+	s.Require().NoError(err)
+	s.Require().Len(deliveries, 1)
+	s.True(deliveries[0].Success)
+	s.Equal(http.StatusOK, deliveries[0].ResponseStatus)
+	s.Equal(`{"received":true}`, deliveries[0].ResponseBody)
+	s.Equal("test", deliveries[0].ResponseHeaders["X-Receiver"])
+	s.NotEmpty(deliveries[0].RequestHeaders[model.SignatureHeader])
+
+	webhook, err := s.service.GetByID(subscribed.ID)
+	s.Require().NoError(err)
+	s.NotNil(webhook.LastDeliveryAt)
+}
+
+func (s *WebhookServiceTestSuite) TestFailedDeliveryIsRetried() {
+	created := s.createWebhook(model.EventCategoryCreated)
+	s.receiver.status = http.StatusServiceUnavailable
+
+	s.Require().NoError(s.service.Dispatch(s.ctx, s.categoryEvent(model.EventCategoryCreated)))
+	s.process()
+
+	stats := s.stats()
+	s.Equal(int64(1), stats.Scheduled)
+	s.Zero(stats.Dead)
+
+	deliveries, err := s.service.GetDeliveries(created.ID, model.DeliveryQuery{})
+	s.Require().NoError(err)
+	s.Require().Len(deliveries, 1)
+	s.False(deliveries[0].Success)
+	s.Equal(http.StatusServiceUnavailable, deliveries[0].ResponseStatus)
+	s.Equal("receiver responded with status 503", deliveries[0].Error)
+
+	webhook, err := s.service.GetByID(created.ID)
+	s.Require().NoError(err)
+	s.Equal(1, webhook.ConsecutiveFailures)
+	s.False(webhook.IsDisabled())
+}
+
+func (s *WebhookServiceTestSuite) TestWebhookDisabledAfterRepeatedFailures() {
+	created := s.createWebhook(model.EventCategoryCreated)
+	s.Require().NoError(s.db.Model(&model.Webhook{}).Where("id = ?", created.ID).
+		Update("consecutive_failures", model.DisableAfterFailures-1).Error)
+	s.receiver.status = http.StatusInternalServerError
+
+	s.Require().NoError(s.service.Dispatch(s.ctx, s.categoryEvent(model.EventCategoryCreated)))
+	s.process()
+
+	webhook, err := s.service.GetByID(created.ID)
+	s.Require().NoError(err)
+	s.True(webhook.IsDisabled())
+	s.NotEmpty(webhook.DisabledReason)
+	s.Equal(int64(1), s.stats().Dead)
+
+	// webhook nonaktif tidak menerima event baru
+	s.Require().NoError(s.service.Dispatch(s.ctx, s.categoryEvent(model.EventCategoryCreated)))
+	s.Zero(s.stats().Ready)
+
+	enabled, err := s.service.Enable(created.ID)
+	s.Require().NoError(err)
+	s.False(enabled.IsDisabled())
+	s.Zero(enabled.ConsecutiveFailures)
+}
+
+func (s *WebhookServiceTestSuite) TestSuccessResetsFailureCount() {
+	created := s.createWebhook(model.EventCategoryDeleted)
+	s.Require().NoError(s.db.Model(&model.Webhook{}).Where("id = ?", created.ID).
+		Update("consecutive_failures", 3).Error)
+
+	s.Require().NoError(s.service.Dispatch(s.ctx, s.categoryEvent(model.EventCategoryDeleted)))
+	s.process()
+
+	webhook, err := s.service.GetByID(created.ID)
+	s.Require().NoError(err)
+	s.Zero(webhook.ConsecutiveFailures)
+}
+
+func (s *WebhookServiceTestSuite) TestSendTest() {
+	created := s.createWebhook(model.EventCategoryCreated)
+	s.receiver.status = http.StatusBadRequest
+
+	delivery, err := s.service.SendTest(s.ctx, created.ID)
+	s.Require().NoError(err)
+	s.True(delivery.Test)
+	s.False(delivery.Success)
+	s.Equal(http.StatusBadRequest, delivery.ResponseStatus)
+	s.Require().Len(s.receiver.requests, 1)
+	s.Equal("webhook.test", s.receiver.requests[0].Event)
+
+	// test tidak dihitung sebagai kegagalan
+	webhook, err := s.service.GetByID(created.ID)
+	s.Require().NoError(err)
+	s.Zero(webhook.ConsecutiveFailures)
+
+	_, err = s.service.SendTest(s.ctx, 999)
+	s.ErrorIs(err, errs.ErrWebhookNotFound)
+}
+
+func (s *WebhookServiceTestSuite) TestUnreachableReceiverIsLogged() {
+	created := s.createWebhook(model.EventCategoryCreated)
+	s.server.Close()
+
+	delivery, err := s.service.SendTest(s.ctx, created.ID)
+	s.Require().NoError(err)
+	s.False(delivery.Success)
+	s.Zero(delivery.ResponseStatus)
+	s.NotEmpty(delivery.Error)
+}
+
+func (s *WebhookServiceTestSuite) TestPrivateReceiverIsBlocked() {
+	created := s.createWebhook(model.EventCategoryCreated)
+	s.service.client = newClient(false)
+
+	delivery, err := s.service.SendTest(s.ctx, created.ID)
+	s.Require().NoError(err)
+	s.False(delivery.Success)
+	s.Contains(delivery.Error, errBlockedAddress.Error())
+	s.Empty(s.receiver.requests)
+}
+
+func (s *WebhookServiceTestSuite) TestIsPublic() {
+	for _, address := range []string{"127.0.0.1", "::1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "169.254.169.254", "fe80::1", "fd00::1", "0.0.0.0", "0.1.2.3", "::", "224.0.0.1", "::ffff:127.0.0.1"} {
+		s.False(isPublic(net.ParseIP(address)), address)
+	}
+	for _, address := range []string{"93.184.216.34", "1.1.1.1", "2606:4700:4700::1111"} {
+		s.True(isPublic(net.ParseIP(address)), address)
+	}
+}
+
+func (s *WebhookServiceTestSuite) TestDeleteRemovesDeliveriesAndSkipsQueuedJobs() {
+	created := s.createWebhook(model.EventCategoryCreated)
+	_, err := s.service.SendTest(s.ctx, created.ID)
+	s.Require().NoError(err)
+	s.Require().NoError(s.service.Dispatch(s.ctx, s.categoryEvent(model.EventCategoryCreated)))
+
+	s.Require().NoError(s.service.Delete(created.ID))
+	var count int64
+	s.Require().NoError(s.db.Model(&model.Delivery{}).Count(&count).Error)
+	s.Zero(count)
+
+	s.process()
+	s.Len(s.receiver.requests, 1)
+	s.Zero(s.stats().Dead)
+}
+
+func (s *WebhookServiceTestSuite) TestVerifySignatureRejectsReplayAndTampering() {
+	body := []byte(`{"id":"1"}`)
+	signedAt := time.Now().Add(-10 * time.Minute)
+	header := model.Sign("secret", signedAt, body)
+
+	s.NoError(model.VerifySignature("secret", header, body, signedAt, model.SignatureTolerance))
+	s.ErrorIs(model.VerifySignature("secret", header, body, time.Now(), model.SignatureTolerance), model.ErrSignatureExpired)
+	s.ErrorIs(model.VerifySignature("secret", header, []byte(`{"id":"2"}`), signedAt, model.SignatureTolerance), model.ErrInvalidSignature)
+	s.ErrorIs(model.VerifySignature("other", header, body, signedAt, model.SignatureTolerance), model.ErrInvalidSignature)
+	s.ErrorIs(model.VerifySignature("secret", "garbage", body, signedAt, model.SignatureTolerance), model.ErrInvalidSignature)
+}
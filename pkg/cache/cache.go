@@ -0,0 +1,29 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// BackendRedis menyimpan cache di Redis sehingga dipakai bersama semua instance
+	BackendRedis = "redis"
+	// BackendMemory menyimpan cache di memori proses (LRU); hanya cocok untuk satu instance
+	// karena invalidasi dari instance lain tidak terlihat
+	BackendMemory = "memory"
+	// BackendNone menonaktifkan cache
+	BackendNone = "none"
+)
+
+// Cache adalah backend cache berbasis byte. Tag mengelompokkan key agar dapat
+// diinvalidasi bersama, mis. semua key yang memuat satu category.
+type Cache interface {
+	// Get mengembalikan nilai dan true jika key ada dan belum kedaluwarsa
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set menyimpan nilai selama ttl dan mendaftarkan key ke setiap tag
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration, tags ...string) error
+	// Delete menghapus key
+	Delete(ctx context.Context, keys ...string) error
+	// InvalidateTags menghapus semua key yang terdaftar di tag
+	InvalidateTags(ctx context.Context, tags ...string) error
+}
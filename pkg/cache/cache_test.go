@@ -0,0 +1,273 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"boilerplate/pkg/fieldcrypt"
+	"boilerplate/pkg/logger"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/suite"
+)
+
+// BackendTestSuite menjalankan kontrak Cache yang sama untuk setiap backend
+type BackendTestSuite struct {
+	suite.Suite
+	ctx     context.Context
+	cache   Cache
+	advance func(time.Duration)
+	newFn   func(s *BackendTestSuite) (Cache, func(time.Duration))
+}
+
+func TestRedisBackend(t *testing.T) {
+	suite.Run(t, &BackendTestSuite{newFn: func(s *BackendTestSuite) (Cache, func(time.Duration)) {
+		server := miniredis.RunT(s.T())
+		client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+		return NewRedisCache(client, ""), server.FastForward
+	}})
+}
+
+func TestLRUBackend(t *testing.T) {
+	suite.Run(t, &BackendTestSuite{newFn: func(s *BackendTestSuite) (Cache, func(time.Duration)) {
+		lru := NewLRU(0)
+		now := time.Now()
+		lru.now = func() time.Time { return now }
+		return lru, func(d time.Duration) { now = now.Add(d) }
+	}})
+}
+
+func (s *BackendTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.cache, s.advance = s.newFn(s)
+}
+
+func (s *BackendTestSuite) get(key string) ([]byte, bool) {
+	value, found, err := s.cache.Get(s.ctx, key)
+	s.Require().NoError(err)
+	return value, found
+}
+
+func (s *BackendTestSuite) TestSetGetAndExpire() {
+	_, found := s.get("a")
+	s.False(found)
+
+	s.Require().NoError(s.cache.Set(s.ctx, "a", []byte("1"), time.Minute))
+	value, found := s.get("a")
+	s.True(found)
+	s.Equal([]byte("1"), value)
+
+	s.advance(time.Minute + time.Second)
+	_, found = s.get("a")
+	s.False(found)
+}
+
+func (s *BackendTestSuite) TestInvalidateTags() {
+	s.Require().NoError(s.cache.Set(s.ctx, "list", []byte("[]"), time.Minute, "list"))
+	s.Require().NoError(s.cache.Set(s.ctx, "1", []byte("one"), time.Minute, "1"))
+	s.Require().NoError(s.cache.Set(s.ctx, "2", []byte("two"), time.Minute, "2"))
+
+	s.Require().NoError(s.cache.InvalidateTags(s.ctx, "list", "1"))
+	_, found := s.get("list")
+	s.False(found)
+	_, found = s.get("1")
+	s.False(found)
+	_, found = s.get("2")
+	s.True(found)
+
+	// key yang disimpan ulang terdaftar lagi di tag
+	s.Require().NoError(s.cache.Set(s.ctx, "1", []byte("one"), time.Minute, "1"))
+	s.Require().NoError(s.cache.InvalidateTags(s.ctx, "1"))
+	_, found = s.get("1")
+	s.False(found)
+}
+
+func (s *BackendTestSuite) TestDelete() {
+	s.Require().NoError(s.cache.Set(s.ctx, "a", []byte("1"), time.Minute, "tag"))
+	s.Require().NoError(s.cache.Delete(s.ctx, "a", "missing"))
+	_, found := s.get("a")
+	s.False(found)
+	s.NoError(s.cache.InvalidateTags(s.ctx, "tag"))
+}
+
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	lru := NewLRU(2)
+	_ = lru.Set(ctx, "a", []byte("a"), time.Minute, "letters")
+	_ = lru.Set(ctx, "b", []byte("b"), time.Minute, "letters")
+	if _, found, _ := lru.Get(ctx, "a"); !found {
+		t.Fatal("expected a to be cached")
+	}
+	_ = lru.Set(ctx, "c", []byte("c"), time.Minute, "letters")
+
+	if _, found, _ := lru.Get(ctx, "b"); found {
+		t.Error("expected b to be evicted")
+	}
+	if lru.Len() != 2 {
+		t.Errorf("expected 2 entries, got %d", lru.Len())
+	}
+	if len(lru.tags["letters"]) != 2 {
+		t.Errorf("expected evicted key to leave the tag, got %v", lru.tags["letters"])
+	}
+}
+
+type item struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+type LoaderTestSuite struct {
+	suite.Suite
+	ctx    context.Context
+	lru    *LRU
+	loader *Loader
+	loads  atomic.Int32
+}
+
+func TestLoaderSuite(t *testing.T) {
+	suite.Run(t, new(LoaderTestSuite))
+}
+
+func (s *LoaderTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.lru = NewLRU(0)
+	s.loader = NewLoader(s.lru, "item", time.Minute, logger.NewLogger())
+	s.loads.Store(0)
+}
+
+func (s *LoaderTestSuite) load(id uint) func(context.Context) (*item, error) {
+	return func(context.Context) (*item, error) {
+		s.loads.Add(1)
+		return &item{ID: id, Name: "first"}, nil
+	}
+}
+
+func (s *LoaderTestSuite) TestGetOrLoadCachesAndCountsHits() {
+	first, err := GetOrLoad(s.ctx, s.loader, "1", s.load(1), "1")
+	s.Require().NoError(err)
+	first.Name = "mutated by caller"
+
+	second, err := GetOrLoad(s.ctx, s.loader, "1", s.load(1), "1")
+	s.Require().NoError(err)
+	s.Equal("first", second.Name)
+	s.Equal(int32(1), s.loads.Load())
+
+	stats := s.loader.Stats()
+	s.Equal("item", stats.Name)
+	s.Equal(uint64(1), stats.Hits)
+	s.Equal(uint64(1), stats.Misses)
+	s.Equal(0.5, stats.HitRatio)
+
+	// key dan tag diberi prefix nama loader
+	_, found, _ := s.lru.Get(s.ctx, "item:1")
+	s.True(found)
+	s.Require().NoError(s.loader.Invalidate(s.ctx, "1"))
+	_, err = GetOrLoad(s.ctx, s.loader, "1", s.load(1), "1")
+	s.Require().NoError(err)
+	s.Equal(int32(2), s.loads.Load())
+}
+
+func (s *LoaderTestSuite) TestErrorsAreNotCached() {
+	failure := errors.New("not found")
+	_, err := GetOrLoad(s.ctx, s.loader, "1", func(context.Context) (*item, error) {
+		s.loads.Add(1)
+		return nil, failure
+	})
+	s.ErrorIs(err, failure)
+
+	value, err := GetOrLoad(s.ctx, s.loader, "1", s.load(1))
+	s.Require().NoError(err)
+	s.Equal(uint(1), value.ID)
+	s.Equal(int32(2), s.loads.Load())
+}
+
+func (s *LoaderTestSuite) TestSingleflightPreventsStampede() {
+	release := make(chan struct{})
+	load := func(context.Context) ([]item, error) {
+		s.loads.Add(1)
+		<-release
+		return []item{{ID: 1}}, nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([][]item, 10)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = GetOrLoad(s.ctx, s.loader, "list", load, "list")
+		}(i)
+	}
+	s.Eventually(func() bool { return s.loads.Load() == 1 }, time.Second, time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	s.Equal(int32(1), s.loads.Load())
+	for _, result := range results {
+		s.Equal([]item{{ID: 1}}, result)
+	}
+}
+
+func (s *LoaderTestSuite) TestCorruptEntryIsReloaded() {
+	s.Require().NoError(s.lru.Set(s.ctx, "item:1", []byte("not json"), time.Minute))
+
+	value, err := GetOrLoad(s.ctx, s.loader, "1", s.load(1))
+	s.Require().NoError(err)
+	s.Equal(uint(1), value.ID)
+	s.Equal(uint64(1), s.loader.Stats().Errors)
+}
+
+func (s *LoaderTestSuite) TestNilLoaderAlwaysLoads() {
+	var loader *Loader
+	for i := 0; i < 2; i++ {
+		_, err := GetOrLoad(s.ctx, loader, "1", s.load(1))
+		s.Require().NoError(err)
+	}
+	s.Equal(int32(2), s.loads.Load())
+	s.NoError(loader.Invalidate(s.ctx, "1"))
+	s.Nil(NewRegistry(nil, logger.NewLogger()).Loader("item", time.Minute))
+}
+
+func (s *LoaderTestSuite) TestEncryptedCodec() {
+	keyring, err := fieldcrypt.NewKeyring(1, map[uint32][]byte{1: bytes.Repeat([]byte{1}, fieldcrypt.KeySize)}, []byte("blind"))
+	s.Require().NoError(err)
+	previous := fieldcrypt.DefaultKeyring()
+	fieldcrypt.SetDefaultKeyring(keyring)
+	s.T().Cleanup(func() { fieldcrypt.SetDefaultKeyring(previous) })
+
+	loader := NewLoader(s.lru, "secret", time.Minute, logger.NewLogger(), WithCodec(Encrypted("cache:secret")))
+	_, err = GetOrLoad(s.ctx, loader, "1", s.load(1))
+	s.Require().NoError(err)
+
+	raw, found, _ := s.lru.Get(s.ctx, "secret:1")
+	s.Require().True(found)
+	s.NotContains(string(raw), "first")
+
+	value, err := GetOrLoad(s.ctx, loader, "1", s.load(1))
+	s.Require().NoError(err)
+	s.Equal("first", value.Name)
+	s.Equal(int32(1), s.loads.Load())
+}
+
+func (s *LoaderTestSuite) TestRegistryStats() {
+	registry := NewRegistry(s.lru, logger.NewLogger())
+	categories := registry.Loader("category", time.Minute)
+	registry.Loader("user", time.Minute)
+
+	_, err := GetOrLoad(s.ctx, categories, "1", s.load(1))
+	s.Require().NoError(err)
+
+	stats := registry.Stats()
+	s.Require().Len(stats, 2)
+	s.Equal("category", stats[0].Name)
+	s.Equal(uint64(1), stats[0].Misses)
+	s.Equal("user", stats[1].Name)
+	s.Zero(stats[1].Misses)
+}
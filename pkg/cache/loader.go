@@ -0,0 +1,252 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"boilerplate/pkg/fieldcrypt"
+	"boilerplate/pkg/logger"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/singleflight"
+)
+
+// Codec mengubah nilai ke byte yang disimpan backend dan sebaliknya
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+
+// JSON adalah codec default. Field dengan tag json:"-" tidak ikut tersimpan.
+var JSON Codec = jsonCodec{}
+
+type encryptedCodec struct {
+	aad string
+}
+
+// Encrypted mengenkripsi hasil JSON dengan keyring fieldcrypt default sebelum disimpan,
+// untuk nilai yang memuat data pribadi. Tanpa key enkripsi nilai disimpan apa adanya.
+func Encrypted(aad string) Codec {
+	return encryptedCodec{aad: aad}
+}
+
+func (c encryptedCodec) Marshal(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext, err := fieldcrypt.Encrypt(string(data), c.aad)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(ciphertext), nil
+}
+
+func (c encryptedCodec) Unmarshal(data []byte, v interface{}) error {
+	plaintext, err := fieldcrypt.Decrypt(string(data), c.aad)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal([]byte(plaintext), v)
+}
+
+// Option mengatur Loader
+type Option func(*Loader)
+
+// WithCodec mengganti codec JSON default
+func WithCodec(codec Codec) Option {
+	return func(l *Loader) {
+		l.codec = codec
+	}
+}
+
+// Stats adalah hitungan akses satu loader sejak proses berjalan
+type Stats struct {
+	Name     string  `json:"name"`
+	Hits     uint64  `json:"hits"`
+	Misses   uint64  `json:"misses"`
+	Errors   uint64  `json:"errors"`
+	HitRatio float64 `json:"hit_ratio"`
+}
+
+// Loader adalah namespace cache untuk satu jenis data dengan TTL, codec dan
+// statistik sendiri. Key dan tag diberi prefix nama loader. Loader nil selalu
+// memuat dari sumber sehingga service tetap berjalan tanpa cache.
+type Loader struct {
+	cache  Cache
+	name   string
+	ttl    time.Duration
+	codec  Codec
+	logger logger.Logger
+	group  singleflight.Group
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+	errors atomic.Uint64
+}
+
+// NewLoader membuat loader di atas backend
+func NewLoader(cache Cache, name string, ttl time.Duration, logger logger.Logger, opts ...Option) *Loader {
+	l := &Loader{
+		cache:  cache,
+		name:   name,
+		ttl:    ttl,
+		codec:  JSON,
+		logger: logger,
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// GetOrLoad mengembalikan nilai key dari cache atau memanggil load lalu menyimpannya
+// dengan tag. Pemanggilan bersamaan untuk key yang sama hanya menjalankan satu load
+// (singleflight). Error dari load tidak di-cache; kegagalan backend hanya dicatat dan
+// nilai tetap dimuat dari sumber.
+func GetOrLoad[T any](ctx context.Context, l *Loader, key string, load func(ctx context.Context) (T, error), tags ...string) (T, error) {
+	if l == nil {
+		return load(ctx)
+	}
+
+	key = l.name + ":" + key
+	data, found, err := l.cache.Get(ctx, key)
+	if err != nil {
+		l.fail(key, err, "Gagal membaca cache")
+	}
+	if found {
+		var cached T
+		err := l.codec.Unmarshal(data, &cached)
+		if err == nil {
+			l.hits.Add(1)
+			return cached, nil
+		}
+		l.fail(key, err, "Gagal membaca nilai cache, dimuat ulang")
+	}
+	l.misses.Add(1)
+
+	// setiap pemanggil men-decode salinannya sendiri agar nilai tidak dipakai bersama
+	type result struct {
+		data  []byte
+		value T
+	}
+	shared, err, _ := l.group.Do(key, func() (interface{}, error) {
+		loaded, err := load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		data, err := l.codec.Marshal(loaded)
+		if err != nil {
+			l.fail(key, err, "Gagal meng-encode nilai cache")
+			return result{value: loaded}, nil
+		}
+		if err := l.cache.Set(ctx, key, data, l.ttl, l.tags(tags)...); err != nil {
+			l.fail(key, err, "Gagal menyimpan cache")
+		}
+		return result{data: data, value: loaded}, nil
+	})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	loaded := shared.(result)
+	if loaded.data == nil {
+		return loaded.value, nil
+	}
+	var decoded T
+	if err := l.codec.Unmarshal(loaded.data, &decoded); err != nil {
+		return loaded.value, nil
+	}
+	return decoded, nil
+}
+
+// Invalidate menghapus semua key dengan salah satu tag. Nilai yang sedang dimuat
+// saat invalidasi dapat tersimpan kembali, tetapi paling lama bertahan selama TTL.
+func (l *Loader) Invalidate(ctx context.Context, tags ...string) error {
+	if l == nil || len(tags) == 0 {
+		return nil
+	}
+	if err := l.cache.InvalidateTags(ctx, l.tags(tags)...); err != nil {
+		l.fail(l.name, err, "Gagal menginvalidasi cache")
+		return err
+	}
+	return nil
+}
+
+// Stats mengembalikan hitungan hit, miss dan error loader
+func (l *Loader) Stats() Stats {
+	stats := Stats{
+		Name:   l.name,
+		Hits:   l.hits.Load(),
+		Misses: l.misses.Load(),
+		Errors: l.errors.Load(),
+	}
+	if total := stats.Hits + stats.Misses; total > 0 {
+		stats.HitRatio = float64(stats.Hits) / float64(total)
+	}
+	return stats
+}
+
+func (l *Loader) tags(tags []string) []string {
+	prefixed := make([]string, len(tags))
+	for i, tag := range tags {
+		prefixed[i] = l.name + ":" + tag
+	}
+	return prefixed
+}
+
+func (l *Loader) fail(key string, err error, message string) {
+	l.errors.Add(1)
+	l.logger.WithFields(logrus.Fields{
+		"key":   key,
+		"error": err.Error(),
+	}).Warn(message)
+}
+
+// Registry membuat loader di atas satu backend dan mengumpulkan statistiknya.
+// Registry tanpa backend (cache dinonaktifkan) membuat loader nil.
+type Registry struct {
+	cache   Cache
+	logger  logger.Logger
+	mu      sync.Mutex
+	loaders []*Loader
+}
+
+// NewRegistry membuat registry; cache nil menonaktifkan cache
+func NewRegistry(cache Cache, logger logger.Logger) *Registry {
+	return &Registry{cache: cache, logger: logger}
+}
+
+// Loader membuat dan mendaftarkan loader baru
+func (r *Registry) Loader(name string, ttl time.Duration, opts ...Option) *Loader {
+	if r.cache == nil {
+		return nil
+	}
+
+	loader := NewLoader(r.cache, name, ttl, r.logger, opts...)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.loaders = append(r.loaders, loader)
+	return loader
+}
+
+// Stats mengembalikan statistik setiap loader yang terdaftar
+func (r *Registry) Stats() []Stats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats := make([]Stats, len(r.loaders))
+	for i, loader := range r.loaders {
+		stats[i] = loader.Stats()
+	}
+	return stats
+}
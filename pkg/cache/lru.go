@@ -0,0 +1,137 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultMaxEntries adalah kapasitas LRU jika tidak diatur
+const DefaultMaxEntries = 10000
+
+type lruEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+	tags      []string
+}
+
+// LRU adalah cache di memori proses dengan kapasitas tetap. Entry yang paling lama
+// tidak dibaca dibuang saat kapasitas penuh; entry kedaluwarsa dibuang saat dibaca.
+type LRU struct {
+	mu         sync.Mutex
+	maxEntries int
+	order      *list.List
+	entries    map[string]*list.Element
+	tags       map[string]map[string]struct{}
+	now        func() time.Time
+}
+
+// NewLRU membuat cache LRU; maxEntries <= 0 memakai DefaultMaxEntries
+func NewLRU(maxEntries int) *LRU {
+	if maxEntries <= 0 {
+		maxEntries = DefaultMaxEntries
+	}
+	return &LRU{
+		maxEntries: maxEntries,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+		tags:       make(map[string]map[string]struct{}),
+		now:        time.Now,
+	}
+}
+
+// Len mengembalikan jumlah entry, termasuk yang kedaluwarsa tetapi belum dibuang
+func (l *LRU) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.order.Len()
+}
+
+func (l *LRU) Get(_ context.Context, key string) ([]byte, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	element, ok := l.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	entry := element.Value.(*lruEntry)
+	if !l.now().Before(entry.expiresAt) {
+		l.remove(element)
+		return nil, false, nil
+	}
+	l.order.MoveToFront(element)
+	return entry.value, true, nil
+}
+
+func (l *LRU) Set(_ context.Context, key string, value []byte, ttl time.Duration, tags ...string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if element, ok := l.entries[key]; ok {
+		l.remove(element)
+	}
+
+	entry := &lruEntry{
+		key:       key,
+		value:     append([]byte(nil), value...),
+		expiresAt: l.now().Add(ttl),
+		tags:      append([]string(nil), tags...),
+	}
+	l.entries[key] = l.order.PushFront(entry)
+	for _, tag := range tags {
+		members, ok := l.tags[tag]
+		if !ok {
+			members = make(map[string]struct{})
+			l.tags[tag] = members
+		}
+		members[key] = struct{}{}
+	}
+
+	for l.order.Len() > l.maxEntries {
+		l.remove(l.order.Back())
+	}
+	return nil
+}
+
+func (l *LRU) Delete(_ context.Context, keys ...string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, key := range keys {
+		if element, ok := l.entries[key]; ok {
+			l.remove(element)
+		}
+	}
+	return nil
+}
+
+func (l *LRU) InvalidateTags(_ context.Context, tags ...string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, tag := range tags {
+		for key := range l.tags[tag] {
+			if element, ok := l.entries[key]; ok {
+				l.remove(element)
+			}
+		}
+		delete(l.tags, tag)
+	}
+	return nil
+}
+
+// remove membuang entry beserta keanggotaannya di tag; mu harus dipegang pemanggil
+func (l *LRU) remove(element *list.Element) {
+	entry := l.order.Remove(element).(*lruEntry)
+	delete(l.entries, entry.key)
+	for _, tag := range entry.tags {
+		members := l.tags[tag]
+		delete(members, entry.key)
+		if len(members) == 0 {
+			delete(l.tags, tag)
+		}
+	}
+}
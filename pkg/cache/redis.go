@@ -0,0 +1,102 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const defaultRedisPrefix = "cache"
+
+// setScript menyimpan nilai lalu menambahkan key ke set setiap tag. TTL set tag
+// hanya diperpanjang agar tidak pernah lebih pendek dari key anggotanya.
+// KEYS = [key, tag...], ARGV = [value, ttl ms]
+var setScript = redis.NewScript(`
+redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+for i = 2, #KEYS do
+	redis.call('SADD', KEYS[i], KEYS[1])
+	if redis.call('PTTL', KEYS[i]) < tonumber(ARGV[2]) then
+		redis.call('PEXPIRE', KEYS[i], ARGV[2])
+	end
+end
+return 1
+`)
+
+// invalidateScript menghapus semua anggota set tag beserta set-nya. KEYS = [tag...]
+var invalidateScript = redis.NewScript(`
+local deleted = 0
+for i = 1, #KEYS do
+	local members = redis.call('SMEMBERS', KEYS[i])
+	for _, member in ipairs(members) do
+		deleted = deleted + redis.call('DEL', member)
+	end
+	redis.call('DEL', KEYS[i])
+end
+return deleted
+`)
+
+// RedisCache menyimpan nilai di Redis. Setiap tag adalah set berisi key anggotanya.
+type RedisCache struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisCache membuat cache Redis dengan prefix key; kosong berarti "cache"
+func NewRedisCache(client *redis.Client, prefix string) *RedisCache {
+	if prefix == "" {
+		prefix = defaultRedisPrefix
+	}
+	return &RedisCache{client: client, prefix: prefix}
+}
+
+func (r *RedisCache) key(key string) string {
+	return r.prefix + ":" + key
+}
+
+func (r *RedisCache) tagKey(tag string) string {
+	return r.prefix + ":tag:" + tag
+}
+
+func (r *RedisCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := r.client.Get(ctx, r.key(key)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+func (r *RedisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration, tags ...string) error {
+	keys := make([]string, 0, len(tags)+1)
+	keys = append(keys, r.key(key))
+	for _, tag := range tags {
+		keys = append(keys, r.tagKey(tag))
+	}
+	return setScript.Run(ctx, r.client, keys, value, ttl.Milliseconds()).Err()
+}
+
+func (r *RedisCache) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = r.key(key)
+	}
+	return r.client.Del(ctx, prefixed...).Err()
+}
+
+func (r *RedisCache) InvalidateTags(ctx context.Context, tags ...string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	keys := make([]string, len(tags))
+	for i, tag := range tags {
+		keys[i] = r.tagKey(tag)
+	}
+	return invalidateScript.Run(ctx, r.client, keys).Err()
+}
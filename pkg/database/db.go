@@ -3,8 +3,17 @@ package database
 import (
 	"fmt"
 
+	apiKeyModel "boilerplate/internal/apikey/model"
 	categoryModel "boilerplate/internal/category/model"
+	identityModel "boilerplate/internal/identity/model"
+	inviteModel "boilerplate/internal/invite/model"
+	oauthModel "boilerplate/internal/oauth/model"
+	privacyModel "boilerplate/internal/privacy/model"
+	schedulerModel "boilerplate/internal/scheduler/model"
 	userModel "boilerplate/internal/user/model"
+	webhookModel "boilerplate/internal/webhook/model"
+	"boilerplate/pkg/events"
+	"boilerplate/pkg/search"
 
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
@@ -20,7 +29,7 @@ func InitDB(host, port, user, password, dbname string) (*gorm.DB, error) {
 	}
 
 	// Auto Migrate
-	err = db.AutoMigrate(&userModel.User{}, &categoryModel.Category{})
+	err = db.AutoMigrate(&userModel.User{}, &categoryModel.Category{}, &categoryModel.Import{}, &apiKeyModel.APIKey{}, &identityModel.UserIdentity{}, &oauthModel.Client{}, &oauthModel.Consent{}, &inviteModel.Invite{}, &userModel.PasswordHistory{}, &userModel.LoginEvent{}, &privacyModel.Export{}, &privacyModel.Tombstone{}, &schedulerModel.Run{}, &webhookModel.Webhook{}, &webhookModel.Delivery{}, &events.OutboxMessage{}, &events.ProcessedEvent{}, &search.MySQLDocument{})
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,23 @@
+package database
+
+import (
+	errs "boilerplate/shared/errors"
+
+	"gorm.io/gorm"
+)
+
+// UpdateWithVersion menjalankan compare-and-swap update:
+// UPDATE ... SET ..., version = version + 1 WHERE id = ? AND version = ?
+// ErrVersionConflict dikembalikan jika row sudah diubah oleh request lain.
+func UpdateWithVersion(db *gorm.DB, model interface{}, id, version uint, values map[string]interface{}) error {
+	values["version"] = gorm.Expr("version + 1")
+
+	result := db.Model(model).Where("id = ? AND version = ?", id, version).Updates(values)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errs.ErrVersionConflict
+	}
+	return nil
+}
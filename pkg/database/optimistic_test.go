@@ -0,0 +1,51 @@
+package database
+
+import (
+	"testing"
+
+	"boilerplate/internal/testutil"
+	errs "boilerplate/shared/errors"
+
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type versioned struct {
+	ID      uint `gorm:"primaryKey"`
+	Name    string
+	Version uint `gorm:"not null;default:1"`
+}
+
+type OptimisticTestSuite struct {
+	suite.Suite
+	db *gorm.DB
+}
+
+func TestOptimisticSuite(t *testing.T) {
+	suite.Run(t, new(OptimisticTestSuite))
+}
+
+func (s *OptimisticTestSuite) SetupTest() {
+	db := testutil.NewDB(s.T(), &versioned{})
+	s.db = db
+}
+
+func (s *OptimisticTestSuite) TestUpdateWithVersion() {
+	row := versioned{Name: "first"}
+	s.Require().NoError(s.db.Create(&row).Error)
+	s.Equal(uint(1), row.Version)
+
+	s.Require().NoError(UpdateWithVersion(s.db, &versioned{}, row.ID, 1, map[string]interface{}{"name": "second"}))
+
+	// versi lama ditolak dan row tidak berubah
+	err := UpdateWithVersion(s.db, &versioned{}, row.ID, 1, map[string]interface{}{"name": "stale"})
+	s.ErrorIs(err, errs.ErrVersionConflict)
+
+	var stored versioned
+	s.Require().NoError(s.db.First(&stored, row.ID).Error)
+	s.Equal("second", stored.Name)
+	s.Equal(uint(2), stored.Version)
+
+	err = UpdateWithVersion(s.db, &versioned{}, 99, 1, map[string]interface{}{"name": "missing"})
+	s.ErrorIs(err, errs.ErrVersionConflict)
+}
@@ -0,0 +1,94 @@
+package etag
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	errs "boilerplate/shared/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	HeaderETag        = "ETag"
+	HeaderIfMatch     = "If-Match"
+	HeaderIfNoneMatch = "If-None-Match"
+)
+
+// Generate membuat strong ETag dari id dan version sebuah resource
+func Generate(id, version uint) string {
+	return fmt.Sprintf(`"%d-%d"`, id, version)
+}
+
+// Set menulis header ETag ke response
+func Set(c echo.Context, tag string) {
+	c.Response().Header().Set(HeaderETag, tag)
+}
+
+// NotModified mengecek header If-None-Match dengan perbandingan weak (RFC 9110)
+func NotModified(c echo.Context, current string) bool {
+	header := c.Request().Header.Get(HeaderIfNoneMatch)
+	if header == "" {
+		return false
+	}
+	return match(header, current, true)
+}
+
+// CheckIfMatch mengecek header If-Match dengan perbandingan strong. Header kosong
+// hanya error jika required bernilai true.
+func CheckIfMatch(c echo.Context, current string, required bool) error {
+	header := c.Request().Header.Get(HeaderIfMatch)
+	if header == "" {
+		if required {
+			return errs.ErrPreconditionRequired
+		}
+		return nil
+	}
+
+	if !match(header, current, false) {
+		return errs.ErrPreconditionFailed
+	}
+	return nil
+}
+
+// HasIfMatch mengecek apakah request membawa header If-Match
+func HasIfMatch(c echo.Context) bool {
+	return c.Request().Header.Get(HeaderIfMatch) != ""
+}
+
+// ConflictError mengubah version conflict menjadi 412 jika client mengirim If-Match
+func ConflictError(c echo.Context, err error) error {
+	if errors.Is(err, errs.ErrVersionConflict) && HasIfMatch(c) {
+		return errs.ErrPreconditionFailed.Wrap(err)
+	}
+	return err
+}
+
+func match(header, current string, weak bool) bool {
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+
+	currentWeak, currentOpaque := split(current)
+	for _, candidate := range strings.Split(header, ",") {
+		isWeak, opaque := split(strings.TrimSpace(candidate))
+		if opaque == "" {
+			continue
+		}
+		if !weak && (isWeak || currentWeak) {
+			continue
+		}
+		if opaque == currentOpaque {
+			return true
+		}
+	}
+	return false
+}
+
+func split(tag string) (bool, string) {
+	if strings.HasPrefix(tag, "W/") {
+		return true, strings.TrimPrefix(tag, "W/")
+	}
+	return false, tag
+}
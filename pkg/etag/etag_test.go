@@ -0,0 +1,86 @@
+package etag
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	errs "boilerplate/shared/errors"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/suite"
+)
+
+type ETagTestSuite struct {
+	suite.Suite
+	e *echo.Echo
+}
+
+func TestETagSuite(t *testing.T) {
+	suite.Run(t, new(ETagTestSuite))
+}
+
+func (s *ETagTestSuite) SetupTest() {
+	s.e = echo.New()
+}
+
+func (s *ETagTestSuite) context(header, value string) echo.Context {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if header != "" {
+		req.Header.Set(header, value)
+	}
+	return s.e.NewContext(req, httptest.NewRecorder())
+}
+
+func (s *ETagTestSuite) TestCheckIfMatch() {
+	current := Generate(1, 2)
+
+	tests := []struct {
+		name        string
+		header      string
+		required    bool
+		expectedErr error
+	}{
+		{name: "missing header not required", header: "", required: false, expectedErr: nil},
+		{name: "missing header required", header: "", required: true, expectedErr: errs.ErrPreconditionRequired},
+		{name: "matching etag", header: `"1-2"`, expectedErr: nil},
+		{name: "matching etag in list", header: `"1-1", "1-2"`, expectedErr: nil},
+		{name: "wildcard", header: "*", expectedErr: nil},
+		{name: "stale etag", header: `"1-1"`, expectedErr: errs.ErrPreconditionFailed},
+		{name: "weak etag never matches strongly", header: `W/"1-2"`, expectedErr: errs.ErrPreconditionFailed},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			var c echo.Context
+			if tt.header == "" {
+				c = s.context("", "")
+			} else {
+				c = s.context(HeaderIfMatch, tt.header)
+			}
+			s.Equal(tt.expectedErr, CheckIfMatch(c, current, tt.required))
+		})
+	}
+}
+
+func (s *ETagTestSuite) TestNotModified() {
+	current := Generate(1, 2)
+
+	tests := []struct {
+		name     string
+		header   string
+		expected bool
+	}{
+		{name: "missing header", header: "", expected: false},
+		{name: "matching etag", header: `"1-2"`, expected: true},
+		{name: "weak etag matches weakly", header: `W/"1-2"`, expected: true},
+		{name: "stale etag", header: `"1-1"`, expected: false},
+		{name: "wildcard", header: "*", expected: true},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			s.Equal(tt.expected, NotModified(s.context(HeaderIfNoneMatch, tt.header), current))
+		})
+	}
+}
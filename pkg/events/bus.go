@@ -0,0 +1,74 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
+// Handler memproses satu event. Event dapat diterima lebih dari sekali
+// (at-least-once), bungkus dengan OnceDB atau OnceRedis bila efeknya tidak idempoten.
+type Handler func(ctx context.Context, envelope Envelope) error
+
+// AllEvents dipakai sebagai nama event untuk subscriber yang menerima semua event
+const AllEvents = "*"
+
+// Bus meneruskan event ke subscriber di proses yang sama. Subscriber didaftarkan
+// oleh modul saat startup; relay memanggil Publish untuk setiap event di outbox.
+type Bus struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+}
+
+func NewBus() *Bus {
+	return &Bus{handlers: make(map[string][]Handler)}
+}
+
+// Subscribe mendaftarkan handler untuk event bernama name, atau AllEvents
+func (b *Bus) Subscribe(name string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[name] = append(b.handlers[name], handler)
+}
+
+// Publish menjalankan semua handler untuk event secara berurutan. Setiap handler
+// tetap dijalankan meskipun handler lain gagal; error digabung dan dikembalikan
+// sehingga relay mengirim ulang event ke semua handler.
+func (b *Bus) Publish(ctx context.Context, envelope Envelope) error {
+	b.mu.RLock()
+	handlers := make([]Handler, 0, len(b.handlers[envelope.Name])+len(b.handlers[AllEvents]))
+	handlers = append(handlers, b.handlers[envelope.Name]...)
+	handlers = append(handlers, b.handlers[AllEvents]...)
+	b.mu.RUnlock()
+
+	var errs []error
+	for _, handler := range handlers {
+		if err := safeCall(ctx, handler, envelope); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Subscribe mendaftarkan handler bertipe; payload didekode ke T sebelum fn dipanggil
+func Subscribe[T Event](b *Bus, fn func(ctx context.Context, envelope Envelope, event T) error) {
+	var zero T
+	b.Subscribe(zero.EventName(), func(ctx context.Context, envelope Envelope) error {
+		var event T
+		if err := envelope.Decode(&event); err != nil {
+			return fmt.Errorf("decode %s: %w", envelope.Name, err)
+		}
+		return fn(ctx, envelope, event)
+	})
+}
+
+func safeCall(ctx context.Context, handler Handler, envelope Envelope) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v\n%s", r, debug.Stack())
+		}
+	}()
+	return handler(ctx, envelope)
+}
@@ -0,0 +1,54 @@
+package events
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// Event adalah domain event yang dicatat ke outbox. EventName dipakai sebagai
+// nama routing untuk subscriber dan broker, mis. "user.registered".
+type Event interface {
+	EventName() string
+}
+
+// Envelope adalah event yang sudah diserialisasi beserta metadatanya. ID unik per
+// event dan tetap sama di setiap pengiriman ulang sehingga dipakai untuk deduplikasi.
+type Envelope struct {
+	ID         string          `json:"id"`
+	Name       string          `json:"name"`
+	Payload    json.RawMessage `json:"payload"`
+	OccurredAt time.Time       `json:"occurred_at"`
+}
+
+// NewEnvelope menyerialisasi event dengan ID baru
+func NewEnvelope(event Event) (Envelope, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return Envelope{}, err
+	}
+	id, err := newID()
+	if err != nil {
+		return Envelope{}, err
+	}
+	return Envelope{
+		ID:         id,
+		Name:       event.EventName(),
+		Payload:    payload,
+		OccurredAt: time.Now(),
+	}, nil
+}
+
+// Decode mengurai payload event ke v
+func (e Envelope) Decode(v interface{}) error {
+	return json.Unmarshal(e.Payload, v)
+}
+
+func newID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
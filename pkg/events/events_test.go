@@ -0,0 +1,273 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"boilerplate/internal/testutil"
+	"boilerplate/pkg/logger"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type accountOpened struct {
+	AccountID uint `json:"account_id"`
+}
+
+func (accountOpened) EventName() string { return "account.opened" }
+
+type account struct {
+	ID   uint `gorm:"primaryKey"`
+	Name string
+}
+
+type EventsTestSuite struct {
+	suite.Suite
+	ctx     context.Context
+	db      *gorm.DB
+	client  *redis.Client
+	bus     *Bus
+	streams *RedisStreams
+	relay   *Relay
+	now     time.Time
+}
+
+func TestEventsSuite(t *testing.T) {
+	suite.Run(t, new(EventsTestSuite))
+}
+
+func (s *EventsTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	db := testutil.NewDB(s.T(), &OutboxMessage{}, &ProcessedEvent{}, &account{})
+	s.db = db
+
+	s.client = testutil.NewRedis(s.T())
+
+	log := logger.NewLogger()
+	s.bus = NewBus()
+	s.streams = NewRedisStreams(s.client, "events", log)
+	s.streams.Block = -1
+	// relay berjalan sedikit di depan agar event yang baru dicatat langsung jatuh tempo
+	s.now = time.Now().Add(time.Minute)
+	s.relay = NewRelay(db, s.bus, s.streams, log, RelayConfig{BaseBackoff: time.Second, MaxBackoff: 4 * time.Second})
+	s.relay.now = func() time.Time { return s.now }
+}
+
+func (s *EventsTestSuite) openAccount(name string) *account {
+	acc := &account{Name: name}
+	s.Require().NoError(s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(acc).Error; err != nil {
+			return err
+		}
+		return Record(tx, accountOpened{AccountID: acc.ID})
+	}))
+	return acc
+}
+
+func (s *EventsTestSuite) pending() []OutboxMessage {
+	var messages []OutboxMessage
+	s.Require().NoError(s.db.Where("published_at IS NULL").Find(&messages).Error)
+	return messages
+}
+
+func (s *EventsTestSuite) TestRecordRollsBackWithTransaction() {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		acc := &account{Name: "rollback"}
+		if err := tx.Create(acc).Error; err != nil {
+			return err
+		}
+		if err := Record(tx, accountOpened{AccountID: acc.ID}); err != nil {
+			return err
+		}
+		return errors.New("boom")
+	})
+	s.Error(err)
+
+	var count int64
+	s.Require().NoError(s.db.Model(&OutboxMessage{}).Count(&count).Error)
+	s.Zero(count)
+}
+
+func (s *EventsTestSuite) TestRelayPublishesToBusAndBroker() {
+	var received []uint
+	var all []string
+	Subscribe(s.bus, func(_ context.Context, _ Envelope, event accountOpened) error {
+		received = append(received, event.AccountID)
+		return nil
+	})
+	s.bus.Subscribe(AllEvents, func(_ context.Context, envelope Envelope) error {
+		all = append(all, envelope.Name)
+		return nil
+	})
+
+	acc := s.openAccount("alice")
+	published, err := s.relay.RelayBatch(s.ctx)
+	s.Require().NoError(err)
+	s.Equal(1, published)
+	s.Equal([]uint{acc.ID}, received)
+	s.Equal([]string{"account.opened"}, all)
+	s.Empty(s.pending())
+
+	length, err := s.client.XLen(s.ctx, "events").Result()
+	s.Require().NoError(err)
+	s.Equal(int64(1), length)
+
+	// event yang sudah dipublikasikan tidak dikirim ulang
+	published, err = s.relay.RelayBatch(s.ctx)
+	s.Require().NoError(err)
+	s.Zero(published)
+}
+
+func (s *EventsTestSuite) TestRelayRetriesFailedEventsWithBackoff() {
+	calls := 0
+	s.bus.Subscribe("account.opened", func(context.Context, Envelope) error {
+		calls++
+		if calls == 1 {
+			return errors.New("subscriber down")
+		}
+		return nil
+	})
+	s.openAccount("bob")
+
+	published, err := s.relay.RelayBatch(s.ctx)
+	s.Require().NoError(err)
+	s.Zero(published)
+	pending := s.pending()
+	s.Require().Len(pending, 1)
+	s.Equal(1, pending[0].Attempts)
+	s.Equal("subscriber down", pending[0].LastError)
+
+	// belum jatuh tempo
+	published, err = s.relay.RelayBatch(s.ctx)
+	s.Require().NoError(err)
+	s.Zero(published)
+
+	s.now = s.now.Add(2 * time.Second)
+	published, err = s.relay.RelayBatch(s.ctx)
+	s.Require().NoError(err)
+	s.Equal(1, published)
+	s.Equal(2, calls)
+	s.Empty(s.pending())
+}
+
+func (s *EventsTestSuite) TestClaimedEventsAreSkippedUntilClaimTimeout() {
+	s.openAccount("carol")
+	messages, err := s.relay.claim(s.ctx)
+	s.Require().NoError(err)
+	s.Len(messages, 1)
+
+	// relay lain tidak mengambil event yang sedang diproses
+	messages, err = s.relay.claim(s.ctx)
+	s.Require().NoError(err)
+	s.Empty(messages)
+
+	s.now = s.now.Add(defaultClaimTimeout + time.Second)
+	messages, err = s.relay.claim(s.ctx)
+	s.Require().NoError(err)
+	s.Len(messages, 1)
+}
+
+func (s *EventsTestSuite) TestBackoffIsCapped() {
+	s.Equal(time.Second, s.relay.Backoff(1))
+	s.Equal(2*time.Second, s.relay.Backoff(2))
+	s.Equal(4*time.Second, s.relay.Backoff(5))
+}
+
+func (s *EventsTestSuite) TestOnceDBSkipsDuplicates() {
+	envelope, err := NewEnvelope(accountOpened{AccountID: 1})
+	s.Require().NoError(err)
+
+	fail := true
+	handler := OnceDB(s.db, "welcome", func(_ context.Context, tx *gorm.DB, _ Envelope) error {
+		if err := tx.Create(&account{Name: "side effect"}).Error; err != nil {
+			return err
+		}
+		if fail {
+			return errors.New("mail down")
+		}
+		return nil
+	})
+
+	// handler gagal: penanda ikut di-rollback sehingga event dapat diulang
+	s.Error(handler(s.ctx, envelope))
+	fail = false
+	s.NoError(handler(s.ctx, envelope))
+	s.NoError(handler(s.ctx, envelope))
+
+	var count int64
+	s.Require().NoError(s.db.Model(&account{}).Count(&count).Error)
+	s.Equal(int64(1), count)
+
+	// consumer lain tetap memproses event yang sama
+	other := OnceDB(s.db, "audit", func(context.Context, *gorm.DB, Envelope) error { return nil })
+	s.NoError(other(s.ctx, envelope))
+	s.Require().NoError(s.db.Model(&ProcessedEvent{}).Count(&count).Error)
+	s.Equal(int64(2), count)
+}
+
+func (s *EventsTestSuite) TestOnceRedisSkipsDuplicates() {
+	envelope, err := NewEnvelope(accountOpened{AccountID: 1})
+	s.Require().NoError(err)
+
+	calls := 0
+	handler := OnceRedis(s.client, "webhook", time.Hour, func(context.Context, Envelope) error {
+		calls++
+		if calls == 1 {
+			return errors.New("timeout")
+		}
+		return nil
+	})
+
+	s.Error(handler(s.ctx, envelope))
+	s.NoError(handler(s.ctx, envelope))
+	s.NoError(handler(s.ctx, envelope))
+	s.Equal(2, calls)
+}
+
+func (s *EventsTestSuite) TestRedisStreamsRedeliversUnackedMessages() {
+	s.Require().NoError(s.client.XGroupCreateMkStream(s.ctx, "events", "search", "0").Err())
+	envelope, err := NewEnvelope(accountOpened{AccountID: 7})
+	s.Require().NoError(err)
+	s.Require().NoError(s.streams.Publish(s.ctx, envelope))
+
+	var received []string
+	failing := func(_ context.Context, e Envelope) error {
+		received = append(received, e.ID)
+		return errors.New("index unavailable")
+	}
+	s.Require().NoError(s.streams.ConsumeBatch(s.ctx, "search", "worker-1", failing))
+
+	pending, err := s.client.XPending(s.ctx, "events", "search").Result()
+	s.Require().NoError(err)
+	s.Equal(int64(1), pending.Count)
+
+	// consumer lain mengklaim pesan yang tidak di-ack setelah ClaimIdle
+	s.streams.ClaimIdle = 0
+	succeeding := func(_ context.Context, e Envelope) error {
+		received = append(received, e.ID)
+		return nil
+	}
+	s.Require().NoError(s.streams.ConsumeBatch(s.ctx, "search", "worker-2", succeeding))
+	s.Equal([]string{envelope.ID, envelope.ID}, received)
+
+	pending, err = s.client.XPending(s.ctx, "events", "search").Result()
+	s.Require().NoError(err)
+	s.Zero(pending.Count)
+}
+
+func (s *EventsTestSuite) TestPurgePublished() {
+	s.openAccount("dave")
+	s.openAccount("erin")
+	_, err := s.relay.RelayBatch(s.ctx)
+	s.Require().NoError(err)
+	s.openAccount("frank")
+
+	purged, err := PurgePublished(s.ctx, s.db, time.Now().Add(time.Hour))
+	s.Require().NoError(err)
+	s.Equal(int64(2), purged)
+	s.Len(s.pending(), 1)
+}
@@ -0,0 +1,72 @@
+package events
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ProcessedEvent mencatat event yang sudah diproses consumer. Baris ditulis dalam
+// transaksi yang sama dengan efek handler sehingga pengiriman ulang diabaikan.
+type ProcessedEvent struct {
+	ID          uint64    `gorm:"primaryKey"`
+	Consumer    string    `gorm:"size:100;not null;uniqueIndex:idx_processed_events_consumer_event"`
+	EventID     string    `gorm:"size:32;not null;uniqueIndex:idx_processed_events_consumer_event"`
+	ProcessedAt time.Time `gorm:"not null;index"`
+}
+
+// TxHandler memproses event di dalam transaksi database
+type TxHandler func(ctx context.Context, tx *gorm.DB, envelope Envelope) error
+
+// OnceDB membungkus handler agar setiap event diproses paling banyak sekali oleh
+// consumer. Penanda dan efek handler di-commit bersama; bila handler gagal keduanya
+// di-rollback sehingga event dapat dicoba ulang.
+func OnceDB(db *gorm.DB, consumer string, fn TxHandler) Handler {
+	return func(ctx context.Context, envelope Envelope) error {
+		return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&ProcessedEvent{
+				Consumer:    consumer,
+				EventID:     envelope.ID,
+				ProcessedAt: time.Now(),
+			})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return nil
+			}
+			return fn(ctx, tx, envelope)
+		})
+	}
+}
+
+// OnceRedis membungkus handler dengan penanda SET NX di Redis selama ttl. Dipakai
+// untuk efek di luar database (mis. memanggil API lain); penanda dihapus bila
+// handler gagal. Handler yang mati di tengah jalan tidak akan diulang sebelum ttl habis.
+func OnceRedis(client *redis.Client, consumer string, ttl time.Duration, handler Handler) Handler {
+	return func(ctx context.Context, envelope Envelope) error {
+		key := "events:processed:" + consumer + ":" + envelope.ID
+		first, err := client.SetNX(ctx, key, time.Now().Unix(), ttl).Result()
+		if err != nil {
+			return err
+		}
+		if !first {
+			return nil
+		}
+
+		if err := handler(ctx, envelope); err != nil {
+			client.Del(context.WithoutCancel(ctx), key)
+			return err
+		}
+		return nil
+	}
+}
+
+// PurgeProcessed menghapus penanda event yang diproses sebelum before
+func PurgeProcessed(ctx context.Context, db *gorm.DB, before time.Time) (int64, error) {
+	result := db.WithContext(ctx).Where("processed_at < ?", before).Delete(&ProcessedEvent{})
+	return result.RowsAffected, result.Error
+}
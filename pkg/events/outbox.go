@@ -0,0 +1,68 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// OutboxMessage adalah event yang menunggu dipublikasikan relay. Baris ditulis
+// dalam transaksi yang sama dengan perubahan state sehingga event tidak hilang
+// maupun terkirim untuk transaksi yang di-rollback.
+type OutboxMessage struct {
+	ID            uint64          `gorm:"primaryKey"`
+	EventID       string          `gorm:"size:32;uniqueIndex;not null"`
+	Name          string          `gorm:"size:100;not null"`
+	Payload       json.RawMessage `gorm:"type:json;not null"`
+	OccurredAt    time.Time       `gorm:"not null"`
+	PublishedAt   *time.Time      `gorm:"index"`
+	Attempts      int             `gorm:"not null;default:0"`
+	LastError     string          `gorm:"type:text"`
+	NextAttemptAt time.Time       `gorm:"index;not null"`
+}
+
+func (OutboxMessage) TableName() string {
+	return "outbox"
+}
+
+// Envelope mengembalikan event yang disimpan
+func (m *OutboxMessage) Envelope() Envelope {
+	return Envelope{
+		ID:         m.EventID,
+		Name:       m.Name,
+		Payload:    m.Payload,
+		OccurredAt: m.OccurredAt,
+	}
+}
+
+// Record menulis event ke outbox. tx harus transaksi yang juga menyimpan perubahan
+// state agar keduanya commit atau rollback bersama.
+func Record(tx *gorm.DB, events ...Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	messages := make([]OutboxMessage, 0, len(events))
+	for _, event := range events {
+		envelope, err := NewEnvelope(event)
+		if err != nil {
+			return err
+		}
+		messages = append(messages, OutboxMessage{
+			EventID:       envelope.ID,
+			Name:          envelope.Name,
+			Payload:       envelope.Payload,
+			OccurredAt:    envelope.OccurredAt,
+			NextAttemptAt: envelope.OccurredAt,
+		})
+	}
+	return tx.Create(&messages).Error
+}
+
+// PurgePublished menghapus event outbox yang dipublikasikan sebelum before
+func PurgePublished(ctx context.Context, db *gorm.DB, before time.Time) (int64, error) {
+	result := db.WithContext(ctx).Where("published_at < ?", before).Delete(&OutboxMessage{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,140 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"boilerplate/pkg/logger"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultStreamMaxLen = 100000
+	defaultClaimIdle    = time.Minute
+	defaultReadBlock    = 5 * time.Second
+	streamReadCount     = 50
+	envelopeField       = "envelope"
+)
+
+// RedisStreams adalah Broker di atas satu Redis Stream. Consumer membaca lewat
+// consumer group; pesan yang tidak di-ack (consumer mati atau handler gagal)
+// diklaim ulang setelah ClaimIdle sehingga pengiriman at-least-once.
+type RedisStreams struct {
+	client *redis.Client
+	stream string
+	logger logger.Logger
+	// MaxLen membatasi panjang stream secara perkiraan (XADD MAXLEN ~)
+	MaxLen int64
+	// ClaimIdle adalah lama pesan pending sebelum diklaim consumer lain
+	ClaimIdle time.Duration
+	// Block adalah lama XREADGROUP menunggu pesan baru
+	Block time.Duration
+}
+
+func NewRedisStreams(client *redis.Client, stream string, logger logger.Logger) *RedisStreams {
+	return &RedisStreams{
+		client:    client,
+		stream:    stream,
+		logger:    logger,
+		MaxLen:    defaultStreamMaxLen,
+		ClaimIdle: defaultClaimIdle,
+		Block:     defaultReadBlock,
+	}
+}
+
+// Publish menambahkan event ke stream
+func (s *RedisStreams) Publish(ctx context.Context, envelope Envelope) error {
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+	return s.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: s.stream,
+		MaxLen: s.MaxLen,
+		Approx: true,
+		Values: map[string]interface{}{envelopeField: data},
+	}).Err()
+}
+
+// Consume membaca stream sebagai consumer di group sampai ctx dibatalkan. Group
+// dibuat bila belum ada dan membaca stream dari awal. Pesan di-ack setelah handler
+// berhasil; pesan yang tidak dapat didekode di-ack dan dicatat di log.
+func (s *RedisStreams) Consume(ctx context.Context, group, consumer string, handler Handler) error {
+	if err := s.client.XGroupCreateMkStream(ctx, s.stream, group, "0").Err(); err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return err
+	}
+
+	for {
+		if err := s.ConsumeBatch(ctx, group, consumer, handler); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// ConsumeBatch mengklaim pesan pending yang terlalu lama lalu membaca pesan baru,
+// masing-masing paling banyak satu batch
+func (s *RedisStreams) ConsumeBatch(ctx context.Context, group, consumer string, handler Handler) error {
+	claimed, _, err := s.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+		Stream:   s.stream,
+		Group:    group,
+		Consumer: consumer,
+		MinIdle:  s.ClaimIdle,
+		Start:    "0-0",
+		Count:    streamReadCount,
+	}).Result()
+	if err != nil {
+		return err
+	}
+	s.handle(ctx, group, claimed, handler)
+
+	streams, err := s.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    group,
+		Consumer: consumer,
+		Streams:  []string{s.stream, ">"},
+		Count:    streamReadCount,
+		Block:    s.Block,
+	}).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, stream := range streams {
+		s.handle(ctx, group, stream.Messages, handler)
+	}
+	return nil
+}
+
+func (s *RedisStreams) handle(ctx context.Context, group string, messages []redis.XMessage, handler Handler) {
+	for _, message := range messages {
+		fields := logrus.Fields{"stream": s.stream, "group": group, "message_id": message.ID}
+
+		var envelope Envelope
+		raw, _ := message.Values[envelopeField].(string)
+		if err := json.Unmarshal([]byte(raw), &envelope); err != nil {
+			fields["error"] = err.Error()
+			s.logger.WithFields(fields).Error("Gagal membaca event dari stream")
+		} else if err := safeCall(ctx, handler, envelope); err != nil {
+			// tidak di-ack; pesan diklaim ulang setelah ClaimIdle
+			fields["event_id"] = envelope.ID
+			fields["event"] = envelope.Name
+			fields["error"] = err.Error()
+			s.logger.WithFields(fields).Error("Gagal memproses event dari stream")
+			continue
+		}
+
+		if err := s.client.XAck(ctx, s.stream, group, message.ID).Err(); err != nil {
+			fields["error"] = err.Error()
+			s.logger.WithFields(fields).Error("Gagal melakukan ack event")
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"boilerplate/pkg/logger"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Broker meneruskan event ke sistem di luar proses, mis. Redis Streams
+type Broker interface {
+	Publish(ctx context.Context, envelope Envelope) error
+}
+
+// RelayConfig mengatur relay outbox. Nilai kosong memakai default.
+type RelayConfig struct {
+	// BatchSize adalah jumlah event yang diklaim per putaran
+	BatchSize int
+	// PollInterval adalah jeda polling saat outbox kosong
+	PollInterval time.Duration
+	// ClaimTimeout adalah batas waktu satu putaran. Event yang belum selesai setelah
+	// batas ini dianggap milik relay yang mati dan dapat diklaim relay lain.
+	ClaimTimeout time.Duration
+	// BaseBackoff dan MaxBackoff mengatur jeda retry: BaseBackoff * 2^(percobaan-1), maksimal MaxBackoff
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+}
+
+const (
+	defaultBatchSize    = 100
+	defaultPollInterval = time.Second
+	defaultClaimTimeout = time.Minute
+	defaultBaseBackoff  = 5 * time.Second
+	defaultMaxBackoff   = 10 * time.Minute
+	// maxErrorLength membatasi pesan error yang disimpan di outbox
+	maxErrorLength = 1000
+)
+
+// Relay membaca event yang belum dipublikasikan dari outbox lalu meneruskannya ke
+// bus dan broker. Pengiriman at-least-once: event yang gagal di salah satu tujuan
+// dikirim ulang ke semua tujuan. Beberapa relay dapat berjalan bersamaan karena
+// setiap event diklaim dengan SELECT ... FOR UPDATE SKIP LOCKED.
+type Relay struct {
+	db     *gorm.DB
+	bus    *Bus
+	broker Broker
+	logger logger.Logger
+	cfg    RelayConfig
+	now    func() time.Time
+}
+
+// NewRelay membuat relay outbox. broker boleh nil bila event hanya dipakai di proses ini.
+func NewRelay(db *gorm.DB, bus *Bus, broker Broker, logger logger.Logger, cfg RelayConfig) *Relay {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultBatchSize
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = defaultPollInterval
+	}
+	if cfg.ClaimTimeout <= 0 {
+		cfg.ClaimTimeout = defaultClaimTimeout
+	}
+	if cfg.BaseBackoff <= 0 {
+		cfg.BaseBackoff = defaultBaseBackoff
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = defaultMaxBackoff
+	}
+	return &Relay{db: db, bus: bus, broker: broker, logger: logger, cfg: cfg, now: time.Now}
+}
+
+// Run mempublikasikan event sampai ctx dibatalkan
+func (r *Relay) Run(ctx context.Context) {
+	for {
+		published, err := r.RelayBatch(ctx)
+		if err != nil && !errors.Is(err, context.Canceled) {
+			r.logger.WithFields(logrus.Fields{
+				"error": err.Error(),
+			}).Error("Gagal membaca outbox")
+		}
+		if published > 0 && err == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(r.cfg.PollInterval):
+		}
+	}
+}
+
+// RelayBatch mengklaim satu batch event yang jatuh tempo lalu mempublikasikannya.
+// Mengembalikan jumlah event yang berhasil dipublikasikan.
+func (r *Relay) RelayBatch(ctx context.Context) (int, error) {
+	messages, err := r.claim(ctx)
+	if err != nil || len(messages) == 0 {
+		return 0, err
+	}
+
+	published := 0
+	for i := range messages {
+		// publish tidak ikut dibatalkan agar event yang sedang dikirim tetap ditandai
+		publishCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), r.cfg.ClaimTimeout)
+		err := r.publish(publishCtx, messages[i].Envelope())
+		cancel()
+
+		if err != nil {
+			r.fail(ctx, &messages[i], err)
+			continue
+		}
+		if err := r.db.WithContext(context.WithoutCancel(ctx)).Model(&messages[i]).
+			Updates(map[string]interface{}{"published_at": r.now(), "last_error": ""}).Error; err != nil {
+			return published, err
+		}
+		published++
+	}
+	return published, nil
+}
+
+// Backoff mengembalikan jeda sebelum percobaan berikutnya setelah attempts percobaan gagal
+func (r *Relay) Backoff(attempts int) time.Duration {
+	delay := r.cfg.BaseBackoff
+	for i := 1; i < attempts && delay < r.cfg.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > r.cfg.MaxBackoff {
+		delay = r.cfg.MaxBackoff
+	}
+	return delay
+}
+
+// claim menggeser next_attempt_at event yang jatuh tempo sejauh ClaimTimeout sehingga
+// relay lain tidak mengambilnya selama batch ini diproses. Transaksi dibuat singkat
+// agar handler yang memakai database tidak menunggu lock outbox.
+func (r *Relay) claim(ctx context.Context) ([]OutboxMessage, error) {
+	now := r.now()
+	var messages []OutboxMessage
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("published_at IS NULL AND next_attempt_at <= ?", now).
+			Order("id").
+			Limit(r.cfg.BatchSize).
+			Find(&messages).Error; err != nil {
+			return err
+		}
+		if len(messages) == 0 {
+			return nil
+		}
+
+		ids := make([]uint64, len(messages))
+		for i := range messages {
+			ids[i] = messages[i].ID
+		}
+		return tx.Model(&OutboxMessage{}).Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(r.cfg.ClaimTimeout)).Error
+	})
+	return messages, err
+}
+
+func (r *Relay) publish(ctx context.Context, envelope Envelope) error {
+	if err := r.bus.Publish(ctx, envelope); err != nil {
+		return err
+	}
+	if r.broker != nil {
+		return r.broker.Publish(ctx, envelope)
+	}
+	return nil
+}
+
+func (r *Relay) fail(ctx context.Context, message *OutboxMessage, cause error) {
+	attempts := message.Attempts + 1
+	reason := cause.Error()
+	if len(reason) > maxErrorLength {
+		reason = reason[:maxErrorLength]
+	}
+
+	fields := logrus.Fields{
+		"event_id": message.EventID,
+		"event":    message.Name,
+		"attempts": attempts,
+		"error":    reason,
+	}
+	r.logger.WithFields(fields).Error("Gagal mempublikasikan event")
+
+	if err := r.db.WithContext(context.WithoutCancel(ctx)).Model(message).Updates(map[string]interface{}{
+		"attempts":        attempts,
+		"last_error":      reason,
+		"next_attempt_at": r.now().Add(r.Backoff(attempts)),
+	}).Error; err != nil {
+		fields["error"] = err.Error()
+		r.logger.WithFields(fields).Error("Gagal menyimpan status event outbox")
+	}
+}
@@ -0,0 +1,126 @@
+package fieldcrypt
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// KeyFile adalah kumpulan data key yang dibungkus (envelope) dengan master key.
+// Hanya master key yang perlu dirahasiakan di luar database dan file ini.
+type KeyFile struct {
+	Active uint32 `json:"active"`
+	// Keys berisi versi key -> data key terbungkus (base64)
+	Keys map[string]string `json:"keys"`
+}
+
+// ReadKeyFile membaca KeyFile berformat JSON
+func ReadKeyFile(path string) (KeyFile, error) {
+	var file KeyFile
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return file, err
+	}
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return file, fmt.Errorf("fieldcrypt: key file %s: %w", path, err)
+	}
+	return file, nil
+}
+
+// ParseKeyList membaca daftar "versi:key,versi:key" dari environment
+func ParseKeyList(list string) (map[string]string, error) {
+	keys := make(map[string]string)
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		version, wrapped, found := strings.Cut(entry, ":")
+		if !found {
+			return nil, fmt.Errorf("fieldcrypt: key entry %q must be <version>:<wrapped key>", entry)
+		}
+		keys[strings.TrimSpace(version)] = strings.TrimSpace(wrapped)
+	}
+	return keys, nil
+}
+
+// LoadKeyring membuka data key di file dengan master key. blindIndexKey kosong
+// berarti key blind index diturunkan dari master key.
+func LoadKeyring(master []byte, file KeyFile, blindIndexKey []byte) (*Keyring, error) {
+	if len(master) != KeySize {
+		return nil, fmt.Errorf("fieldcrypt: master key must be %d bytes", KeySize)
+	}
+
+	dataKeys := make(map[uint32][]byte, len(file.Keys))
+	for rawVersion, wrapped := range file.Keys {
+		version, err := strconv.ParseUint(rawVersion, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("fieldcrypt: invalid key version %q", rawVersion)
+		}
+		key, err := UnwrapKey(master, wrapped, uint32(version))
+		if err != nil {
+			return nil, err
+		}
+		dataKeys[uint32(version)] = key
+	}
+
+	if len(blindIndexKey) == 0 {
+		blindIndexKey = DeriveBlindIndexKey(master)
+	}
+	return NewKeyring(file.Active, dataKeys, blindIndexKey)
+}
+
+// GenerateDataKey membuat data key acak
+func GenerateDataKey() ([]byte, error) {
+	key := make([]byte, KeySize)
+	_, err := rand.Read(key)
+	return key, err
+}
+
+// WrapKey mengenkripsi data key dengan master key; versi menjadi AAD
+func WrapKey(master, dataKey []byte, version uint32) (string, error) {
+	aead, err := newAEAD(master)
+	if err != nil {
+		return "", fmt.Errorf("fieldcrypt: master key: %w", err)
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, dataKey, wrapAAD(version))
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// UnwrapKey membuka data key yang dibungkus WrapKey
+func UnwrapKey(master []byte, wrapped string, version uint32) ([]byte, error) {
+	aead, err := newAEAD(master)
+	if err != nil {
+		return nil, fmt.Errorf("fieldcrypt: master key: %w", err)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(wrapped)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return nil, fmt.Errorf("fieldcrypt: data key %d is not valid base64", version)
+	}
+	key, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], wrapAAD(version))
+	if err != nil {
+		return nil, fmt.Errorf("fieldcrypt: cannot unwrap data key %d with this master key", version)
+	}
+	return key, nil
+}
+
+// DeriveBlindIndexKey menurunkan key blind index dari master key
+func DeriveBlindIndexKey(master []byte) []byte {
+	mac := hmac.New(sha256.New, master)
+	mac.Write([]byte("fieldcrypt blind index"))
+	return mac.Sum(nil)
+}
+
+func wrapAAD(version uint32) []byte {
+	return []byte("fieldcrypt data key " + strconv.FormatUint(uint64(version), 10))
+}
@@ -0,0 +1,224 @@
+package fieldcrypt
+
+import (
+	"boilerplate/internal/testutil"
+
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type secretRecord struct {
+	ID     uint
+	Secret string `gorm:"size:512;serializer:encrypted"`
+	Note   string
+}
+
+type FieldCryptTestSuite struct {
+	suite.Suite
+	master []byte
+}
+
+func TestFieldCryptSuite(t *testing.T) {
+	suite.Run(t, new(FieldCryptTestSuite))
+}
+
+func (s *FieldCryptTestSuite) SetupTest() {
+	s.master = bytes.Repeat([]byte{7}, KeySize)
+	previous := DefaultKeyring()
+	s.T().Cleanup(func() { SetDefaultKeyring(previous) })
+}
+
+func (s *FieldCryptTestSuite) keyring(active uint32, versions ...uint32) *Keyring {
+	keys := make(map[uint32][]byte)
+	for _, version := range versions {
+		keys[version] = bytes.Repeat([]byte{byte(version)}, KeySize)
+	}
+	keyring, err := NewKeyring(active, keys, []byte("blind"))
+	s.Require().NoError(err)
+	return keyring
+}
+
+func (s *FieldCryptTestSuite) TestRoundTrip() {
+	keyring := s.keyring(1, 1)
+
+	first, err := keyring.Encrypt("jane@example.com", "email")
+	s.Require().NoError(err)
+	second, err := keyring.Encrypt("jane@example.com", "email")
+	s.Require().NoError(err)
+	s.True(strings.HasPrefix(first, "enc:v1:1:"))
+	s.NotEqual(first, second, "nonce must be random")
+
+	plaintext, err := keyring.Decrypt(first, "email")
+	s.Require().NoError(err)
+	s.Equal("jane@example.com", plaintext)
+}
+
+func (s *FieldCryptTestSuite) TestAADBindsColumn() {
+	keyring := s.keyring(1, 1)
+	ciphertext, err := keyring.Encrypt("Jane", "name")
+	s.Require().NoError(err)
+
+	_, err = keyring.Decrypt(ciphertext, "email")
+	s.Error(err)
+}
+
+func (s *FieldCryptTestSuite) TestLegacyPlaintextAndMalformedValues() {
+	keyring := s.keyring(1, 1)
+
+	plaintext, err := keyring.Decrypt("jane@example.com", "email")
+	s.Require().NoError(err)
+	s.Equal("jane@example.com", plaintext)
+	s.True(keyring.NeedsReencrypt("jane@example.com"))
+
+	_, err = keyring.Decrypt("enc:v1:1:***", "email")
+	s.ErrorIs(err, ErrMalformedValue)
+	_, err = keyring.Decrypt("enc:v1:9:AAAA", "email")
+	s.ErrorIs(err, ErrUnknownKeyVersion)
+}
+
+func (s *FieldCryptTestSuite) TestKeyRotation() {
+	old := s.keyring(1, 1)
+	ciphertext, err := old.Encrypt("Jane", "name")
+	s.Require().NoError(err)
+
+	rotated := s.keyring(2, 1, 2)
+	plaintext, err := rotated.Decrypt(ciphertext, "name")
+	s.Require().NoError(err)
+	s.Equal("Jane", plaintext)
+	s.True(rotated.NeedsReencrypt(ciphertext))
+	s.False(old.NeedsReencrypt(ciphertext))
+
+	_, err = NewKeyring(3, map[uint32][]byte{1: bytes.Repeat([]byte{1}, KeySize)}, nil)
+	s.ErrorIs(err, ErrUnknownKeyVersion)
+}
+
+func (s *FieldCryptTestSuite) TestDisabledKeyringStoresPlaintext() {
+	keyring, err := NewKeyring(0, nil, nil)
+	s.Require().NoError(err)
+	s.False(keyring.Enabled())
+
+	value, err := keyring.Encrypt("Jane", "name")
+	s.Require().NoError(err)
+	s.Equal("Jane", value)
+	s.False(keyring.NeedsReencrypt(value))
+
+	_, err = keyring.Decrypt("enc:v1:1:AAAA", "name")
+	s.ErrorIs(err, ErrDisabled)
+}
+
+func (s *FieldCryptTestSuite) TestDisabledKeyringEscapesPrefixedPlaintext() {
+	disabled, err := NewKeyring(0, nil, nil)
+	s.Require().NoError(err)
+
+	for _, plaintext := range []string{"enc:v1:1:AAAA", "enc:v1:", "enc:other", "plain:text"} {
+		stored, err := disabled.Encrypt(plaintext, "name")
+		s.Require().NoError(err)
+		s.Equal("plain:"+plaintext, stored)
+
+		value, err := disabled.Decrypt(stored, "name")
+		s.Require().NoError(err)
+		s.Equal(plaintext, value)
+
+		// nilai yang di-escape tetap terbaca dan dienkripsi ulang setelah key dipasang
+		enabled := s.keyring(1, 1)
+		s.True(enabled.NeedsReencrypt(stored))
+		value, err = enabled.Decrypt(stored, "name")
+		s.Require().NoError(err)
+		s.Equal(plaintext, value)
+	}
+}
+
+func (s *FieldCryptTestSuite) TestBlindIndex() {
+	keyring := s.keyring(1, 1)
+	s.Equal(keyring.BlindIndex("jane@example.com"), keyring.BlindIndex(" Jane@Example.com "))
+	s.NotEqual(keyring.BlindIndex("jane@example.com"), keyring.BlindIndex("john@example.com"))
+	s.Len(keyring.BlindIndex("jane@example.com"), 64)
+
+	other, err := NewKeyring(1, map[uint32][]byte{1: bytes.Repeat([]byte{1}, KeySize)}, []byte("other"))
+	s.Require().NoError(err)
+	s.NotEqual(keyring.BlindIndex("jane@example.com"), other.BlindIndex("jane@example.com"))
+}
+
+func (s *FieldCryptTestSuite) TestEnvelope() {
+	dataKey, err := GenerateDataKey()
+	s.Require().NoError(err)
+	wrapped, err := WrapKey(s.master, dataKey, 1)
+	s.Require().NoError(err)
+
+	unwrapped, err := UnwrapKey(s.master, wrapped, 1)
+	s.Require().NoError(err)
+	s.Equal(dataKey, unwrapped)
+
+	_, err = UnwrapKey(s.master, wrapped, 2)
+	s.Error(err, "version is bound to the wrapped key")
+	_, err = UnwrapKey(bytes.Repeat([]byte{8}, KeySize), wrapped, 1)
+	s.Error(err)
+
+	keys, err := ParseKeyList(" 1:" + wrapped + " ,")
+	s.Require().NoError(err)
+	keyring, err := LoadKeyring(s.master, KeyFile{Active: 1, Keys: keys}, nil)
+	s.Require().NoError(err)
+	s.Equal(uint32(1), keyring.ActiveVersion())
+	s.Equal(DeriveBlindIndexKey(s.master), keyring.blindKey)
+
+	_, err = ParseKeyList("missing-version")
+	s.Error(err)
+}
+
+func (s *FieldCryptTestSuite) openDB() *gorm.DB {
+	db := testutil.NewDB(s.T(), &secretRecord{})
+	return db
+}
+
+func (s *FieldCryptTestSuite) rawSecret(db *gorm.DB, id uint) string {
+	var raw string
+	s.Require().NoError(db.Raw("SELECT secret FROM secret_records WHERE id = ?", id).Scan(&raw).Error)
+	return raw
+}
+
+func (s *FieldCryptTestSuite) TestSerializerAndReencrypt() {
+	db := s.openDB()
+
+	// baris lama sebelum enkripsi diaktifkan
+	SetDefaultKeyring(s.keyring(0))
+	legacy := secretRecord{Secret: "legacy", Note: "a"}
+	s.Require().NoError(db.Create(&legacy).Error)
+	s.Equal("legacy", s.rawSecret(db, legacy.ID))
+
+	SetDefaultKeyring(s.keyring(1, 1))
+	current := secretRecord{Secret: "current", Note: "b"}
+	s.Require().NoError(db.Create(&current).Error)
+	s.True(strings.HasPrefix(s.rawSecret(db, current.ID), "enc:v1:1:"))
+
+	var loaded []secretRecord
+	s.Require().NoError(db.Order("id").Find(&loaded).Error)
+	s.Equal([]string{"legacy", "current"}, []string{loaded[0].Secret, loaded[1].Secret})
+
+	SetDefaultKeyring(s.keyring(2, 1, 2))
+	result, err := Reencrypt(context.Background(), db, &secretRecord{}, 1)
+	s.Require().NoError(err)
+	s.Equal(Result{Scanned: 2, Updated: 2}, result)
+	s.True(strings.HasPrefix(s.rawSecret(db, legacy.ID), "enc:v1:2:"))
+	s.True(strings.HasPrefix(s.rawSecret(db, current.ID), "enc:v1:2:"))
+
+	loaded = nil
+	s.Require().NoError(db.Order("id").Find(&loaded).Error)
+	s.Equal("legacy", loaded[0].Secret)
+	s.Equal("a", loaded[0].Note)
+
+	// semua baris sudah memakai key aktif
+	result, err = Reencrypt(context.Background(), db, &secretRecord{}, 10)
+	s.Require().NoError(err)
+	s.Equal(Result{Scanned: 2}, result)
+}
+
+func (s *FieldCryptTestSuite) TestReencryptRequiresKeys() {
+	SetDefaultKeyring(s.keyring(0))
+	_, err := Reencrypt(context.Background(), s.openDB(), &secretRecord{}, 10)
+	s.ErrorIs(err, ErrDisabled)
+}
@@ -0,0 +1,47 @@
+package fieldcrypt
+
+import (
+	"gorm.io/gorm/clause"
+)
+
+// IndexOf mengembalikan blind index value untuk kolom blind index bertipe *string.
+// Index selalu diisi, juga saat enkripsi tidak aktif, agar unique index pada kolom
+// blind index tetap menjaga keunikan.
+func IndexOf(value string) *string {
+	index := BlindIndex(value)
+	return &index
+}
+
+// IndexCondition mengembalikan kondisi exact match kolom terenkripsi lewat blind
+// index-nya. Baris plaintext yang belum di-enkripsi ulang (index kosong atau dihitung
+// sebelum enkripsi aktif) tetap cocok lewat kolom aslinya; ciphertext tidak pernah
+// sama dengan nilai yang dicari sehingga fallback ini hanya berlaku untuk plaintext.
+func IndexCondition(column, indexColumn, value string) clause.Expr {
+	return clause.Expr{
+		SQL:  "(" + indexColumn + " = ? OR " + column + " = ?)",
+		Vars: []interface{}{BlindIndex(value), value},
+	}
+}
+
+// EncryptChanges menyiapkan update dengan map, karena serializer GORM tidak berlaku
+// untuk map: blind index pada indexes (kolom -> kolom blind index) diisi dari
+// plaintext, lalu kolom pada columns dienkripsi dengan nama kolom sebagai AAD.
+func EncryptChanges(changes map[string]interface{}, columns []string, indexes map[string]string) error {
+	for column, indexColumn := range indexes {
+		if plaintext, ok := changes[column].(string); ok {
+			changes[indexColumn] = IndexOf(plaintext)
+		}
+	}
+	for _, column := range columns {
+		plaintext, ok := changes[column].(string)
+		if !ok {
+			continue
+		}
+		ciphertext, err := Encrypt(plaintext, column)
+		if err != nil {
+			return err
+		}
+		changes[column] = ciphertext
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package fieldcrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// KeySize adalah panjang master key dan data key (AES-256)
+const KeySize = 32
+
+// prefix menandai nilai terenkripsi: enc:v1:<versi key>:<base64(nonce||ciphertext)>.
+// Nilai tanpa prefix dianggap plaintext lama dan dikembalikan apa adanya.
+const prefix = "enc:v1:"
+
+// plainPrefix meng-escape plaintext yang ditulis saat enkripsi nonaktif dan diawali
+// "enc:" atau plainPrefix sendiri, sehingga tidak pernah terbaca sebagai ciphertext.
+const plainPrefix = "plain:"
+
+var (
+	ErrUnknownKeyVersion = errors.New("fieldcrypt: unknown key version")
+	ErrMalformedValue    = errors.New("fieldcrypt: malformed encrypted value")
+	ErrDisabled          = errors.New("fieldcrypt: encryption is not configured")
+)
+
+// Keyring menyimpan data key berversi. Nilai baru dienkripsi dengan key aktif;
+// key lama tetap dipakai untuk dekripsi sampai semua baris di-enkripsi ulang.
+// Keyring tanpa data key menonaktifkan enkripsi; nilai disimpan sebagai plaintext.
+type Keyring struct {
+	active   uint32
+	aeads    map[uint32]cipher.AEAD
+	blindKey []byte
+}
+
+// NewKeyring membuat keyring dari data key plaintext. blindIndexKey dipakai untuk
+// HMAC blind index dan tidak boleh berganti tanpa menghitung ulang index.
+func NewKeyring(active uint32, dataKeys map[uint32][]byte, blindIndexKey []byte) (*Keyring, error) {
+	k := &Keyring{
+		active:   active,
+		aeads:    make(map[uint32]cipher.AEAD, len(dataKeys)),
+		blindKey: blindIndexKey,
+	}
+	for version, key := range dataKeys {
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, fmt.Errorf("fieldcrypt: data key %d: %w", version, err)
+		}
+		k.aeads[version] = aead
+	}
+	if len(dataKeys) > 0 {
+		if _, ok := k.aeads[active]; !ok {
+			return nil, fmt.Errorf("%w: active key %d", ErrUnknownKeyVersion, active)
+		}
+	}
+	return k, nil
+}
+
+// Enabled reports whether new values are encrypted
+func (k *Keyring) Enabled() bool {
+	return len(k.aeads) > 0
+}
+
+// ActiveVersion returns the key version used for new values
+func (k *Keyring) ActiveVersion() uint32 {
+	return k.active
+}
+
+// Encrypt mengenkripsi plaintext dengan key aktif. aad mengikat ciphertext ke
+// kolomnya sehingga nilai tidak dapat dipindah ke kolom lain.
+func (k *Keyring) Encrypt(plaintext, aad string) (string, error) {
+	if !k.Enabled() {
+		if strings.HasPrefix(plaintext, "enc:") || strings.HasPrefix(plaintext, plainPrefix) {
+			return plainPrefix + plaintext, nil
+		}
+		return plaintext, nil
+	}
+
+	aead := k.aeads[k.active]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(aad))
+	return prefix + strconv.FormatUint(uint64(k.active), 10) + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt mengembalikan plaintext. Nilai tanpa prefix enkripsi dikembalikan apa
+// adanya agar baris lama tetap terbaca sebelum di-enkripsi ulang.
+func (k *Keyring) Decrypt(value, aad string) (string, error) {
+	if plaintext, escaped := strings.CutPrefix(value, plainPrefix); escaped {
+		return plaintext, nil
+	}
+	version, payload, ok := parse(value)
+	if !ok {
+		if strings.HasPrefix(value, prefix) {
+			return "", ErrMalformedValue
+		}
+		return value, nil
+	}
+	if !k.Enabled() {
+		return "", ErrDisabled
+	}
+
+	aead, found := k.aeads[version]
+	if !found {
+		return "", fmt.Errorf("%w: %d", ErrUnknownKeyVersion, version)
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(payload)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", ErrMalformedValue
+	}
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(aad))
+	if err != nil {
+		return "", fmt.Errorf("fieldcrypt: decrypt: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+// NeedsReencrypt reports whether value is plaintext or uses an older key version
+func (k *Keyring) NeedsReencrypt(value string) bool {
+	if !k.Enabled() || value == "" {
+		return false
+	}
+	version, _, ok := parse(value)
+	return !ok || version != k.active
+}
+
+// BlindIndex mengembalikan HMAC-SHA256 deterministik dari nilai yang dinormalisasi
+// (trim, huruf kecil) untuk pencarian exact match pada kolom terenkripsi
+func (k *Keyring) BlindIndex(value string) string {
+	mac := hmac.New(sha256.New, k.blindKey)
+	mac.Write([]byte(strings.ToLower(strings.TrimSpace(value))))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func parse(value string) (uint32, string, bool) {
+	rest, found := strings.CutPrefix(value, prefix)
+	if !found {
+		return 0, "", false
+	}
+	rawVersion, payload, found := strings.Cut(rest, ":")
+	if !found {
+		return 0, "", false
+	}
+	version, err := strconv.ParseUint(rawVersion, 10, 32)
+	if err != nil {
+		return 0, "", false
+	}
+	return uint32(version), payload, true
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("key must be %d bytes", KeySize)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+var (
+	defaultMu      sync.RWMutex
+	defaultKeyring = &Keyring{aeads: map[uint32]cipher.AEAD{}}
+)
+
+// SetDefaultKeyring mengganti keyring yang dipakai serializer; dipanggil sekali saat startup
+func SetDefaultKeyring(keyring *Keyring) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultKeyring = keyring
+}
+
+// DefaultKeyring returns the keyring configured at startup
+func DefaultKeyring() *Keyring {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultKeyring
+}
+
+// Enabled reports whether the default keyring encrypts new values
+func Enabled() bool {
+	return DefaultKeyring().Enabled()
+}
+
+// Encrypt mengenkripsi dengan keyring default
+func Encrypt(plaintext, aad string) (string, error) {
+	return DefaultKeyring().Encrypt(plaintext, aad)
+}
+
+// Decrypt mendekripsi dengan keyring default
+func Decrypt(value, aad string) (string, error) {
+	return DefaultKeyring().Decrypt(value, aad)
+}
+
+// BlindIndex menghitung blind index dengan keyring default
+func BlindIndex(value string) string {
+	return DefaultKeyring().BlindIndex(value)
+}
@@ -0,0 +1,136 @@
+package fieldcrypt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm"
+)
+
+// DefaultBatchSize adalah jumlah baris per batch re-enkripsi
+const DefaultBatchSize = 500
+
+// Result adalah ringkasan satu proses re-enkripsi
+type Result struct {
+	Scanned int
+	Updated int
+	// Skipped adalah baris yang berubah di antara dibaca dan ditulis; baris itu
+	// sudah ditulis aplikasi dengan key aktif sehingga tidak perlu diulang
+	Skipped int
+}
+
+// Reencrypt menulis ulang kolom terenkripsi milik model yang masih plaintext atau
+// memakai versi key lama, per batch berdasarkan primary key. Setiap baris ditulis
+// dengan compare-and-swap pada nilai lama sehingga aman dijalankan saat aplikasi
+// tetap melayani request. extraColumns ikut ditulis, mis. blind index yang diisi
+// hook BeforeSave model.
+func Reencrypt(ctx context.Context, db *gorm.DB, model interface{}, batchSize int, extraColumns ...string) (Result, error) {
+	var result Result
+	keyring := DefaultKeyring()
+	if !keyring.Enabled() {
+		return result, ErrDisabled
+	}
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return result, err
+	}
+	primary := stmt.Schema.PrioritizedPrimaryField
+	if primary == nil {
+		return result, errors.New("fieldcrypt: model has no primary key")
+	}
+	var columns []string
+	for _, field := range stmt.Schema.Fields {
+		if field.TagSettings["SERIALIZER"] == SerializerName {
+			columns = append(columns, field.DBName)
+		}
+	}
+	if len(columns) == 0 {
+		return result, fmt.Errorf("fieldcrypt: %s has no encrypted fields", stmt.Schema.Name)
+	}
+
+	modelType := reflect.TypeOf(model)
+	for modelType.Kind() == reflect.Ptr {
+		modelType = modelType.Elem()
+	}
+
+	var lastID interface{} = 0
+	for {
+		var rows []map[string]interface{}
+		err := db.WithContext(ctx).Table(stmt.Schema.Table).
+			Select(append([]string{primary.DBName}, columns...)).
+			Where(primary.DBName+" > ?", lastID).
+			Order(primary.DBName).
+			Limit(batchSize).
+			Find(&rows).Error
+		if err != nil {
+			return result, err
+		}
+		if len(rows) == 0 {
+			return result, nil
+		}
+
+		for _, row := range rows {
+			result.Scanned++
+			lastID = row[primary.DBName]
+
+			stale := false
+			for _, column := range columns {
+				if keyring.NeedsReencrypt(asString(row[column])) {
+					stale = true
+					break
+				}
+			}
+			if !stale {
+				continue
+			}
+
+			updated, err := reencryptRow(ctx, db, modelType, primary.DBName, row, columns, extraColumns)
+			if err != nil {
+				return result, fmt.Errorf("fieldcrypt: %s %s=%v: %w", stmt.Schema.Table, primary.DBName, lastID, err)
+			}
+			if updated {
+				result.Updated++
+			} else {
+				result.Skipped++
+			}
+		}
+	}
+}
+
+func reencryptRow(ctx context.Context, db *gorm.DB, modelType reflect.Type, primary string, raw map[string]interface{}, columns, extraColumns []string) (bool, error) {
+	record := reflect.New(modelType).Interface()
+	if err := db.WithContext(ctx).Where(primary+" = ?", raw[primary]).Take(record).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	query := db.WithContext(ctx).Model(record).Select(append(append([]string{}, columns...), extraColumns...))
+	for _, column := range columns {
+		if raw[column] == nil {
+			query = query.Where(column + " IS NULL")
+		} else {
+			query = query.Where(column+" = ?", raw[column])
+		}
+	}
+	res := query.Updates(record)
+	return res.RowsAffected > 0, res.Error
+}
+
+func asString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,53 @@
+package fieldcrypt
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm/schema"
+)
+
+// SerializerName adalah nama serializer GORM untuk kolom string terenkripsi:
+//
+//	Email string `gorm:"serializer:encrypted"`
+const SerializerName = "encrypted"
+
+func init() {
+	schema.RegisterSerializer(SerializerName, Serializer{})
+}
+
+// Serializer mengenkripsi field string secara transparan dengan keyring default.
+// Nama kolom dipakai sebagai AAD. Serializer hanya berlaku untuk Create, Save,
+// update dengan struct dan query; update dengan map harus mengenkripsi sendiri.
+type Serializer struct{}
+
+// Scan mendekripsi nilai kolom ke field
+func (Serializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	var raw string
+	switch v := dbValue.(type) {
+	case nil:
+	case string:
+		raw = v
+	case []byte:
+		raw = string(v)
+	default:
+		return fmt.Errorf("fieldcrypt: unsupported column type %T for %s", dbValue, field.DBName)
+	}
+
+	plaintext, err := Decrypt(raw, field.DBName)
+	if err != nil {
+		return fmt.Errorf("%s: %w", field.DBName, err)
+	}
+	field.ReflectValueOf(ctx, dst).SetString(plaintext)
+	return nil
+}
+
+// Value mengenkripsi nilai field sebelum ditulis
+func (Serializer) Value(_ context.Context, field *schema.Field, _ reflect.Value, fieldValue interface{}) (interface{}, error) {
+	plaintext, ok := fieldValue.(string)
+	if !ok {
+		return nil, fmt.Errorf("fieldcrypt: field %s must be a string", field.Name)
+	}
+	return Encrypt(plaintext, field.DBName)
+}
@@ -0,0 +1,145 @@
+package i18n
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	errs "boilerplate/shared/errors"
+
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/id"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	enTranslations "github.com/go-playground/validator/v10/translations/en"
+	idTranslations "github.com/go-playground/validator/v10/translations/id"
+	"golang.org/x/text/language"
+)
+
+const (
+	LocaleEN      = "en"
+	LocaleID      = "id"
+	DefaultLocale = LocaleEN
+
+	// ContextKey adalah key echo.Context tempat locale request disimpan
+	ContextKey = "locale"
+
+	// invalidFieldKey dipakai untuk tag validator yang tidak punya terjemahan
+	invalidFieldKey = "validation.invalid"
+)
+
+// catalogs berisi terjemahan pesan publik per locale, di-key dengan kode AppError
+var catalogs = map[string]map[string]string{
+	LocaleEN: messagesEN,
+	LocaleID: messagesID,
+}
+
+var supported = []language.Tag{language.English, language.Indonesian}
+
+// Translator memilih terjemahan pesan validasi dan error berdasarkan locale
+type Translator struct {
+	uni     *ut.UniversalTranslator
+	matcher language.Matcher
+}
+
+// New registers English and Indonesian translations on validate and loads the
+// AppError message catalogs.
+func New(validate *validator.Validate) (*Translator, error) {
+	enLocale := en.New()
+	uni := ut.New(enLocale, enLocale, id.New())
+
+	enTrans, _ := uni.GetTranslator(LocaleEN)
+	if err := enTranslations.RegisterDefaultTranslations(validate, enTrans); err != nil {
+		return nil, err
+	}
+
+	idTrans, _ := uni.GetTranslator(LocaleID)
+	if err := idTranslations.RegisterDefaultTranslations(validate, idTrans); err != nil {
+		return nil, err
+	}
+
+	for _, appErr := range errs.Catalog() {
+		if err := enTrans.Add(appErr.Code, appErr.Message, true); err != nil {
+			return nil, fmt.Errorf("i18n %s %s: %w", LocaleEN, appErr.Code, err)
+		}
+	}
+
+	for locale, messages := range catalogs {
+		trans, _ := uni.GetTranslator(locale)
+		for key, message := range messages {
+			if err := trans.Add(key, message, true); err != nil {
+				return nil, fmt.Errorf("i18n %s %s: %w", locale, key, err)
+			}
+		}
+	}
+
+	return &Translator{
+		uni:     uni,
+		matcher: language.NewMatcher(supported),
+	}, nil
+}
+
+// JSONTagName makes validation errors report JSON field names instead of
+// Go struct field names.
+func JSONTagName(field reflect.StructField) string {
+	name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+// Match memilih locale yang didukung dari header Accept-Language
+func (t *Translator) Match(acceptLanguage string) string {
+	if acceptLanguage == "" {
+		return DefaultLocale
+	}
+
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return DefaultLocale
+	}
+
+	_, index, confidence := t.matcher.Match(tags...)
+	if confidence == language.No {
+		return DefaultLocale
+	}
+	base, _ := supported[index].Base()
+	return base.String()
+}
+
+// Message menerjemahkan pesan publik AppError; pesan bawaan dipakai jika tidak ada
+func (t *Translator) Message(locale string, appErr *errs.AppError) string {
+	message, err := t.translator(locale).T(appErr.Code)
+	if err != nil || message == "" {
+		return appErr.Message
+	}
+	return message
+}
+
+// ValidationMessages translates every field error, falling back to a
+// generic "field is invalid" message for tags without a registered translation.
+func (t *Translator) ValidationMessages(locale string, ve validator.ValidationErrors) map[string]string {
+	trans := t.translator(locale)
+
+	messages := make(map[string]string, len(ve))
+	for _, fe := range ve {
+		message := fe.Translate(trans)
+		if message == fe.Error() {
+			message, _ = trans.T(invalidFieldKey, fe.Field())
+		}
+		messages[fe.Field()] = message
+	}
+	return messages
+}
+
+func (t *Translator) translator(locale string) ut.Translator {
+	trans, found := t.uni.GetTranslator(locale)
+	if !found {
+		trans, _ = t.uni.GetTranslator(DefaultLocale)
+	}
+	return trans
+}
@@ -0,0 +1,83 @@
+package i18n
+
+import (
+	"testing"
+
+	errs "boilerplate/shared/errors"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/suite"
+)
+
+type signupInput struct {
+	FullName string `json:"full_name" validate:"required,min=2"`
+	Email    string `json:"email" validate:"required,email"`
+	Code     string `json:"code" validate:"palindrome"`
+}
+
+type I18nTestSuite struct {
+	suite.Suite
+	validate   *validator.Validate
+	translator *Translator
+}
+
+func TestI18nSuite(t *testing.T) {
+	suite.Run(t, new(I18nTestSuite))
+}
+
+func (s *I18nTestSuite) SetupTest() {
+	s.validate = validator.New()
+	s.validate.RegisterTagNameFunc(JSONTagName)
+	s.Require().NoError(s.validate.RegisterValidation("palindrome", func(fl validator.FieldLevel) bool {
+		return false
+	}))
+
+	translator, err := New(s.validate)
+	s.Require().NoError(err)
+	s.translator = translator
+}
+
+func (s *I18nTestSuite) TestMatch() {
+	tests := []struct {
+		header   string
+		expected string
+	}{
+		{header: "", expected: LocaleEN},
+		{header: "id-ID,id;q=0.9,en;q=0.8", expected: LocaleID},
+		{header: "en-US", expected: LocaleEN},
+		{header: "fr-FR", expected: LocaleEN},
+		{header: "invalid;;q", expected: LocaleEN},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.header, func() {
+			s.Equal(tt.expected, s.translator.Match(tt.header))
+		})
+	}
+}
+
+func (s *I18nTestSuite) TestCatalogIsTranslated() {
+	for _, appErr := range errs.Catalog() {
+		s.Equal(appErr.Message, s.translator.Message(LocaleEN, appErr), appErr.Code)
+
+		message, ok := messagesID[appErr.Code]
+		s.True(ok, "missing Indonesian translation for %s", appErr.Code)
+		s.Equal(message, s.translator.Message(LocaleID, appErr))
+	}
+}
+
+func (s *I18nTestSuite) TestValidationMessages() {
+	err := s.validate.Struct(signupInput{FullName: "A", Email: "not-an-email"})
+	ve, ok := err.(validator.ValidationErrors)
+	s.Require().True(ok)
+
+	en := s.translator.ValidationMessages(LocaleEN, ve)
+	s.Equal("full_name must be at least 2 characters in length", en["full_name"])
+	s.Equal("email must be a valid email address", en["email"])
+	s.Equal("code is invalid", en["code"])
+
+	id := s.translator.ValidationMessages(LocaleID, ve)
+	s.Contains(id["full_name"], "full_name")
+	s.NotEqual(en["full_name"], id["full_name"])
+	s.Equal("code tidak valid", id["code"])
+}
@@ -0,0 +1,7 @@
+package i18n
+
+// messagesEN hanya berisi pesan tambahan; pesan AppError bahasa Inggris
+// diambil langsung dari katalog shared/errors.
+var messagesEN = map[string]string{
+	invalidFieldKey: "{0} is invalid",
+}
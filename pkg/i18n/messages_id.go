@@ -0,0 +1,85 @@
+package i18n
+
+var messagesID = map[string]string{
+	invalidFieldKey: "{0} tidak valid",
+
+	"user.invalid_email":              "email tidak valid",
+	"user.password_too_short":         "password minimal 6 karakter",
+	"user.password_policy":            "password tidak memenuhi kebijakan password",
+	"user.password_hash_failed":       "gagal memproses password",
+	"user.invalid_password":           "password salah",
+	"user.email_taken":                "email sudah terdaftar",
+	"user.not_found":                  "user tidak ditemukan",
+	"user.disabled":                   "akun dinonaktifkan",
+	"user.last_admin":                 "tidak dapat menurunkan atau menonaktifkan admin aktif terakhir",
+	"user.password_reset_required":    "password wajib diganti sebelum melanjutkan",
+	"user.impersonation_not_allowed":  "user ini tidak dapat diimpersonasi",
+	"user.registration_disabled":      "registrasi terbuka dinonaktifkan, dibutuhkan undangan",
+	"user.erased":                     "data pribadi user ini telah dihapus",
+	"invite.not_found":                "undangan tidak ditemukan",
+	"invite.invalid":                  "undangan tidak valid, kedaluwarsa, dicabut atau sudah dipakai",
+	"invite.invalid_expiry":           "masa berlaku undangan harus di masa depan",
+	"invite.already_accepted":         "undangan sudah diterima",
+	"category.name_required":          "nama category wajib diisi",
+	"category.not_found":              "category tidak ditemukan",
+	"category.slug_invalid":           "slug category harus memuat huruf atau angka",
+	"category.import_duplicate_row":   "baris lain di file mencocokkan category yang sama",
+	"category.import_ambiguous_match": "lebih dari satu category cocok dengan baris ini",
+	"category.import_empty":           "file import tidak berisi baris",
+	"category.import_too_large":       "file import melebihi batas ukuran atau jumlah baris",
+	"category.import_invalid_file":    "file import tidak dapat dibaca",
+	"category.import_not_found":       "import tidak ditemukan",
+	"api_key.invalid":                 "API key tidak valid, kedaluwarsa atau sudah dicabut",
+	"api_key.ip_not_allowed":          "API key tidak diizinkan dari alamat IP ini",
+	"api_key.not_found":               "API key tidak ditemukan",
+	"api_key.insufficient_scope":      "API key tidak memiliki scope yang dibutuhkan",
+	"api_key.session_required":        "endpoint ini membutuhkan sesi user, bukan API key",
+	"oidc.provider_not_found":         "provider login tidak ditemukan",
+	"oidc.invalid_state":              "sesi login kedaluwarsa atau tidak valid, silakan coba lagi",
+	"oidc.denied":                     "login ditolak oleh provider",
+	"oidc.exchange_failed":            "gagal menyelesaikan login dengan provider",
+	"oidc.invalid_id_token":           "ID token dari provider tidak valid",
+	"oidc.email_not_verified":         "provider tidak mengembalikan alamat email yang terverifikasi",
+	"oauth.unknown_client":            "client OAuth tidak dikenal",
+	"oauth.invalid_redirect_uri":      "redirect_uri tidak terdaftar untuk client ini",
+	"oauth.invalid_client_config":     "konfigurasi client OAuth tidak valid",
+	"oauth.client_not_found":          "client OAuth tidak ditemukan",
+	"oauth.consent_not_found":         "consent tidak ditemukan",
+	"privacy.export_not_found":        "export tidak ditemukan",
+	"privacy.export_in_progress":      "export lain masih diproses",
+	"privacy.export_not_ready":        "export belum selesai diproses",
+	"privacy.export_expired":          "export sudah kedaluwarsa, silakan minta export baru",
+	"job.not_found":                   "job tidak ditemukan",
+	"scheduler.task_not_found":        "task terjadwal tidak ditemukan",
+	"webhook.not_found":               "webhook tidak ditemukan",
+	"search.query_too_short":          "query pencarian harus memuat kata minimal 2 karakter",
+	"job.not_failed":                  "hanya job yang gagal yang dapat dicoba ulang",
+	"auth.invalid_credentials":        "email atau password salah",
+	"auth.unauthorized":               "tidak terautentikasi",
+	"auth.missing_authorization":      "header Authorization wajib diisi",
+	"auth.invalid_authorization":      "format header Authorization tidak valid",
+	"auth.invalid_token":              "token tidak valid",
+	"auth.token_revoked":              "token sudah dicabut atau kedaluwarsa",
+	"auth.admin_required":             "akses ditolak: membutuhkan role admin",
+	"auth.forbidden":                  "akses ditolak",
+	"auth.impersonation_forbidden":    "aksi ini tidak diizinkan selama impersonasi user",
+	"route.not_found":                 "route tidak ditemukan",
+	"route.method_not_allowed":        "method tidak diizinkan",
+	"request.invalid_payload":         "payload request tidak valid",
+	"request.invalid_id":              "id tidak valid",
+	"request.validation_failed":       "validasi gagal",
+	"request.bad_request":             "request tidak valid",
+	"request.unsupported_media_type":  "media type tidak didukung",
+	"request.precondition_failed":     "If-Match tidak cocok dengan versi resource saat ini",
+	"request.precondition_required":   "header If-Match wajib diisi",
+	"request.invalid_idempotency_key": "Idempotency-Key tidak boleh lebih dari 255 karakter",
+	"request.idempotency_in_progress": "request dengan Idempotency-Key ini masih diproses",
+	"request.idempotency_key_reused":  "Idempotency-Key sudah dipakai untuk request lain",
+	"resource.version_conflict":       "resource telah diubah oleh request lain",
+	"resource.not_found":              "resource tidak ditemukan",
+	"resource.already_exists":         "resource sudah ada",
+	"request.contract_violation":      "request tidak sesuai dengan kontrak API",
+	"request.payload_too_large":       "payload request terlalu besar",
+	"internal.error":                  "terjadi kesalahan pada server",
+	"internal.contract_violation":     "response tidak sesuai dengan kontrak API",
+}
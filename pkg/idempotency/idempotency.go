@@ -0,0 +1,136 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// DefaultTTL adalah masa simpan response untuk replay jika TTL tidak diatur
+	DefaultTTL = 24 * time.Hour
+	// DefaultLockTTL membatasi berapa lama request pertama menahan key. Jika proses
+	// mati sebelum response disimpan, key dapat dipakai lagi setelah batas ini.
+	DefaultLockTTL = time.Minute
+	// MaxKeyLength adalah panjang maksimal header Idempotency-Key
+	MaxKeyLength = 255
+
+	keyPrefix = "idempotency:"
+)
+
+// Record adalah state satu idempotency key. Selama request pertama diproses
+// Completed bernilai false; setelah itu record berisi response untuk replay.
+type Record struct {
+	Fingerprint string      `json:"fingerprint"`
+	Completed   bool        `json:"completed"`
+	Status      int         `json:"status,omitempty"`
+	Header      http.Header `json:"header,omitempty"`
+	Body        []byte      `json:"body,omitempty"`
+	CreatedAt   time.Time   `json:"created_at"`
+}
+
+// Store menyimpan record idempotency di Redis
+type Store struct {
+	client  *redis.Client
+	ttl     time.Duration
+	lockTTL time.Duration
+}
+
+// NewStore membuat store dengan TTL response; nilai 0 memakai DefaultTTL
+func NewStore(client *redis.Client, ttl time.Duration) *Store {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &Store{client: client, ttl: ttl, lockTTL: min(DefaultLockTTL, ttl)}
+}
+
+// TTL mengembalikan masa simpan response
+func (s *Store) TTL() time.Duration {
+	return s.ttl
+}
+
+// Key menyusun key Redis dari pemilik request (mis. "user:1") dan idempotency key.
+// Key dari client di-hash agar panjang dan karakternya tidak memengaruhi key Redis.
+func Key(scope, key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return keyPrefix + scope + ":" + hex.EncodeToString(sum[:])
+}
+
+// Fingerprint menghitung sidik request dari method, path dan body. Key yang sama
+// dengan fingerprint berbeda berarti client memakai ulang key untuk request lain.
+func Fingerprint(method, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method))
+	hash.Write([]byte{0})
+	hash.Write([]byte(path))
+	hash.Write([]byte{0})
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// Begin mencoba mengklaim key untuk request dengan fingerprint tertentu. Jika key
+// belum dipakai, Begin mengembalikan (nil, nil) dan pemanggil wajib memanggil Complete
+// atau Release. Jika sudah dipakai, record yang ada dikembalikan.
+func (s *Store) Begin(ctx context.Context, key, fingerprint string) (*Record, error) {
+	pending, err := json.Marshal(Record{Fingerprint: fingerprint, CreatedAt: time.Now()})
+	if err != nil {
+		return nil, err
+	}
+
+	// record dapat kedaluwarsa di antara SET NX dan GET, jadi coba sekali lagi
+	for i := 0; i < 2; i++ {
+		acquired, err := s.client.SetNX(ctx, key, pending, s.lockTTL).Result()
+		if err != nil {
+			return nil, err
+		}
+		if acquired {
+			return nil, nil
+		}
+
+		record, err := s.get(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		if record != nil {
+			return record, nil
+		}
+	}
+	return nil, errors.New("idempotency: key changed during acquire")
+}
+
+// Complete menyimpan response request pertama untuk replay selama TTL
+func (s *Store) Complete(ctx context.Context, key string, record Record) error {
+	record.Completed = true
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return s.client.Set(ctx, key, data, s.ttl).Err()
+}
+
+// Release melepas key tanpa menyimpan response sehingga request dapat dicoba ulang
+func (s *Store) Release(ctx context.Context, key string) error {
+	return s.client.Del(ctx, key).Err()
+}
+
+func (s *Store) get(ctx context.Context, key string) (*Record, error) {
+	data, err := s.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var record Record
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
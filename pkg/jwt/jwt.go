@@ -1,6 +1,8 @@
 package jwt
 
 import (
+	"crypto/rand"
+	"encoding/base64"
 	"errors"
 	"time"
 
@@ -9,9 +11,21 @@ import (
 
 type Claims struct {
 	UserID uint `json:"user_id"`
+	// Act diisi pada token impersonasi dengan admin yang bertindak atas nama user (RFC 8693)
+	Act *Actor `json:"act,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// Actor adalah isi claim "act"
+type Actor struct {
+	UserID uint `json:"user_id"`
+}
+
+// IsImpersonation reports whether the token was issued for an admin acting as the user
+func (c *Claims) IsImpersonation() bool {
+	return c.Act != nil
+}
+
 // GenerateToken menghasilkan JWT token untuk user
 func GenerateToken(userID uint, secretKey string) (string, error) {
 	claims := Claims{
@@ -27,6 +41,35 @@ func GenerateToken(userID uint, secretKey string) (string, error) {
 	return token.SignedString([]byte(secretKey))
 }
 
+// GenerateImpersonationToken menghasilkan token berumur pendek untuk actorID yang
+// bertindak sebagai userID. ID token (jti) dikembalikan agar sesi dapat dicabut.
+func GenerateImpersonationToken(userID, actorID uint, ttl time.Duration, secretKey string) (string, string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	tokenID := base64.RawURLEncoding.EncodeToString(buf)
+
+	now := time.Now()
+	claims := Claims{
+		UserID: userID,
+		Act:    &Actor{UserID: actorID},
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID,
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signed, err := token.SignedString([]byte(secretKey))
+	if err != nil {
+		return "", "", err
+	}
+	return signed, tokenID, nil
+}
+
 // ValidateToken memvalidasi JWT token dan mengembalikan claims jika valid
 func ValidateToken(tokenString string, secretKey string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
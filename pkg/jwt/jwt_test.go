@@ -0,0 +1,53 @@
+package jwt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+const testSecret = "test-secret"
+
+type JWTTestSuite struct {
+	suite.Suite
+}
+
+func TestJWTSuite(t *testing.T) {
+	suite.Run(t, new(JWTTestSuite))
+}
+
+func (s *JWTTestSuite) TestGenerateAndValidate() {
+	token, err := GenerateToken(42, testSecret)
+	s.Require().NoError(err)
+
+	claims, err := ValidateToken(token, testSecret)
+	s.Require().NoError(err)
+	s.Equal(uint(42), claims.UserID)
+	s.False(claims.IsImpersonation())
+
+	_, err = ValidateToken(token, "other-secret")
+	s.Error(err)
+}
+
+func (s *JWTTestSuite) TestImpersonationToken() {
+	token, tokenID, err := GenerateImpersonationToken(42, 7, time.Minute, testSecret)
+	s.Require().NoError(err)
+	s.NotEmpty(tokenID)
+
+	claims, err := ValidateToken(token, testSecret)
+	s.Require().NoError(err)
+	s.True(claims.IsImpersonation())
+	s.Equal(uint(42), claims.UserID)
+	s.Equal(uint(7), claims.Act.UserID)
+	s.Equal(tokenID, claims.ID)
+	s.WithinDuration(time.Now().Add(time.Minute), claims.ExpiresAt.Time, 2*time.Second)
+}
+
+func (s *JWTTestSuite) TestExpiredImpersonationToken() {
+	token, _, err := GenerateImpersonationToken(42, 7, -time.Minute, testSecret)
+	s.Require().NoError(err)
+
+	_, err = ValidateToken(token, testSecret)
+	s.Error(err)
+}
@@ -0,0 +1,143 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// signingKeyBits adalah ukuran key RSA yang dibuat jika tidak ada key file
+const signingKeyBits = 2048
+
+// IDTokenClaims adalah claim ID token OpenID Connect
+type IDTokenClaims struct {
+	Nonce    string           `json:"nonce,omitempty"`
+	AuthTime *jwt.NumericDate `json:"auth_time,omitempty"`
+	Email    string           `json:"email,omitempty"`
+	Name     string           `json:"name,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// AccessTokenClaims adalah claim access token OAuth 2.0 (RFC 9068)
+type AccessTokenClaims struct {
+	ClientID string `json:"client_id"`
+	Scope    string `json:"scope,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// JSONWebKey adalah public key RSA dalam format JWK (RFC 7517)
+type JSONWebKey struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	N         string `json:"n"`
+	E         string `json:"e"`
+}
+
+// JSONWebKeySet adalah dokumen JWKS yang dipublikasikan untuk verifikasi token
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// Signer menandatangani token dengan RS256 sehingga pihak lain dapat
+// memverifikasinya lewat JWKS tanpa mengetahui secret
+type Signer struct {
+	key   *rsa.PrivateKey
+	keyID string
+}
+
+// NewSigner membuat signer dari private key RSA. Key ID diturunkan dari public key.
+func NewSigner(key *rsa.PrivateKey) *Signer {
+	sum := sha256.Sum256(x509.MarshalPKCS1PublicKey(&key.PublicKey))
+	return &Signer{
+		key:   key,
+		keyID: base64.RawURLEncoding.EncodeToString(sum[:8]),
+	}
+}
+
+// LoadSigner membaca private key RSA PEM (PKCS#1 atau PKCS#8). Jika path kosong,
+// key sementara dibuat sehingga token tidak lagi valid setelah restart.
+func LoadSigner(path string) (*Signer, error) {
+	if path == "" {
+		key, err := rsa.GenerateKey(rand.Reader, signingKeyBits)
+		if err != nil {
+			return nil, err
+		}
+		return NewSigner(key), nil
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, errors.New("signing key is not PEM encoded")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return NewSigner(key), nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("signing key is not an RSA key")
+	}
+	return NewSigner(key), nil
+}
+
+// KeyID mengembalikan kid yang dipasang di header setiap token
+func (s *Signer) KeyID() string {
+	return s.keyID
+}
+
+// PublicKey mengembalikan public key untuk memverifikasi token signer ini
+func (s *Signer) PublicKey() *rsa.PublicKey {
+	return &s.key.PublicKey
+}
+
+// Sign menandatangani claims dengan RS256
+func (s *Signer) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = s.keyID
+	return token.SignedString(s.key)
+}
+
+// Parse memverifikasi token yang ditandatangani signer ini dan mengisi claims
+func (s *Signer) Parse(tokenString string, claims jwt.Claims, options ...jwt.ParserOption) error {
+	options = append(options, jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg()}))
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		return s.PublicKey(), nil
+	}, options...)
+	if err != nil {
+		return err
+	}
+	if !token.Valid {
+		return errors.New("invalid token claims")
+	}
+	return nil
+}
+
+// JWKS mengembalikan public key signer dalam format JWKS
+func (s *Signer) JWKS() JSONWebKeySet {
+	return JSONWebKeySet{Keys: []JSONWebKey{{
+		KeyType:   "RSA",
+		Use:       "sig",
+		Algorithm: jwt.SigningMethodRS256.Alg(),
+		KeyID:     s.keyID,
+		N:         base64.RawURLEncoding.EncodeToString(s.key.N.Bytes()),
+		E:         base64.RawURLEncoding.EncodeToString(big.NewInt(int64(s.key.E)).Bytes()),
+	}}}
+}
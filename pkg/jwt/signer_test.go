@@ -0,0 +1,118 @@
+package jwt
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/suite"
+)
+
+type SignerTestSuite struct {
+	suite.Suite
+	signer *Signer
+}
+
+func TestSignerSuite(t *testing.T) {
+	suite.Run(t, new(SignerTestSuite))
+}
+
+func (s *SignerTestSuite) SetupTest() {
+	signer, err := LoadSigner("")
+	s.Require().NoError(err)
+	s.signer = signer
+}
+
+func (s *SignerTestSuite) idTokenClaims() IDTokenClaims {
+	return IDTokenClaims{
+		Nonce: "n-0S6_WzA2Mj",
+		Email: "user@example.com",
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    "https://issuer.example.com",
+			Subject:   "42",
+			Audience:  jwt.ClaimStrings{"internal-tool"},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+}
+
+func (s *SignerTestSuite) TestSignAndParse() {
+	token, err := s.signer.Sign(s.idTokenClaims())
+	s.Require().NoError(err)
+
+	var claims IDTokenClaims
+	s.Require().NoError(s.signer.Parse(token, &claims, jwt.WithIssuer("https://issuer.example.com")))
+	s.Equal("42", claims.Subject)
+	s.Equal("n-0S6_WzA2Mj", claims.Nonce)
+
+	s.Error(s.signer.Parse(token, &IDTokenClaims{}, jwt.WithIssuer("https://other.example.com")))
+}
+
+func (s *SignerTestSuite) TestParseRejectsOtherKeysAndHMAC() {
+	other, err := LoadSigner("")
+	s.Require().NoError(err)
+	token, err := other.Sign(s.idTokenClaims())
+	s.Require().NoError(err)
+	s.Error(s.signer.Parse(token, &IDTokenClaims{}))
+
+	hmacToken, err := GenerateToken(42, "secret")
+	s.Require().NoError(err)
+	s.Error(s.signer.Parse(hmacToken, &AccessTokenClaims{}))
+}
+
+// TestJWKSVerifiableByRelyingParty memastikan ID token dapat diverifikasi oleh library OIDC pihak lain
+func (s *SignerTestSuite) TestJWKSVerifiableByRelyingParty() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(s.signer.JWKS())
+	}))
+	defer server.Close()
+
+	token, err := s.signer.Sign(s.idTokenClaims())
+	s.Require().NoError(err)
+
+	ctx := context.Background()
+	verifier := oidc.NewVerifier("https://issuer.example.com", oidc.NewRemoteKeySet(ctx, server.URL), &oidc.Config{ClientID: "internal-tool"})
+	idToken, err := verifier.Verify(ctx, token)
+	s.Require().NoError(err)
+	s.Equal("42", idToken.Subject)
+	s.Equal("n-0S6_WzA2Mj", idToken.Nonce)
+}
+
+func (s *SignerTestSuite) TestLoadSignerFromPEM() {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	s.Require().NoError(err)
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	s.Require().NoError(err)
+
+	blocks := map[string]*pem.Block{
+		"pkcs1.pem": {Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)},
+		"pkcs8.pem": {Type: "PRIVATE KEY", Bytes: pkcs8},
+	}
+	dir := s.T().TempDir()
+	for name, block := range blocks {
+		s.Run(name, func() {
+			path := filepath.Join(dir, name)
+			s.Require().NoError(os.WriteFile(path, pem.EncodeToMemory(block), 0o600))
+
+			signer, err := LoadSigner(path)
+			s.Require().NoError(err)
+			s.Equal(NewSigner(key).KeyID(), signer.KeyID())
+		})
+	}
+
+	_, err = LoadSigner(filepath.Join(dir, "missing.pem"))
+	s.Error(err)
+}
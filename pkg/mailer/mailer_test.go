@@ -0,0 +1,65 @@
+package mailer
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"boilerplate/internal/testutil"
+	"boilerplate/pkg/logger"
+	"boilerplate/pkg/queue"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildMessage(t *testing.T) {
+	raw, err := buildMessage("noreply@example.com", Message{
+		To:      "user@example.com",
+		Subject: "Login baru",
+		Body:    "baris 1\nbaris 2",
+	})
+	require.NoError(t, err)
+
+	header, body, found := strings.Cut(string(raw), "\r\n\r\n")
+	require.True(t, found)
+	assert.Contains(t, header, "From: noreply@example.com\r\n")
+	assert.Contains(t, header, "To: user@example.com\r\n")
+	assert.Contains(t, header, "Subject: Login baru\r\n")
+	assert.Equal(t, "baris 1\r\nbaris 2", body)
+}
+
+func TestBuildMessageRejectsHeaderInjection(t *testing.T) {
+	_, err := buildMessage("noreply@example.com", Message{
+		To:      "user@example.com\r\nBcc: victim@example.com",
+		Subject: "hi",
+	})
+	assert.Error(t, err)
+}
+
+// recordingMailer menangkap email yang dikirim handler job
+type recordingMailer struct {
+	sent []Message
+}
+
+func (m *recordingMailer) Send(_ context.Context, msg Message) error {
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+func TestQueueMailerDeliversThroughWorker(t *testing.T) {
+	jobs := queue.New(testutil.NewRedisClient(t).Client(), queue.Config{})
+
+	delivery := &recordingMailer{}
+	worker := queue.NewWorker(jobs, logger.NewLogger())
+	RegisterJobs(worker, delivery)
+
+	msg := Message{To: "user@example.com", Subject: "Login baru", Body: "halo"}
+	require.NoError(t, NewQueueMailer(jobs).Send(context.Background(), msg))
+	assert.Empty(t, delivery.sent)
+
+	processed, err := worker.ProcessNext(context.Background())
+	require.NoError(t, err)
+	assert.True(t, processed)
+	assert.Equal(t, []Message{msg}, delivery.sent)
+}
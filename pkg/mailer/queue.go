@@ -0,0 +1,32 @@
+package mailer
+
+import (
+	"context"
+
+	"boilerplate/pkg/queue"
+)
+
+// SendJob adalah tipe job pengiriman email
+const SendJob = "mail.send"
+
+// QueueMailer mengantrikan email sebagai job sehingga request tidak menunggu SMTP.
+// Email dikirim oleh command worker lewat handler yang didaftarkan RegisterJobs.
+type QueueMailer struct {
+	queue *queue.Queue
+}
+
+func NewQueueMailer(queue *queue.Queue) *QueueMailer {
+	return &QueueMailer{queue: queue}
+}
+
+func (m *QueueMailer) Send(ctx context.Context, msg Message) error {
+	_, err := m.queue.Enqueue(ctx, SendJob, msg)
+	return err
+}
+
+// RegisterJobs mendaftarkan handler job email yang mengirim lewat delivery
+func RegisterJobs(worker *queue.Worker, delivery Mailer) {
+	queue.Register(worker, SendJob, func(ctx context.Context, msg Message) error {
+		return delivery.Send(ctx, msg)
+	})
+}
@@ -2,7 +2,7 @@ package middleware
 
 import (
 	"boilerplate/internal/user/model"
-	"boilerplate/pkg/response"
+	errs "boilerplate/shared/errors"
 
 	"github.com/labstack/echo/v4"
 )
@@ -12,20 +12,20 @@ func AdminMiddleware() echo.MiddlewareFunc {
 		return func(c echo.Context) error {
 			userObj := c.Get("user")
 			if userObj == nil {
-				return response.Unauthorized(c, "unauthorized", nil)
+				return errs.ErrUnauthorized
 			}
 
 			user, ok := userObj.(*model.User)
 			if !ok {
-				return response.Unauthorized(c, "invalid user data", nil)
+				return errs.ErrUnauthorized
 			}
 
 			if user == nil {
-				return response.Unauthorized(c, "user not found", nil)
+				return errs.ErrUserNotFound
 			}
 
 			if !user.IsAdmin() {
-				return response.Forbidden(c, "access denied: admin role required", nil)
+				return errs.ErrAdminRequired
 			}
 
 			return next(c)
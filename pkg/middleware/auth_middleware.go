@@ -4,63 +4,187 @@ import (
 	"context"
 	"strings"
 
+	"boilerplate/internal/apikey"
 	service "boilerplate/internal/user"
+	userModel "boilerplate/internal/user/model"
 	"boilerplate/pkg/jwt"
-	"boilerplate/pkg/response"
+	"boilerplate/pkg/logger"
+	errs "boilerplate/shared/errors"
 
 	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
 )
 
-func AuthMiddleware(userService service.UserServiceInterface, jwtSecret string) echo.MiddlewareFunc {
+// APIKeyHeader adalah header alternatif untuk mengirim API key
+const APIKeyHeader = "X-API-Key"
+
+// passwordResetExempt adalah route yang tetap boleh diakses selama user
+// diwajibkan mengganti password oleh admin
+var passwordResetExempt = map[string]bool{
+	"GET /admin/v1/user/me":     true,
+	"PUT /admin/v1/user/update": true,
+	"PATCH /admin/v1/user/me":   true,
+	"POST /logout":              true,
+}
+
+// impersonationBlocked adalah route sensitif yang ditolak untuk sesi impersonasi.
+// Penggantian password lewat PUT/PATCH profil ditolak di handler user.
+var impersonationBlocked = map[string]bool{
+	"DELETE /admin/v1/user/delete":              true,
+	"POST /admin/v1/user/me/export":             true,
+	"GET /admin/v1/user/me/export/:id/download": true,
+	"POST /admin/v1/api-keys":                   true,
+	"PUT /admin/v1/api-keys/:id":                true,
+	"GET /authorize":                            true,
+	"POST /authorize":                           true,
+}
+
+// AuthMiddleware menerima Bearer JWT, "Authorization: ApiKey <key>" atau header X-API-Key.
+// Token impersonasi menyimpan user target di "user"/"user_id" dan admin di "actor"/"actor_id".
+func AuthMiddleware(userService service.UserServiceInterface, apiKeyService apikey.APIKeyServiceInterface, jwtSecret string, log logger.Logger) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
+			if rawKey := c.Request().Header.Get(APIKeyHeader); rawKey != "" {
+				return authenticateAPIKey(c, next, userService, apiKeyService, rawKey)
+			}
+
 			authHeader := c.Request().Header.Get("Authorization")
 			if authHeader == "" {
-				return response.Unauthorized(c, "authorization header is required", nil)
+				return errs.ErrMissingAuthorization
 			}
 
 			parts := strings.Split(authHeader, " ")
-			if len(parts) != 2 || parts[0] != "Bearer" {
-				return response.Unauthorized(c, "invalid authorization header format", nil)
+			if len(parts) != 2 {
+				return errs.ErrInvalidAuthorization
+			}
+
+			switch parts[0] {
+			case "Bearer":
+			case "ApiKey":
+				return authenticateAPIKey(c, next, userService, apiKeyService, parts[1])
+			default:
+				return errs.ErrInvalidAuthorization
 			}
 
 			tokenString := parts[1]
 			claims, err := jwt.ValidateToken(tokenString, jwtSecret)
 			if err != nil {
-				return response.Unauthorized(c, "invalid token", err)
+				return errs.ErrInvalidToken.Wrap(err)
 			}
 
 			// Periksa token di Redis
 			ctx := context.Background()
-			storedToken, err := userService.GetStoredToken(ctx, claims.UserID)
+			var storedToken string
+			if claims.IsImpersonation() {
+				storedToken, err = userService.GetImpersonationToken(ctx, claims.ID)
+			} else {
+				storedToken, err = userService.GetStoredToken(ctx, claims.UserID)
+			}
 			if err != nil {
 				// Jika token tidak ditemukan di Redis, berarti user sudah logout
-				return response.Unauthorized(c, "token has been revoked or expired", nil)
+				return errs.ErrTokenRevoked.Wrap(err)
 			}
 
 			if storedToken != tokenString {
 				// Jika token berbeda, berarti user menggunakan token yang tidak valid
-				return response.Unauthorized(c, "token is invalid or has been revoked", nil)
+				return errs.ErrTokenRevoked
 			}
 
 			user, err := userService.GetUserByID(claims.UserID)
 			if err != nil {
-				return response.Unauthorized(c, "user not found", err)
+				return errs.ErrUnauthorized.Wrap(err)
 			}
 
 			if user == nil {
-				return response.Unauthorized(c, "user not found", nil)
+				return errs.ErrUnauthorized
+			}
+
+			if err := checkAccount(c, user); err != nil {
+				return err
 			}
 
 			// Pastikan user adalah pointer yang valid sebelum disimpan ke context
-			if user != nil {
-				c.Set("user", user)
-				c.Set("user_id", claims.UserID)
-			} else {
-				return response.Unauthorized(c, "invalid user data", nil)
+			c.Set("user", user)
+			c.Set("user_id", claims.UserID)
+
+			if claims.IsImpersonation() {
+				return impersonate(c, next, userService, claims, log)
 			}
 
 			return next(c)
 		}
 	}
 }
+
+// authenticateAPIKey memvalidasi API key lalu menyimpan pemilik key dan key itu sendiri ke context
+func authenticateAPIKey(c echo.Context, next echo.HandlerFunc, userService service.UserServiceInterface, apiKeyService apikey.APIKeyServiceInterface, rawKey string) error {
+	key, err := apiKeyService.Authenticate(rawKey, c.RealIP())
+	if err != nil {
+		return err
+	}
+
+	user, err := userService.GetUserByID(key.UserID)
+	if err != nil {
+		return errs.ErrInvalidAPIKey.Wrap(err)
+	}
+
+	if err := checkAccount(c, user); err != nil {
+		return err
+	}
+
+	c.Set("user", user)
+	c.Set("user_id", key.UserID)
+	c.Set("api_key", key)
+
+	return next(c)
+}
+
+// checkAccount menolak akun yang dinonaktifkan admin dan membatasi akun yang
+// wajib mengganti password ke route pada passwordResetExempt
+func checkAccount(c echo.Context, user *userModel.User) error {
+	if user.IsDisabled() {
+		return errs.ErrAccountDisabled
+	}
+	if user.PasswordResetRequired && !passwordResetExempt[c.Request().Method+" "+c.Path()] {
+		return errs.ErrPasswordResetRequired
+	}
+	return nil
+}
+
+// impersonate memastikan admin pada claim "act" masih aktif, menolak route sensitif
+// dan mencatat setiap request yang dilakukan atas nama user
+func impersonate(c echo.Context, next echo.HandlerFunc, userService service.UserServiceInterface, claims *jwt.Claims, log logger.Logger) error {
+	actor, err := userService.GetUserByID(claims.Act.UserID)
+	if err != nil {
+		return errs.ErrTokenRevoked.Wrap(err)
+	}
+	if !actor.IsAdmin() || actor.IsDisabled() {
+		return errs.ErrTokenRevoked
+	}
+
+	c.Set("actor", actor)
+	c.Set("actor_id", actor.ID)
+	c.Set("impersonation_id", claims.ID)
+
+	req := c.Request()
+	fields := logrus.Fields{
+		"actor_id": actor.ID,
+		"user_id":  claims.UserID,
+		"token_id": claims.ID,
+		"method":   req.Method,
+		"path":     c.Path(),
+	}
+
+	if impersonationBlocked[req.Method+" "+c.Path()] {
+		log.WithFields(fields).Warn("Request impersonasi ke route sensitif ditolak")
+		return errs.ErrImpersonationForbidden
+	}
+
+	err = next(c)
+	fields["status"] = c.Response().Status
+	if err != nil {
+		fields["error"] = err.Error()
+	}
+	log.WithFields(fields).Info("Request dilakukan dalam sesi impersonasi")
+	return err
+}
@@ -0,0 +1,152 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+
+	"boilerplate/pkg/logger"
+	"boilerplate/pkg/openapi"
+	"boilerplate/shared/constants"
+	errs "boilerplate/shared/errors"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// ContractModeEnforce menolak request/response yang melanggar kontrak
+	ContractModeEnforce = "enforce"
+	// ContractModeLog hanya mencatat pelanggaran kontrak
+	ContractModeLog = "log"
+	// ContractModeOff mematikan validasi kontrak
+	ContractModeOff = "off"
+)
+
+// ContractMiddleware memvalidasi parameter path dan query, body request dan body
+// response JSON terhadap dokumen OpenAPI. Route yang tidak ada di dokumen diteruskan
+// apa adanya. Body request dibaca sampai ContractMaxBodySize; body yang lebih besar
+// ditolak pada mode enforce dan diteruskan tanpa validasi pada mode log. Body operation
+// yang menerima file (mis. import) diteruskan ke handler tanpa dibaca sehingga handler
+// memakai batas ukurannya sendiri.
+func ContractMiddleware(validator *openapi.Validator, mode string, log logger.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		if mode == ContractModeOff {
+			return next
+		}
+
+		return func(c echo.Context) error {
+			req := c.Request()
+			op, ok := validator.Operation(req.Method, c.Path())
+			if !ok {
+				return next(c)
+			}
+
+			var body []byte
+			validateBody := true
+			if !op.AcceptsFiles() && !openapi.IsFileType(req.Header.Get(echo.HeaderContentType)) {
+				var err error
+				body, err = io.ReadAll(io.LimitReader(req.Body, constants.ContractMaxBodySize+1))
+				if err != nil {
+					return errs.ErrInvalidPayload.Wrap(err)
+				}
+				if len(body) > constants.ContractMaxBodySize {
+					if mode == ContractModeEnforce {
+						return errs.ErrPayloadTooLarge
+					}
+					log.WithFields(logrus.Fields{
+						"method": req.Method,
+						"path":   c.Path(),
+					}).Warn("Body request melebihi batas validasi kontrak, body tidak divalidasi")
+					validateBody = false
+					req.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), req.Body))
+				} else {
+					req.Body = io.NopCloser(bytes.NewReader(body))
+				}
+			}
+
+			params := make(map[string]string)
+			for i, name := range c.ParamNames() {
+				params[name] = c.ParamValues()[i]
+			}
+
+			if validateBody {
+				if violations := op.ValidateRequest(params, req.URL.Query(), req.Header.Get(echo.HeaderContentType), body); len(violations) > 0 {
+					log.WithFields(logrus.Fields{
+						"method":     req.Method,
+						"path":       c.Path(),
+						"violations": violations,
+					}).Warn("Request melanggar kontrak OpenAPI")
+					if mode == ContractModeEnforce {
+						return errs.ErrContractViolation.WithDetails(map[string]interface{}{"violations": violations})
+					}
+				}
+			}
+
+			res := c.Response()
+			capture := &captureWriter{ResponseWriter: res.Writer, status: http.StatusOK}
+			res.Writer = capture
+			err := next(c)
+			res.Writer = capture.ResponseWriter
+
+			if capture.passthrough || !res.Committed {
+				return err
+			}
+
+			if violations := op.ValidateResponse(capture.status, res.Header().Get(echo.HeaderContentType), capture.body.Bytes()); len(violations) > 0 {
+				log.WithFields(logrus.Fields{
+					"method":     req.Method,
+					"path":       c.Path(),
+					"status":     capture.status,
+					"violations": violations,
+				}).Error("Response melanggar kontrak OpenAPI")
+				if mode == ContractModeEnforce {
+					// buang response asli agar error handler dapat menulis problem+json
+					for key := range res.Header() {
+						res.Header().Del(key)
+					}
+					res.Committed = false
+					res.Size = 0
+					return errs.ErrResponseContractViolation.WithDetails(map[string]interface{}{"violations": violations})
+				}
+			}
+
+			capture.ResponseWriter.WriteHeader(capture.status)
+			if _, writeErr := capture.ResponseWriter.Write(capture.body.Bytes()); writeErr != nil {
+				return writeErr
+			}
+			return err
+		}
+	}
+}
+
+// captureWriter menahan response JSON agar dapat divalidasi sebelum dikirim.
+// Response selain JSON (file, stream) langsung diteruskan ke client.
+type captureWriter struct {
+	http.ResponseWriter
+	status      int
+	body        bytes.Buffer
+	passthrough bool
+}
+
+func (w *captureWriter) WriteHeader(code int) {
+	w.status = code
+	if !strings.Contains(w.Header().Get(echo.HeaderContentType), "json") && code != http.StatusNotModified && code != http.StatusNoContent {
+		w.passthrough = true
+		w.ResponseWriter.WriteHeader(code)
+	}
+}
+
+func (w *captureWriter) Write(b []byte) (int, error) {
+	if w.passthrough {
+		return w.ResponseWriter.Write(b)
+	}
+	return w.body.Write(b)
+}
+
+func (w *captureWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok && w.passthrough {
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,192 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"boilerplate/pkg/logger"
+	"boilerplate/pkg/openapi"
+	"boilerplate/shared/constants"
+	errs "boilerplate/shared/errors"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/suite"
+)
+
+type contractInput struct {
+	Name string `json:"name" validate:"required,min=2"`
+}
+
+type contractOutput struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name" validate:"required"`
+}
+
+type contractEnvelope struct {
+	Status string      `json:"status"`
+	Data   interface{} `json:"data,omitempty"`
+}
+
+type ContractMiddlewareTestSuite struct {
+	suite.Suite
+	validator *openapi.Validator
+	response  string
+	received  []byte
+	calls     int
+	err       error
+}
+
+func TestContractMiddlewareSuite(t *testing.T) {
+	suite.Run(t, new(ContractMiddlewareTestSuite))
+}
+
+func (s *ContractMiddlewareTestSuite) SetupTest() {
+	e := s.routes(echo.New())
+	registry := openapi.NewRegistry(openapi.Options{Envelope: contractEnvelope{}, EnvelopeDataField: "data"})
+	registry.Add(http.MethodPut, "/widgets/:id", openapi.Route{Request: contractInput{}, Response: contractOutput{}})
+	registry.Add(http.MethodPost, "/files", openapi.Route{Request: []byte{}, RequestTypes: []string{"text/csv"}})
+	registry.Add(http.MethodPost, "/imports", openapi.Route{Request: []contractInput{}, RequestTypes: []string{openapi.JSONContentType, "text/csv"}, Response: contractOutput{}})
+
+	// route tanpa dokumentasi tetap terdaftar di server tapi tidak ada di dokumen
+	var documented []*echo.Route
+	for _, route := range e.Routes() {
+		if route.Path != "/undocumented" {
+			documented = append(documented, route)
+		}
+	}
+	doc, err := registry.Build(documented)
+	s.Require().NoError(err)
+	s.validator, err = openapi.NewValidator(doc)
+	s.Require().NoError(err)
+
+	s.response = `{"status":"success","data":{"id":1,"name":"Widget"}}`
+	s.received = nil
+	s.calls = 0
+	s.err = nil
+}
+
+func (s *ContractMiddlewareTestSuite) routes(e *echo.Echo) *echo.Echo {
+	handler := func(c echo.Context) error {
+		s.calls++
+		body, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			return err
+		}
+		s.received = body
+		return c.JSONBlob(http.StatusOK, []byte(s.response))
+	}
+	e.PUT("/widgets/:id", handler)
+	e.POST("/files", handler)
+	e.POST("/imports", handler)
+	e.POST("/undocumented", handler)
+	return e
+}
+
+func (s *ContractMiddlewareTestSuite) do(mode, method, path, contentType, body string) *httptest.ResponseRecorder {
+	e := echo.New()
+	e.HTTPErrorHandler = func(err error, c echo.Context) {
+		s.err = err
+		_ = c.NoContent(http.StatusTeapot)
+	}
+	e.Use(ContractMiddleware(s.validator, mode, logger.NewLogger()))
+	s.routes(e)
+
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, contentType)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func (s *ContractMiddlewareTestSuite) TestInvalidRequestByMode() {
+	tests := []struct {
+		mode        string
+		expectedErr error
+		calls       int
+	}{
+		{mode: ContractModeEnforce, expectedErr: errs.ErrContractViolation, calls: 0},
+		{mode: ContractModeLog, calls: 1},
+		{mode: ContractModeOff, calls: 1},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.mode, func() {
+			s.SetupTest()
+			s.do(tt.mode, http.MethodPut, "/widgets/1", echo.MIMEApplicationJSON, `{"name":"x"}`)
+			if tt.expectedErr != nil {
+				s.ErrorIs(s.err, tt.expectedErr)
+			} else {
+				s.NoError(s.err)
+				s.JSONEq(`{"name":"x"}`, string(s.received))
+			}
+			s.Equal(tt.calls, s.calls)
+		})
+	}
+}
+
+func (s *ContractMiddlewareTestSuite) TestInvalidResponseByMode() {
+	s.response = `{"status":"success","data":{"id":1}}`
+
+	rec := s.do(ContractModeEnforce, http.MethodPut, "/widgets/1", echo.MIMEApplicationJSON, `{"name":"Widget"}`)
+	s.ErrorIs(s.err, errs.ErrResponseContractViolation)
+	s.Equal(http.StatusTeapot, rec.Code)
+
+	s.err = nil
+	rec = s.do(ContractModeLog, http.MethodPut, "/widgets/1", echo.MIMEApplicationJSON, `{"name":"Widget"}`)
+	s.NoError(s.err)
+	s.Equal(http.StatusOK, rec.Code)
+	s.JSONEq(s.response, rec.Body.String())
+}
+
+func (s *ContractMiddlewareTestSuite) TestUndocumentedRoutePassesThrough() {
+	rec := s.do(ContractModeEnforce, http.MethodPost, "/undocumented", echo.MIMEApplicationJSON, `{"anything":true}`)
+	s.NoError(s.err)
+	s.Equal(http.StatusOK, rec.Code)
+	s.Equal(`{"anything":true}`, string(s.received))
+}
+
+func (s *ContractMiddlewareTestSuite) TestBodySizeLimit() {
+	large := `{"name":"` + strings.Repeat("a", constants.ContractMaxBodySize) + `"}`
+
+	s.do(ContractModeEnforce, http.MethodPut, "/widgets/1", echo.MIMEApplicationJSON, large)
+	s.ErrorIs(s.err, errs.ErrPayloadTooLarge)
+	s.Zero(s.calls)
+
+	// mode log tidak menolak request, body diteruskan utuh tanpa divalidasi
+	s.SetupTest()
+	rec := s.do(ContractModeLog, http.MethodPut, "/widgets/1", echo.MIMEApplicationJSON, large)
+	s.NoError(s.err)
+	s.Equal(http.StatusOK, rec.Code)
+	s.Equal(1, s.calls)
+	s.Equal(large, string(s.received))
+}
+
+func (s *ContractMiddlewareTestSuite) TestLargeJSONImportIsStreamedUnread() {
+	row := `{"name":"` + strings.Repeat("a", 1000) + `"},`
+	large := "[" + strings.Repeat(row, constants.ContractMaxBodySize/len(row)+1) + `{"name":"Books"}]`
+	s.Greater(len(large), constants.ContractMaxBodySize)
+
+	rec := s.do(ContractModeEnforce, http.MethodPost, "/imports", echo.MIMEApplicationJSON, large)
+	s.NoError(s.err)
+	s.Equal(http.StatusOK, rec.Code)
+	s.Equal(large, string(s.received))
+
+	// Content-Type yang tidak didokumentasikan tetap ditolak tanpa membaca body
+	s.SetupTest()
+	s.do(ContractModeEnforce, http.MethodPost, "/imports", "application/zip", "PK")
+	s.ErrorIs(s.err, errs.ErrContractViolation)
+	s.Zero(s.calls)
+}
+
+func (s *ContractMiddlewareTestSuite) TestFileBodyIsStreamedUnread() {
+	file := strings.Repeat("name\nBooks\n", constants.ContractMaxBodySize/10)
+
+	rec := s.do(ContractModeEnforce, http.MethodPost, "/files", "text/csv", file)
+	s.NoError(s.err)
+	s.Equal(http.StatusOK, rec.Code)
+	s.True(bytes.Equal([]byte(file), s.received))
+}
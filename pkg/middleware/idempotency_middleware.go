@@ -0,0 +1,145 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"boilerplate/pkg/idempotency"
+	"boilerplate/pkg/logger"
+	errs "boilerplate/shared/errors"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// IdempotencyKeyHeader adalah header yang dikirim client untuk request yang aman dicoba ulang
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader menandai response yang diambil dari penyimpanan, bukan diproses ulang
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+// replaySkippedHeaders tidak disimpan karena dihitung ulang saat replay
+var replaySkippedHeaders = map[string]bool{
+	echo.HeaderContentLength: true,
+	"Date":                   true,
+	echo.HeaderSetCookie:     true,
+}
+
+// IdempotencyMiddleware menyimpan response pertama request dengan header Idempotency-Key
+// dan mengirim ulang response tersebut untuk request berikutnya dengan key, user dan
+// payload yang sama. Request dengan key yang masih diproses ditolak 409; key yang dipakai
+// untuk payload lain ditolak 422. Request tanpa header diproses seperti biasa.
+//
+// Hanya response sukses dan 4xx yang ditulis handler yang disimpan; error dan 5xx
+// melepas key sehingga client dapat mencoba ulang dengan key yang sama. Pasang setelah
+// AuthMiddleware agar key dipisahkan per user.
+func IdempotencyMiddleware(store *idempotency.Store, log logger.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			rawKey := c.Request().Header.Get(IdempotencyKeyHeader)
+			if rawKey == "" {
+				return next(c)
+			}
+			if len(rawKey) > idempotency.MaxKeyLength {
+				return errs.ErrInvalidIdempotencyKey
+			}
+
+			req := c.Request()
+			body, err := io.ReadAll(req.Body)
+			if err != nil {
+				return errs.ErrInvalidPayload.Wrap(err)
+			}
+			req.Body = io.NopCloser(bytes.NewReader(body))
+
+			scope := "anonymous"
+			if userID, ok := c.Get("user_id").(uint); ok {
+				scope = fmt.Sprintf("user:%d", userID)
+			}
+			key := idempotency.Key(scope, rawKey)
+			fingerprint := idempotency.Fingerprint(req.Method, req.URL.Path, body)
+
+			record, err := store.Begin(req.Context(), key, fingerprint)
+			if err != nil {
+				return err
+			}
+			if record != nil {
+				if record.Fingerprint != fingerprint {
+					return errs.ErrIdempotencyKeyReused
+				}
+				if !record.Completed {
+					return errs.ErrIdempotencyInProgress
+				}
+				return replay(c, record)
+			}
+
+			res := c.Response()
+			recorder := &responseRecorder{ResponseWriter: res.Writer}
+			res.Writer = recorder
+			err = next(c)
+			res.Writer = recorder.ResponseWriter
+
+			fields := logrus.Fields{"method": req.Method, "path": c.Path()}
+			// context request dapat sudah dibatalkan saat client memutus koneksi
+			ctx, cancel := context.WithTimeout(context.WithoutCancel(req.Context()), 5*time.Second)
+			defer cancel()
+
+			if err != nil || !res.Committed || res.Status >= http.StatusInternalServerError {
+				if releaseErr := store.Release(ctx, key); releaseErr != nil {
+					log.WithFields(fields).WithField("error", releaseErr.Error()).Error("Gagal melepas idempotency key")
+				}
+				return err
+			}
+
+			header := make(http.Header)
+			for name, values := range res.Header() {
+				if !replaySkippedHeaders[name] {
+					header[name] = values
+				}
+			}
+			if storeErr := store.Complete(ctx, key, idempotency.Record{
+				Fingerprint: fingerprint,
+				Status:      res.Status,
+				Header:      header,
+				Body:        recorder.body.Bytes(),
+				CreatedAt:   time.Now(),
+			}); storeErr != nil {
+				log.WithFields(fields).WithField("error", storeErr.Error()).Error("Gagal menyimpan response idempotency")
+			}
+			return nil
+		}
+	}
+}
+
+func replay(c echo.Context, record *idempotency.Record) error {
+	res := c.Response()
+	for name, values := range record.Header {
+		res.Header()[name] = values
+	}
+	res.Header().Set(IdempotentReplayedHeader, strconv.FormatBool(true))
+	res.WriteHeader(record.Status)
+	_, err := res.Write(record.Body)
+	return err
+}
+
+// responseRecorder meneruskan response ke client sambil menyalin body-nya
+type responseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,168 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"boilerplate/pkg/idempotency"
+	"boilerplate/pkg/logger"
+	errs "boilerplate/shared/errors"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/suite"
+)
+
+type IdempotencyMiddlewareTestSuite struct {
+	suite.Suite
+	e      *echo.Echo
+	redis  *miniredis.Miniredis
+	calls  atomic.Int32
+	status int
+	fail   error
+	block  chan struct{}
+	mw     echo.MiddlewareFunc
+}
+
+func TestIdempotencyMiddlewareSuite(t *testing.T) {
+	suite.Run(t, new(IdempotencyMiddlewareTestSuite))
+}
+
+func (s *IdempotencyMiddlewareTestSuite) SetupTest() {
+	s.e = echo.New()
+	s.redis = miniredis.RunT(s.T())
+	client := redis.NewClient(&redis.Options{Addr: s.redis.Addr()})
+	s.mw = IdempotencyMiddleware(idempotency.NewStore(client, time.Hour), logger.NewLogger())
+	s.calls.Store(0)
+	s.status = http.StatusCreated
+	s.fail = nil
+	s.block = nil
+}
+
+func (s *IdempotencyMiddlewareTestSuite) handler(c echo.Context) error {
+	n := s.calls.Add(1)
+	if s.block != nil {
+		<-s.block
+	}
+	if s.fail != nil {
+		return s.fail
+	}
+	c.Response().Header().Set("Location", "/categories/1")
+	return c.JSON(s.status, map[string]interface{}{"call": n})
+}
+
+func (s *IdempotencyMiddlewareTestSuite) do(key, body string, userID uint) (*httptest.ResponseRecorder, error) {
+	req := httptest.NewRequest(http.MethodPost, "/admin/v1/categories", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	rec := httptest.NewRecorder()
+	c := s.e.NewContext(req, rec)
+	if userID != 0 {
+		c.Set("user_id", userID)
+	}
+	return rec, s.mw(s.handler)(c)
+}
+
+func (s *IdempotencyMiddlewareTestSuite) TestReplaysFirstResponse() {
+	first, err := s.do("key-1", `{"name":"a"}`, 1)
+	s.Require().NoError(err)
+	s.Equal(http.StatusCreated, first.Code)
+	s.Empty(first.Header().Get(IdempotentReplayedHeader))
+
+	second, err := s.do("key-1", `{"name":"a"}`, 1)
+	s.Require().NoError(err)
+	s.Equal(http.StatusCreated, second.Code)
+	s.Equal(first.Body.String(), second.Body.String())
+	s.Equal("/categories/1", second.Header().Get("Location"))
+	s.Equal("true", second.Header().Get(IdempotentReplayedHeader))
+	s.Equal(int32(1), s.calls.Load())
+}
+
+func (s *IdempotencyMiddlewareTestSuite) TestRequestsWithoutKeyAreNotDeduplicated() {
+	for i := 0; i < 2; i++ {
+		_, err := s.do("", `{"name":"a"}`, 1)
+		s.Require().NoError(err)
+	}
+	s.Equal(int32(2), s.calls.Load())
+}
+
+func (s *IdempotencyMiddlewareTestSuite) TestKeysAreScopedPerUser() {
+	_, err := s.do("key-1", `{"name":"a"}`, 1)
+	s.Require().NoError(err)
+	rec, err := s.do("key-1", `{"name":"a"}`, 2)
+	s.Require().NoError(err)
+	s.Empty(rec.Header().Get(IdempotentReplayedHeader))
+	s.Equal(int32(2), s.calls.Load())
+}
+
+func (s *IdempotencyMiddlewareTestSuite) TestKeyReusedWithDifferentPayload() {
+	_, err := s.do("key-1", `{"name":"a"}`, 1)
+	s.Require().NoError(err)
+
+	_, err = s.do("key-1", `{"name":"b"}`, 1)
+	s.ErrorIs(err, errs.ErrIdempotencyKeyReused)
+	s.Equal(int32(1), s.calls.Load())
+}
+
+func (s *IdempotencyMiddlewareTestSuite) TestConcurrentDuplicateIsRejected() {
+	s.block = make(chan struct{})
+	done := make(chan error)
+	go func() {
+		_, err := s.do("key-1", `{"name":"a"}`, 1)
+		done <- err
+	}()
+	s.Eventually(func() bool { return s.calls.Load() == 1 }, time.Second, 5*time.Millisecond)
+
+	_, err := s.do("key-1", `{"name":"a"}`, 1)
+	s.ErrorIs(err, errs.ErrIdempotencyInProgress)
+	_, err = s.do("key-1", `{"name":"b"}`, 1)
+	s.ErrorIs(err, errs.ErrIdempotencyKeyReused)
+
+	close(s.block)
+	s.Require().NoError(<-done)
+	s.block = nil
+	rec, err := s.do("key-1", `{"name":"a"}`, 1)
+	s.Require().NoError(err)
+	s.Equal("true", rec.Header().Get(IdempotentReplayedHeader))
+}
+
+func (s *IdempotencyMiddlewareTestSuite) TestFailedRequestReleasesKey() {
+	s.fail = errs.ErrInternal
+	_, err := s.do("key-1", `{"name":"a"}`, 1)
+	s.ErrorIs(err, errs.ErrInternal)
+
+	s.fail = nil
+	s.status = http.StatusServiceUnavailable
+	_, err = s.do("key-1", `{"name":"a"}`, 1)
+	s.Require().NoError(err)
+
+	s.status = http.StatusCreated
+	rec, err := s.do("key-1", `{"name":"a"}`, 1)
+	s.Require().NoError(err)
+	s.Equal(http.StatusCreated, rec.Code)
+	s.Empty(rec.Header().Get(IdempotentReplayedHeader))
+	s.Equal(int32(3), s.calls.Load())
+}
+
+func (s *IdempotencyMiddlewareTestSuite) TestResponseExpiresAfterTTL() {
+	_, err := s.do("key-1", `{"name":"a"}`, 1)
+	s.Require().NoError(err)
+
+	s.redis.FastForward(time.Hour + time.Second)
+	rec, err := s.do("key-1", `{"name":"b"}`, 1)
+	s.Require().NoError(err)
+	s.Empty(rec.Header().Get(IdempotentReplayedHeader))
+}
+
+func (s *IdempotencyMiddlewareTestSuite) TestKeyTooLong() {
+	_, err := s.do(strings.Repeat("k", idempotency.MaxKeyLength+1), `{}`, 1)
+	s.ErrorIs(err, errs.ErrInvalidIdempotencyKey)
+	s.Zero(s.calls.Load())
+}
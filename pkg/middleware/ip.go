@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// NewIPExtractor menentukan IP client yang dipakai c.RealIP(), mis. untuk allowlist
+// API key dan riwayat login. Tanpa trusted proxy, IP diambil dari koneksi langsung
+// dan header X-Forwarded-For/X-Real-IP diabaikan karena dapat dipalsukan client.
+// Dengan trusted proxy (IP atau CIDR), X-Forwarded-For hanya dipercaya sampai hop
+// pertama yang bukan proxy tersebut.
+func NewIPExtractor(trustedProxies []string) (echo.IPExtractor, error) {
+	if len(trustedProxies) == 0 {
+		return echo.ExtractIPDirect(), nil
+	}
+
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+	for _, proxy := range trustedProxies {
+		ipRange, err := parseRange(proxy)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, echo.TrustIPRange(ipRange))
+	}
+	return echo.ExtractIPFromXFFHeader(options...), nil
+}
+
+// parseRange menerima CIDR atau satu IP
+func parseRange(value string) (*net.IPNet, error) {
+	if strings.Contains(value, "/") {
+		_, ipRange, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, fmt.Errorf("trusted proxy %q: %w", value, err)
+		}
+		return ipRange, nil
+	}
+
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return nil, fmt.Errorf("trusted proxy %q: invalid IP", value)
+	}
+	bits := 128
+	if ip.To4() != nil {
+		ip = ip.To4()
+		bits = 32
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+}
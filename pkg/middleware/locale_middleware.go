@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"boilerplate/pkg/i18n"
+
+	"github.com/labstack/echo/v4"
+)
+
+// LocaleMiddleware memilih locale dari header Accept-Language dan menyimpannya di context
+func LocaleMiddleware(translator *i18n.Translator) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			locale := translator.Match(c.Request().Header.Get("Accept-Language"))
+			c.Set(i18n.ContextKey, locale)
+			c.Response().Header().Set("Content-Language", locale)
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+
+	"boilerplate/internal/apikey/model"
+	errs "boilerplate/shared/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ScopeMiddleware mewajibkan scope "<resource>:read" untuk method aman dan
+// "<resource>:write" untuk method lain. Hanya berlaku untuk request dengan API key;
+// sesi JWT tetap diatur oleh role user.
+func ScopeMiddleware(resource string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key, ok := c.Get("api_key").(*model.APIKey)
+			if !ok {
+				return next(c)
+			}
+
+			scope := resource + ":write"
+			switch c.Request().Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				scope = resource + ":read"
+			}
+
+			if !key.HasScope(scope) {
+				return errs.ErrInsufficientScope.WithDetails(map[string]interface{}{
+					"required_scope": scope,
+				})
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// SessionOnlyMiddleware menolak request yang diautentikasi dengan API key,
+// misalnya untuk endpoint pengelolaan API key itu sendiri
+func SessionOnlyMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if _, ok := c.Get("api_key").(*model.APIKey); ok {
+				return errs.ErrSessionRequired
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+const swaggerUIVersion = "5.17.14"
+
+// JSONHandler melayani dokumen OpenAPI
+func JSONHandler(doc *Document) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return c.JSON(http.StatusOK, doc)
+	}
+}
+
+// UIHandler melayani Swagger UI yang memuat dokumen dari specURL
+func UIHandler(title, specURL string) echo.HandlerFunc {
+	page := fmt.Sprintf(`<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>%[1]s</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@%[3]s/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@%[3]s/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: %[2]q, dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>`, title, specURL, swaggerUIVersion)
+
+	return func(c echo.Context) error {
+		return c.HTML(http.StatusOK, page)
+	}
+}
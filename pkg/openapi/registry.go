@@ -0,0 +1,388 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	JSONContentType       = "application/json"
+	MergePatchContentType = "application/merge-patch+json"
+	JSONPatchContentType  = "application/json-patch+json"
+	FormContentType       = "application/x-www-form-urlencoded"
+
+	bearerSecurity = "bearerAuth"
+	apiKeySecurity = "apiKeyAuth"
+)
+
+// Param mendeskripsikan parameter path atau query
+type Param struct {
+	Name        string
+	In          string
+	Description string
+	Required    bool
+	Schema      *Schema
+}
+
+// Route adalah anotasi dokumentasi untuk satu route Echo
+type Route struct {
+	OperationID string
+	Summary     string
+	Tags        []string
+	Params      []Param
+	// Request adalah DTO body request; nil jika route tidak menerima body
+	Request interface{}
+	// RequestTypes adalah content type body yang diterima, default application/json.
+	// Content type selain JSON dan form, mis. text/csv, didokumentasikan sebagai file
+	// dan isinya tidak divalidasi.
+	RequestTypes []string
+	// Response adalah tipe field data di envelope response; nil jika kosong
+	Response interface{}
+	// Status adalah status sukses, default 200. Status 3xx didokumentasikan sebagai redirect tanpa body
+	Status int
+	// Responses mendokumentasikan status sukses lain beserta tipe field data-nya, mis.
+	// 200 untuk proses yang selesai langsung selain 202 untuk proses yang diantrikan
+	Responses map[int]interface{}
+	Secured   bool
+	// Scopes adalah scope API key yang dibutuhkan; kosong berarti route hanya menerima sesi JWT
+	Scopes []string
+	// Raw menandai response yang tidak dibungkus envelope, mis. endpoint protokol OAuth
+	Raw bool
+	// ResponseTypes mendokumentasikan response berupa file, mis. application/zip.
+	// Isi file tidak memiliki schema sehingga tidak divalidasi.
+	ResponseTypes []string
+	// Errors mendokumentasikan body error application/json per status selain
+	// problem+json, mis. error OAuth (RFC 6749)
+	Errors map[int]interface{}
+	// Hidden menandai route yang terdaftar tapi tidak dimasukkan ke dokumen
+	Hidden bool
+}
+
+// Options mengatur envelope response dan body error yang dipakai semua route
+type Options struct {
+	Info Info
+	// Envelope dibungkus di sekitar setiap response sukses
+	Envelope interface{}
+	// EnvelopeDataField adalah nama field JSON envelope tempat Route.Response diletakkan
+	EnvelopeDataField string
+	// Problem adalah body error application/problem+json
+	Problem interface{}
+}
+
+// Registry menyimpan anotasi route dan membangun dokumen OpenAPI dari
+// route yang benar-benar terdaftar di Echo.
+type Registry struct {
+	options Options
+	routes  map[string]Route
+	order   []string
+}
+
+func NewRegistry(options Options) *Registry {
+	return &Registry{
+		options: options,
+		routes:  make(map[string]Route),
+	}
+}
+
+// Add menambahkan anotasi untuk method dan path Echo (mis. "/categories/:id")
+func (r *Registry) Add(method, path string, route Route) {
+	key := routeKey(method, path)
+	if _, exists := r.routes[key]; !exists {
+		r.order = append(r.order, key)
+	}
+	r.routes[key] = route
+}
+
+// Lookup returns the annotation of an Echo route
+func (r *Registry) Lookup(method, path string) (Route, bool) {
+	route, ok := r.routes[routeKey(method, path)]
+	return route, ok
+}
+
+// Build generates the document for the given Echo routes. It fails when a
+// route has no annotation or an annotation has no matching route, so the
+// document can never drift from the router.
+func (r *Registry) Build(routes []*echo.Route) (*Document, error) {
+	g := newGenerator()
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    r.options.Info,
+		Paths:   make(map[string]*PathItem),
+		Components: Components{
+			SecuritySchemes: map[string]*SecurityScheme{
+				bearerSecurity: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+				apiKeySecurity: {Type: "apiKey", In: "header", Name: "X-API-Key", Description: "Also accepted as \"Authorization: ApiKey <key>\""},
+			},
+		},
+	}
+
+	seen := make(map[string]bool)
+	var undocumented []string
+	for _, route := range routes {
+		if route.Method == echo.RouteNotFound {
+			continue
+		}
+
+		key := routeKey(route.Method, route.Path)
+		annotation, ok := r.routes[key]
+		if !ok {
+			undocumented = append(undocumented, key)
+			continue
+		}
+		seen[key] = true
+		if annotation.Hidden {
+			continue
+		}
+
+		path := Path(route.Path)
+		item, ok := doc.Paths[path]
+		if !ok {
+			item = &PathItem{}
+			doc.Paths[path] = item
+		}
+		(*item)[strings.ToLower(route.Method)] = r.operation(g, route, annotation)
+	}
+
+	var unregistered []string
+	for _, key := range r.order {
+		if !seen[key] {
+			unregistered = append(unregistered, key)
+		}
+	}
+
+	if len(undocumented) > 0 || len(unregistered) > 0 {
+		sort.Strings(undocumented)
+		return nil, fmt.Errorf("openapi: spec drift, undocumented routes %v, documented but unregistered routes %v", undocumented, unregistered)
+	}
+
+	doc.Components.Schemas = g.schemas
+	return doc, nil
+}
+
+func (r *Registry) operation(g *generator, route *echo.Route, annotation Route) *Operation {
+	op := &Operation{
+		OperationID: annotation.OperationID,
+		Summary:     annotation.Summary,
+		Tags:        annotation.Tags,
+		Parameters:  parameters(route.Path, annotation.Params),
+		Responses:   make(map[string]*Response),
+	}
+	if op.OperationID == "" {
+		op.OperationID = operationID(route)
+	}
+
+	if annotation.Request != nil {
+		types := annotation.RequestTypes
+		if len(types) == 0 {
+			types = []string{JSONContentType}
+		}
+
+		body := &RequestBody{Required: true, Content: make(map[string]*MediaType)}
+		for _, contentType := range types {
+			body.Content[contentType] = &MediaType{Schema: requestSchema(g, contentType, annotation.Request)}
+		}
+		op.RequestBody = body
+	}
+
+	status := annotation.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	if status >= http.StatusMultipleChoices && status < http.StatusBadRequest {
+		// Redirect tidak memiliki body, hanya header Location
+		op.Responses[fmt.Sprint(status)] = &Response{
+			Description: http.StatusText(status),
+			Headers: map[string]*Header{
+				"Location": {Schema: &Schema{Type: "string", Format: "uri"}},
+			},
+		}
+	} else if len(annotation.ResponseTypes) > 0 {
+		content := make(map[string]*MediaType)
+		for _, contentType := range annotation.ResponseTypes {
+			schema := &Schema{Type: "string", Format: "binary"}
+			if strings.Contains(contentType, "json") {
+				schema = &Schema{}
+			}
+			content[contentType] = &MediaType{Schema: schema}
+		}
+		op.Responses[fmt.Sprint(status)] = &Response{
+			Description: http.StatusText(status),
+			Content:     content,
+		}
+	} else {
+		op.Responses[fmt.Sprint(status)] = r.jsonResponse(g, status, annotation.Response, annotation.Raw)
+	}
+	for successStatus, body := range annotation.Responses {
+		op.Responses[fmt.Sprint(successStatus)] = r.jsonResponse(g, successStatus, body, annotation.Raw)
+	}
+	for errorStatus, body := range annotation.Errors {
+		op.Responses[fmt.Sprint(errorStatus)] = &Response{
+			Description: http.StatusText(errorStatus),
+			Content: map[string]*MediaType{
+				JSONContentType: {Schema: g.SchemaOf(body)},
+			},
+		}
+	}
+	if r.options.Problem != nil {
+		op.Responses["default"] = &Response{
+			Description: "Problem details (RFC 7807)",
+			Content: map[string]*MediaType{
+				"application/problem+json": {Schema: g.SchemaOf(r.options.Problem)},
+			},
+		}
+	}
+
+	if annotation.Secured {
+		op.Security = []map[string][]string{{bearerSecurity: {}}}
+		if len(annotation.Scopes) > 0 {
+			op.Security = append(op.Security, map[string][]string{apiKeySecurity: annotation.Scopes})
+		}
+	}
+	return op
+}
+
+// jsonResponse returns a JSON response wrapped in the envelope unless raw
+func (r *Registry) jsonResponse(g *generator, status int, data interface{}, raw bool) *Response {
+	schema := r.envelope(g, data)
+	if raw {
+		schema = g.SchemaOf(data)
+	}
+	return &Response{
+		Description: http.StatusText(status),
+		Content: map[string]*MediaType{
+			JSONContentType: {Schema: schema},
+		},
+	}
+}
+
+func (r *Registry) envelope(g *generator, data interface{}) *Schema {
+	if r.options.Envelope == nil {
+		return g.SchemaOf(data)
+	}
+
+	envelope := g.structSchema(indirect(reflect.TypeOf(r.options.Envelope)))
+	if data == nil {
+		delete(envelope.Properties, r.options.EnvelopeDataField)
+	} else {
+		envelope.Properties[r.options.EnvelopeDataField] = g.SchemaOf(data)
+	}
+	return envelope
+}
+
+// requestSchema returns the body schema for a content type. Merge patches
+// accept any subset of fields, and null removes a value (RFC 7396).
+func requestSchema(g *generator, contentType string, request interface{}) *Schema {
+	switch contentType {
+	case MergePatchContentType:
+		full := g.structSchema(indirect(reflect.TypeOf(request)))
+		partial := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+		for name, property := range full.Properties {
+			copied := *property
+			if t, ok := copied.Type.(string); ok {
+				copied.Type = []string{t, "null"}
+			}
+			partial.Properties[name] = &copied
+		}
+		return partial
+	case JSONPatchContentType:
+		return &Schema{
+			Type: "array",
+			Items: &Schema{
+				Type:     "object",
+				Required: []string{"op", "path"},
+				Properties: map[string]*Schema{
+					"op":    {Type: "string", Enum: []interface{}{"add", "remove", "replace", "move", "copy", "test"}},
+					"path":  {Type: "string"},
+					"from":  {Type: "string"},
+					"value": {},
+				},
+			},
+		}
+	default:
+		if IsFileType(contentType) {
+			return &Schema{Type: "string", Format: "binary"}
+		}
+		return g.SchemaOf(request)
+	}
+}
+
+// IsFileType reports whether a body of this content type is an opaque file
+// rather than JSON or form data
+func IsFileType(contentType string) bool {
+	contentType = mediaType(contentType)
+	return contentType != "" && !strings.Contains(contentType, "json") && contentType != FormContentType
+}
+
+func parameters(echoPath string, declared []Param) []*Parameter {
+	byName := make(map[string]Param)
+	for _, p := range declared {
+		byName[p.In+":"+p.Name] = p
+	}
+
+	var params []*Parameter
+	for _, segment := range strings.Split(echoPath, "/") {
+		if !strings.HasPrefix(segment, ":") {
+			continue
+		}
+		name := strings.TrimPrefix(segment, ":")
+		param := &Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "string"}}
+		if p, ok := byName["path:"+name]; ok {
+			param.Description = p.Description
+			if p.Schema != nil {
+				param.Schema = p.Schema
+			}
+		}
+		params = append(params, param)
+	}
+
+	for _, p := range declared {
+		if p.In == "path" {
+			continue
+		}
+		schema := p.Schema
+		if schema == nil {
+			schema = &Schema{Type: "string"}
+		}
+		params = append(params, &Parameter{Name: p.Name, In: p.In, Description: p.Description, Required: p.Required, Schema: schema})
+	}
+	return params
+}
+
+// Path converts an Echo path to an OpenAPI path template
+func Path(echoPath string) string {
+	segments := strings.Split(echoPath, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			segments[i] = "{" + strings.TrimPrefix(segment, ":") + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// operationID menurunkan operationId dari nama handler, mis.
+// "boilerplate/internal/user.(*UserHandler).Register-fm" menjadi "userRegister".
+func operationID(route *echo.Route) string {
+	name := strings.TrimSuffix(route.Name, "-fm")
+	name = name[strings.LastIndex(name, "/")+1:]
+
+	parts := strings.Split(name, ".")
+	if len(parts) >= 3 {
+		receiver := strings.Trim(parts[len(parts)-2], "(*)")
+		receiver = strings.TrimSuffix(receiver, "Handler")
+		if receiver != "" {
+			return strings.ToLower(receiver[:1]) + receiver[1:] + parts[len(parts)-1]
+		}
+	}
+
+	id := strings.ToLower(route.Method) + strings.NewReplacer("/", "_", ":", "", "{", "", "}", "").Replace(route.Path)
+	return strings.TrimSuffix(id, "_")
+}
+
+func routeKey(method, path string) string {
+	return method + " " + path
+}
@@ -0,0 +1,68 @@
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type schemaChild struct {
+	Name string `json:"name"`
+}
+
+type schemaSample struct {
+	Name      string           `json:"name"`
+	Note      *string          `json:"note"`
+	Count     **int            `json:"count"`
+	DeletedAt *time.Time       `json:"deleted_at"`
+	Raw       *json.RawMessage `json:"raw"`
+	Any       *interface{}     `json:"any"`
+	Child     *schemaChild     `json:"child"`
+	Tags      *[]string        `json:"tags"`
+}
+
+type SchemaTestSuite struct {
+	suite.Suite
+	properties map[string]*Schema
+}
+
+func TestSchemaSuite(t *testing.T) {
+	suite.Run(t, new(SchemaTestSuite))
+}
+
+func (s *SchemaTestSuite) SetupTest() {
+	g := newGenerator()
+	s.Require().NotPanics(func() {
+		ref := g.SchemaOf(schemaSample{})
+		s.Equal("#/components/schemas/schemaSample", ref.Ref)
+	})
+	s.properties = g.schemas["schemaSample"].Properties
+}
+
+func (s *SchemaTestSuite) TestNullablePointers() {
+	tests := []struct {
+		field    string
+		expected interface{}
+	}{
+		{field: "name", expected: "string"},
+		{field: "note", expected: []string{"string", "null"}},
+		{field: "count", expected: []string{"integer", "null"}},
+		{field: "deleted_at", expected: []string{"string", "null"}},
+		{field: "tags", expected: []string{"array", "null"}},
+		// schema tanpa type sudah menerima null sehingga dibiarkan
+		{field: "raw", expected: nil},
+		{field: "any", expected: nil},
+		// $ref tidak dapat digabung dengan type
+		{field: "child", expected: nil},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.field, func() {
+			s.Require().Contains(s.properties, tt.field)
+			s.Equal(tt.expected, s.properties[tt.field].Type)
+		})
+	}
+	s.Equal("#/components/schemas/schemaChild", s.properties["child"].Ref)
+}
@@ -0,0 +1,315 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+const documentURL = "openapi.json"
+
+var printer = message.NewPrinter(language.English)
+
+// Violation adalah satu pelanggaran kontrak OpenAPI
+type Violation struct {
+	Location string `json:"location"`
+	Message  string `json:"message"`
+}
+
+// Validator memvalidasi request dan response terhadap dokumen OpenAPI
+type Validator struct {
+	operations map[string]*OperationValidator
+}
+
+// OperationValidator berisi schema terkompilasi untuk satu operation
+type OperationValidator struct {
+	params       []*paramValidator
+	bodyRequired bool
+	acceptsFiles bool
+	bodies       map[string]*jsonschema.Schema
+	responses    map[string]map[string]*jsonschema.Schema
+}
+
+type paramValidator struct {
+	name     string
+	in       string
+	required bool
+	kind     interface{}
+	schema   *jsonschema.Schema
+}
+
+// NewValidator mengkompilasi schema setiap parameter, body request dan response
+// pada dokumen.
+func NewValidator(doc *Document) (*Validator, error) {
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	resource, err := jsonschema.UnmarshalJSON(bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+
+	compiler := jsonschema.NewCompiler()
+	compiler.DefaultDraft(jsonschema.Draft2020)
+	compiler.AssertFormat()
+	if err := compiler.AddResource(documentURL, resource); err != nil {
+		return nil, err
+	}
+
+	v := &Validator{operations: make(map[string]*OperationValidator)}
+	for path, item := range doc.Paths {
+		for method, op := range *item {
+			base := "#/paths/" + pointerEscape(path) + "/" + method
+			compiled, err := compileOperation(compiler, base, op)
+			if err != nil {
+				return nil, fmt.Errorf("openapi: %s %s: %w", strings.ToUpper(method), path, err)
+			}
+			v.operations[routeKey(strings.ToUpper(method), path)] = compiled
+		}
+	}
+	return v, nil
+}
+
+// Operation mengembalikan validator untuk route Echo yang terdokumentasi
+func (v *Validator) Operation(method, echoPath string) (*OperationValidator, bool) {
+	op, ok := v.operations[routeKey(method, Path(echoPath))]
+	return op, ok
+}
+
+func compileOperation(compiler *jsonschema.Compiler, base string, op *Operation) (*OperationValidator, error) {
+	compiled := &OperationValidator{
+		bodies:    make(map[string]*jsonschema.Schema),
+		responses: make(map[string]map[string]*jsonschema.Schema),
+	}
+
+	for i, param := range op.Parameters {
+		schema, err := compiler.Compile(fmt.Sprintf("%s%s/parameters/%d/schema", documentURL, base, i))
+		if err != nil {
+			return nil, err
+		}
+		compiled.params = append(compiled.params, &paramValidator{
+			name:     param.Name,
+			in:       param.In,
+			required: param.Required,
+			kind:     param.Schema.Type,
+			schema:   schema,
+		})
+	}
+
+	if op.RequestBody != nil {
+		compiled.bodyRequired = op.RequestBody.Required
+		for contentType := range op.RequestBody.Content {
+			schema, err := compiler.Compile(documentURL + base + "/requestBody/content/" + pointerEscape(contentType) + "/schema")
+			if err != nil {
+				return nil, err
+			}
+			compiled.bodies[contentType] = schema
+			if IsFileType(contentType) {
+				compiled.acceptsFiles = true
+			}
+		}
+	}
+
+	for status, response := range op.Responses {
+		compiled.responses[status] = make(map[string]*jsonschema.Schema)
+		for contentType := range response.Content {
+			schema, err := compiler.Compile(documentURL + base + "/responses/" + status + "/content/" + pointerEscape(contentType) + "/schema")
+			if err != nil {
+				return nil, err
+			}
+			compiled.responses[status][contentType] = schema
+		}
+	}
+	return compiled, nil
+}
+
+// AcceptsFiles menandakan operation menerima file (mis. CSV atau XLSX) sehingga
+// body-nya dapat melebihi batas body JSON dan tidak perlu dibaca middleware kontrak
+func (o *OperationValidator) AcceptsFiles() bool {
+	return o.acceptsFiles
+}
+
+// ValidateRequest memeriksa parameter path dan query serta body request. Body nil
+// pada operation yang menerima file berarti body tidak dibaca, sehingga hanya
+// Content-Type yang diperiksa.
+func (o *OperationValidator) ValidateRequest(pathParams map[string]string, query url.Values, contentType string, body []byte) []Violation {
+	var violations []Violation
+
+	for _, param := range o.params {
+		var raw string
+		var present bool
+		switch param.in {
+		case "path":
+			raw, present = pathParams[param.name]
+		case "query":
+			present = query.Has(param.name)
+			raw = query.Get(param.name)
+		default:
+			continue
+		}
+
+		location := param.in + "." + param.name
+		if !present {
+			if param.required {
+				violations = append(violations, Violation{Location: location, Message: "parameter is required"})
+			}
+			continue
+		}
+
+		value, err := coerce(raw, param.kind)
+		if err != nil {
+			violations = append(violations, Violation{Location: location, Message: err.Error()})
+			continue
+		}
+		violations = append(violations, validate(param.schema, location, value)...)
+	}
+
+	if len(o.bodies) == 0 {
+		return violations
+	}
+	// isi file tidak divalidasi sehingga middleware kontrak tidak perlu membacanya
+	if _, ok := o.bodies[mediaType(contentType)]; ok && IsFileType(contentType) {
+		return violations
+	}
+	if o.acceptsFiles && body == nil {
+		if _, ok := o.bodies[mediaType(contentType)]; !ok && contentType != "" {
+			violations = append(violations, Violation{Location: "header.Content-Type", Message: fmt.Sprintf("unsupported content type %q", contentType)})
+		}
+		return violations
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		if o.bodyRequired {
+			violations = append(violations, Violation{Location: "body", Message: "request body is required"})
+		}
+		return violations
+	}
+
+	schema, ok := o.bodies[mediaType(contentType)]
+	if !ok {
+		return append(violations, Violation{Location: "header.Content-Type", Message: fmt.Sprintf("unsupported content type %q", contentType)})
+	}
+	if mediaType(contentType) == FormContentType {
+		return append(violations, validateForm(schema, body)...)
+	}
+	return append(violations, validateJSON(schema, "body", body)...)
+}
+
+// validateForm memvalidasi body form sebagai object berisi nilai string
+func validateForm(schema *jsonschema.Schema, body []byte) []Violation {
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return []Violation{{Location: "body", Message: "invalid form body: " + err.Error()}}
+	}
+
+	object := make(map[string]interface{}, len(values))
+	for name := range values {
+		object[name] = values.Get(name)
+	}
+	return validate(schema, "body", object)
+}
+
+// ValidateResponse memeriksa body response JSON terhadap response yang
+// didokumentasikan untuk status code-nya.
+func (o *OperationValidator) ValidateResponse(status int, contentType string, body []byte) []Violation {
+	if status == http.StatusNoContent || status == http.StatusNotModified {
+		return nil
+	}
+
+	contents, ok := o.responses[strconv.Itoa(status)]
+	if !ok && status >= http.StatusBadRequest {
+		contents, ok = o.responses["default"]
+	}
+	if !ok {
+		return []Violation{{Location: "response.status", Message: fmt.Sprintf("undocumented status %d", status)}}
+	}
+
+	schema, ok := contents[mediaType(contentType)]
+	if !ok {
+		return []Violation{{Location: "response.header.Content-Type", Message: fmt.Sprintf("undocumented content type %q", contentType)}}
+	}
+	return validateJSON(schema, "response", body)
+}
+
+func validateJSON(schema *jsonschema.Schema, location string, body []byte) []Violation {
+	value, err := jsonschema.UnmarshalJSON(bytes.NewReader(body))
+	if err != nil {
+		return []Violation{{Location: location, Message: "invalid JSON: " + err.Error()}}
+	}
+	return validate(schema, location, value)
+}
+
+func validate(schema *jsonschema.Schema, location string, value interface{}) []Violation {
+	err := schema.Validate(value)
+	if err == nil {
+		return nil
+	}
+
+	ve, ok := err.(*jsonschema.ValidationError)
+	if !ok {
+		return []Violation{{Location: location, Message: err.Error()}}
+	}
+
+	var violations []Violation
+	collect(ve, location, &violations)
+	sort.Slice(violations, func(i, j int) bool {
+		return violations[i].Location < violations[j].Location
+	})
+	return violations
+}
+
+func collect(ve *jsonschema.ValidationError, location string, violations *[]Violation) {
+	if len(ve.Causes) == 0 {
+		at := location
+		if len(ve.InstanceLocation) > 0 {
+			at += "/" + strings.Join(ve.InstanceLocation, "/")
+		}
+		*violations = append(*violations, Violation{Location: at, Message: ve.ErrorKind.LocalizedString(printer)})
+		return
+	}
+	for _, cause := range ve.Causes {
+		collect(cause, location, violations)
+	}
+}
+
+// coerce mengubah nilai parameter string ke tipe JSON sesuai schema
+func coerce(raw string, kind interface{}) (interface{}, error) {
+	switch kind {
+	case "integer", "number":
+		number := json.Number(raw)
+		if _, err := number.Float64(); err != nil {
+			return nil, fmt.Errorf("must be a %s", kind)
+		}
+		return number, nil
+	case "boolean":
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("must be a boolean")
+		}
+		return value, nil
+	default:
+		return raw, nil
+	}
+}
+
+func mediaType(contentType string) string {
+	parsed, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return contentType
+	}
+	return parsed
+}
+
+func pointerEscape(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
@@ -0,0 +1,242 @@
+package openapi
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/suite"
+)
+
+type widgetInput struct {
+	Name  string `json:"name" validate:"required,min=2"`
+	Email string `json:"email" validate:"omitempty,email"`
+}
+
+type widget struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name" validate:"required"`
+}
+
+type envelope struct {
+	Status string      `json:"status"`
+	Data   interface{} `json:"data,omitempty"`
+}
+
+type ValidatorTestSuite struct {
+	suite.Suite
+	validator *Validator
+}
+
+func TestValidatorSuite(t *testing.T) {
+	suite.Run(t, new(ValidatorTestSuite))
+}
+
+func (s *ValidatorTestSuite) SetupTest() {
+	e := echo.New()
+	noop := func(c echo.Context) error { return nil }
+	e.PUT("/widgets/:id", noop)
+
+	registry := NewRegistry(Options{Envelope: envelope{}, EnvelopeDataField: "data"})
+	registry.Add(http.MethodPut, "/widgets/:id", Route{
+		Params: []Param{
+			{Name: "id", In: "path", Schema: &Schema{Type: "integer", Minimum: new(float64)}},
+			{Name: "dry_run", In: "query", Schema: &Schema{Type: "boolean"}},
+		},
+		Request:  widgetInput{},
+		Response: widget{},
+	})
+
+	doc, err := registry.Build(e.Routes())
+	s.Require().NoError(err)
+
+	s.validator, err = NewValidator(doc)
+	s.Require().NoError(err)
+}
+
+func (s *ValidatorTestSuite) operation() *OperationValidator {
+	op, ok := s.validator.Operation(http.MethodPut, "/widgets/:id")
+	s.Require().True(ok)
+	return op
+}
+
+func (s *ValidatorTestSuite) TestValidateRequest() {
+	tests := []struct {
+		name        string
+		id          string
+		query       url.Values
+		contentType string
+		body        string
+		locations   []string
+	}{
+		{
+			name:        "valid request",
+			id:          "1",
+			query:       url.Values{"dry_run": {"true"}},
+			contentType: "application/json; charset=utf-8",
+			body:        `{"name":"Gear","email":"a@b.co"}`,
+		},
+		{
+			name:        "non numeric path parameter",
+			id:          "abc",
+			contentType: "application/json",
+			body:        `{"name":"Gear"}`,
+			locations:   []string{"path.id"},
+		},
+		{
+			name:        "invalid query parameter",
+			id:          "1",
+			query:       url.Values{"dry_run": {"maybe"}},
+			contentType: "application/json",
+			body:        `{"name":"Gear"}`,
+			locations:   []string{"query.dry_run"},
+		},
+		{
+			name:        "body violates constraints",
+			id:          "1",
+			contentType: "application/json",
+			body:        `{"name":"G","email":"nope"}`,
+			locations:   []string{"body/email", "body/name"},
+		},
+		{
+			name:        "missing body",
+			id:          "1",
+			contentType: "application/json",
+			locations:   []string{"body"},
+		},
+		{
+			name:        "unsupported content type",
+			id:          "1",
+			contentType: "text/plain",
+			body:        `name=Gear`,
+			locations:   []string{"header.Content-Type"},
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			violations := s.operation().ValidateRequest(map[string]string{"id": tt.id}, tt.query, tt.contentType, []byte(tt.body))
+
+			var locations []string
+			for _, v := range violations {
+				locations = append(locations, v.Location)
+			}
+			s.Equal(tt.locations, locations)
+		})
+	}
+}
+
+func (s *ValidatorTestSuite) TestValidateResponse() {
+	op := s.operation()
+
+	s.Empty(op.ValidateResponse(http.StatusOK, echo.MIMEApplicationJSON, []byte(`{"status":"success","data":{"id":1,"name":"Gear"}}`)))
+	s.Empty(op.ValidateResponse(http.StatusNotModified, "", nil))
+
+	violations := op.ValidateResponse(http.StatusOK, echo.MIMEApplicationJSON, []byte(`{"status":"success","data":{"id":"1"}}`))
+	s.NotEmpty(violations)
+
+	violations = op.ValidateResponse(http.StatusCreated, echo.MIMEApplicationJSON, []byte(`{}`))
+	s.Equal([]Violation{{Location: "response.status", Message: "undocumented status 201"}}, violations)
+}
+
+type tokenInput struct {
+	GrantType string `json:"grant_type" validate:"required,oneof=client_credentials"`
+	Scope     string `json:"scope"`
+}
+
+type tokenOutput struct {
+	AccessToken string `json:"access_token" validate:"required"`
+}
+
+type protocolError struct {
+	Error string `json:"error" validate:"required"`
+}
+
+func (s *ValidatorTestSuite) TestFormRequestAndRawResponse() {
+	e := echo.New()
+	e.POST("/token", func(c echo.Context) error { return nil })
+
+	registry := NewRegistry(Options{Envelope: envelope{}, EnvelopeDataField: "data"})
+	registry.Add(http.MethodPost, "/token", Route{
+		Request:      tokenInput{},
+		RequestTypes: []string{FormContentType},
+		Response:     tokenOutput{},
+		Raw:          true,
+		Errors:       map[int]interface{}{http.StatusBadRequest: protocolError{}},
+	})
+	doc, err := registry.Build(e.Routes())
+	s.Require().NoError(err)
+	validator, err := NewValidator(doc)
+	s.Require().NoError(err)
+
+	op, ok := validator.Operation(http.MethodPost, "/token")
+	s.Require().True(ok)
+
+	s.Empty(op.ValidateRequest(nil, nil, FormContentType, []byte("grant_type=client_credentials&scope=a+b")))
+	s.NotEmpty(op.ValidateRequest(nil, nil, FormContentType, []byte("grant_type=password")))
+
+	s.Empty(op.ValidateResponse(http.StatusOK, echo.MIMEApplicationJSON, []byte(`{"access_token":"abc"}`)))
+	s.NotEmpty(op.ValidateResponse(http.StatusOK, echo.MIMEApplicationJSON, []byte(`{"status":"success","data":{"access_token":"abc"}}`)))
+	s.Empty(op.ValidateResponse(http.StatusBadRequest, echo.MIMEApplicationJSON, []byte(`{"error":"invalid_grant"}`)))
+}
+
+func (s *ValidatorTestSuite) TestFileResponseIsNotValidated() {
+	e := echo.New()
+	e.GET("/widgets/:id/file", func(c echo.Context) error { return nil })
+
+	registry := NewRegistry(Options{Envelope: envelope{}, EnvelopeDataField: "data"})
+	registry.Add(http.MethodGet, "/widgets/:id/file", Route{
+		Params:        []Param{{Name: "id", In: "path", Schema: &Schema{Type: "integer"}}},
+		ResponseTypes: []string{JSONContentType, "application/zip"},
+	})
+	doc, err := registry.Build(e.Routes())
+	s.Require().NoError(err)
+
+	content := (*doc.Paths["/widgets/{id}/file"])["get"].Responses["200"].Content
+	s.Equal("binary", content["application/zip"].Schema.Format)
+
+	validator, err := NewValidator(doc)
+	s.Require().NoError(err)
+	op, ok := validator.Operation(http.MethodGet, "/widgets/:id/file")
+	s.Require().True(ok)
+	s.Empty(op.ValidateResponse(http.StatusOK, "application/json", []byte(`{"anything":[1,2,3]}`)))
+}
+
+func (s *ValidatorTestSuite) TestFileRequestIsNotValidated() {
+	e := echo.New()
+	e.POST("/widgets/import", func(c echo.Context) error { return nil })
+
+	registry := NewRegistry(Options{Envelope: envelope{}, EnvelopeDataField: "data"})
+	registry.Add(http.MethodPost, "/widgets/import", Route{
+		Request:      []widgetInput{},
+		RequestTypes: []string{JSONContentType, "text/csv"},
+		Response:     widget{},
+		Status:       http.StatusAccepted,
+		Responses:    map[int]interface{}{http.StatusOK: widget{}},
+	})
+	doc, err := registry.Build(e.Routes())
+	s.Require().NoError(err)
+
+	content := (*doc.Paths["/widgets/import"])["post"].RequestBody.Content
+	s.Equal("binary", content["text/csv"].Schema.Format)
+
+	validator, err := NewValidator(doc)
+	s.Require().NoError(err)
+	op, ok := validator.Operation(http.MethodPost, "/widgets/import")
+	s.Require().True(ok)
+	s.Empty(op.ValidateRequest(nil, nil, "text/csv; charset=utf-8", []byte("name\nWidget\n")))
+	s.Empty(op.ValidateRequest(nil, nil, JSONContentType, []byte(`[{"name":"Widget"}]`)))
+	s.NotEmpty(op.ValidateRequest(nil, nil, JSONContentType, []byte(`[{"name":"W"}]`)))
+	s.NotEmpty(op.ValidateRequest(nil, nil, "application/zip", []byte("PK")))
+	s.True(op.AcceptsFiles())
+	// body yang tidak dibaca middleware hanya diperiksa Content-Type-nya
+	s.Empty(op.ValidateRequest(nil, nil, JSONContentType, nil))
+	s.NotEmpty(op.ValidateRequest(nil, nil, "application/zip", nil))
+
+	// import yang selesai langsung mengembalikan 200, yang diantrikan 202
+	for _, status := range []int{http.StatusOK, http.StatusAccepted} {
+		s.Empty(op.ValidateResponse(status, JSONContentType, []byte(`{"status":"success","data":{"id":1,"name":"Widget"}}`)))
+		s.NotEmpty(op.ValidateResponse(status, JSONContentType, []byte(`{"status":"success","data":{"id":1}}`)))
+	}
+}
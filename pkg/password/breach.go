@@ -0,0 +1,158 @@
+package password
+
+import (
+	"bufio"
+	"crypto/sha1"
+	_ "embed"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// prefixLength adalah panjang prefix hash untuk k-anonymity (sama dengan range API HIBP)
+	prefixLength = 5
+	// MaxBreachFileSize adalah batas ukuran file daftar yang dimuat ke memori (sekitar
+	// 1,5 juta hash). Dataset yang lebih besar dipakai dalam bentuk direktori per prefix.
+	MaxBreachFileSize = 64 << 20
+)
+
+//go:embed breached.txt
+var bundledBreachList string
+
+// BreachList adalah daftar hash SHA-1 password yang pernah bocor. Hash
+// dikelompokkan per prefix 5 karakter seperti range API Have I Been Pwned,
+// sehingga format file sama dengan dataset HIBP: satu hash per baris dengan
+// jumlah kemunculan opsional ("<SHA1>:<count>"). Baris diawali "#" diabaikan.
+//
+// Daftar dari direktori tidak dimuat ke memori: setiap pemeriksaan hanya membaca
+// file "<PREFIX>.txt" milik prefix hash password, berisi "<SUFFIX>:<count>" per
+// baris seperti hasil PwnedPasswordsDownloader tanpa opsi single file.
+type BreachList struct {
+	ranges map[string]map[string]struct{}
+	size   int
+	dir    string
+}
+
+// LoadBreachList membaca daftar dari path; path kosong memakai daftar bawaan. Path
+// berupa direktori dibaca per prefix saat pemeriksaan; file dimuat ke memori dan
+// ditolak jika lebih besar dari MaxBreachFileSize.
+func LoadBreachList(path string) (*BreachList, error) {
+	if path == "" {
+		return ParseBreachList(strings.NewReader(bundledBreachList))
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return openBreachDir(path)
+	}
+	if info.Size() > MaxBreachFileSize {
+		return nil, fmt.Errorf("breach list %s is larger than %d bytes, use a directory with one file per prefix", path, MaxBreachFileSize)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ParseBreachList(io.LimitReader(file, MaxBreachFileSize))
+}
+
+// openBreachDir memastikan direktori berisi file per prefix agar salah konfigurasi
+// tidak diam-diam menonaktifkan pemeriksaan
+func openBreachDir(dir string) (*BreachList, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, strings.Repeat("[0-9A-F]", prefixLength)+".txt"))
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("breach list %s has no <PREFIX>.txt range files", dir)
+	}
+	return &BreachList{dir: dir}, nil
+}
+
+// ParseBreachList parses one SHA-1 hash per line
+func ParseBreachList(r io.Reader) (*BreachList, error) {
+	list := &BreachList{ranges: make(map[string]map[string]struct{})}
+
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		hash, _, _ := strings.Cut(text, ":")
+		hash = strings.ToUpper(hash)
+		if len(hash) != sha1.Size*2 {
+			return nil, fmt.Errorf("breach list line %d: invalid SHA-1 hash", line)
+		}
+		if _, err := hex.DecodeString(hash); err != nil {
+			return nil, fmt.Errorf("breach list line %d: %w", line, err)
+		}
+
+		prefix, suffix := hash[:prefixLength], hash[prefixLength:]
+		suffixes, ok := list.ranges[prefix]
+		if !ok {
+			suffixes = make(map[string]struct{})
+			list.ranges[prefix] = suffixes
+		}
+		if _, exists := suffixes[suffix]; !exists {
+			suffixes[suffix] = struct{}{}
+			list.size++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// Contains reports whether the password's SHA-1 hash is in the list
+func (b *BreachList) Contains(password string) bool {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:prefixLength], hash[prefixLength:]
+
+	if b.dir != "" {
+		return b.rangeContains(prefix, suffix)
+	}
+	suffixes, ok := b.ranges[prefix]
+	if !ok {
+		return false
+	}
+	_, found := suffixes[suffix]
+	return found
+}
+
+// rangeContains mencari suffix di file prefix. File yang tidak ada atau tidak dapat
+// dibaca dianggap tidak memuat hash agar registrasi tidak terhenti.
+func (b *BreachList) rangeContains(prefix, suffix string) bool {
+	file, err := os.Open(filepath.Join(b.dir, prefix+".txt"))
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		candidate, _, _ := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if strings.EqualFold(candidate, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Len returns the number of hashes loaded in memory; 0 for a directory list
+func (b *BreachList) Len() int {
+	return b.size
+}
@@ -0,0 +1,76 @@
+package password
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type BreachListTestSuite struct {
+	suite.Suite
+}
+
+func TestBreachListSuite(t *testing.T) {
+	suite.Run(t, new(BreachListTestSuite))
+}
+
+func (s *BreachListTestSuite) TestBundledList() {
+	list, err := LoadBreachList("")
+	s.Require().NoError(err)
+	s.Greater(list.Len(), 100)
+
+	for _, common := range []string{"123456", "password", "qwerty", "password123", "P@ssw0rd"} {
+		s.True(list.Contains(common), common)
+	}
+	s.False(list.Contains("a-long-unlikely-passphrase-0451"))
+}
+
+func (s *BreachListTestSuite) TestLoadFromFile() {
+	path := filepath.Join(s.T().TempDir(), "breached.txt")
+	// huruf kecil dan jumlah kemunculan diterima seperti dataset HIBP
+	s.Require().NoError(os.WriteFile(path, []byte("7c4a8d09ca3762af61e59520943dc26494f8941b:37359195\n"), 0o600))
+
+	list, err := LoadBreachList(path)
+	s.Require().NoError(err)
+	s.Equal(1, list.Len())
+	s.True(list.Contains("123456"))
+	s.False(list.Contains("password"))
+}
+
+func (s *BreachListTestSuite) TestLoadFromRangeDirectory() {
+	dir := s.T().TempDir()
+	// SHA-1 "123456" = 7C4A8D09CA3762AF61E59520943DC26494F8941B
+	s.Require().NoError(os.WriteFile(filepath.Join(dir, "7C4A8.txt"), []byte("0000000000000000000000000000000000A:1\nd09ca3762af61e59520943dc26494f8941b:37359195\n"), 0o600))
+	s.Require().NoError(os.WriteFile(filepath.Join(dir, "5BAA6.txt"), []byte("0000000000000000000000000000000000B:2\n"), 0o600))
+
+	list, err := LoadBreachList(dir)
+	s.Require().NoError(err)
+	s.True(list.Contains("123456"))
+	s.False(list.Contains("password"), "suffix is not in the range file")
+	s.False(list.Contains("qwerty"), "range file does not exist")
+
+	_, err = LoadBreachList(s.T().TempDir())
+	s.ErrorContains(err, "range files")
+}
+
+func (s *BreachListTestSuite) TestRejectsOversizedFile() {
+	path := filepath.Join(s.T().TempDir(), "breached.txt")
+	file, err := os.Create(path)
+	s.Require().NoError(err)
+	s.Require().NoError(file.Truncate(MaxBreachFileSize + 1))
+	s.Require().NoError(file.Close())
+
+	_, err = LoadBreachList(path)
+	s.ErrorContains(err, "directory")
+}
+
+func (s *BreachListTestSuite) TestRejectsInvalidHash() {
+	_, err := ParseBreachList(strings.NewReader("not-a-hash\n"))
+	s.ErrorContains(err, "line 1")
+
+	_, err = LoadBreachList(filepath.Join(s.T().TempDir(), "missing.txt"))
+	s.Error(err)
+}
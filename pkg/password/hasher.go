@@ -0,0 +1,101 @@
+package password
+
+import (
+	"errors"
+	"sync"
+
+	"boilerplate/shared/constants"
+)
+
+// ErrUnsupportedHash dikembalikan jika format hash tidak dikenali oleh hasher mana pun
+var ErrUnsupportedHash = errors.New("password: unsupported hash format")
+
+// PasswordHasher meng-hash dan memverifikasi password. Hash dienkode dalam format
+// PHC (mis. "$argon2id$v=19$m=19456,t=2,p=1$<salt>$<hash>") sehingga algoritma dan
+// parameternya tersimpan di dalam hash itu sendiri.
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	// Verify returns false without error when the password does not match
+	Verify(encoded, password string) (bool, error)
+	// NeedsRehash reports whether encoded was produced with other parameters
+	NeedsRehash(encoded string) bool
+	// Supports reports whether encoded was produced by this algorithm
+	Supports(encoded string) bool
+}
+
+// Hasher meng-hash dengan algoritma saat ini dan tetap dapat memverifikasi hash
+// dari algoritma lain yang didaftarkan, sehingga hash lama dapat dimigrasikan
+// saat user login.
+type Hasher struct {
+	current   PasswordHasher
+	supported []PasswordHasher
+}
+
+// NewHasher membuat Hasher; legacy adalah algoritma lama yang hanya dipakai untuk verifikasi
+func NewHasher(current PasswordHasher, legacy ...PasswordHasher) *Hasher {
+	return &Hasher{
+		current:   current,
+		supported: append([]PasswordHasher{current}, legacy...),
+	}
+}
+
+func (h *Hasher) Hash(password string) (string, error) {
+	return h.current.Hash(password)
+}
+
+func (h *Hasher) Verify(encoded, password string) (bool, error) {
+	for _, hasher := range h.supported {
+		if hasher.Supports(encoded) {
+			return hasher.Verify(encoded, password)
+		}
+	}
+	return false, ErrUnsupportedHash
+}
+
+// NeedsRehash reports whether encoded uses another algorithm or outdated parameters
+func (h *Hasher) NeedsRehash(encoded string) bool {
+	return !h.current.Supports(encoded) || h.current.NeedsRehash(encoded)
+}
+
+func (h *Hasher) Supports(encoded string) bool {
+	for _, hasher := range h.supported {
+		if hasher.Supports(encoded) {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	defaultMu     sync.RWMutex
+	defaultHasher PasswordHasher = NewHasher(BcryptHasher{Cost: constants.BcryptCost}, DefaultArgon2idHasher())
+)
+
+// SetDefaultHasher mengganti hasher yang dipakai model User; dipanggil sekali saat startup
+func SetDefaultHasher(hasher PasswordHasher) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultHasher = hasher
+}
+
+// DefaultHasher returns the hasher configured at startup
+func DefaultHasher() PasswordHasher {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultHasher
+}
+
+// Hash meng-hash password dengan hasher default
+func Hash(password string) (string, error) {
+	return DefaultHasher().Hash(password)
+}
+
+// Verify memverifikasi password dengan hasher default
+func Verify(encoded, password string) (bool, error) {
+	return DefaultHasher().Verify(encoded, password)
+}
+
+// NeedsRehash memeriksa hash dengan hasher default
+func NeedsRehash(encoded string) bool {
+	return DefaultHasher().NeedsRehash(encoded)
+}
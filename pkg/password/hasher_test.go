@@ -0,0 +1,135 @@
+package password
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type HasherTestSuite struct {
+	suite.Suite
+}
+
+func TestHasherSuite(t *testing.T) {
+	suite.Run(t, new(HasherTestSuite))
+}
+
+// fastArgon2id keeps the test suite quick; production uses DefaultArgon2idHasher
+func fastArgon2id() Argon2idHasher {
+	return Argon2idHasher{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+}
+
+func (s *HasherTestSuite) TestRoundTrip() {
+	for name, hasher := range map[string]PasswordHasher{
+		"bcrypt":   BcryptHasher{Cost: bcrypt.MinCost},
+		"argon2id": fastArgon2id(),
+	} {
+		s.Run(name, func() {
+			encoded, err := hasher.Hash("correct horse")
+			s.Require().NoError(err)
+			s.True(hasher.Supports(encoded))
+
+			ok, err := hasher.Verify(encoded, "correct horse")
+			s.NoError(err)
+			s.True(ok)
+
+			ok, err = hasher.Verify(encoded, "wrong horse")
+			s.NoError(err)
+			s.False(ok)
+
+			again, err := hasher.Hash("correct horse")
+			s.Require().NoError(err)
+			s.NotEqual(encoded, again, "salt must differ per hash")
+		})
+	}
+}
+
+func (s *HasherTestSuite) TestArgon2idFormat() {
+	encoded, err := fastArgon2id().Hash("secret")
+	s.Require().NoError(err)
+	s.True(strings.HasPrefix(encoded, "$argon2id$v=19$m=1024,t=1,p=1$"))
+	s.Len(strings.Split(encoded, "$"), 6)
+
+	for _, malformed := range []string{
+		"$argon2id$v=19$m=1024,t=1,p=1$salt",
+		"$argon2id$v=18$m=1024,t=1,p=1$c2FsdHNhbHRzYWx0c2FsdA$a2V5",
+		"$argon2id$v=19$m=x,t=1,p=1$c2FsdHNhbHRzYWx0c2FsdA$a2V5",
+		"$argon2id$v=19$m=1024,t=1,p=1$!!!$a2V5",
+	} {
+		_, err := fastArgon2id().Verify(malformed, "secret")
+		s.Error(err, malformed)
+	}
+}
+
+func (s *HasherTestSuite) TestNeedsRehash() {
+	argon := fastArgon2id()
+	encoded, err := argon.Hash("secret")
+	s.Require().NoError(err)
+	s.False(argon.NeedsRehash(encoded))
+
+	stronger := argon
+	stronger.Iterations = 2
+	s.True(stronger.NeedsRehash(encoded))
+
+	legacy, err := BcryptHasher{Cost: bcrypt.MinCost}.Hash("secret")
+	s.Require().NoError(err)
+	s.False(BcryptHasher{Cost: bcrypt.MinCost}.NeedsRehash(legacy))
+	s.True(BcryptHasher{Cost: bcrypt.MinCost + 1}.NeedsRehash(legacy))
+
+	hasher := NewHasher(argon, BcryptHasher{Cost: bcrypt.MinCost})
+	s.True(hasher.NeedsRehash(legacy), "bcrypt hashes migrate to argon2id")
+	s.False(hasher.NeedsRehash(encoded))
+}
+
+func (s *HasherTestSuite) TestHasherVerifiesLegacyHashes() {
+	bcryptHasher := BcryptHasher{Cost: bcrypt.MinCost}
+	legacy, err := bcryptHasher.Hash("secret")
+	s.Require().NoError(err)
+
+	hasher := NewHasher(fastArgon2id(), bcryptHasher)
+	ok, err := hasher.Verify(legacy, "secret")
+	s.NoError(err)
+	s.True(ok)
+
+	encoded, err := hasher.Hash("secret")
+	s.Require().NoError(err)
+	s.True(strings.HasPrefix(encoded, "$argon2id$"))
+
+	_, err = hasher.Verify("$md5$abc", "secret")
+	s.ErrorIs(err, ErrUnsupportedHash)
+
+	// Without bcrypt registered the legacy hash can no longer be verified
+	_, err = NewHasher(fastArgon2id()).Verify(legacy, "secret")
+	s.ErrorIs(err, ErrUnsupportedHash)
+}
+
+func benchmarkHash(b *testing.B, hasher PasswordHasher) {
+	for i := 0; i < b.N; i++ {
+		if _, err := hasher.Hash("correct horse battery staple"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkBcrypt(b *testing.B) {
+	for _, cost := range []int{10, 12} {
+		b.Run("cost="+strconv.Itoa(cost), func(b *testing.B) {
+			benchmarkHash(b, BcryptHasher{Cost: cost})
+		})
+	}
+}
+
+func BenchmarkArgon2id(b *testing.B) {
+	b.Run("default", func(b *testing.B) {
+		benchmarkHash(b, DefaultArgon2idHasher())
+	})
+	b.Run("m=64MiB,t=3", func(b *testing.B) {
+		hasher := DefaultArgon2idHasher()
+		hasher.Memory = 64 * 1024
+		hasher.Iterations = 3
+		benchmarkHash(b, hasher)
+	})
+}
@@ -0,0 +1,167 @@
+package password
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"boilerplate/shared/constants"
+	errs "boilerplate/shared/errors"
+)
+
+// Kode aturan password policy. Kode bersifat stabil sehingga client dapat
+// menampilkan pesannya sendiri.
+const (
+	RuleMinLength    = "min_length"
+	RuleMaxLength    = "max_length"
+	RuleLowercase    = "lowercase"
+	RuleUppercase    = "uppercase"
+	RuleDigit        = "digit"
+	RuleSymbol       = "symbol"
+	RulePersonalInfo = "personal_info"
+	RuleBreached     = "breached"
+	RuleHistory      = "history"
+)
+
+// Character class yang dapat diwajibkan lewat Policy.RequiredClasses
+const (
+	ClassLowercase = "lower"
+	ClassUppercase = "upper"
+	ClassDigit     = "digit"
+	ClassSymbol    = "symbol"
+)
+
+// DefaultMaxLength mengikuti batas input bcrypt (72 byte)
+const DefaultMaxLength = 72
+
+// minPersonalInfoLength adalah panjang minimal bagian email/nama yang diperiksa,
+// agar potongan pendek seperti "a" tidak menolak hampir semua password
+const minPersonalInfoLength = 3
+
+// Violation adalah satu aturan policy yang dilanggar
+type Violation struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Policy adalah aturan password yang diterapkan saat registrasi, update profil
+// dan reset password. HistorySize diperiksa oleh pemanggil karena membutuhkan
+// hash password lama.
+type Policy struct {
+	MinLength int
+	MaxLength int
+	// RequiredClasses berisi ClassLowercase, ClassUppercase, ClassDigit dan/atau ClassSymbol
+	RequiredClasses []string
+	// DisallowPersonalInfo menolak password yang memuat email atau nama user
+	DisallowPersonalInfo bool
+	// HistorySize adalah jumlah password sebelumnya yang tidak boleh dipakai ulang
+	HistorySize int
+	// Breached adalah daftar password bocor; nil menonaktifkan pemeriksaan
+	Breached *BreachList
+}
+
+// DefaultPolicy returns the policy used when nothing is configured
+func DefaultPolicy() Policy {
+	return Policy{
+		MinLength:            constants.PasswordMinLength,
+		MaxLength:            DefaultMaxLength,
+		DisallowPersonalInfo: true,
+	}
+}
+
+// Check returns every rule the password violates. personal berisi email dan
+// nama user yang tidak boleh muncul di dalam password.
+func (p Policy) Check(password string, personal ...string) []Violation {
+	var violations []Violation
+
+	length := utf8.RuneCountInString(password)
+	minLength := max(p.MinLength, constants.PasswordMinLength)
+	if length < minLength {
+		violations = append(violations, Violation{Rule: RuleMinLength, Message: fmt.Sprintf("password must be at least %d characters", minLength)})
+	}
+	if p.MaxLength > 0 && length > p.MaxLength {
+		violations = append(violations, Violation{Rule: RuleMaxLength, Message: fmt.Sprintf("password must be at most %d characters", p.MaxLength)})
+	}
+
+	for _, class := range p.RequiredClasses {
+		if violation, ok := checkClass(class, password); !ok {
+			violations = append(violations, violation)
+		}
+	}
+
+	if p.DisallowPersonalInfo && containsPersonalInfo(password, personal) {
+		violations = append(violations, Violation{Rule: RulePersonalInfo, Message: "password must not contain your email or name"})
+	}
+
+	if p.Breached != nil && p.Breached.Contains(password) {
+		violations = append(violations, Violation{Rule: RuleBreached, Message: "password has appeared in a data breach, choose another one"})
+	}
+
+	return violations
+}
+
+// Validate returns ErrPasswordPolicy carrying every violated rule, or nil
+func (p Policy) Validate(password string, personal ...string) error {
+	return Error(p.Check(password, personal...))
+}
+
+// HistoryViolation adalah pelanggaran untuk password yang sama dengan password
+// saat ini atau salah satu dari HistorySize password sebelumnya
+func (p Policy) HistoryViolation() Violation {
+	return Violation{Rule: RuleHistory, Message: fmt.Sprintf("password must differ from your current and last %d passwords", p.HistorySize)}
+}
+
+// Error membungkus violations menjadi ErrPasswordPolicy; nil jika tidak ada pelanggaran
+func Error(violations []Violation) error {
+	if len(violations) == 0 {
+		return nil
+	}
+	return errs.ErrPasswordPolicy.WithDetails(map[string]interface{}{"violations": violations})
+}
+
+func checkClass(class, password string) (Violation, bool) {
+	var rule, message string
+	var matches func(rune) bool
+
+	switch class {
+	case ClassLowercase:
+		rule, message, matches = RuleLowercase, "password must contain a lowercase letter", unicode.IsLower
+	case ClassUppercase:
+		rule, message, matches = RuleUppercase, "password must contain an uppercase letter", unicode.IsUpper
+	case ClassDigit:
+		rule, message, matches = RuleDigit, "password must contain a digit", unicode.IsDigit
+	case ClassSymbol:
+		rule, message = RuleSymbol, "password must contain a symbol"
+		matches = func(r rune) bool { return unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r) }
+	default:
+		return Violation{}, true
+	}
+
+	if strings.IndexFunc(password, matches) >= 0 {
+		return Violation{}, true
+	}
+	return Violation{Rule: rule, Message: message}, false
+}
+
+// containsPersonalInfo memeriksa email lengkap, bagian lokal email dan setiap kata nama
+func containsPersonalInfo(password string, personal []string) bool {
+	lower := strings.ToLower(password)
+	for _, value := range personal {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value == "" {
+			continue
+		}
+
+		candidates := strings.Fields(value)
+		if local, _, ok := strings.Cut(value, "@"); ok {
+			candidates = append(candidates, local)
+		}
+		for _, candidate := range candidates {
+			if utf8.RuneCountInString(candidate) >= minPersonalInfoLength && strings.Contains(lower, candidate) {
+				return true
+			}
+		}
+	}
+	return false
+}
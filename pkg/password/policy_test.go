@@ -0,0 +1,86 @@
+package password
+
+import (
+	"strings"
+	"testing"
+
+	errs "boilerplate/shared/errors"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type PolicyTestSuite struct {
+	suite.Suite
+}
+
+func TestPolicySuite(t *testing.T) {
+	suite.Run(t, new(PolicyTestSuite))
+}
+
+func rules(violations []Violation) []string {
+	var codes []string
+	for _, v := range violations {
+		codes = append(codes, v.Rule)
+	}
+	return codes
+}
+
+func (s *PolicyTestSuite) TestCheck() {
+	strict := Policy{
+		MinLength:            10,
+		MaxLength:            20,
+		RequiredClasses:      []string{ClassLowercase, ClassUppercase, ClassDigit, ClassSymbol},
+		DisallowPersonalInfo: true,
+	}
+
+	tests := []struct {
+		name     string
+		password string
+		expected []string
+	}{
+		{name: "valid", password: "Correct-Horse-9", expected: nil},
+		{name: "too short", password: "Ab1!", expected: []string{RuleMinLength}},
+		{name: "too long", password: "Correct-Horse-Battery-9", expected: []string{RuleMaxLength}},
+		{name: "missing classes", password: "alllowercase", expected: []string{RuleUppercase, RuleDigit, RuleSymbol}},
+		{name: "contains email local part", password: "Jane.Doe-2024!", expected: []string{RulePersonalInfo}},
+		{name: "contains name", password: "Smith-Rules-42", expected: []string{RulePersonalInfo}},
+		{name: "length counts runes", password: "Ünïcödé-Pässwörd1", expected: nil},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			s.Equal(tt.expected, rules(strict.Check(tt.password, "jane.doe@example.com", "Jane Smith")))
+		})
+	}
+}
+
+func (s *PolicyTestSuite) TestMinLengthNeverBelowFloor() {
+	s.Equal([]string{RuleMinLength}, rules(Policy{MinLength: 1}.Check("abc")))
+}
+
+func (s *PolicyTestSuite) TestShortNamePartsIgnored() {
+	s.Empty(DefaultPolicy().Check("al-password-x", "al@example.com", "Al"))
+}
+
+func (s *PolicyTestSuite) TestBreached() {
+	list, err := ParseBreachList(strings.NewReader("# comment\n5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8:3861493\n"))
+	s.Require().NoError(err)
+
+	policy := DefaultPolicy()
+	policy.Breached = list
+	s.Equal([]string{RuleBreached}, rules(policy.Check("password")))
+	s.Empty(policy.Check("not-in-the-list"))
+}
+
+func (s *PolicyTestSuite) TestValidateReturnsPerRuleDetails() {
+	err := DefaultPolicy().Validate("abc")
+	s.Require().ErrorIs(err, errs.ErrPasswordPolicy)
+
+	appErr, ok := errs.As(err)
+	s.Require().True(ok)
+	violations := appErr.Details["violations"].([]Violation)
+	s.Equal([]string{RuleMinLength}, rules(violations))
+	s.NotEmpty(violations[0].Message)
+
+	s.NoError(DefaultPolicy().Validate("long-enough"))
+}
@@ -0,0 +1,69 @@
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+
+	errs "boilerplate/shared/errors"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+)
+
+const (
+	// MergePatchContentType adalah media type RFC 7396 JSON Merge Patch
+	MergePatchContentType = "application/merge-patch+json"
+	// JSONPatchContentType adalah media type RFC 6902 JSON Patch
+	JSONPatchContentType = "application/json-patch+json"
+)
+
+// Apply menerapkan patch ke dokumen JSON original sesuai content type.
+// application/json diperlakukan sebagai merge patch.
+func Apply(contentType string, original, patch []byte) ([]byte, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, errs.ErrUnsupportedMediaType
+	}
+
+	switch mediaType {
+	case MergePatchContentType, "application/json":
+		patched, err := jsonpatch.MergePatch(original, patch)
+		if err != nil {
+			return nil, errs.ErrInvalidPayload.Wrap(err)
+		}
+		return patched, nil
+	case JSONPatchContentType:
+		operations, err := jsonpatch.DecodePatch(patch)
+		if err != nil {
+			return nil, errs.ErrInvalidPayload.Wrap(err)
+		}
+		patched, err := operations.Apply(original)
+		if err != nil {
+			return nil, errs.ErrInvalidPayload.Wrap(err)
+		}
+		return patched, nil
+	default:
+		return nil, errs.ErrUnsupportedMediaType
+	}
+}
+
+// ApplyTo marshals current, applies the patch and decodes the result into dst.
+// Fields that are not part of the patchable document are rejected.
+func ApplyTo(contentType string, current interface{}, patch []byte, dst interface{}) error {
+	original, err := json.Marshal(current)
+	if err != nil {
+		return err
+	}
+
+	patched, err := Apply(contentType, original, patch)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(patched))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(dst); err != nil {
+		return errs.ErrInvalidPayload.Wrap(err)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package patch
+
+import (
+	"testing"
+
+	errs "boilerplate/shared/errors"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type document struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type PatchTestSuite struct {
+	suite.Suite
+}
+
+func TestPatchSuite(t *testing.T) {
+	suite.Run(t, new(PatchTestSuite))
+}
+
+func (s *PatchTestSuite) TestApplyTo() {
+	current := document{Name: "Books", Description: "Printed books"}
+
+	tests := []struct {
+		name        string
+		contentType string
+		patch       string
+		expected    document
+		expectedErr error
+	}{
+		{
+			name:        "merge patch changes one field",
+			contentType: MergePatchContentType,
+			patch:       `{"name":"E-Books"}`,
+			expected:    document{Name: "E-Books", Description: "Printed books"},
+		},
+		{
+			name:        "merge patch null clears field",
+			contentType: MergePatchContentType + "; charset=utf-8",
+			patch:       `{"description":null}`,
+			expected:    document{Name: "Books"},
+		},
+		{
+			name:        "json patch replace",
+			contentType: JSONPatchContentType,
+			patch:       `[{"op":"replace","path":"/description","value":"Audio"}]`,
+			expected:    document{Name: "Books", Description: "Audio"},
+		},
+		{
+			name:        "unsupported content type",
+			contentType: "text/plain",
+			patch:       `name=x`,
+			expectedErr: errs.ErrUnsupportedMediaType,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			var result document
+			err := ApplyTo(tt.contentType, current, []byte(tt.patch), &result)
+			if tt.expectedErr != nil {
+				s.Equal(tt.expectedErr, err)
+				return
+			}
+			s.NoError(err)
+			s.Equal(tt.expected, result)
+		})
+	}
+}
+
+func (s *PatchTestSuite) TestApplyToRejectsUnknownFields() {
+	var result document
+	err := ApplyTo(MergePatchContentType, document{Name: "Books"}, []byte(`{"created_by":1}`), &result)
+	s.ErrorIs(err, errs.ErrInvalidPayload)
+}
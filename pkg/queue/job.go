@@ -0,0 +1,97 @@
+package queue
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// State job di antrian
+const (
+	StateReady      = "ready"
+	StateScheduled  = "scheduled"
+	StateProcessing = "processing"
+	StateDead       = "dead"
+)
+
+var (
+	// ErrDuplicate dikembalikan Enqueue jika job dengan unique key yang sama masih ada
+	ErrDuplicate = errors.New("queue: job with this unique key already exists")
+	// ErrNotFound dikembalikan jika job tidak ada (sudah selesai atau tidak pernah ada)
+	ErrNotFound = errors.New("queue: job not found")
+	// ErrNotDead dikembalikan Retry untuk job yang tidak berada di dead-letter queue
+	ErrNotDead = errors.New("queue: job is not in the dead-letter queue")
+)
+
+// Job adalah satu unit kerja. Payload disimpan sebagai JSON dan didekode ke tipe
+// milik handler saat dijalankan.
+type Job struct {
+	ID          string          `json:"id"`
+	Type        string          `json:"type"`
+	Payload     json.RawMessage `json:"payload"`
+	State       string          `json:"state,omitempty"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"max_attempts"`
+	UniqueKey   string          `json:"unique_key,omitempty"`
+	LastError   string          `json:"last_error,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	RunAt       time.Time       `json:"run_at"`
+	FailedAt    *time.Time      `json:"failed_at,omitempty"`
+}
+
+// Option mengatur job saat di-enqueue
+type Option func(*Job)
+
+// Delay menunda job selama d
+func Delay(d time.Duration) Option {
+	return func(j *Job) {
+		j.RunAt = j.CreatedAt.Add(d)
+	}
+}
+
+// At menjadwalkan job pada waktu t
+func At(t time.Time) Option {
+	return func(j *Job) {
+		j.RunAt = t
+	}
+}
+
+// MaxAttempts mengganti jumlah percobaan maksimal sebelum job masuk dead-letter queue
+func MaxAttempts(n int) Option {
+	return func(j *Job) {
+		j.MaxAttempts = n
+	}
+}
+
+// Unique menolak job baru dengan key yang sama selama job sebelumnya belum selesai
+// atau belum masuk dead-letter queue
+func Unique(key string) Option {
+	return func(j *Job) {
+		j.UniqueKey = key
+	}
+}
+
+// Decode mendekode payload ke v
+func (j *Job) Decode(v interface{}) error {
+	return json.Unmarshal(j.Payload, v)
+}
+
+// permanentError menandai error yang tidak perlu dicoba ulang
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent membungkus err agar job langsung masuk dead-letter queue tanpa retry,
+// mis. untuk payload yang tidak valid
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether err was wrapped with Permanent
+func IsPermanent(err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent)
+}
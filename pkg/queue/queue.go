@@ -0,0 +1,423 @@
+package queue
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Config mengatur antrian. Nilai kosong memakai default.
+type Config struct {
+	// Prefix key Redis, default "queue"
+	Prefix string
+	// VisibilityTimeout adalah batas waktu satu percobaan. Job yang belum di-ack
+	// setelah batas ini dianggap milik worker yang mati dan dikembalikan ke antrian.
+	VisibilityTimeout time.Duration
+	// MaxAttempts default untuk job baru
+	MaxAttempts int
+	// BaseBackoff dan MaxBackoff mengatur jeda retry: BaseBackoff * 2^(percobaan-1), maksimal MaxBackoff
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+}
+
+const (
+	defaultPrefix            = "queue"
+	defaultVisibilityTimeout = 5 * time.Minute
+	defaultMaxAttempts       = 5
+	defaultBaseBackoff       = 10 * time.Second
+	defaultMaxBackoff        = time.Hour
+	// maintenanceBatch membatasi job yang dipindahkan per panggilan dequeue
+	maintenanceBatch = 100
+)
+
+// Stats adalah jumlah job per state
+type Stats struct {
+	Ready      int64 `json:"ready"`
+	Scheduled  int64 `json:"scheduled"`
+	Processing int64 `json:"processing"`
+	Dead       int64 `json:"dead"`
+}
+
+// Queue adalah antrian job di Redis. Data job disimpan di hash; ID job berpindah
+// antara list ready dan sorted set scheduled, processing (skor = batas visibility)
+// dan dead. Setiap perpindahan dilakukan atomik dengan script Lua.
+type Queue struct {
+	client *redis.Client
+	cfg    Config
+	now    func() time.Time
+}
+
+// New membuat antrian di atas koneksi Redis yang sudah ada
+func New(client *redis.Client, cfg Config) *Queue {
+	if cfg.Prefix == "" {
+		cfg.Prefix = defaultPrefix
+	}
+	if cfg.VisibilityTimeout <= 0 {
+		cfg.VisibilityTimeout = defaultVisibilityTimeout
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = defaultMaxAttempts
+	}
+	if cfg.BaseBackoff <= 0 {
+		cfg.BaseBackoff = defaultBaseBackoff
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = defaultMaxBackoff
+	}
+	return &Queue{client: client, cfg: cfg, now: time.Now}
+}
+
+func (q *Queue) key(name string) string {
+	return q.cfg.Prefix + ":" + name
+}
+
+func (q *Queue) uniqueKey(job *Job) string {
+	return q.key("unique:" + job.UniqueKey)
+}
+
+// Backoff mengembalikan jeda sebelum percobaan berikutnya setelah attempts percobaan gagal
+func (q *Queue) Backoff(attempts int) time.Duration {
+	delay := q.cfg.BaseBackoff
+	for i := 1; i < attempts && delay < q.cfg.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > q.cfg.MaxBackoff {
+		delay = q.cfg.MaxBackoff
+	}
+	return delay
+}
+
+// enqueueScript: KEYS jobs, ready, scheduled, unique; ARGV id, data, run_at, now, has_unique
+var enqueueScript = redis.NewScript(`
+if ARGV[5] == "1" and not redis.call("SET", KEYS[4], ARGV[1], "NX") then
+	return 0
+end
+redis.call("HSET", KEYS[1], ARGV[1], ARGV[2])
+if tonumber(ARGV[3]) <= tonumber(ARGV[4]) then
+	redis.call("LPUSH", KEYS[2], ARGV[1])
+else
+	redis.call("ZADD", KEYS[3], ARGV[3], ARGV[1])
+end
+return 1
+`)
+
+// dequeueScript memindahkan job terjadwal yang sudah jatuh tempo ke ready,
+// mengembalikan job yang melewati visibility timeout, lalu mengambil satu job.
+// KEYS ready, scheduled, processing; ARGV now, deadline, batch
+var dequeueScript = redis.NewScript(`
+local due = redis.call("ZRANGEBYSCORE", KEYS[2], "-inf", ARGV[1], "LIMIT", 0, ARGV[3])
+for _, id in ipairs(due) do
+	redis.call("ZREM", KEYS[2], id)
+	redis.call("LPUSH", KEYS[1], id)
+end
+local expired = redis.call("ZRANGEBYSCORE", KEYS[3], "-inf", ARGV[1], "LIMIT", 0, ARGV[3])
+for _, id in ipairs(expired) do
+	redis.call("ZREM", KEYS[3], id)
+	redis.call("RPUSH", KEYS[1], id)
+end
+local id = redis.call("RPOP", KEYS[1])
+if not id then
+	return false
+end
+redis.call("ZADD", KEYS[3], ARGV[2], id)
+return id
+`)
+
+// ackScript: KEYS processing, jobs, unique; ARGV id, has_unique
+var ackScript = redis.NewScript(`
+if redis.call("ZREM", KEYS[1], ARGV[1]) == 0 then
+	return 0
+end
+redis.call("HDEL", KEYS[2], ARGV[1])
+if ARGV[2] == "1" and redis.call("GET", KEYS[3]) == ARGV[1] then
+	redis.call("DEL", KEYS[3])
+end
+return 1
+`)
+
+// moveScript memindahkan job dari processing ke sorted set target (scheduled atau dead).
+// KEYS processing, jobs, target, unique; ARGV id, data, score, release_unique
+var moveScript = redis.NewScript(`
+if redis.call("ZREM", KEYS[1], ARGV[1]) == 0 then
+	return 0
+end
+redis.call("HSET", KEYS[2], ARGV[1], ARGV[2])
+redis.call("ZADD", KEYS[3], ARGV[3], ARGV[1])
+if ARGV[4] == "1" and redis.call("GET", KEYS[4]) == ARGV[1] then
+	redis.call("DEL", KEYS[4])
+end
+return 1
+`)
+
+// retryScript: KEYS dead, jobs, ready; ARGV id, data
+var retryScript = redis.NewScript(`
+if redis.call("ZREM", KEYS[1], ARGV[1]) == 0 then
+	return 0
+end
+redis.call("HSET", KEYS[2], ARGV[1], ARGV[2])
+redis.call("LPUSH", KEYS[3], ARGV[1])
+return 1
+`)
+
+// Enqueue menambahkan job. payload di-encode sebagai JSON. Dengan opsi Unique,
+// ErrDuplicate dikembalikan jika job dengan key yang sama belum selesai.
+func (q *Queue) Enqueue(ctx context.Context, jobType string, payload interface{}, opts ...Option) (*Job, error) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("queue: encode payload: %w", err)
+	}
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+
+	now := q.now()
+	job := &Job{
+		ID:          id,
+		Type:        jobType,
+		Payload:     raw,
+		MaxAttempts: q.cfg.MaxAttempts,
+		CreatedAt:   now,
+		RunAt:       now,
+	}
+	for _, opt := range opts {
+		opt(job)
+	}
+
+	data, err := json.Marshal(job)
+	if err != nil {
+		return nil, err
+	}
+	added, err := enqueueScript.Run(ctx, q.client,
+		[]string{q.key("jobs"), q.key("ready"), q.key("scheduled"), q.uniqueKey(job)},
+		job.ID, data, job.RunAt.UnixMilli(), now.UnixMilli(), flag(job.UniqueKey != ""),
+	).Int()
+	if err != nil {
+		return nil, err
+	}
+	if added == 0 {
+		return nil, ErrDuplicate
+	}
+
+	if job.RunAt.After(now) {
+		job.State = StateScheduled
+	} else {
+		job.State = StateReady
+	}
+	return job, nil
+}
+
+// Dequeue mengambil satu job dan menandainya sedang diproses sampai visibility
+// timeout. Mengembalikan nil tanpa error jika antrian kosong.
+func (q *Queue) Dequeue(ctx context.Context) (*Job, error) {
+	now := q.now()
+	id, err := dequeueScript.Run(ctx, q.client,
+		[]string{q.key("ready"), q.key("scheduled"), q.key("processing")},
+		now.UnixMilli(), now.Add(q.cfg.VisibilityTimeout).UnixMilli(), maintenanceBatch,
+	).Text()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := q.load(ctx, id)
+	if errors.Is(err, ErrNotFound) {
+		// data job hilang; buang ID yatim agar tidak diambil lagi
+		return nil, q.client.ZRem(ctx, q.key("processing"), id).Err()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	job.Attempts++
+	job.State = StateProcessing
+	if err := q.save(ctx, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// Ack menandai job selesai dan menghapusnya
+func (q *Queue) Ack(ctx context.Context, job *Job) error {
+	return ackScript.Run(ctx, q.client,
+		[]string{q.key("processing"), q.key("jobs"), q.uniqueKey(job)},
+		job.ID, flag(job.UniqueKey != ""),
+	).Err()
+}
+
+// Fail mencatat percobaan yang gagal. Job dijadwalkan ulang dengan backoff
+// eksponensial, atau dipindah ke dead-letter queue jika percobaan habis atau
+// error bersifat permanen.
+func (q *Queue) Fail(ctx context.Context, job *Job, cause error) error {
+	now := q.now()
+	job.LastError = cause.Error()
+
+	if job.Attempts < job.MaxAttempts && !IsPermanent(cause) {
+		job.State = StateScheduled
+		job.RunAt = now.Add(q.Backoff(job.Attempts))
+		return q.move(ctx, job, q.key("scheduled"), job.RunAt.UnixMilli(), false)
+	}
+
+	job.State = StateDead
+	job.FailedAt = &now
+	return q.move(ctx, job, q.key("dead"), now.UnixMilli(), true)
+}
+
+func (q *Queue) move(ctx context.Context, job *Job, target string, score int64, releaseUnique bool) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	return moveScript.Run(ctx, q.client,
+		[]string{q.key("processing"), q.key("jobs"), target, q.uniqueKey(job)},
+		job.ID, data, score, flag(releaseUnique),
+	).Err()
+}
+
+// Retry mengembalikan job dari dead-letter queue ke antrian dengan jumlah percobaan direset
+func (q *Queue) Retry(ctx context.Context, id string) (*Job, error) {
+	job, err := q.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	job.Attempts = 0
+	job.FailedAt = nil
+	job.RunAt = q.now()
+	job.State = StateReady
+	data, err := json.Marshal(job)
+	if err != nil {
+		return nil, err
+	}
+	moved, err := retryScript.Run(ctx, q.client,
+		[]string{q.key("dead"), q.key("jobs"), q.key("ready")},
+		job.ID, data,
+	).Int()
+	if err != nil {
+		return nil, err
+	}
+	if moved == 0 {
+		return nil, ErrNotDead
+	}
+	return job, nil
+}
+
+// Get mengembalikan job beserta state-nya
+func (q *Queue) Get(ctx context.Context, id string) (*Job, error) {
+	job, err := q.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	job.State = StateReady
+	for state, set := range map[string]string{
+		StateScheduled:  q.key("scheduled"),
+		StateProcessing: q.key("processing"),
+		StateDead:       q.key("dead"),
+	} {
+		err := q.client.ZScore(ctx, set, id).Err()
+		if err == nil {
+			job.State = state
+			break
+		}
+		if !errors.Is(err, redis.Nil) {
+			return nil, err
+		}
+	}
+	return job, nil
+}
+
+// ListDead mengembalikan job di dead-letter queue, terbaru lebih dulu, beserta totalnya
+func (q *Queue) ListDead(ctx context.Context, offset, limit int) ([]Job, int64, error) {
+	total, err := q.client.ZCard(ctx, q.key("dead")).Result()
+	if err != nil {
+		return nil, 0, err
+	}
+	ids, err := q.client.ZRevRange(ctx, q.key("dead"), int64(offset), int64(offset+limit-1)).Result()
+	if err != nil || len(ids) == 0 {
+		return []Job{}, total, err
+	}
+
+	values, err := q.client.HMGet(ctx, q.key("jobs"), ids...).Result()
+	if err != nil {
+		return nil, 0, err
+	}
+	jobs := make([]Job, 0, len(values))
+	for _, value := range values {
+		raw, ok := value.(string)
+		if !ok {
+			continue
+		}
+		var job Job
+		if err := json.Unmarshal([]byte(raw), &job); err != nil {
+			return nil, 0, err
+		}
+		job.State = StateDead
+		jobs = append(jobs, job)
+	}
+	return jobs, total, nil
+}
+
+// Stats mengembalikan jumlah job per state
+func (q *Queue) Stats(ctx context.Context) (Stats, error) {
+	pipe := q.client.Pipeline()
+	ready := pipe.LLen(ctx, q.key("ready"))
+	scheduled := pipe.ZCard(ctx, q.key("scheduled"))
+	processing := pipe.ZCard(ctx, q.key("processing"))
+	dead := pipe.ZCard(ctx, q.key("dead"))
+	if _, err := pipe.Exec(ctx); err != nil {
+		return Stats{}, err
+	}
+	return Stats{
+		Ready:      ready.Val(),
+		Scheduled:  scheduled.Val(),
+		Processing: processing.Val(),
+		Dead:       dead.Val(),
+	}, nil
+}
+
+func (q *Queue) load(ctx context.Context, id string) (*Job, error) {
+	raw, err := q.client.HGet(ctx, q.key("jobs"), id).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var job Job
+	if err := json.Unmarshal([]byte(raw), &job); err != nil {
+		return nil, fmt.Errorf("queue: decode job %s: %w", id, err)
+	}
+	return &job, nil
+}
+
+func (q *Queue) save(ctx context.Context, job *Job) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	return q.client.HSet(ctx, q.key("jobs"), job.ID, data).Err()
+}
+
+func newID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func flag(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
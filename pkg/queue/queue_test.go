@@ -0,0 +1,314 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"boilerplate/internal/testutil"
+	"boilerplate/pkg/logger"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type emailPayload struct {
+	To string `json:"to"`
+}
+
+type QueueTestSuite struct {
+	suite.Suite
+	ctx    context.Context
+	now    time.Time
+	queue  *Queue
+	worker *Worker
+}
+
+func TestQueueSuite(t *testing.T) {
+	suite.Run(t, new(QueueTestSuite))
+}
+
+func (s *QueueTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	client := testutil.NewRedis(s.T())
+
+	s.now = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	s.queue = New(client, Config{VisibilityTimeout: time.Minute, MaxAttempts: 3, BaseBackoff: time.Second, MaxBackoff: 5 * time.Second})
+	s.queue.now = func() time.Time { return s.now }
+	s.worker = NewWorker(s.queue, logger.NewLogger())
+}
+
+func (s *QueueTestSuite) advance(d time.Duration) {
+	s.now = s.now.Add(d)
+}
+
+func (s *QueueTestSuite) stats() Stats {
+	stats, err := s.queue.Stats(s.ctx)
+	s.Require().NoError(err)
+	return stats
+}
+
+func (s *QueueTestSuite) TestTypedHandlerAndAck() {
+	var received []string
+	Register(s.worker, "email", func(_ context.Context, payload emailPayload) error {
+		received = append(received, payload.To)
+		return nil
+	})
+
+	job, err := s.queue.Enqueue(s.ctx, "email", emailPayload{To: "jane@example.com"})
+	s.Require().NoError(err)
+	s.Equal(StateReady, job.State)
+
+	processed, err := s.worker.ProcessNext(s.ctx)
+	s.Require().NoError(err)
+	s.True(processed)
+	s.Equal([]string{"jane@example.com"}, received)
+	s.Equal(Stats{}, s.stats())
+
+	_, err = s.queue.Get(s.ctx, job.ID)
+	s.ErrorIs(err, ErrNotFound)
+
+	processed, err = s.worker.ProcessNext(s.ctx)
+	s.NoError(err)
+	s.False(processed)
+}
+
+func (s *QueueTestSuite) TestDelayedJob() {
+	job, err := s.queue.Enqueue(s.ctx, "email", emailPayload{}, Delay(time.Minute))
+	s.Require().NoError(err)
+	s.Equal(StateScheduled, job.State)
+
+	next, err := s.queue.Dequeue(s.ctx)
+	s.Require().NoError(err)
+	s.Nil(next)
+
+	s.advance(time.Minute)
+	next, err = s.queue.Dequeue(s.ctx)
+	s.Require().NoError(err)
+	s.Require().NotNil(next)
+	s.Equal(job.ID, next.ID)
+	s.Equal(1, next.Attempts)
+}
+
+func (s *QueueTestSuite) TestRetryWithBackoffThenDeadLetter() {
+	Register(s.worker, "email", func(context.Context, emailPayload) error {
+		return errors.New("smtp unavailable")
+	})
+	job, err := s.queue.Enqueue(s.ctx, "email", emailPayload{})
+	s.Require().NoError(err)
+
+	_, err = s.worker.ProcessNext(s.ctx)
+	s.Require().NoError(err)
+	stored, err := s.queue.Get(s.ctx, job.ID)
+	s.Require().NoError(err)
+	s.Equal(StateScheduled, stored.State)
+	s.Equal("smtp unavailable", stored.LastError)
+	s.Equal(s.now.Add(time.Second), stored.RunAt)
+
+	// belum jatuh tempo
+	processed, err := s.worker.ProcessNext(s.ctx)
+	s.Require().NoError(err)
+	s.False(processed)
+
+	s.advance(time.Second)
+	_, err = s.worker.ProcessNext(s.ctx)
+	s.Require().NoError(err)
+	stored, err = s.queue.Get(s.ctx, job.ID)
+	s.Require().NoError(err)
+	s.Equal(s.now.Add(2*time.Second), stored.RunAt)
+
+	s.advance(2 * time.Second)
+	_, err = s.worker.ProcessNext(s.ctx)
+	s.Require().NoError(err)
+	stored, err = s.queue.Get(s.ctx, job.ID)
+	s.Require().NoError(err)
+	s.Equal(StateDead, stored.State)
+	s.Equal(3, stored.Attempts)
+	s.NotNil(stored.FailedAt)
+	s.Equal(Stats{Dead: 1}, s.stats())
+}
+
+func (s *QueueTestSuite) TestBackoffIsCapped() {
+	s.Equal(time.Second, s.queue.Backoff(1))
+	s.Equal(4*time.Second, s.queue.Backoff(3))
+	s.Equal(5*time.Second, s.queue.Backoff(10))
+}
+
+func (s *QueueTestSuite) TestPermanentErrorsSkipRetries() {
+	Register(s.worker, "email", func(context.Context, emailPayload) error {
+		return nil
+	})
+	s.worker.Handle("panics", func(context.Context, *Job) error {
+		panic("boom")
+	})
+
+	for _, jobType := range []string{"unknown", "panics"} {
+		_, err := s.queue.Enqueue(s.ctx, jobType, nil, MaxAttempts(1))
+		s.Require().NoError(err)
+	}
+	bad, err := s.queue.Enqueue(s.ctx, "email", "not an object")
+	s.Require().NoError(err)
+
+	for i := 0; i < 3; i++ {
+		_, err := s.worker.ProcessNext(s.ctx)
+		s.Require().NoError(err)
+	}
+	s.Equal(Stats{Dead: 3}, s.stats())
+
+	stored, err := s.queue.Get(s.ctx, bad.ID)
+	s.Require().NoError(err)
+	s.Equal(1, stored.Attempts)
+	s.Contains(stored.LastError, "decode payload")
+}
+
+func (s *QueueTestSuite) TestUniqueKey() {
+	first, err := s.queue.Enqueue(s.ctx, "email", emailPayload{}, Unique("digest:1"))
+	s.Require().NoError(err)
+	_, err = s.queue.Enqueue(s.ctx, "email", emailPayload{}, Unique("digest:1"))
+	s.ErrorIs(err, ErrDuplicate)
+	_, err = s.queue.Enqueue(s.ctx, "email", emailPayload{}, Unique("digest:2"))
+	s.NoError(err)
+
+	job, err := s.queue.Dequeue(s.ctx)
+	s.Require().NoError(err)
+	s.Equal(first.ID, job.ID)
+	s.Require().NoError(s.queue.Ack(s.ctx, job))
+
+	_, err = s.queue.Enqueue(s.ctx, "email", emailPayload{}, Unique("digest:1"))
+	s.NoError(err, "key is released once the job completes")
+}
+
+func (s *QueueTestSuite) TestVisibilityTimeoutRecoversCrashedJob() {
+	job, err := s.queue.Enqueue(s.ctx, "email", emailPayload{}, MaxAttempts(2))
+	s.Require().NoError(err)
+
+	// worker mati setelah mengambil job
+	taken, err := s.queue.Dequeue(s.ctx)
+	s.Require().NoError(err)
+	s.Equal(job.ID, taken.ID)
+
+	next, err := s.queue.Dequeue(s.ctx)
+	s.Require().NoError(err)
+	s.Nil(next, "job stays invisible until the timeout")
+
+	s.advance(time.Minute)
+	var runs atomic.Int32
+	s.worker.Handle("email", func(context.Context, *Job) error {
+		runs.Add(1)
+		return nil
+	})
+	processed, err := s.worker.ProcessNext(s.ctx)
+	s.Require().NoError(err)
+	s.True(processed)
+	s.Equal(int32(1), runs.Load())
+	s.Equal(Stats{}, s.stats())
+
+	// job yang selalu melewati timeout berhenti dicoba
+	poison, err := s.queue.Enqueue(s.ctx, "email", emailPayload{}, MaxAttempts(1))
+	s.Require().NoError(err)
+	_, err = s.queue.Dequeue(s.ctx)
+	s.Require().NoError(err)
+	s.advance(time.Minute)
+	_, err = s.worker.ProcessNext(s.ctx)
+	s.Require().NoError(err)
+	stored, err := s.queue.Get(s.ctx, poison.ID)
+	s.Require().NoError(err)
+	s.Equal(StateDead, stored.State)
+	s.Equal(int32(1), runs.Load())
+}
+
+func (s *QueueTestSuite) TestResultIsRecordedAfterVisibilityTimeout() {
+	s.queue.cfg.VisibilityTimeout = 20 * time.Millisecond
+	s.worker.Handle("slow", func(ctx context.Context, _ *Job) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	s.worker.Handle("late", func(ctx context.Context, _ *Job) error {
+		<-ctx.Done()
+		return nil
+	})
+
+	slow, err := s.queue.Enqueue(s.ctx, "slow", emailPayload{})
+	s.Require().NoError(err)
+	processed, err := s.worker.ProcessNext(s.ctx)
+	s.Require().NoError(err)
+	s.True(processed)
+	stored, err := s.queue.Get(s.ctx, slow.ID)
+	s.Require().NoError(err)
+	s.Equal(StateScheduled, stored.State)
+	s.Contains(stored.LastError, context.DeadlineExceeded.Error())
+
+	// handler yang berhasil tepat saat timeout tetap di-ack
+	_, err = s.queue.Enqueue(s.ctx, "late", emailPayload{})
+	s.Require().NoError(err)
+	processed, err = s.worker.ProcessNext(s.ctx)
+	s.Require().NoError(err)
+	s.True(processed)
+	s.Equal(Stats{Scheduled: 1}, s.stats())
+}
+
+func (s *QueueTestSuite) TestListAndRetryDeadJobs() {
+	fail := true
+	Register(s.worker, "email", func(context.Context, emailPayload) error {
+		if fail {
+			return errors.New("smtp unavailable")
+		}
+		return nil
+	})
+	job, err := s.queue.Enqueue(s.ctx, "email", emailPayload{To: "jane@example.com"}, MaxAttempts(1))
+	s.Require().NoError(err)
+	_, err = s.worker.ProcessNext(s.ctx)
+	s.Require().NoError(err)
+
+	dead, total, err := s.queue.ListDead(s.ctx, 0, 10)
+	s.Require().NoError(err)
+	s.Equal(int64(1), total)
+	s.Require().Len(dead, 1)
+	s.Equal(job.ID, dead[0].ID)
+	s.Equal(StateDead, dead[0].State)
+
+	_, err = s.queue.Retry(s.ctx, "missing")
+	s.ErrorIs(err, ErrNotFound)
+
+	retried, err := s.queue.Retry(s.ctx, job.ID)
+	s.Require().NoError(err)
+	s.Equal(0, retried.Attempts)
+	s.Equal(Stats{Ready: 1}, s.stats())
+
+	_, err = s.queue.Retry(s.ctx, job.ID)
+	s.ErrorIs(err, ErrNotDead)
+
+	fail = false
+	_, err = s.worker.ProcessNext(s.ctx)
+	s.Require().NoError(err)
+	s.Equal(Stats{}, s.stats())
+}
+
+func (s *QueueTestSuite) TestRunStopsWhenContextIsCancelled() {
+	var runs atomic.Int32
+	s.worker.PollInterval = 10 * time.Millisecond
+	s.worker.Handle("email", func(context.Context, *Job) error {
+		runs.Add(1)
+		return nil
+	})
+	for i := 0; i < 5; i++ {
+		_, err := s.queue.Enqueue(s.ctx, "email", emailPayload{})
+		s.Require().NoError(err)
+	}
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	done := make(chan struct{})
+	go func() {
+		s.worker.Run(ctx, 3)
+		close(done)
+	}()
+
+	s.Eventually(func() bool { return runs.Load() == 5 }, time.Second, 10*time.Millisecond)
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		s.Fail("worker did not stop")
+	}
+}
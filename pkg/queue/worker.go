@@ -0,0 +1,180 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"boilerplate/pkg/logger"
+
+	"github.com/sirupsen/logrus"
+)
+
+// HandlerFunc memproses satu job. Error membuat job dicoba ulang; bungkus dengan
+// Permanent untuk langsung memindahkannya ke dead-letter queue.
+type HandlerFunc func(ctx context.Context, job *Job) error
+
+const (
+	// DefaultPollInterval adalah jeda polling saat antrian kosong
+	DefaultPollInterval = time.Second
+	// completeTimeout membatasi Ack/Fail setelah handler selesai
+	completeTimeout = 5 * time.Second
+)
+
+// Worker mengambil job dari antrian dan menjalankan handler sesuai tipe job.
+// Handler didaftarkan oleh modul sebelum Run dipanggil.
+type Worker struct {
+	queue        *Queue
+	logger       logger.Logger
+	handlers     map[string]HandlerFunc
+	PollInterval time.Duration
+}
+
+func NewWorker(queue *Queue, logger logger.Logger) *Worker {
+	return &Worker{
+		queue:        queue,
+		logger:       logger,
+		handlers:     make(map[string]HandlerFunc),
+		PollInterval: DefaultPollInterval,
+	}
+}
+
+// Handle mendaftarkan handler untuk tipe job
+func (w *Worker) Handle(jobType string, handler HandlerFunc) {
+	if _, exists := w.handlers[jobType]; exists {
+		panic("queue: handler already registered for " + jobType)
+	}
+	w.handlers[jobType] = handler
+}
+
+// Types mengembalikan tipe job yang memiliki handler
+func (w *Worker) Types() []string {
+	types := make([]string, 0, len(w.handlers))
+	for jobType := range w.handlers {
+		types = append(types, jobType)
+	}
+	return types
+}
+
+// Register mendaftarkan handler bertipe; payload didekode ke T sebelum fn dipanggil.
+// Payload yang tidak dapat didekode tidak dicoba ulang.
+func Register[T any](w *Worker, jobType string, fn func(ctx context.Context, payload T) error) {
+	w.Handle(jobType, func(ctx context.Context, job *Job) error {
+		var payload T
+		if err := job.Decode(&payload); err != nil {
+			return Permanent(fmt.Errorf("decode payload: %w", err))
+		}
+		return fn(ctx, payload)
+	})
+}
+
+// Run menjalankan concurrency goroutine pemroses sampai ctx dibatalkan, lalu
+// menunggu job yang sedang berjalan selesai
+func (w *Worker) Run(ctx context.Context, concurrency int) {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.loop(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+func (w *Worker) loop(ctx context.Context) {
+	for ctx.Err() == nil {
+		processed, err := w.ProcessNext(ctx)
+		if err != nil {
+			w.logger.WithFields(logrus.Fields{
+				"error": err.Error(),
+			}).Error("Gagal mengambil job dari antrian")
+		}
+		if processed && err == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(w.PollInterval):
+		}
+	}
+}
+
+// ProcessNext mengambil dan menjalankan satu job. Mengembalikan false jika antrian kosong.
+func (w *Worker) ProcessNext(ctx context.Context) (bool, error) {
+	job, err := w.queue.Dequeue(ctx)
+	if err != nil || job == nil {
+		return false, err
+	}
+
+	// job tetap diselesaikan walau worker sedang berhenti; batasnya visibility timeout
+	runCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), w.queue.cfg.VisibilityTimeout)
+	defer cancel()
+
+	start := time.Now()
+	runErr := w.run(runCtx, job)
+	fields := logrus.Fields{
+		"job_id":   job.ID,
+		"job_type": job.Type,
+		"attempt":  job.Attempts,
+		"duration": time.Since(start).String(),
+	}
+
+	// runCtx sudah habis jika handler berjalan sampai visibility timeout; hasil job
+	// tetap dicatat dengan context sendiri agar tidak dijalankan ulang tanpa perlu
+	completeCtx, cancelComplete := context.WithTimeout(context.WithoutCancel(ctx), completeTimeout)
+	defer cancelComplete()
+
+	if runErr == nil {
+		if err := w.queue.Ack(completeCtx, job); err != nil {
+			return true, err
+		}
+		w.logger.WithFields(fields).Debug("Job selesai")
+		return true, nil
+	}
+
+	if err := w.queue.Fail(completeCtx, job, runErr); err != nil {
+		return true, err
+	}
+	fields["error"] = runErr.Error()
+	if job.State == StateDead {
+		w.logger.WithFields(fields).Error("Job gagal dan dipindah ke dead-letter queue")
+	} else {
+		fields["retry_at"] = job.RunAt
+		w.logger.WithFields(fields).Warn("Job gagal, dijadwalkan ulang")
+	}
+	return true, nil
+}
+
+func (w *Worker) run(ctx context.Context, job *Job) (err error) {
+	// job yang berulang kali melewati visibility timeout (mis. membuat worker crash)
+	// tidak diambil lagi
+	if job.Attempts > job.MaxAttempts {
+		return Permanent(errors.New("visibility timeout exceeded on every attempt"))
+	}
+
+	handler, ok := w.handlers[job.Type]
+	if !ok {
+		return Permanent(fmt.Errorf("no handler registered for job type %q", job.Type))
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			w.logger.WithFields(logrus.Fields{
+				"job_id":   job.ID,
+				"job_type": job.Type,
+				"stack":    string(debug.Stack()),
+			}).Error("Handler job panic")
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return handler(ctx, job)
+}
@@ -2,6 +2,7 @@ package redis
 
 import (
 	"context"
+	"strconv"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -58,5 +59,104 @@ func (r *RedisClient) DeleteToken(ctx context.Context, userID uint) error {
 }
 
 func getTokenKey(userID uint) string {
-	return "user_token:" + string(userID)
+	return "user_token:" + strconv.FormatUint(uint64(userID), 10)
+}
+
+// SetOIDCState menyimpan state login OIDC (nonce, PKCE verifier) sampai callback
+func (r *RedisClient) SetOIDCState(ctx context.Context, state, payload string, expiration time.Duration) error {
+	if r.client == nil {
+		return redis.ErrClosed
+	}
+	return r.client.Set(ctx, getOIDCStateKey(state), payload, expiration).Err()
+}
+
+// TakeOIDCState mengambil dan menghapus state sehingga setiap state hanya bisa dipakai sekali
+func (r *RedisClient) TakeOIDCState(ctx context.Context, state string) (string, error) {
+	if r.client == nil {
+		return "", redis.ErrClosed
+	}
+	return r.client.GetDel(ctx, getOIDCStateKey(state)).Result()
+}
+
+func getOIDCStateKey(state string) string {
+	return "oidc_state:" + state
+}
+
+// SetAuthorizationCode menyimpan authorization code OAuth sampai ditukar di /token
+func (r *RedisClient) SetAuthorizationCode(ctx context.Context, code, payload string, expiration time.Duration) error {
+	if r.client == nil {
+		return redis.ErrClosed
+	}
+	return r.client.Set(ctx, getAuthorizationCodeKey(code), payload, expiration).Err()
+}
+
+// TakeAuthorizationCode mengambil dan menghapus code sehingga setiap code hanya bisa ditukar sekali
+func (r *RedisClient) TakeAuthorizationCode(ctx context.Context, code string) (string, error) {
+	if r.client == nil {
+		return "", redis.ErrClosed
+	}
+	return r.client.GetDel(ctx, getAuthorizationCodeKey(code)).Result()
+}
+
+func getAuthorizationCodeKey(code string) string {
+	return "oauth_code:" + code
+}
+
+// SetImpersonationToken menyimpan token impersonasi per ID token sehingga sesi user asli tidak
+// tertimpa. ID token juga dicatat per user agar semua impersonasi user dapat dicabut sekaligus.
+func (r *RedisClient) SetImpersonationToken(ctx context.Context, userID uint, tokenID, token string, expiration time.Duration) error {
+	if r.client == nil {
+		return redis.ErrClosed
+	}
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, getImpersonationTokenKey(tokenID), token, expiration)
+		pipe.SAdd(ctx, getUserImpersonationsKey(userID), tokenID)
+		pipe.Expire(ctx, getUserImpersonationsKey(userID), expiration)
+		return nil
+	})
+	return err
+}
+
+func (r *RedisClient) GetImpersonationToken(ctx context.Context, tokenID string) (string, error) {
+	if r.client == nil {
+		return "", redis.ErrClosed
+	}
+	return r.client.Get(ctx, getImpersonationTokenKey(tokenID)).Result()
+}
+
+func (r *RedisClient) DeleteImpersonationToken(ctx context.Context, tokenID string) error {
+	if r.client == nil {
+		return redis.ErrClosed
+	}
+	return r.client.Del(ctx, getImpersonationTokenKey(tokenID)).Err()
+}
+
+// DeleteUserImpersonationTokens mencabut semua token impersonasi yang bertindak sebagai user
+func (r *RedisClient) DeleteUserImpersonationTokens(ctx context.Context, userID uint) error {
+	if r.client == nil {
+		return redis.ErrClosed
+	}
+	tokenIDs, err := r.client.SMembers(ctx, getUserImpersonationsKey(userID)).Result()
+	if err != nil {
+		return err
+	}
+	keys := []string{getUserImpersonationsKey(userID)}
+	for _, tokenID := range tokenIDs {
+		keys = append(keys, getImpersonationTokenKey(tokenID))
+	}
+	return r.client.Del(ctx, keys...).Err()
+}
+
+func getImpersonationTokenKey(tokenID string) string {
+	return "impersonation_token:" + tokenID
+}
+
+func getUserImpersonationsKey(userID uint) string {
+	return "user_impersonations:" + strconv.FormatUint(uint64(userID), 10)
+}
+
+// Client mengembalikan koneksi Redis untuk subsistem yang membutuhkan perintah
+// di luar helper di atas, mis. antrian job
+func (r *RedisClient) Client() *redis.Client {
+	return r.client
 }
@@ -0,0 +1,152 @@
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"boilerplate/pkg/i18n"
+	"boilerplate/pkg/logger"
+	errs "boilerplate/shared/errors"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/go-sql-driver/mysql"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	// ProblemContentType adalah media type RFC 7807
+	ProblemContentType = "application/problem+json"
+
+	problemTypePrefix = "urn:problem:"
+
+	mysqlDuplicateEntry = 1062
+)
+
+// Problem adalah body RFC 7807 problem details
+type Problem struct {
+	Type     string                 `json:"type"`
+	Title    string                 `json:"title"`
+	Status   int                    `json:"status"`
+	Detail   string                 `json:"detail,omitempty"`
+	Instance string                 `json:"instance,omitempty"`
+	Code     string                 `json:"code"`
+	Errors   map[string]string      `json:"errors,omitempty"`
+	Details  map[string]interface{} `json:"details,omitempty"`
+}
+
+// NewHTTPErrorHandler returns the central Echo error handler. Handlers and
+// middleware simply return errors; this handler maps them to problem+json.
+func NewHTTPErrorHandler(log logger.Logger, translator *i18n.Translator) echo.HTTPErrorHandler {
+	return func(err error, c echo.Context) {
+		if c.Response().Committed {
+			return
+		}
+
+		appErr := ToAppError(err)
+		fields := logrus.Fields{
+			"code":   appErr.Code,
+			"status": appErr.Status,
+			"method": c.Request().Method,
+			"path":   c.Request().URL.Path,
+			"error":  err.Error(),
+		}
+		if appErr.Status >= http.StatusInternalServerError {
+			log.WithFields(fields).Error("Request gagal diproses")
+		} else {
+			log.WithFields(fields).Debug("Request ditolak")
+		}
+
+		if writeErr := WriteProblem(c, translator, appErr); writeErr != nil {
+			log.WithFields(logrus.Fields{"error": writeErr.Error()}).Error("Gagal menulis response error")
+		}
+	}
+}
+
+// WriteProblem menulis AppError sebagai application/problem+json dalam locale request
+func WriteProblem(c echo.Context, translator *i18n.Translator, appErr *errs.AppError) error {
+	if c.Request().Method == http.MethodHead {
+		return c.NoContent(appErr.Status)
+	}
+
+	locale, ok := c.Get(i18n.ContextKey).(string)
+	if !ok {
+		locale = translator.Match(c.Request().Header.Get("Accept-Language"))
+	}
+
+	problem := Problem{
+		Type:     problemTypePrefix + appErr.Code,
+		Title:    http.StatusText(appErr.Status),
+		Status:   appErr.Status,
+		Detail:   translator.Message(locale, appErr),
+		Instance: c.Request().URL.Path,
+		Code:     appErr.Code,
+		Details:  appErr.Details,
+	}
+
+	var ve validator.ValidationErrors
+	if errors.As(appErr.Cause, &ve) {
+		problem.Errors = translator.ValidationMessages(locale, ve)
+	}
+
+	body, err := json.Marshal(problem)
+	if err != nil {
+		return err
+	}
+	return c.Blob(appErr.Status, ProblemContentType, body)
+}
+
+// ToAppError maps any error to an AppError without leaking internal messages
+func ToAppError(err error) *errs.AppError {
+	if appErr, ok := errs.As(err); ok {
+		return appErr
+	}
+
+	var ve validator.ValidationErrors
+	if errors.As(err, &ve) {
+		return errs.ErrValidationFailed.Wrap(err)
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return errs.ErrResourceNotFound.Wrap(err)
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry {
+		return errs.ErrResourceAlreadyExists.Wrap(err)
+	}
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return errs.ErrResourceAlreadyExists.Wrap(err)
+	}
+
+	var he *echo.HTTPError
+	if errors.As(err, &he) {
+		return fromHTTPError(he)
+	}
+
+	return errs.ErrInternal.Wrap(err)
+}
+
+func fromHTTPError(he *echo.HTTPError) *errs.AppError {
+	switch he.Code {
+	case http.StatusNotFound:
+		return errs.ErrRouteNotFound.Wrap(he)
+	case http.StatusMethodNotAllowed:
+		return errs.ErrMethodNotAllowed.Wrap(he)
+	case http.StatusUnauthorized:
+		return errs.ErrUnauthorized.Wrap(he)
+	case http.StatusForbidden:
+		return errs.ErrForbidden.Wrap(he)
+	case http.StatusUnsupportedMediaType:
+		return errs.ErrUnsupportedMediaType.Wrap(he)
+	case http.StatusRequestEntityTooLarge:
+		return errs.ErrPayloadTooLarge.Wrap(he)
+	}
+
+	if he.Code >= http.StatusInternalServerError {
+		return errs.ErrInternal.Wrap(he)
+	}
+	return errs.New(errs.ErrBadRequest.Code, he.Code, http.StatusText(he.Code)).Wrap(he)
+}
@@ -0,0 +1,107 @@
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"boilerplate/pkg/i18n"
+	"boilerplate/pkg/logger"
+	errs "boilerplate/shared/errors"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/go-sql-driver/mysql"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type ProblemTestSuite struct {
+	suite.Suite
+	e *echo.Echo
+}
+
+func TestProblemSuite(t *testing.T) {
+	suite.Run(t, new(ProblemTestSuite))
+}
+
+func (s *ProblemTestSuite) SetupTest() {
+	translator, err := i18n.New(validator.New())
+	s.Require().NoError(err)
+
+	s.e = echo.New()
+	s.e.HTTPErrorHandler = NewHTTPErrorHandler(logger.NewLogger(), translator)
+}
+
+func (s *ProblemTestSuite) TestHTTPErrorHandler() {
+	tests := []struct {
+		name         string
+		err          error
+		expectedCode string
+		status       int
+		hiddenDetail string
+	}{
+		{
+			name:         "application error",
+			err:          errs.ErrEmailAlreadyRegistered,
+			expectedCode: "user.email_taken",
+			status:       http.StatusConflict,
+		},
+		{
+			name:         "record not found",
+			err:          gorm.ErrRecordNotFound,
+			expectedCode: "resource.not_found",
+			status:       http.StatusNotFound,
+			hiddenDetail: "record not found",
+		},
+		{
+			name:         "mysql duplicate entry",
+			err:          &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'a@b.c' for key 'users.email'"},
+			expectedCode: "resource.already_exists",
+			status:       http.StatusConflict,
+			hiddenDetail: "Duplicate entry",
+		},
+		{
+			name:         "wrapped cause is not leaked",
+			err:          errs.ErrInvalidPayload.Wrap(errors.New("unexpected EOF")),
+			expectedCode: "request.invalid_payload",
+			status:       http.StatusBadRequest,
+			hiddenDetail: "unexpected EOF",
+		},
+		{
+			name:         "echo body limit",
+			err:          echo.ErrStatusRequestEntityTooLarge,
+			expectedCode: "request.payload_too_large",
+			status:       http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:         "unknown error",
+			err:          errors.New("dial tcp 10.0.0.1:3306: connection refused"),
+			expectedCode: "internal.error",
+			status:       http.StatusInternalServerError,
+			hiddenDetail: "connection refused",
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			req := httptest.NewRequest(http.MethodGet, "/resource", nil)
+			rec := httptest.NewRecorder()
+			s.e.HTTPErrorHandler(tt.err, s.e.NewContext(req, rec))
+
+			s.Equal(tt.status, rec.Code)
+			s.Equal(ProblemContentType, rec.Header().Get(echo.HeaderContentType))
+			if tt.hiddenDetail != "" {
+				s.NotContains(rec.Body.String(), tt.hiddenDetail)
+			}
+
+			var problem Problem
+			s.NoError(json.Unmarshal(rec.Body.Bytes(), &problem))
+			s.Equal(tt.expectedCode, problem.Code)
+			s.Equal(tt.status, problem.Status)
+			s.Equal("/resource", problem.Instance)
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package search
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/mapping"
+	"github.com/blevesearch/bleve/v2/search/highlight/highlighter/html"
+	"github.com/blevesearch/bleve/v2/search/query"
+)
+
+// typeField menyimpan jenis dokumen di index Bleve; tidak ikut dicari
+const typeField = "doc_type"
+
+// clearBatchSize adalah jumlah dokumen yang dihapus per batch saat Clear
+const clearBatchSize = 1000
+
+// EmbeddedSearcher adalah index Bleve di memori proses. Setiap kata dicocokkan
+// sebagai kata utuh, prefix, atau dengan salah ketik (fuzzy) sesuai panjangnya.
+// Nilai kolom disimpan untuk highlight, termasuk dokumen sensitif, sehingga index
+// sengaja tidak ditulis ke disk; index kosong setiap proses dimulai dan perlu diisi
+// ulang dengan reindex.
+type EmbeddedSearcher struct {
+	index bleve.Index
+}
+
+// NewEmbeddedSearcher membuat index kosong di memori
+func NewEmbeddedSearcher() (*EmbeddedSearcher, error) {
+	index, err := bleve.NewMemOnly(indexMapping())
+	if err != nil {
+		return nil, err
+	}
+	return &EmbeddedSearcher{index: index}, nil
+}
+
+func indexMapping() *mapping.IndexMappingImpl {
+	typeMapping := bleve.NewKeywordFieldMapping()
+	typeMapping.IncludeInAll = false
+
+	indexMapping := bleve.NewIndexMapping()
+	indexMapping.DefaultMapping.AddFieldMappingsAt(typeField, typeMapping)
+	return indexMapping
+}
+
+// Close menutup index
+func (s *EmbeddedSearcher) Close() error {
+	return s.index.Close()
+}
+
+func (s *EmbeddedSearcher) Index(_ context.Context, docs ...Document) error {
+	batch := s.index.NewBatch()
+	for _, doc := range docs {
+		fields := make(map[string]interface{}, len(doc.Fields)+1)
+		for name, value := range doc.Fields {
+			fields[name] = value
+		}
+		fields[typeField] = doc.Type
+		if err := batch.Index(documentID(doc.Type, doc.ID), fields); err != nil {
+			return err
+		}
+	}
+	return s.index.Batch(batch)
+}
+
+func (s *EmbeddedSearcher) Delete(_ context.Context, docType string, ids ...uint) error {
+	batch := s.index.NewBatch()
+	for _, id := range ids {
+		batch.Delete(documentID(docType, id))
+	}
+	return s.index.Batch(batch)
+}
+
+func (s *EmbeddedSearcher) Clear(ctx context.Context, docType string) error {
+	for {
+		request := bleve.NewSearchRequestOptions(typeQuery([]string{docType}), clearBatchSize, 0, false)
+		result, err := s.index.SearchInContext(ctx, request)
+		if err != nil {
+			return err
+		}
+		if len(result.Hits) == 0 {
+			return nil
+		}
+
+		batch := s.index.NewBatch()
+		for _, hit := range result.Hits {
+			batch.Delete(hit.ID)
+		}
+		if err := s.index.Batch(batch); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *EmbeddedSearcher) Search(ctx context.Context, q Query) ([]Hit, error) {
+	terms := Terms(q.Text)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	clauses := make([]query.Query, 0, len(terms)+1)
+	for _, term := range terms {
+		clauses = append(clauses, termQuery(term))
+	}
+	if len(q.Types) > 0 {
+		clauses = append(clauses, typeQuery(q.Types))
+	}
+
+	request := bleve.NewSearchRequestOptions(bleve.NewConjunctionQuery(clauses...), q.limit(), 0, false)
+	request.Highlight = bleve.NewHighlightWithStyle(html.Name)
+	request.SortBy([]string{"-_score", "_id"})
+	result, err := s.index.SearchInContext(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([]Hit, 0, len(result.Hits))
+	for _, match := range result.Hits {
+		docType, id, ok := parseDocumentID(match.ID)
+		if !ok {
+			continue
+		}
+		hits = append(hits, Hit{
+			Type:       docType,
+			ID:         id,
+			Score:      match.Score,
+			Highlights: match.Fragments,
+		})
+	}
+	return hits, nil
+}
+
+// termQuery mencocokkan satu kata sebagai kata utuh (bobot tertinggi), prefix, atau
+// dengan salah ketik
+func termQuery(term string) query.Query {
+	exact := bleve.NewTermQuery(term)
+	exact.SetBoost(3)
+	prefix := bleve.NewPrefixQuery(term)
+	prefix.SetBoost(2)
+	clauses := []query.Query{exact, prefix}
+
+	if fuzziness := fuzziness(term); fuzziness > 0 {
+		fuzzy := bleve.NewFuzzyQuery(term)
+		fuzzy.SetFuzziness(fuzziness)
+		clauses = append(clauses, fuzzy)
+	}
+	return bleve.NewDisjunctionQuery(clauses...)
+}
+
+// fuzziness adalah jumlah salah ketik yang ditoleransi: tidak ada untuk kata
+// pendek agar hasil tidak terlalu longgar, satu untuk kata 4-7 huruf dan dua untuk
+// kata yang lebih panjang
+func fuzziness(term string) int {
+	switch length := len([]rune(term)); {
+	case length < 4:
+		return 0
+	case length < 8:
+		return 1
+	default:
+		return 2
+	}
+}
+
+func typeQuery(types []string) query.Query {
+	clauses := make([]query.Query, len(types))
+	for i, docType := range types {
+		term := bleve.NewTermQuery(docType)
+		term.SetField(typeField)
+		clauses[i] = term
+	}
+	return bleve.NewDisjunctionQuery(clauses...)
+}
+
+func documentID(docType string, id uint) string {
+	return docType + ":" + strconv.FormatUint(uint64(id), 10)
+}
+
+func parseDocumentID(value string) (string, uint, bool) {
+	docType, rawID, found := strings.Cut(value, ":")
+	if !found {
+		return "", 0, false
+	}
+	id, err := strconv.ParseUint(rawID, 10, 32)
+	if err != nil {
+		return "", 0, false
+	}
+	return docType, uint(id), true
+}
@@ -0,0 +1,54 @@
+package search
+
+import (
+	"context"
+
+	"boilerplate/pkg/logger"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Indexer dipakai service untuk memperbarui index setelah perubahan di-commit.
+// Kegagalan dicatat lalu dikembalikan; service mengabaikannya karena perubahan
+// sudah tersimpan dan reindex atau event outbox memperbaiki index. Indexer nil
+// tidak melakukan apa pun sehingga service tetap berjalan tanpa pencarian.
+type Indexer struct {
+	searcher Searcher
+	logger   logger.Logger
+}
+
+// NewIndexer membuat indexer di atas searcher
+func NewIndexer(searcher Searcher, logger logger.Logger) *Indexer {
+	return &Indexer{searcher: searcher, logger: logger}
+}
+
+// Index menyimpan atau mengganti dokumen
+func (i *Indexer) Index(ctx context.Context, docs ...Document) error {
+	if i == nil || len(docs) == 0 {
+		return nil
+	}
+	if err := i.searcher.Index(ctx, docs...); err != nil {
+		i.fail(docs[0].Type, err, "Gagal memperbarui index pencarian")
+		return err
+	}
+	return nil
+}
+
+// Delete menghapus dokumen dari index
+func (i *Indexer) Delete(ctx context.Context, docType string, ids ...uint) error {
+	if i == nil || len(ids) == 0 {
+		return nil
+	}
+	if err := i.searcher.Delete(ctx, docType, ids...); err != nil {
+		i.fail(docType, err, "Gagal menghapus dokumen dari index pencarian")
+		return err
+	}
+	return nil
+}
+
+func (i *Indexer) fail(docType string, err error, message string) {
+	i.logger.WithFields(logrus.Fields{
+		"type":  docType,
+		"error": err.Error(),
+	}).Warn(message)
+}
@@ -0,0 +1,142 @@
+package search
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"boilerplate/pkg/fieldcrypt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// sensitiveTokenLength adalah panjang hex blind index per kata di dokumen sensitif
+const sensitiveTokenLength = 16
+
+// MySQLDocument adalah baris index di tabel search_documents
+type MySQLDocument struct {
+	ID        uint      `gorm:"primaryKey"`
+	DocType   string    `gorm:"size:32;not null;uniqueIndex:idx_search_documents_doc,priority:1"`
+	DocID     uint      `gorm:"not null;uniqueIndex:idx_search_documents_doc,priority:2"`
+	Content   string    `gorm:"type:text;not null;index:idx_search_documents_content,class:FULLTEXT"`
+	UpdatedAt time.Time `gorm:"not null"`
+}
+
+func (MySQLDocument) TableName() string {
+	return "search_documents"
+}
+
+// MySQLSearcher mencari dengan MATCH ... AGAINST di tabel search_documents.
+// Dokumen biasa disimpan sebagai plaintext dan dicocokkan per prefix kata. Dokumen
+// sensitif hanya disimpan sebagai blind index per kata sehingga hanya cocok dengan
+// kata yang utuh, tanpa prefix; highlight dibuat oleh pemanggil dari data asli.
+type MySQLSearcher struct {
+	db *gorm.DB
+}
+
+// NewMySQLSearcher membuat searcher MySQL. Tabel dibuat oleh migrasi database.
+func NewMySQLSearcher(db *gorm.DB) *MySQLSearcher {
+	return &MySQLSearcher{db: db}
+}
+
+func (s *MySQLSearcher) Index(ctx context.Context, docs ...Document) error {
+	if len(docs) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	rows := make([]MySQLDocument, len(docs))
+	for i, doc := range docs {
+		rows[i] = MySQLDocument{
+			DocType:   doc.Type,
+			DocID:     doc.ID,
+			Content:   content(doc),
+			UpdatedAt: now,
+		}
+	}
+	return s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "doc_type"}, {Name: "doc_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"content", "updated_at"}),
+	}).Create(&rows).Error
+}
+
+func (s *MySQLSearcher) Delete(ctx context.Context, docType string, ids ...uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return s.db.WithContext(ctx).
+		Where("doc_type = ? AND doc_id IN ?", docType, ids).
+		Delete(&MySQLDocument{}).Error
+}
+
+func (s *MySQLSearcher) Clear(ctx context.Context, docType string) error {
+	return s.db.WithContext(ctx).Where("doc_type = ?", docType).Delete(&MySQLDocument{}).Error
+}
+
+func (s *MySQLSearcher) Search(ctx context.Context, query Query) ([]Hit, error) {
+	expression := booleanQuery(Terms(query.Text))
+	if expression == "" {
+		return nil, nil
+	}
+
+	db := s.db.WithContext(ctx).Model(&MySQLDocument{}).
+		Select("doc_type, doc_id, MATCH(content) AGAINST (? IN BOOLEAN MODE) AS score", expression).
+		Where("MATCH(content) AGAINST (? IN BOOLEAN MODE)", expression)
+	if len(query.Types) > 0 {
+		db = db.Where("doc_type IN ?", query.Types)
+	}
+
+	var rows []struct {
+		DocType string
+		DocID   uint
+		Score   float64
+	}
+	if err := db.Order("score DESC").Order("doc_type").Order("doc_id").Limit(query.limit()).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	hits := make([]Hit, len(rows))
+	for i, row := range rows {
+		hits[i] = Hit{Type: row.DocType, ID: row.DocID, Score: row.Score}
+	}
+	return hits, nil
+}
+
+// content menggabungkan nilai kolom dokumen. Kata di dokumen sensitif diganti blind
+// index-nya sehingga tabel index tidak memuat data pribadi.
+func content(doc Document) string {
+	names := make([]string, 0, len(doc.Fields))
+	for name := range doc.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	values := make([]string, 0, len(names))
+	for _, name := range names {
+		if !doc.Sensitive {
+			values = append(values, doc.Fields[name])
+			continue
+		}
+		for _, term := range Terms(doc.Fields[name]) {
+			values = append(values, sensitiveToken(term))
+		}
+	}
+	return strings.Join(values, "\n")
+}
+
+func sensitiveToken(term string) string {
+	return fieldcrypt.BlindIndex(term)[:sensitiveTokenLength]
+}
+
+// booleanQuery mewajibkan setiap kata cocok, baik sebagai prefix kata plaintext
+// maupun sebagai blind index kata utuh. Terms hanya berisi huruf dan angka sehingga
+// tidak ada operator boolean dari input user.
+func booleanQuery(terms []string) string {
+	clauses := make([]string, len(terms))
+	for i, term := range terms {
+		clauses[i] = "+(" + term + "* " + sensitiveToken(term) + ")"
+	}
+	return strings.Join(clauses, " ")
+}
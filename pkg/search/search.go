@@ -0,0 +1,150 @@
+package search
+
+import (
+	"context"
+	"html"
+	"strings"
+	"unicode"
+)
+
+const (
+	// BackendMySQL memakai index FULLTEXT di tabel search_documents sehingga dipakai
+	// bersama semua instance
+	BackendMySQL = "mysql"
+	// BackendEmbedded memakai index Bleve di memori proses server. Index hanya memuat
+	// perubahan dari proses itu; cocok untuk setup satu instance, SQLite dan test.
+	BackendEmbedded = "embedded"
+)
+
+const (
+	// DefaultLimit adalah jumlah hit maksimal jika Query.Limit tidak diisi
+	DefaultLimit = 20
+	// MaxLimit membatasi Query.Limit
+	MaxLimit = 100
+	// MinTermLength adalah panjang minimal kata yang dicari; kata lebih pendek diabaikan
+	MinTermLength = 2
+)
+
+// Document adalah satu entitas yang dapat dicari. Fields berisi nama kolom dan
+// nilainya, mis. {"name": ..., "description": ...}. Sensitive menandai dokumen
+// berisi data pribadi yang terenkripsi di database; backend tidak boleh menyimpan
+// nilainya sebagai plaintext di penyimpanan permanen.
+type Document struct {
+	Type      string
+	ID        uint
+	Fields    map[string]string
+	Sensitive bool
+}
+
+// Query adalah permintaan pencarian. Types kosong berarti semua jenis dokumen.
+type Query struct {
+	Text  string
+	Types []string
+	Limit int
+}
+
+// Hit adalah dokumen yang cocok, terurut dari skor tertinggi. Highlights berisi
+// potongan nilai kolom dengan kata yang cocok dibungkus <mark>; backend yang tidak
+// dapat membuat highlight membiarkannya kosong.
+type Hit struct {
+	Type       string
+	ID         uint
+	Score      float64
+	Highlights map[string][]string
+}
+
+// Searcher adalah index pencarian full-text
+type Searcher interface {
+	// Index menyimpan atau mengganti dokumen
+	Index(ctx context.Context, docs ...Document) error
+	// Delete menghapus dokumen; ID yang tidak ada diabaikan
+	Delete(ctx context.Context, docType string, ids ...uint) error
+	// Clear menghapus semua dokumen satu jenis, dipakai sebelum reindex
+	Clear(ctx context.Context, docType string) error
+	// Search mencari dokumen yang memuat semua kata di query
+	Search(ctx context.Context, query Query) ([]Hit, error)
+}
+
+// Terms memecah teks menjadi kata huruf kecil tanpa duplikat. Tanda baca memisahkan
+// kata sehingga "john.doe@example.com" menjadi john, doe, example dan com.
+func Terms(text string) []string {
+	seen := make(map[string]bool)
+	var terms []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), isSeparator) {
+		if len([]rune(word)) < MinTermLength || seen[word] {
+			continue
+		}
+		seen[word] = true
+		terms = append(terms, word)
+	}
+	return terms
+}
+
+func isSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+}
+
+// Highlight menandai kata di setiap kolom yang diawali salah satu term, sama dengan
+// pencocokan prefix di backend MySQL. Kolom tanpa kata yang cocok tidak disertakan.
+func Highlight(fields map[string]string, terms []string) map[string][]string {
+	highlights := make(map[string][]string)
+	for name, value := range fields {
+		if fragment, ok := highlight(value, terms); ok {
+			highlights[name] = []string{fragment}
+		}
+	}
+	return highlights
+}
+
+func highlight(value string, terms []string) (string, bool) {
+	var b strings.Builder
+	matched := false
+	start := -1
+	flush := func(end int) {
+		word := value[start:end]
+		if matchesAny(strings.ToLower(word), terms) {
+			matched = true
+			b.WriteString("<mark>" + html.EscapeString(word) + "</mark>")
+		} else {
+			b.WriteString(html.EscapeString(word))
+		}
+		start = -1
+	}
+	for i, r := range value {
+		if isSeparator(r) {
+			if start >= 0 {
+				flush(i)
+			}
+			b.WriteString(html.EscapeString(string(r)))
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		flush(len(value))
+	}
+	return b.String(), matched
+}
+
+func matchesAny(word string, terms []string) bool {
+	for _, term := range terms {
+		if strings.HasPrefix(word, term) {
+			return true
+		}
+	}
+	return false
+}
+
+// limit mengembalikan batas hit yang valid untuk query
+func (q Query) limit() int {
+	switch {
+	case q.Limit <= 0:
+		return DefaultLimit
+	case q.Limit > MaxLimit:
+		return MaxLimit
+	default:
+		return q.Limit
+	}
+}
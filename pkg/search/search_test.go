@@ -0,0 +1,152 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"boilerplate/pkg/fieldcrypt"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type EmbeddedSearcherTestSuite struct {
+	suite.Suite
+	ctx      context.Context
+	searcher *EmbeddedSearcher
+}
+
+func TestEmbeddedSearcherSuite(t *testing.T) {
+	suite.Run(t, new(EmbeddedSearcherTestSuite))
+}
+
+func (s *EmbeddedSearcherTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	searcher, err := NewEmbeddedSearcher()
+	s.Require().NoError(err)
+	s.T().Cleanup(func() { _ = searcher.Close() })
+	s.searcher = searcher
+
+	s.Require().NoError(s.searcher.Index(s.ctx,
+		Document{Type: "category", ID: 1, Fields: map[string]string{"name": "Electronics", "description": "Phones & <laptops>"}},
+		Document{Type: "category", ID: 2, Fields: map[string]string{"name": "Books", "description": "Novels by John Grisham"}},
+		Document{Type: "user", ID: 1, Fields: map[string]string{"name": "John Smith", "email": "john@example.com"}, Sensitive: true},
+	))
+}
+
+func (s *EmbeddedSearcherTestSuite) search(text string, types ...string) []Hit {
+	hits, err := s.searcher.Search(s.ctx, Query{Text: text, Types: types})
+	s.Require().NoError(err)
+	return hits
+}
+
+func (s *EmbeddedSearcherTestSuite) TestRanksAndHighlights() {
+	hits := s.search("john")
+	s.Require().Len(hits, 2)
+	for _, hit := range hits {
+		s.Positive(hit.Score)
+	}
+	s.GreaterOrEqual(hits[0].Score, hits[1].Score)
+
+	user := hits[0]
+	if user.Type != "user" {
+		user = hits[1]
+	}
+	s.Equal(uint(1), user.ID)
+	s.Contains(user.Highlights["name"][0], "<mark>John</mark>")
+}
+
+func (s *EmbeddedSearcherTestSuite) TestToleratesTyposAndPrefixes() {
+	hits := s.search("electornics")
+	s.Require().Len(hits, 1)
+	s.Equal("category", hits[0].Type)
+	s.Contains(hits[0].Highlights["name"][0], "<mark>Electronics</mark>")
+
+	hits = s.search("lapt")
+	s.Require().Len(hits, 1)
+	s.Equal(uint(1), hits[0].ID)
+	// nilai kolom di-escape sebelum ditandai
+	s.Contains(hits[0].Highlights["description"][0], "&lt;<mark>laptops</mark>&gt;")
+
+	// kata pendek tidak ditoleransi salah ketik
+	s.Empty(s.search("jon"))
+}
+
+func (s *EmbeddedSearcherTestSuite) TestEveryTermMustMatch() {
+	s.Len(s.search("john grisham"), 1)
+	s.Empty(s.search("john electronics"))
+	s.Empty(s.search("!!"))
+}
+
+func (s *EmbeddedSearcherTestSuite) TestFiltersByType() {
+	hits := s.search("john", "user")
+	s.Require().Len(hits, 1)
+	s.Equal("user", hits[0].Type)
+}
+
+func (s *EmbeddedSearcherTestSuite) TestIndexReplacesAndDeleteRemoves() {
+	s.Require().NoError(s.searcher.Index(s.ctx, Document{Type: "category", ID: 2, Fields: map[string]string{"name": "Magazines"}}))
+	s.Len(s.search("john"), 1)
+	s.Len(s.search("magazines"), 1)
+
+	s.Require().NoError(s.searcher.Delete(s.ctx, "user", 1, 99))
+	s.Empty(s.search("john"))
+}
+
+func (s *EmbeddedSearcherTestSuite) TestClearRemovesOneType() {
+	s.Require().NoError(s.searcher.Clear(s.ctx, "category"))
+	s.Empty(s.search("electronics"))
+	s.Len(s.search("john"), 1)
+}
+
+func TestTerms(t *testing.T) {
+	got := strings.Join(Terms("John.Doe@Example.com  john a"), ",")
+	if got != "john,doe,example,com" {
+		t.Errorf("unexpected terms %q", got)
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	highlights := Highlight(map[string]string{
+		"name":        "Jo <Smith>",
+		"description": "nothing here",
+	}, []string{"jo", "smi"})
+
+	if len(highlights) != 1 {
+		t.Fatalf("expected only matching fields, got %v", highlights)
+	}
+	if got := highlights["name"][0]; got != "<mark>Jo</mark> &lt;<mark>Smith</mark>&gt;" {
+		t.Errorf("unexpected highlight %q", got)
+	}
+}
+
+func TestMySQLContentHidesSensitiveValues(t *testing.T) {
+	keyring, err := fieldcrypt.NewKeyring(1, map[uint32][]byte{1: bytes.Repeat([]byte{1}, fieldcrypt.KeySize)}, []byte("blind"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous := fieldcrypt.DefaultKeyring()
+	fieldcrypt.SetDefaultKeyring(keyring)
+	t.Cleanup(func() { fieldcrypt.SetDefaultKeyring(previous) })
+
+	plain := content(Document{Type: "category", Fields: map[string]string{"name": "Books", "description": "Novels"}})
+	if plain != "Novels\nBooks" {
+		t.Errorf("unexpected category content %q", plain)
+	}
+
+	sensitive := content(Document{Type: "user", Fields: map[string]string{"name": "John Smith", "email": "john@example.com"}, Sensitive: true})
+	if strings.Contains(strings.ToLower(sensitive), "john") {
+		t.Errorf("sensitive content leaks plaintext: %q", sensitive)
+	}
+	// kata yang sama menghasilkan token yang sama di semua kolom
+	token := sensitiveToken("john")
+	if strings.Count(sensitive, token) != 2 {
+		t.Errorf("expected token %s for both fields in %q", token, sensitive)
+	}
+
+	query := booleanQuery(Terms("John s"))
+	if query != "+(john* "+token+")" {
+		t.Errorf("unexpected boolean query %q", query)
+	}
+}
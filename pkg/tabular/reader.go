@@ -0,0 +1,179 @@
+package tabular
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Read membaca seluruh baris data dari file. Baris pertama CSV dan sheet pertama
+// XLSX adalah header; JSON berupa array object. Nama kolom dinormalisasi menjadi
+// huruf kecil dan baris yang seluruhnya kosong dilewati. maxRows > 0 membatasi
+// jumlah baris data.
+func Read(r io.Reader, format string, maxRows int) ([]Record, error) {
+	switch format {
+	case FormatCSV:
+		return readCSV(r, maxRows)
+	case FormatJSON:
+		return readJSON(r, maxRows)
+	case FormatXLSX:
+		return readXLSX(r, maxRows)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+func readCSV(r io.Reader, maxRows int) ([]Record, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	var header []string
+	var records []Record
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header == nil {
+			header = normalizeHeader(row)
+			continue
+		}
+
+		line, _ := reader.FieldPos(0)
+		record, ok := newRecord(line, header, row, unescapeFormula)
+		if !ok {
+			continue
+		}
+		if maxRows > 0 && len(records) == maxRows {
+			return nil, ErrTooManyRows
+		}
+		records = append(records, record)
+	}
+}
+
+func readJSON(r io.Reader, maxRows int) ([]Record, error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+
+	var rows []map[string]interface{}
+	if err := decoder.Decode(&rows); err != nil {
+		return nil, err
+	}
+	if maxRows > 0 && len(rows) > maxRows {
+		return nil, ErrTooManyRows
+	}
+
+	records := make([]Record, 0, len(rows))
+	for i, row := range rows {
+		values := make(map[string]string, len(row))
+		for column, value := range row {
+			switch v := value.(type) {
+			case nil:
+				values[normalizeColumn(column)] = ""
+			case string:
+				values[normalizeColumn(column)] = v
+			case json.Number, bool:
+				values[normalizeColumn(column)] = fmt.Sprint(v)
+			default:
+				return nil, fmt.Errorf("row %d column %q: nested values are not supported", i+1, column)
+			}
+		}
+		records = append(records, Record{Line: i + 1, Values: values})
+	}
+	return records, nil
+}
+
+func readXLSX(r io.Reader, maxRows int) ([]Record, error) {
+	file, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	sheets := file.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, nil
+	}
+	rows, err := file.Rows(sheets[0])
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var header []string
+	var records []Record
+	for line := 1; rows.Next(); line++ {
+		row, err := rows.Columns()
+		if err != nil {
+			return nil, err
+		}
+		if header == nil {
+			if len(row) > 0 {
+				header = normalizeHeader(row)
+			}
+			continue
+		}
+
+		record, ok := newRecord(line, header, row, nil)
+		if !ok {
+			continue
+		}
+		if maxRows > 0 && len(records) == maxRows {
+			return nil, ErrTooManyRows
+		}
+		records = append(records, record)
+	}
+	return records, rows.Error()
+}
+
+func normalizeHeader(row []string) []string {
+	header := make([]string, len(row))
+	for i, column := range row {
+		header[i] = normalizeColumn(column)
+	}
+	return header
+}
+
+// newRecord memetakan nilai baris ke kolom header. Nilai tanpa header diabaikan;
+// ok bernilai false untuk baris yang seluruhnya kosong.
+func newRecord(line int, header, row []string, transform func(string) string) (Record, bool) {
+	values := make(map[string]string, len(header))
+	blank := true
+	for i, column := range header {
+		if column == "" || i >= len(row) {
+			continue
+		}
+		value := row[i]
+		if transform != nil {
+			value = transform(value)
+		}
+		values[column] = value
+		if strings.TrimSpace(value) != "" {
+			blank = false
+		}
+	}
+	return Record{Line: line, Values: values}, !blank
+}
+
+// Sniff menebak format dari isi file: XLSX adalah arsip ZIP dan JSON diawali array
+func Sniff(data []byte) string {
+	trimmed := bytes.TrimLeft(bytes.TrimPrefix(data, []byte("\ufeff")), " \t\r\n")
+	switch {
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")):
+		return FormatXLSX
+	case bytes.HasPrefix(trimmed, []byte("[")):
+		return FormatJSON
+	default:
+		return FormatCSV
+	}
+}
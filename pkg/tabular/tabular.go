@@ -0,0 +1,90 @@
+package tabular
+
+import (
+	"errors"
+	"mime"
+	"strings"
+)
+
+// Format file tabel yang didukung
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+	FormatXLSX = "xlsx"
+)
+
+// Formats adalah semua format yang didukung
+var Formats = []string{FormatCSV, FormatJSON, FormatXLSX}
+
+var (
+	// ErrUnsupportedFormat dikembalikan untuk format yang tidak dikenal
+	ErrUnsupportedFormat = errors.New("tabular: unsupported format")
+	// ErrTooManyRows dikembalikan saat file berisi lebih banyak baris dari batas
+	ErrTooManyRows = errors.New("tabular: too many rows")
+)
+
+var contentTypes = map[string]string{
+	FormatCSV:  "text/csv",
+	FormatJSON: "application/json",
+	FormatXLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+// ContentType adalah media type file untuk format
+func ContentType(format string) string {
+	return contentTypes[format]
+}
+
+// FormatOf menentukan format dari header Content-Type
+func FormatOf(contentType string) (string, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", false
+	}
+	if mediaType == "application/csv" {
+		return FormatCSV, true
+	}
+	for format, known := range contentTypes {
+		if mediaType == known {
+			return format, true
+		}
+	}
+	return "", false
+}
+
+// Record adalah satu baris data. Line adalah nomor baris di file (CSV dan XLSX,
+// termasuk header) atau urutan elemen array (JSON), dimulai dari 1.
+type Record struct {
+	Line   int
+	Values map[string]string
+}
+
+// Get mengembalikan nilai kolom tanpa spasi di awal dan akhir
+func (r Record) Get(column string) string {
+	return strings.TrimSpace(r.Values[column])
+}
+
+// normalizeColumn menyamakan nama kolom header, mis. " Name " menjadi "name"
+func normalizeColumn(name string) string {
+	return strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+}
+
+// formulaPrefixes adalah karakter awal yang membuat spreadsheet menjalankan nilai
+// sebagai formula (CSV injection)
+const formulaPrefixes = "=+-@"
+
+// escapeFormula mencegah nilai CSV dijalankan sebagai formula saat dibuka di
+// spreadsheet dengan menambahkan apostrof di depannya
+func escapeFormula(value string) string {
+	if value != "" && strings.ContainsRune(formulaPrefixes, rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// unescapeFormula membalik escapeFormula sehingga file export dapat diimport ulang
+func unescapeFormula(value string) string {
+	if len(value) > 1 && value[0] == '\'' && strings.ContainsRune(formulaPrefixes, rune(value[1])) {
+		return value[1:]
+	}
+	return value
+}
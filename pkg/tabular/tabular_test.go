@@ -0,0 +1,115 @@
+package tabular
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type TabularTestSuite struct {
+	suite.Suite
+}
+
+func TestTabularSuite(t *testing.T) {
+	suite.Run(t, new(TabularTestSuite))
+}
+
+var columns = []string{"id", "name", "description", "created_at"}
+
+func (s *TabularTestSuite) roundTrip(format string) []Record {
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	writer, err := NewWriter(&buf, format, columns)
+	s.Require().NoError(err)
+	s.Require().NoError(writer.Write(uint(1), "Books", "=HYPERLINK(\"x\")", createdAt))
+	s.Require().NoError(writer.Write(uint(2), "Électronique, \"hi-fi\"", "", createdAt))
+	s.Require().NoError(writer.Close())
+
+	s.Equal(format, Sniff(buf.Bytes()))
+	records, err := Read(&buf, format, 0)
+	s.Require().NoError(err)
+	s.Require().Len(records, 2)
+	return records
+}
+
+func (s *TabularTestSuite) TestRoundTripsEveryFormat() {
+	for _, format := range Formats {
+		s.Run(format, func() {
+			records := s.roundTrip(format)
+			s.Equal("1", records[0].Get("id"))
+			s.Equal("Books", records[0].Get("name"))
+			s.Equal("=HYPERLINK(\"x\")", records[0].Get("description"))
+			s.Equal("Électronique, \"hi-fi\"", records[1].Get("name"))
+			s.Empty(records[1].Get("description"))
+			s.Equal("2024-05-01T10:00:00Z", records[1].Get("created_at"))
+		})
+	}
+}
+
+func (s *TabularTestSuite) TestCSVEscapesFormulas() {
+	var buf bytes.Buffer
+	writer, err := NewWriter(&buf, FormatCSV, []string{"name"})
+	s.Require().NoError(err)
+	s.Require().NoError(writer.Write("=1+1"))
+	s.Require().NoError(writer.Close())
+	s.Equal("name\n'=1+1\n", buf.String())
+}
+
+func (s *TabularTestSuite) TestCSVNormalizesHeaderAndSkipsBlankRows() {
+	input := "\ufeff Name ,DESCRIPTION,\nBooks,Novels,ignored\n,,\n\nToys\n"
+	records, err := Read(strings.NewReader(input), FormatCSV, 0)
+	s.Require().NoError(err)
+	s.Require().Len(records, 2)
+	s.Equal(Record{Line: 2, Values: map[string]string{"name": "Books", "description": "Novels"}}, records[0])
+	s.Equal(5, records[1].Line)
+	s.Equal("Toys", records[1].Get("name"))
+}
+
+func (s *TabularTestSuite) TestJSONConvertsScalars() {
+	records, err := Read(strings.NewReader(`[{"Name":"Books","count":3,"active":true,"note":null}]`), FormatJSON, 0)
+	s.Require().NoError(err)
+	s.Equal(map[string]string{"name": "Books", "count": "3", "active": "true", "note": ""}, records[0].Values)
+
+	_, err = Read(strings.NewReader(`[{"name":{"nested":true}}]`), FormatJSON, 0)
+	s.Error(err)
+	_, err = Read(strings.NewReader(`{"name":"Books"}`), FormatJSON, 0)
+	s.Error(err)
+}
+
+func (s *TabularTestSuite) TestLimitsRows() {
+	_, err := Read(strings.NewReader("name\na\nb\nc\n"), FormatCSV, 2)
+	s.ErrorIs(err, ErrTooManyRows)
+	_, err = Read(strings.NewReader(`[{},{},{}]`), FormatJSON, 2)
+	s.ErrorIs(err, ErrTooManyRows)
+
+	records, err := Read(strings.NewReader("name\na\nb\n"), FormatCSV, 2)
+	s.Require().NoError(err)
+	s.Len(records, 2)
+}
+
+func (s *TabularTestSuite) TestEmptyJSONExport() {
+	var buf bytes.Buffer
+	writer, err := NewWriter(&buf, FormatJSON, columns)
+	s.Require().NoError(err)
+	s.Require().NoError(writer.Close())
+	s.JSONEq(`[]`, buf.String())
+}
+
+func (s *TabularTestSuite) TestFormatOf() {
+	format, ok := FormatOf("text/csv; charset=utf-8")
+	s.True(ok)
+	s.Equal(FormatCSV, format)
+	format, ok = FormatOf(ContentType(FormatXLSX))
+	s.True(ok)
+	s.Equal(FormatXLSX, format)
+	_, ok = FormatOf("application/octet-stream")
+	s.False(ok)
+
+	_, err := Read(strings.NewReader(""), "xml", 0)
+	s.ErrorIs(err, ErrUnsupportedFormat)
+	_, err = NewWriter(&bytes.Buffer{}, "xml", columns)
+	s.ErrorIs(err, ErrUnsupportedFormat)
+}
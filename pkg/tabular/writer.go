@@ -0,0 +1,181 @@
+package tabular
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Writer menulis baris data secara streaming. Nilai ditulis sesuai urutan kolom
+// yang diberikan ke NewWriter; Close menyelesaikan file.
+type Writer interface {
+	Write(values ...interface{}) error
+	Close() error
+}
+
+// NewWriter membuat writer untuk format. CSV dan JSON langsung ditulis ke w;
+// XLSX disusun di file sementara oleh excelize lalu ditulis saat Close.
+func NewWriter(w io.Writer, format string, columns []string) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		writer := &csvWriter{csv: csv.NewWriter(w)}
+		header := make([]interface{}, len(columns))
+		for i, column := range columns {
+			header[i] = column
+		}
+		return writer, writer.Write(header...)
+	case FormatJSON:
+		return &jsonWriter{w: bufio.NewWriter(w), columns: columns}, nil
+	case FormatXLSX:
+		return newXLSXWriter(w, columns)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+// text mengubah nilai menjadi teks untuk CSV dan XLSX; waktu ditulis sebagai RFC 3339
+func text(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+type csvWriter struct {
+	csv *csv.Writer
+	row []string
+}
+
+func (w *csvWriter) Write(values ...interface{}) error {
+	w.row = w.row[:0]
+	for _, value := range values {
+		if v, ok := value.(string); ok {
+			value = escapeFormula(v)
+		}
+		w.row = append(w.row, text(value))
+	}
+	return w.csv.Write(w.row)
+}
+
+func (w *csvWriter) Close() error {
+	w.csv.Flush()
+	return w.csv.Error()
+}
+
+// jsonWriter menulis array object dengan urutan kolom yang tetap
+type jsonWriter struct {
+	w       *bufio.Writer
+	columns []string
+	count   int
+}
+
+func (w *jsonWriter) Write(values ...interface{}) error {
+	separator := ","
+	if w.count == 0 {
+		separator = "["
+	}
+	w.count++
+	if _, err := w.w.WriteString(separator + "\n{"); err != nil {
+		return err
+	}
+
+	for i, column := range w.columns {
+		var value interface{}
+		if i < len(values) {
+			value = values[i]
+		}
+		name, err := json.Marshal(column)
+		if err != nil {
+			return err
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			w.w.WriteByte(',')
+		}
+		w.w.Write(name)
+		w.w.WriteByte(':')
+		w.w.Write(encoded)
+	}
+	_, err := w.w.WriteString("}")
+	return err
+}
+
+func (w *jsonWriter) Close() error {
+	closing := "\n]\n"
+	if w.count == 0 {
+		closing = "[]\n"
+	}
+	if _, err := w.w.WriteString(closing); err != nil {
+		return err
+	}
+	return w.w.Flush()
+}
+
+type xlsxWriter struct {
+	w      io.Writer
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	row    int
+}
+
+func newXLSXWriter(w io.Writer, columns []string) (*xlsxWriter, error) {
+	file := excelize.NewFile()
+	stream, err := file.NewStreamWriter(file.GetSheetName(0))
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	writer := &xlsxWriter{w: w, file: file, stream: stream}
+	header := make([]interface{}, len(columns))
+	for i, column := range columns {
+		header[i] = column
+	}
+	if err := writer.Write(header...); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return writer, nil
+}
+
+func (w *xlsxWriter) Write(values ...interface{}) error {
+	w.row++
+	cell, err := excelize.CoordinatesToCellName(1, w.row)
+	if err != nil {
+		return err
+	}
+
+	row := make([]interface{}, len(values))
+	for i, value := range values {
+		// string ditulis sebagai teks sehingga tidak pernah dijalankan sebagai formula
+		switch value.(type) {
+		case string, time.Time, nil:
+			row[i] = text(value)
+		default:
+			row[i] = value
+		}
+	}
+	return w.stream.SetRow(cell, row)
+}
+
+func (w *xlsxWriter) Close() error {
+	defer w.file.Close()
+	if err := w.stream.Flush(); err != nil {
+		return err
+	}
+	return w.file.Write(w.w)
+}
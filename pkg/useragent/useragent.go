@@ -0,0 +1,120 @@
+package useragent
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+const (
+	DeviceDesktop = "desktop"
+	DeviceMobile  = "mobile"
+	DeviceTablet  = "tablet"
+	DeviceBot     = "bot"
+	DeviceOther   = "other"
+
+	unknown = "Unknown"
+)
+
+// Device adalah hasil parsing header User-Agent. Versi browser dan OS sengaja
+// diabaikan agar update browser tidak dianggap sebagai perangkat baru.
+type Device struct {
+	Browser string `json:"browser"`
+	OS      string `json:"os"`
+	Type    string `json:"type"`
+}
+
+// rule dicocokkan berurutan; token yang lebih spesifik harus ada di atas
+type rule struct {
+	token string
+	name  string
+}
+
+var browsers = []rule{
+	{"edg/", "Edge"},
+	{"edge/", "Edge"},
+	{"opr/", "Opera"},
+	{"opera", "Opera"},
+	{"samsungbrowser/", "Samsung Internet"},
+	{"yabrowser/", "Yandex"},
+	{"firefox/", "Firefox"},
+	{"fxios/", "Firefox"},
+	{"crios/", "Chrome"},
+	{"chrome/", "Chrome"},
+	{"chromium/", "Chromium"},
+	{"safari/", "Safari"},
+	{"msie ", "Internet Explorer"},
+	{"trident/", "Internet Explorer"},
+	{"curl/", "curl"},
+	{"postmanruntime/", "Postman"},
+	{"okhttp/", "OkHttp"},
+	{"go-http-client/", "Go HTTP client"},
+	{"python-requests/", "Python Requests"},
+}
+
+var systems = []rule{
+	{"windows", "Windows"},
+	{"iphone", "iOS"},
+	{"ipad", "iPadOS"},
+	{"android", "Android"},
+	{"cros", "ChromeOS"},
+	{"mac os x", "macOS"},
+	{"macintosh", "macOS"},
+	{"linux", "Linux"},
+}
+
+var bots = []string{"bot", "crawler", "spider", "slurp"}
+
+// Parse mengenali browser, sistem operasi dan jenis perangkat dari User-Agent
+func Parse(userAgent string) Device {
+	ua := strings.ToLower(userAgent)
+	device := Device{
+		Browser: match(ua, browsers),
+		OS:      match(ua, systems),
+		Type:    DeviceOther,
+	}
+
+	switch {
+	case ua == "":
+	case containsAny(ua, bots...):
+		device.Type = DeviceBot
+	case strings.Contains(ua, "ipad") || strings.Contains(ua, "tablet") ||
+		(strings.Contains(ua, "android") && !strings.Contains(ua, "mobile")):
+		device.Type = DeviceTablet
+	case strings.Contains(ua, "mobile") || strings.Contains(ua, "iphone"):
+		device.Type = DeviceMobile
+	case device.OS == "Windows" || device.OS == "macOS" || device.OS == "Linux" || device.OS == "ChromeOS":
+		device.Type = DeviceDesktop
+	}
+	return device
+}
+
+// String mengembalikan deskripsi singkat, mis. "Chrome on Windows (desktop)"
+func (d Device) String() string {
+	return d.Browser + " on " + d.OS + " (" + d.Type + ")"
+}
+
+// Fingerprint mengidentifikasi perangkat yang sama antar login tanpa menyimpan
+// User-Agent mentah sebagai kunci
+func (d Device) Fingerprint() string {
+	sum := sha256.Sum256([]byte(d.Browser + "|" + d.OS + "|" + d.Type))
+	return hex.EncodeToString(sum[:])
+}
+
+func match(ua string, rules []rule) string {
+	for _, r := range rules {
+		if strings.Contains(ua, r.token) {
+			return r.name
+		}
+	}
+	return unknown
+}
+
+func containsAny(s string, tokens ...string) bool {
+	for _, token := range tokens {
+		if strings.Contains(s, token) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package useragent
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		ua   string
+		want Device
+	}{
+		{
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36",
+			Device{Browser: "Chrome", OS: "Windows", Type: DeviceDesktop},
+		},
+		{
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36 Edg/126.0.0.0",
+			Device{Browser: "Edge", OS: "Windows", Type: DeviceDesktop},
+		},
+		{
+			"Mozilla/5.0 (Macintosh; Intel Mac OS X 14_5) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.5 Safari/605.1.15",
+			Device{Browser: "Safari", OS: "macOS", Type: DeviceDesktop},
+		},
+		{
+			"Mozilla/5.0 (X11; Ubuntu; Linux x86_64; rv:127.0) Gecko/20100101 Firefox/127.0",
+			Device{Browser: "Firefox", OS: "Linux", Type: DeviceDesktop},
+		},
+		{
+			"Mozilla/5.0 (iPhone; CPU iPhone OS 17_5 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/126.0 Mobile/15E148 Safari/604.1",
+			Device{Browser: "Chrome", OS: "iOS", Type: DeviceMobile},
+		},
+		{
+			"Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Mobile Safari/537.36",
+			Device{Browser: "Chrome", OS: "Android", Type: DeviceMobile},
+		},
+		{
+			"Mozilla/5.0 (Linux; Android 13; SM-X700) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36",
+			Device{Browser: "Chrome", OS: "Android", Type: DeviceTablet},
+		},
+		{
+			"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			Device{Browser: "Unknown", OS: "Unknown", Type: DeviceBot},
+		},
+		{"curl/8.5.0", Device{Browser: "curl", OS: "Unknown", Type: DeviceOther}},
+		{"", Device{Browser: "Unknown", OS: "Unknown", Type: DeviceOther}},
+	}
+
+	for _, tc := range cases {
+		assert.Equal(t, tc.want, Parse(tc.ua), tc.ua)
+	}
+}
+
+func TestFingerprintIgnoresVersions(t *testing.T) {
+	older := Parse("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/125.0.0.0 Safari/537.36")
+	newer := Parse("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36")
+	other := Parse("Mozilla/5.0 (X11; Linux x86_64; rv:127.0) Gecko/20100101 Firefox/127.0")
+
+	assert.Equal(t, older.Fingerprint(), newer.Fingerprint())
+	assert.NotEqual(t, older.Fingerprint(), other.Fingerprint())
+	assert.Equal(t, "Chrome on Windows (desktop)", newer.String())
+}
@@ -0,0 +1,770 @@
+package routes
+
+import (
+	"net/http"
+
+	apiKeyModel "boilerplate/internal/apikey/model"
+	categoryModel "boilerplate/internal/category/model"
+	inviteModel "boilerplate/internal/invite/model"
+	jobModel "boilerplate/internal/job/model"
+	oauthModel "boilerplate/internal/oauth/model"
+	privacyModel "boilerplate/internal/privacy/model"
+	schedulerModel "boilerplate/internal/scheduler/model"
+	searchModel "boilerplate/internal/search/model"
+	userModel "boilerplate/internal/user/model"
+	webhookModel "boilerplate/internal/webhook/model"
+	"boilerplate/pkg/cache"
+	"boilerplate/pkg/idempotency"
+	"boilerplate/pkg/jwt"
+	"boilerplate/pkg/openapi"
+	"boilerplate/pkg/queue"
+	"boilerplate/pkg/response"
+	"boilerplate/pkg/search"
+	"boilerplate/pkg/tabular"
+)
+
+const apiTitle = "Go Boilerplate API"
+
+var (
+	patchTypes = []string{openapi.MergePatchContentType, openapi.JSONPatchContentType}
+
+	idempotencyKeyParam = openapi.Param{
+		Name:        "Idempotency-Key",
+		In:          "header",
+		Description: "Client-generated key; retries with the same key and payload replay the first response",
+		Schema:      &openapi.Schema{Type: "string", MaxLength: intPtr(idempotency.MaxKeyLength)},
+	}
+
+	providerParam = openapi.Param{
+		Name:        "provider",
+		In:          "path",
+		Description: "OIDC provider name from OIDC_PROVIDERS",
+		Schema:      &openapi.Schema{Type: "string"},
+	}
+
+	oauthClientIDParam = openapi.Param{
+		Name:        "id",
+		In:          "path",
+		Description: "OAuth client ID",
+		Schema:      &openapi.Schema{Type: "integer", Format: "int32", Minimum: new(float64)},
+	}
+
+	consentIDParam = openapi.Param{
+		Name:        "id",
+		In:          "path",
+		Description: "Consent ID",
+		Schema:      &openapi.Schema{Type: "integer", Format: "int32", Minimum: new(float64)},
+	}
+
+	authorizeParams = []openapi.Param{
+		{Name: "response_type", In: "query", Schema: &openapi.Schema{Type: "string"}},
+		{Name: "client_id", In: "query", Required: true, Schema: &openapi.Schema{Type: "string"}},
+		{Name: "redirect_uri", In: "query", Required: true, Schema: &openapi.Schema{Type: "string", Format: "uri"}},
+		{Name: "scope", In: "query", Schema: &openapi.Schema{Type: "string"}},
+		{Name: "state", In: "query", Schema: &openapi.Schema{Type: "string"}},
+		{Name: "nonce", In: "query", Schema: &openapi.Schema{Type: "string"}},
+		{Name: "code_challenge", In: "query", Schema: &openapi.Schema{Type: "string"}},
+		{Name: "code_challenge_method", In: "query", Schema: &openapi.Schema{Type: "string"}},
+	}
+
+	apiKeyIDParam = openapi.Param{
+		Name:        "id",
+		In:          "path",
+		Description: "API key ID",
+		Schema:      &openapi.Schema{Type: "integer", Format: "int32", Minimum: new(float64)},
+	}
+
+	inviteIDParam = openapi.Param{
+		Name:        "id",
+		In:          "path",
+		Description: "Invite ID",
+		Schema:      &openapi.Schema{Type: "integer", Format: "int32", Minimum: new(float64)},
+	}
+
+	userIDParam = openapi.Param{
+		Name:        "id",
+		In:          "path",
+		Description: "User ID",
+		Schema:      &openapi.Schema{Type: "integer", Format: "int32", Minimum: new(float64)},
+	}
+
+	exportIDParam = openapi.Param{
+		Name:        "id",
+		In:          "path",
+		Description: "Export ID",
+		Schema:      &openapi.Schema{Type: "integer", Format: "int32", Minimum: new(float64)},
+	}
+
+	loginLimitParam = openapi.Param{
+		Name:        "limit",
+		In:          "query",
+		Description: "Maximum number of events, default 50",
+		Schema:      &openapi.Schema{Type: "integer", Minimum: float64Ptr(1), Maximum: float64Ptr(userModel.MaxLoginEventLimit)},
+	}
+
+	failedLoginParams = []openapi.Param{
+		{Name: "email", In: "query", Description: "Only attempts for this email", Schema: &openapi.Schema{Type: "string"}},
+		{Name: "ip", In: "query", Description: "Only attempts from this IP address", Schema: &openapi.Schema{Type: "string"}},
+		{Name: "since", In: "query", Description: "Only attempts at or after this time", Schema: &openapi.Schema{Type: "string", Format: "date-time"}},
+		loginLimitParam,
+	}
+
+	jobIDParam = openapi.Param{
+		Name:        "id",
+		In:          "path",
+		Description: "Job ID",
+		Schema:      &openapi.Schema{Type: "string"},
+	}
+
+	failedJobParams = []openapi.Param{
+		{Name: "offset", In: "query", Description: "Number of jobs to skip", Schema: &openapi.Schema{Type: "integer", Minimum: new(float64)}},
+		{Name: "limit", In: "query", Description: "Maximum number of jobs, default 50", Schema: &openapi.Schema{Type: "integer", Minimum: float64Ptr(1), Maximum: float64Ptr(jobModel.MaxFailedJobLimit)}},
+	}
+
+	taskNameParam = openapi.Param{
+		Name:        "name",
+		In:          "path",
+		Description: "Scheduled task name",
+		Schema:      &openapi.Schema{Type: "string"},
+	}
+
+	taskRunLimitParam = openapi.Param{
+		Name:        "limit",
+		In:          "query",
+		Description: "Maximum number of runs, default 20",
+		Schema:      &openapi.Schema{Type: "integer", Minimum: float64Ptr(1), Maximum: float64Ptr(schedulerModel.MaxRunLimit)},
+	}
+
+	searchParams = []openapi.Param{
+		{Name: "q", In: "query", Required: true, Description: "Words to search for; every word must match", Schema: &openapi.Schema{Type: "string", MaxLength: intPtr(200)}},
+		{Name: "type", In: "query", Description: "Only search one entity type", Schema: &openapi.Schema{Type: "string", Enum: []interface{}{categoryModel.SearchType, userModel.SearchType}}},
+		{Name: "limit", In: "query", Description: "Maximum number of hits across all types, default 20", Schema: &openapi.Schema{Type: "integer", Minimum: float64Ptr(1), Maximum: float64Ptr(search.MaxLimit)}},
+	}
+
+	webhookIDParam = openapi.Param{
+		Name:        "id",
+		In:          "path",
+		Description: "Webhook ID",
+		Schema:      &openapi.Schema{Type: "integer", Format: "int32", Minimum: new(float64)},
+	}
+
+	deliveryParams = []openapi.Param{
+		webhookIDParam,
+		{Name: "offset", In: "query", Description: "Number of deliveries to skip", Schema: &openapi.Schema{Type: "integer", Minimum: new(float64)}},
+		{Name: "limit", In: "query", Description: "Maximum number of deliveries, default 50", Schema: &openapi.Schema{Type: "integer", Minimum: float64Ptr(1), Maximum: float64Ptr(webhookModel.MaxDeliveryLimit)}},
+	}
+
+	categoryIDParam = openapi.Param{
+		Name:        "id",
+		In:          "path",
+		Description: "Category ID",
+		Schema:      &openapi.Schema{Type: "integer", Format: "int32", Minimum: new(float64)},
+	}
+
+	categoryImportParams = []openapi.Param{
+		idempotencyKeyParam,
+		{Name: "format", In: "query", Description: "File format; detected from Content-Type or the file itself when omitted", Schema: &openapi.Schema{Type: "string", Enum: []interface{}{tabular.FormatCSV, tabular.FormatJSON, tabular.FormatXLSX}}},
+		{Name: "match", In: "query", Description: "Column used to find existing categories, default name", Schema: &openapi.Schema{Type: "string", Enum: []interface{}{categoryModel.ImportMatchName, categoryModel.ImportMatchSlug}}},
+		{Name: "mode", In: "query", Description: "atomic (default) saves nothing when a row fails, best_effort saves every valid row", Schema: &openapi.Schema{Type: "string", Enum: []interface{}{categoryModel.ImportModeAtomic, categoryModel.ImportModeBestEffort}}},
+		{Name: "dry_run", In: "query", Description: "Validate and report without saving", Schema: &openapi.Schema{Type: "boolean"}},
+	}
+
+	categoryImportIDParam = openapi.Param{
+		Name:        "id",
+		In:          "path",
+		Description: "Category import ID",
+		Schema:      &openapi.Schema{Type: "integer", Format: "int32", Minimum: new(float64)},
+	}
+
+	categoryExportParams = []openapi.Param{
+		{Name: "format", In: "query", Description: "File format, default csv", Schema: &openapi.Schema{Type: "string", Enum: []interface{}{tabular.FormatCSV, tabular.FormatJSON, tabular.FormatXLSX}}},
+	}
+
+	// tabularTypes adalah content type file import dan export category
+	tabularTypes = []string{tabular.ContentType(tabular.FormatCSV), tabular.ContentType(tabular.FormatJSON), tabular.ContentType(tabular.FormatXLSX)}
+)
+
+// categoryImportRow mendokumentasikan satu baris file import; kolom lain diabaikan
+type categoryImportRow struct {
+	Name        string `json:"name"`
+	Slug        string `json:"slug,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// tokenResponse mendokumentasikan data response login
+type tokenResponse struct {
+	Token string `json:"token"`
+}
+
+// apiDocs berisi anotasi OpenAPI untuk setiap route di SetupRoutes.
+// Route baru wajib ditambahkan di sini; Build gagal jika ada yang terlewat.
+func apiDocs() *openapi.Registry {
+	docs := openapi.NewRegistry(openapi.Options{
+		Info: openapi.Info{
+			Title:   apiTitle,
+			Version: "1.0.0",
+		},
+		Envelope:          response.Response{},
+		EnvelopeDataField: "data",
+		Problem:           response.Problem{},
+	})
+
+	// Auth
+	docs.Add(http.MethodPost, "/register", openapi.Route{
+		Summary:  "Register a new user",
+		Tags:     []string{"auth"},
+		Params:   []openapi.Param{idempotencyKeyParam},
+		Request:  userModel.RegisterInput{},
+		Response: userModel.User{},
+		Status:   http.StatusCreated,
+	})
+	docs.Add(http.MethodPost, "/login", openapi.Route{
+		Summary:  "Log in with email and password",
+		Tags:     []string{"auth"},
+		Request:  userModel.LoginInput{},
+		Response: tokenResponse{},
+	})
+	docs.Add(http.MethodPost, "/invites/accept", openapi.Route{
+		Summary:  "Accept an invite and create the invited user",
+		Tags:     []string{"auth"},
+		Request:  inviteModel.AcceptInviteInput{},
+		Response: userModel.User{},
+		Status:   http.StatusCreated,
+	})
+	docs.Add(http.MethodGet, "/auth/:provider/login", openapi.Route{
+		Summary: "Redirect to an external OIDC provider",
+		Tags:    []string{"auth"},
+		Params:  []openapi.Param{providerParam},
+		Status:  http.StatusFound,
+	})
+	docs.Add(http.MethodGet, "/auth/:provider/callback", openapi.Route{
+		Summary: "Complete an OIDC login and issue a token",
+		Tags:    []string{"auth"},
+		Params: []openapi.Param{
+			providerParam,
+			{Name: "code", In: "query", Schema: &openapi.Schema{Type: "string"}},
+			{Name: "state", In: "query", Schema: &openapi.Schema{Type: "string"}},
+			{Name: "error", In: "query", Schema: &openapi.Schema{Type: "string"}},
+			{Name: "error_description", In: "query", Schema: &openapi.Schema{Type: "string"}},
+		},
+		Response: tokenResponse{},
+	})
+	// OpenID Connect provider
+	docs.Add(http.MethodGet, "/.well-known/openid-configuration", openapi.Route{
+		Summary:  "OpenID Connect discovery document",
+		Tags:     []string{"oidc"},
+		Response: oauthModel.Discovery{},
+		Raw:      true,
+	})
+	docs.Add(http.MethodGet, "/.well-known/jwks.json", openapi.Route{
+		Summary:  "Public keys for verifying ID and access tokens",
+		Tags:     []string{"oidc"},
+		Response: jwt.JSONWebKeySet{},
+		Raw:      true,
+	})
+	docs.Add(http.MethodGet, "/authorize", openapi.Route{
+		Summary:  "Resolve an authorization code + PKCE request for the current user (called by the frontend consent page, not by browser navigation)",
+		Tags:     []string{"oidc"},
+		Params:   authorizeParams,
+		Response: oauthModel.AuthorizeResult{},
+		Secured:  true,
+	})
+	docs.Add(http.MethodPost, "/authorize", openapi.Route{
+		Summary:  "Approve or deny an authorization request",
+		Tags:     []string{"oidc"},
+		Request:  oauthModel.ConsentInput{},
+		Response: oauthModel.AuthorizeResult{},
+		Secured:  true,
+	})
+	docs.Add(http.MethodPost, "/token", openapi.Route{
+		Summary:      "Exchange an authorization code or client credentials for tokens",
+		Tags:         []string{"oidc"},
+		Request:      oauthModel.TokenRequest{},
+		RequestTypes: []string{openapi.FormContentType},
+		Response:     oauthModel.TokenResponse{},
+		Raw:          true,
+		Errors: map[int]interface{}{
+			http.StatusBadRequest:   oauthModel.ProtocolError{},
+			http.StatusUnauthorized: oauthModel.ProtocolError{},
+		},
+	})
+	docs.Add(http.MethodGet, "/userinfo", openapi.Route{
+		Summary:  "Claims about the user of an access token",
+		Tags:     []string{"oidc"},
+		Response: oauthModel.UserInfo{},
+		Raw:      true,
+		Errors: map[int]interface{}{
+			http.StatusUnauthorized: oauthModel.ProtocolError{},
+		},
+	})
+
+	docs.Add(http.MethodPost, "/logout", openapi.Route{
+		Summary: "Revoke the current token",
+		Tags:    []string{"auth"},
+		Secured: true,
+	})
+
+	// Users
+	docs.Add(http.MethodGet, "/admin/v1/user/me", openapi.Route{
+		Summary:  "Get the current user's profile",
+		Tags:     []string{"users"},
+		Response: userModel.User{},
+		Scopes:   []string{apiKeyModel.ScopeUsersRead},
+		Secured:  true,
+	})
+	docs.Add(http.MethodPatch, "/admin/v1/user/me", openapi.Route{
+		Summary:      "Partially update the current user's profile",
+		Tags:         []string{"users"},
+		Request:      userModel.PatchProfileInput{},
+		RequestTypes: patchTypes,
+		Response:     userModel.User{},
+		Scopes:       []string{apiKeyModel.ScopeUsersWrite},
+		Secured:      true,
+	})
+	docs.Add(http.MethodPut, "/admin/v1/user/update", openapi.Route{
+		Summary:  "Update the current user's profile",
+		Tags:     []string{"users"},
+		Request:  userModel.UpdateProfileInput{},
+		Response: userModel.User{},
+		Scopes:   []string{apiKeyModel.ScopeUsersWrite},
+		Secured:  true,
+	})
+	docs.Add(http.MethodDelete, "/admin/v1/user/delete", openapi.Route{
+		Summary: "Erase the current user's personal data; the account is anonymized and disabled",
+		Tags:    []string{"users"},
+		Scopes:  []string{apiKeyModel.ScopeUsersWrite},
+		Secured: true,
+	})
+	docs.Add(http.MethodGet, "/admin/v1/user", openapi.Route{
+		Summary:  "List users (admin)",
+		Tags:     []string{"users"},
+		Response: []userModel.User{},
+		Scopes:   []string{apiKeyModel.ScopeUsersRead},
+		Secured:  true,
+	})
+	docs.Add(http.MethodGet, "/admin/v1/user/logins/failed", openapi.Route{
+		Summary:  "List failed password logins across all users, newest first (admin)",
+		Tags:     []string{"users"},
+		Params:   failedLoginParams,
+		Response: []userModel.LoginEvent{},
+		Scopes:   []string{apiKeyModel.ScopeUsersRead},
+		Secured:  true,
+	})
+	docs.Add(http.MethodPost, "/admin/v1/user", openapi.Route{
+		Summary:  "Create a user with a role (admin)",
+		Tags:     []string{"users"},
+		Params:   []openapi.Param{idempotencyKeyParam},
+		Request:  userModel.AdminCreateUserInput{},
+		Response: userModel.User{},
+		Status:   http.StatusCreated,
+		Scopes:   []string{apiKeyModel.ScopeUsersWrite},
+		Secured:  true,
+	})
+	docs.Add(http.MethodPut, "/admin/v1/user/:id/role", openapi.Route{
+		Summary:  "Change a user's role; the last active admin cannot be demoted (admin)",
+		Tags:     []string{"users"},
+		Params:   []openapi.Param{userIDParam},
+		Request:  userModel.ChangeRoleInput{},
+		Response: userModel.User{},
+		Scopes:   []string{apiKeyModel.ScopeUsersWrite},
+		Secured:  true,
+	})
+	docs.Add(http.MethodPost, "/admin/v1/user/:id/disable", openapi.Route{
+		Summary:  "Disable an account and revoke its tokens (admin)",
+		Tags:     []string{"users"},
+		Params:   []openapi.Param{userIDParam},
+		Response: userModel.User{},
+		Scopes:   []string{apiKeyModel.ScopeUsersWrite},
+		Secured:  true,
+	})
+	docs.Add(http.MethodPost, "/admin/v1/user/:id/enable", openapi.Route{
+		Summary:  "Re-enable a disabled account (admin)",
+		Tags:     []string{"users"},
+		Params:   []openapi.Param{userIDParam},
+		Response: userModel.User{},
+		Scopes:   []string{apiKeyModel.ScopeUsersWrite},
+		Secured:  true,
+	})
+	docs.Add(http.MethodPost, "/admin/v1/user/:id/force-password-reset", openapi.Route{
+		Summary:  "Require a password change before the user can use the API again (admin)",
+		Tags:     []string{"users"},
+		Params:   []openapi.Param{userIDParam},
+		Response: userModel.User{},
+		Scopes:   []string{apiKeyModel.ScopeUsersWrite},
+		Secured:  true,
+	})
+	docs.Add(http.MethodPost, "/admin/v1/user/:id/revoke-tokens", openapi.Route{
+		Summary: "Revoke the session, API keys and impersonation tokens of a user (admin)",
+		Tags:    []string{"users"},
+		Params:  []openapi.Param{userIDParam},
+		Scopes:  []string{apiKeyModel.ScopeUsersWrite},
+		Secured: true,
+	})
+
+	docs.Add(http.MethodPost, "/admin/v1/user/:id/impersonate", openapi.Route{
+		Summary:  "Issue a short-lived token to act as a non-admin user; POST /logout with it ends the impersonation (admin)",
+		Tags:     []string{"users"},
+		Params:   []openapi.Param{userIDParam},
+		Response: userModel.ImpersonationResult{},
+		Status:   http.StatusCreated,
+		Secured:  true,
+	})
+
+	docs.Add(http.MethodPost, "/admin/v1/user/:id/erase", openapi.Route{
+		Summary:  "Erase a user's personal data and record a tombstone (admin)",
+		Tags:     []string{"users"},
+		Params:   []openapi.Param{userIDParam},
+		Response: privacyModel.Tombstone{},
+		Secured:  true,
+	})
+
+	docs.Add(http.MethodPost, "/admin/v1/user/me/export", openapi.Route{
+		Summary:  "Start an export of all of the current user's data",
+		Tags:     []string{"users"},
+		Request:  privacyModel.ExportInput{},
+		Response: privacyModel.Export{},
+		Status:   http.StatusAccepted,
+		Scopes:   []string{apiKeyModel.ScopeUsersRead},
+		Secured:  true,
+	})
+	docs.Add(http.MethodGet, "/admin/v1/user/me/export/:id", openapi.Route{
+		Summary:  "Get the status of a data export",
+		Tags:     []string{"users"},
+		Params:   []openapi.Param{exportIDParam},
+		Response: privacyModel.Export{},
+		Scopes:   []string{apiKeyModel.ScopeUsersRead},
+		Secured:  true,
+	})
+	docs.Add(http.MethodGet, "/admin/v1/user/me/export/:id/download", openapi.Route{
+		Summary:       "Download a completed data export as JSON or ZIP",
+		Tags:          []string{"users"},
+		Params:        []openapi.Param{exportIDParam},
+		ResponseTypes: []string{"application/json", "application/zip"},
+		Scopes:        []string{apiKeyModel.ScopeUsersRead},
+		Secured:       true,
+	})
+
+	docs.Add(http.MethodGet, "/admin/v1/user/me/consents", openapi.Route{
+		Summary:  "List OAuth clients the current user has approved",
+		Tags:     []string{"users"},
+		Response: []oauthModel.Consent{},
+		Scopes:   []string{apiKeyModel.ScopeUsersRead},
+		Secured:  true,
+	})
+	docs.Add(http.MethodGet, "/admin/v1/user/me/logins", openapi.Route{
+		Summary:  "List the current user's login history, newest first",
+		Tags:     []string{"users"},
+		Params:   []openapi.Param{loginLimitParam},
+		Response: []userModel.LoginEvent{},
+		Scopes:   []string{apiKeyModel.ScopeUsersRead},
+		Secured:  true,
+	})
+	docs.Add(http.MethodDelete, "/admin/v1/user/me/consents/:id", openapi.Route{
+		Summary: "Revoke an OAuth consent",
+		Tags:    []string{"users"},
+		Params:  []openapi.Param{consentIDParam},
+		Scopes:  []string{apiKeyModel.ScopeUsersWrite},
+		Secured: true,
+	})
+
+	// Invites
+	docs.Add(http.MethodPost, "/admin/v1/invites", openapi.Route{
+		Summary:  "Invite a user with a role; the token is returned only once",
+		Tags:     []string{"invites"},
+		Request:  inviteModel.CreateInviteInput{},
+		Response: inviteModel.CreatedInvite{},
+		Status:   http.StatusCreated,
+		Scopes:   []string{apiKeyModel.ScopeUsersWrite},
+		Secured:  true,
+	})
+	docs.Add(http.MethodGet, "/admin/v1/invites", openapi.Route{
+		Summary:  "List invites",
+		Tags:     []string{"invites"},
+		Response: []inviteModel.Invite{},
+		Scopes:   []string{apiKeyModel.ScopeUsersRead},
+		Secured:  true,
+	})
+	docs.Add(http.MethodDelete, "/admin/v1/invites/:id", openapi.Route{
+		Summary: "Revoke a pending invite",
+		Tags:    []string{"invites"},
+		Params:  []openapi.Param{inviteIDParam},
+		Scopes:  []string{apiKeyModel.ScopeUsersWrite},
+		Secured: true,
+	})
+
+	// OAuth clients
+	docs.Add(http.MethodPost, "/admin/v1/oauth/clients", openapi.Route{
+		Summary:  "Register an OAuth client; the secret is returned only once",
+		Tags:     []string{"oauth-clients"},
+		Request:  oauthModel.CreateClientInput{},
+		Response: oauthModel.CreatedClient{},
+		Status:   http.StatusCreated,
+		Secured:  true,
+	})
+	docs.Add(http.MethodGet, "/admin/v1/oauth/clients", openapi.Route{
+		Summary:  "List OAuth clients",
+		Tags:     []string{"oauth-clients"},
+		Response: []oauthModel.Client{},
+		Secured:  true,
+	})
+	docs.Add(http.MethodGet, "/admin/v1/oauth/clients/:id", openapi.Route{
+		Summary:  "Get an OAuth client",
+		Tags:     []string{"oauth-clients"},
+		Params:   []openapi.Param{oauthClientIDParam},
+		Response: oauthModel.Client{},
+		Secured:  true,
+	})
+	docs.Add(http.MethodDelete, "/admin/v1/oauth/clients/:id", openapi.Route{
+		Summary: "Delete an OAuth client and its consents",
+		Tags:    []string{"oauth-clients"},
+		Params:  []openapi.Param{oauthClientIDParam},
+		Secured: true,
+	})
+
+	// Background jobs
+	docs.Add(http.MethodGet, "/admin/v1/jobs", openapi.Route{
+		Summary:  "Count jobs per state",
+		Tags:     []string{"jobs"},
+		Response: queue.Stats{},
+		Secured:  true,
+	})
+	docs.Add(http.MethodGet, "/admin/v1/jobs/failed", openapi.Route{
+		Summary:  "List jobs in the dead-letter queue, newest first",
+		Tags:     []string{"jobs"},
+		Params:   failedJobParams,
+		Response: jobModel.FailedJobList{},
+		Secured:  true,
+	})
+	docs.Add(http.MethodGet, "/admin/v1/jobs/:id", openapi.Route{
+		Summary:  "Get a pending or failed job",
+		Tags:     []string{"jobs"},
+		Params:   []openapi.Param{jobIDParam},
+		Response: queue.Job{},
+		Secured:  true,
+	})
+	docs.Add(http.MethodPost, "/admin/v1/jobs/:id/retry", openapi.Route{
+		Summary:  "Move a failed job back to the queue with its attempts reset",
+		Tags:     []string{"jobs"},
+		Params:   []openapi.Param{jobIDParam},
+		Response: queue.Job{},
+		Secured:  true,
+	})
+
+	// Scheduled tasks
+	docs.Add(http.MethodGet, "/admin/v1/scheduler/tasks", openapi.Route{
+		Summary:  "List scheduled tasks with their next and last run",
+		Tags:     []string{"scheduler"},
+		Response: []schedulerModel.TaskStatus{},
+		Secured:  true,
+	})
+	docs.Add(http.MethodGet, "/admin/v1/scheduler/tasks/:name/runs", openapi.Route{
+		Summary:  "List recent runs of a scheduled task, newest first",
+		Tags:     []string{"scheduler"},
+		Params:   []openapi.Param{taskNameParam, taskRunLimitParam},
+		Response: []schedulerModel.Run{},
+		Secured:  true,
+	})
+
+	// Cache
+	docs.Add(http.MethodGet, "/admin/v1/cache/stats", openapi.Route{
+		Summary:  "Get read-through cache hit and miss counts of the serving instance",
+		Tags:     []string{"cache"},
+		Response: []cache.Stats{},
+		Secured:  true,
+	})
+
+	// Search
+	docs.Add(http.MethodGet, "/admin/v1/search", openapi.Route{
+		Summary:  "Search categories and users; hits are ranked, highlighted and grouped by entity type",
+		Tags:     []string{"search"},
+		Params:   searchParams,
+		Response: searchModel.SearchResult{},
+		Secured:  true,
+	})
+
+	// Webhooks
+	docs.Add(http.MethodPost, "/admin/v1/webhooks", openapi.Route{
+		Summary:  "Subscribe a URL to domain events; the signing secret is returned only once",
+		Tags:     []string{"webhooks"},
+		Request:  webhookModel.CreateWebhookInput{},
+		Response: webhookModel.CreatedWebhook{},
+		Status:   http.StatusCreated,
+		Secured:  true,
+	})
+	docs.Add(http.MethodGet, "/admin/v1/webhooks", openapi.Route{
+		Summary:  "List webhooks",
+		Tags:     []string{"webhooks"},
+		Response: []webhookModel.Webhook{},
+		Secured:  true,
+	})
+	docs.Add(http.MethodGet, "/admin/v1/webhooks/:id", openapi.Route{
+		Summary:  "Get a webhook",
+		Tags:     []string{"webhooks"},
+		Params:   []openapi.Param{webhookIDParam},
+		Response: webhookModel.Webhook{},
+		Secured:  true,
+	})
+	docs.Add(http.MethodPut, "/admin/v1/webhooks/:id", openapi.Route{
+		Summary:  "Update a webhook URL and subscribed events",
+		Tags:     []string{"webhooks"},
+		Params:   []openapi.Param{webhookIDParam},
+		Request:  webhookModel.UpdateWebhookInput{},
+		Response: webhookModel.Webhook{},
+		Secured:  true,
+	})
+	docs.Add(http.MethodDelete, "/admin/v1/webhooks/:id", openapi.Route{
+		Summary: "Delete a webhook and its delivery log",
+		Tags:    []string{"webhooks"},
+		Params:  []openapi.Param{webhookIDParam},
+		Secured: true,
+	})
+	docs.Add(http.MethodPost, "/admin/v1/webhooks/:id/enable", openapi.Route{
+		Summary:  "Re-enable a webhook disabled after repeated failures",
+		Tags:     []string{"webhooks"},
+		Params:   []openapi.Param{webhookIDParam},
+		Response: webhookModel.Webhook{},
+		Secured:  true,
+	})
+	docs.Add(http.MethodPost, "/admin/v1/webhooks/:id/test", openapi.Route{
+		Summary:  "Send a signed webhook.test event once and return the delivery",
+		Tags:     []string{"webhooks"},
+		Params:   []openapi.Param{webhookIDParam},
+		Response: webhookModel.Delivery{},
+		Secured:  true,
+	})
+	docs.Add(http.MethodGet, "/admin/v1/webhooks/:id/deliveries", openapi.Route{
+		Summary:  "List delivery attempts with request and response snapshots, newest first",
+		Tags:     []string{"webhooks"},
+		Params:   deliveryParams,
+		Response: []webhookModel.Delivery{},
+		Secured:  true,
+	})
+
+	// API keys, hanya bisa dikelola dengan sesi JWT
+	docs.Add(http.MethodPost, "/admin/v1/api-keys", openapi.Route{
+		Summary:  "Create an API key; the secret is returned only once",
+		Tags:     []string{"api-keys"},
+		Request:  apiKeyModel.CreateAPIKeyInput{},
+		Response: apiKeyModel.CreatedAPIKey{},
+		Status:   http.StatusCreated,
+		Secured:  true,
+	})
+	docs.Add(http.MethodGet, "/admin/v1/api-keys", openapi.Route{
+		Summary:  "List API keys",
+		Tags:     []string{"api-keys"},
+		Response: []apiKeyModel.APIKey{},
+		Secured:  true,
+	})
+	docs.Add(http.MethodGet, "/admin/v1/api-keys/:id", openapi.Route{
+		Summary:  "Get an API key",
+		Tags:     []string{"api-keys"},
+		Params:   []openapi.Param{apiKeyIDParam},
+		Response: apiKeyModel.APIKey{},
+		Secured:  true,
+	})
+	docs.Add(http.MethodPut, "/admin/v1/api-keys/:id", openapi.Route{
+		Summary:  "Update an API key",
+		Tags:     []string{"api-keys"},
+		Params:   []openapi.Param{apiKeyIDParam},
+		Request:  apiKeyModel.UpdateAPIKeyInput{},
+		Response: apiKeyModel.APIKey{},
+		Secured:  true,
+	})
+	docs.Add(http.MethodDelete, "/admin/v1/api-keys/:id", openapi.Route{
+		Summary: "Revoke an API key",
+		Tags:    []string{"api-keys"},
+		Params:  []openapi.Param{apiKeyIDParam},
+		Secured: true,
+	})
+
+	// Categories
+	docs.Add(http.MethodPost, "/admin/v1/categories", openapi.Route{
+		Summary:  "Create a category",
+		Tags:     []string{"categories"},
+		Params:   []openapi.Param{idempotencyKeyParam},
+		Request:  categoryModel.CreateCategoryInput{},
+		Response: categoryModel.Category{},
+		Status:   http.StatusCreated,
+		Scopes:   []string{apiKeyModel.ScopeCategoriesWrite},
+		Secured:  true,
+	})
+	docs.Add(http.MethodGet, "/admin/v1/categories", openapi.Route{
+		Summary:  "List categories",
+		Tags:     []string{"categories"},
+		Response: []categoryModel.Category{},
+		Scopes:   []string{apiKeyModel.ScopeCategoriesRead},
+		Secured:  true,
+	})
+	docs.Add(http.MethodPost, "/admin/v1/categories/import", openapi.Route{
+		Summary:      "Import categories from CSV, JSON or XLSX; returns 200 when completed inline, 202 when large files run as a background job",
+		Tags:         []string{"categories"},
+		Params:       categoryImportParams,
+		Request:      []categoryImportRow{},
+		RequestTypes: []string{tabular.ContentType(tabular.FormatCSV), tabular.ContentType(tabular.FormatJSON), tabular.ContentType(tabular.FormatXLSX), "application/octet-stream"},
+		Response:     categoryModel.Import{},
+		Status:       http.StatusAccepted,
+		Responses:    map[int]interface{}{http.StatusOK: categoryModel.Import{}},
+		Scopes:       []string{apiKeyModel.ScopeCategoriesWrite},
+		Secured:      true,
+	})
+	docs.Add(http.MethodGet, "/admin/v1/categories/imports/:id", openapi.Route{
+		Summary:  "Get the status and per-row report of a category import",
+		Tags:     []string{"categories"},
+		Params:   []openapi.Param{categoryImportIDParam},
+		Response: categoryModel.Import{},
+		Scopes:   []string{apiKeyModel.ScopeCategoriesRead},
+		Secured:  true,
+	})
+	docs.Add(http.MethodGet, "/admin/v1/categories/export", openapi.Route{
+		Summary:       "Stream all categories as CSV, JSON or XLSX",
+		Tags:          []string{"categories"},
+		Params:        categoryExportParams,
+		ResponseTypes: tabularTypes,
+		Scopes:        []string{apiKeyModel.ScopeCategoriesRead},
+		Secured:       true,
+	})
+	docs.Add(http.MethodGet, "/admin/v1/categories/:id", openapi.Route{
+		Summary:  "Get a category",
+		Tags:     []string{"categories"},
+		Params:   []openapi.Param{categoryIDParam},
+		Response: categoryModel.Category{},
+		Scopes:   []string{apiKeyModel.ScopeCategoriesRead},
+		Secured:  true,
+	})
+	docs.Add(http.MethodPut, "/admin/v1/categories/:id", openapi.Route{
+		Summary:  "Replace a category",
+		Tags:     []string{"categories"},
+		Params:   []openapi.Param{categoryIDParam},
+		Request:  categoryModel.CreateCategoryInput{},
+		Response: categoryModel.Category{},
+		Scopes:   []string{apiKeyModel.ScopeCategoriesWrite},
+		Secured:  true,
+	})
+	docs.Add(http.MethodPatch, "/admin/v1/categories/:id", openapi.Route{
+		Summary:      "Partially update a category",
+		Tags:         []string{"categories"},
+		Params:       []openapi.Param{categoryIDParam},
+		Request:      categoryModel.PatchCategoryInput{},
+		RequestTypes: patchTypes,
+		Response:     categoryModel.Category{},
+		Scopes:       []string{apiKeyModel.ScopeCategoriesWrite},
+		Secured:      true,
+	})
+	docs.Add(http.MethodDelete, "/admin/v1/categories/:id", openapi.Route{
+		Summary: "Delete a category",
+		Tags:    []string{"categories"},
+		Params:  []openapi.Param{categoryIDParam},
+		Scopes:  []string{apiKeyModel.ScopeCategoriesWrite},
+		Secured: true,
+	})
+
+	return docs
+}
+
+func float64Ptr(v float64) *float64 {
+	return &v
+}
+
+func intPtr(v int) *int {
+	return &v
+}
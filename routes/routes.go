@@ -1,8 +1,21 @@
 package routes
 
 import (
+	apiKeyHandler "boilerplate/internal/apikey"
+	apiKeyModel "boilerplate/internal/apikey/model"
+	cacheHandler "boilerplate/internal/cache"
 	categoryHandler "boilerplate/internal/category"
+	identityHandler "boilerplate/internal/identity"
+	inviteHandler "boilerplate/internal/invite"
+	jobHandler "boilerplate/internal/job"
+	oauthHandler "boilerplate/internal/oauth"
+	privacyHandler "boilerplate/internal/privacy"
+	schedulerHandler "boilerplate/internal/scheduler"
+	searchHandler "boilerplate/internal/search"
 	userHandler "boilerplate/internal/user"
+	webhookHandler "boilerplate/internal/webhook"
+	"boilerplate/pkg/middleware"
+	"boilerplate/pkg/openapi"
 
 	"github.com/labstack/echo/v4"
 )
@@ -11,36 +24,160 @@ func SetupRoutes(
 	e *echo.Echo,
 	userHandler *userHandler.UserHandler,
 	categoryHandler *categoryHandler.CategoryHandler,
+	apiKeyHandler *apiKeyHandler.APIKeyHandler,
+	identityHandler *identityHandler.IdentityHandler,
+	oauthHandler *oauthHandler.OAuthHandler,
+	inviteHandler *inviteHandler.InviteHandler,
+	privacyHandler *privacyHandler.PrivacyHandler,
+	jobHandler *jobHandler.JobHandler,
+	schedulerHandler *schedulerHandler.SchedulerHandler,
+	webhookHandler *webhookHandler.WebhookHandler,
+	cacheHandler *cacheHandler.CacheHandler,
+	searchHandler *searchHandler.SearchHandler,
 	authMiddleware echo.MiddlewareFunc,
 	adminMiddleware echo.MiddlewareFunc,
-) {
+	idempotencyMiddleware echo.MiddlewareFunc,
+) (*openapi.Document, error) {
 	// Public routes
-	e.POST("/register", userHandler.Register)
+	e.POST("/register", userHandler.Register, idempotencyMiddleware)
 	e.POST("/login", userHandler.Login)
+	e.POST("/invites/accept", inviteHandler.Accept)
+	e.GET("/auth/:provider/login", identityHandler.Login)
+	e.GET("/auth/:provider/callback", identityHandler.Callback)
+
+	// OpenID Connect provider untuk aplikasi internal
+	e.GET("/.well-known/openid-configuration", oauthHandler.Discovery)
+	e.GET("/.well-known/jwks.json", oauthHandler.JWKS)
+	e.POST("/token", oauthHandler.Token)
+	e.GET("/userinfo", oauthHandler.UserInfo)
 
 	// Protected routes
 	protected := e.Group("")
 	protected.Use(authMiddleware)
 	{
 		// User routes
-		protected.POST("/logout", userHandler.Logout)
+		protected.POST("/logout", userHandler.Logout, middleware.SessionOnlyMiddleware())
+		protected.GET("/authorize", oauthHandler.Authorize, middleware.SessionOnlyMiddleware())
+		protected.POST("/authorize", oauthHandler.Consent, middleware.SessionOnlyMiddleware())
 		// users routes
 		users := protected.Group("/admin/v1/user")
+		users.Use(middleware.ScopeMiddleware(apiKeyModel.ResourceUsers))
 		{
 			users.GET("/me", userHandler.GetMe)
-			users.PUT("/update", userHandler.UpdateProfile)
-			users.DELETE("/delete", userHandler.DeleteAccount)
-			users.GET("", userHandler.GetAllUsers)
+			// perubahan email/password, penghapusan akun dan export data pribadi
+			// hanya lewat sesi user agar API key tidak dapat mengambil alih akun
+			users.PATCH("/me", userHandler.PatchProfile, middleware.SessionOnlyMiddleware())
+			users.PUT("/update", userHandler.UpdateProfile, middleware.SessionOnlyMiddleware())
+			users.DELETE("/delete", privacyHandler.DeleteAccount, middleware.SessionOnlyMiddleware())
+			users.GET("/me/consents", oauthHandler.GetMyConsents)
+			users.DELETE("/me/consents/:id", oauthHandler.RevokeMyConsent)
+			users.GET("/me/logins", userHandler.GetMyLogins)
+			users.POST("/me/export", privacyHandler.RequestExport, middleware.SessionOnlyMiddleware())
+			users.GET("/me/export/:id", privacyHandler.GetExport, middleware.SessionOnlyMiddleware())
+			users.GET("/me/export/:id/download", privacyHandler.DownloadExport, middleware.SessionOnlyMiddleware())
+			// Manajemen user oleh admin
+			users.GET("", userHandler.GetAllUsers, adminMiddleware)
+			users.POST("", userHandler.CreateUser, adminMiddleware, idempotencyMiddleware)
+			users.GET("/logins/failed", userHandler.GetFailedLogins, adminMiddleware)
+			users.PUT("/:id/role", userHandler.ChangeRole, adminMiddleware)
+			users.POST("/:id/disable", userHandler.DisableUser, adminMiddleware)
+			users.POST("/:id/enable", userHandler.EnableUser, adminMiddleware)
+			users.POST("/:id/force-password-reset", userHandler.ForcePasswordReset, adminMiddleware)
+			users.POST("/:id/revoke-tokens", userHandler.RevokeTokens, adminMiddleware)
+			users.POST("/:id/impersonate", userHandler.Impersonate, middleware.SessionOnlyMiddleware(), adminMiddleware)
+			users.POST("/:id/erase", privacyHandler.EraseUser, middleware.SessionOnlyMiddleware(), adminMiddleware)
+		}
+		// API key routes
+		apiKeys := protected.Group("/admin/v1/api-keys")
+		apiKeys.Use(middleware.SessionOnlyMiddleware())
+		{
+			apiKeys.POST("", apiKeyHandler.Create)
+			apiKeys.GET("", apiKeyHandler.GetAll)
+			apiKeys.GET("/:id", apiKeyHandler.GetByID)
+			apiKeys.PUT("/:id", apiKeyHandler.Update)
+			apiKeys.DELETE("/:id", apiKeyHandler.Revoke)
+		}
+		// Invite routes
+		invites := protected.Group("/admin/v1/invites")
+		invites.Use(middleware.ScopeMiddleware(apiKeyModel.ResourceUsers), adminMiddleware)
+		{
+			invites.POST("", inviteHandler.Create)
+			invites.GET("", inviteHandler.GetAll)
+			invites.DELETE("/:id", inviteHandler.Revoke)
+		}
+		// OAuth client routes
+		oauthClients := protected.Group("/admin/v1/oauth/clients")
+		oauthClients.Use(middleware.SessionOnlyMiddleware(), adminMiddleware)
+		{
+			oauthClients.POST("", oauthHandler.CreateClient)
+			oauthClients.GET("", oauthHandler.GetAllClients)
+			oauthClients.GET("/:id", oauthHandler.GetClient)
+			oauthClients.DELETE("/:id", oauthHandler.DeleteClient)
+		}
+		// Background job routes
+		jobs := protected.Group("/admin/v1/jobs")
+		jobs.Use(middleware.SessionOnlyMiddleware(), adminMiddleware)
+		{
+			jobs.GET("", jobHandler.Stats)
+			jobs.GET("/failed", jobHandler.GetFailed)
+			jobs.GET("/:id", jobHandler.GetByID)
+			jobs.POST("/:id/retry", jobHandler.Retry)
+		}
+		// Scheduled task routes
+		schedulerTasks := protected.Group("/admin/v1/scheduler/tasks")
+		schedulerTasks.Use(middleware.SessionOnlyMiddleware(), adminMiddleware)
+		{
+			schedulerTasks.GET("", schedulerHandler.GetTasks)
+			schedulerTasks.GET("/:name/runs", schedulerHandler.GetRuns)
+		}
+		// Cache routes
+		caches := protected.Group("/admin/v1/cache")
+		caches.Use(middleware.SessionOnlyMiddleware(), adminMiddleware)
+		{
+			caches.GET("/stats", cacheHandler.Stats)
+		}
+		// Search routes
+		searches := protected.Group("/admin/v1/search")
+		searches.Use(middleware.SessionOnlyMiddleware(), adminMiddleware)
+		{
+			searches.GET("", searchHandler.Search)
+		}
+		// Webhook routes
+		webhooks := protected.Group("/admin/v1/webhooks")
+		webhooks.Use(middleware.SessionOnlyMiddleware(), adminMiddleware)
+		{
+			webhooks.POST("", webhookHandler.Create)
+			webhooks.GET("", webhookHandler.GetAll)
+			webhooks.GET("/:id", webhookHandler.GetByID)
+			webhooks.PUT("/:id", webhookHandler.Update)
+			webhooks.DELETE("/:id", webhookHandler.Delete)
+			webhooks.POST("/:id/enable", webhookHandler.Enable)
+			webhooks.POST("/:id/test", webhookHandler.SendTest)
+			webhooks.GET("/:id/deliveries", webhookHandler.GetDeliveries)
 		}
 		// Category routes
 		categories := protected.Group("/admin/v1/categories")
-		categories.Use(adminMiddleware)
+		categories.Use(middleware.ScopeMiddleware(apiKeyModel.ResourceCategories), adminMiddleware)
 		{
-			categories.POST("", categoryHandler.Create)
+			categories.POST("", categoryHandler.Create, idempotencyMiddleware)
 			categories.GET("", categoryHandler.GetAll)
+			categories.POST("/import", categoryHandler.Import, idempotencyMiddleware)
+			categories.GET("/imports/:id", categoryHandler.GetImport)
+			categories.GET("/export", categoryHandler.Export)
 			categories.GET("/:id", categoryHandler.GetByID)
 			categories.PUT("/:id", categoryHandler.Update)
+			categories.PATCH("/:id", categoryHandler.Patch)
 			categories.DELETE("/:id", categoryHandler.Delete)
 		}
 	}
+
+	// API documentation, dibangun dari route yang sudah terdaftar di atas
+	doc, err := apiDocs().Build(e.Routes())
+	if err != nil {
+		return nil, err
+	}
+	e.GET("/openapi.json", openapi.JSONHandler(doc))
+	e.GET("/docs", openapi.UIHandler(apiTitle, "/openapi.json"))
+
+	return doc, nil
 }
@@ -0,0 +1,155 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	apiKeyModel "boilerplate/internal/apikey/model"
+	"boilerplate/pkg/openapi"
+	"boilerplate/pkg/response"
+	errs "boilerplate/shared/errors"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/suite"
+)
+
+type RoutesTestSuite struct {
+	suite.Suite
+	e *echo.Echo
+}
+
+func TestRoutesSuite(t *testing.T) {
+	suite.Run(t, new(RoutesTestSuite))
+}
+
+func passthrough(next echo.HandlerFunc) echo.HandlerFunc {
+	return next
+}
+
+func (s *RoutesTestSuite) SetupTest() {
+	s.e = echo.New()
+	_, err := SetupRoutes(s.e, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, passthrough, passthrough, passthrough)
+	s.Require().NoError(err)
+}
+
+func (s *RoutesTestSuite) spec() *openapi.Document {
+	req := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+	rec := httptest.NewRecorder()
+	s.e.ServeHTTP(rec, req)
+	s.Require().Equal(http.StatusOK, rec.Code)
+
+	var doc openapi.Document
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &doc))
+	return &doc
+}
+
+// TestSpecMatchesRoutes gagal jika route ditambah atau dihapus tanpa memperbarui apiDocs
+func (s *RoutesTestSuite) TestSpecMatchesRoutes() {
+	doc := s.spec()
+
+	documented := 0
+	for _, route := range s.e.Routes() {
+		if route.Method == echo.RouteNotFound || route.Path == "/openapi.json" || route.Path == "/docs" {
+			continue
+		}
+		documented++
+
+		item, ok := doc.Paths[openapi.Path(route.Path)]
+		s.Require().True(ok, "missing path %s", route.Path)
+		_, ok = (*item)[strings.ToLower(route.Method)]
+		s.True(ok, "missing operation %s %s", route.Method, route.Path)
+	}
+
+	operations := 0
+	for _, item := range doc.Paths {
+		operations += len(*item)
+	}
+	s.Equal(documented, operations)
+}
+
+func (s *RoutesTestSuite) TestSpecCompilesToContractValidator() {
+	validator, err := openapi.NewValidator(s.spec())
+	s.Require().NoError(err)
+
+	op, ok := validator.Operation(http.MethodPatch, "/admin/v1/categories/:id")
+	s.Require().True(ok)
+	s.NotEmpty(op.ValidateRequest(map[string]string{"id": "x"}, nil, openapi.MergePatchContentType, []byte(`{"name":null}`)))
+
+	// file import tidak divalidasi, query-nya tetap divalidasi
+	op, ok = validator.Operation(http.MethodPost, "/admin/v1/categories/import")
+	s.Require().True(ok)
+	s.Empty(op.ValidateRequest(nil, url.Values{"mode": {"best_effort"}}, "text/csv", []byte("name\nBooks\n")))
+	s.NotEmpty(op.ValidateRequest(nil, url.Values{"mode": {"partial"}}, "text/csv", []byte("name\nBooks\n")))
+}
+
+func (s *RoutesTestSuite) TestUndocumentedRouteFailsBuild() {
+	s.e.GET("/undocumented", func(c echo.Context) error { return nil })
+
+	_, err := apiDocs().Build(s.e.Routes())
+	s.ErrorContains(err, "GET /undocumented")
+}
+
+func (s *RoutesTestSuite) TestSchemasCarryValidatorConstraints() {
+	doc := s.spec()
+
+	register := doc.Components.Schemas["RegisterInput"]
+	s.Require().NotNil(register)
+	s.ElementsMatch([]string{"name", "email", "password"}, register.Required)
+	s.Equal("email", register.Properties["email"].Format)
+	s.Equal(2, *register.Properties["name"].MinLength)
+	s.Equal(50, *register.Properties["name"].MaxLength)
+
+	user := doc.Components.Schemas["User"]
+	s.Require().NotNil(user)
+	s.NotContains(user.Properties, "password")
+
+	item := doc.Paths["/admin/v1/categories/{id}"]
+	s.Require().NotNil(item)
+	for _, method := range []string{"get", "put", "patch", "delete"} {
+		s.Contains(*item, method)
+	}
+	s.Contains((*item)["patch"].RequestBody.Content, openapi.MergePatchContentType)
+}
+
+func (s *RoutesTestSuite) TestAccountRoutesRequireSession() {
+	e := echo.New()
+	var handled error
+	e.HTTPErrorHandler = func(err error, c echo.Context) {
+		handled = err
+		_ = c.NoContent(response.ToAppError(err).Status)
+	}
+	// API key dengan scope users penuh tetap tidak boleh mengubah atau menghapus akun
+	withAPIKey := func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set("api_key", &apiKeyModel.APIKey{Scopes: []string{apiKeyModel.ScopeUsersRead, apiKeyModel.ScopeUsersWrite}})
+			return next(c)
+		}
+	}
+	_, err := SetupRoutes(e, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, withAPIKey, passthrough, passthrough)
+	s.Require().NoError(err)
+
+	routes := []struct {
+		method string
+		path   string
+	}{
+		{http.MethodPatch, "/admin/v1/user/me"},
+		{http.MethodPut, "/admin/v1/user/update"},
+		{http.MethodDelete, "/admin/v1/user/delete"},
+		{http.MethodPost, "/admin/v1/user/me/export"},
+		{http.MethodGet, "/admin/v1/user/me/export/1"},
+		{http.MethodGet, "/admin/v1/user/me/export/1/download"},
+	}
+	for _, route := range routes {
+		s.Run(route.method+" "+route.path, func() {
+			handled = nil
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(route.method, route.path, nil))
+			s.ErrorIs(handled, errs.ErrSessionRequired)
+			s.Equal(http.StatusForbidden, rec.Code)
+		})
+	}
+}
@@ -1,6 +1,10 @@
 package constants
 
-import "golang.org/x/crypto/bcrypt"
+import (
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
 
 type Role string
 
@@ -10,4 +14,47 @@ const (
 
 	PasswordMinLength = 6
 	BcryptCost        = bcrypt.DefaultCost
+
+	// ImpersonationTTL adalah umur token impersonasi admin
+	ImpersonationTTL = 15 * time.Minute
+
+	// MailTimeout membatasi pengiriman satu email notifikasi
+	MailTimeout = 30 * time.Second
+
+	// ExportTTL adalah masa berlaku file export data user
+	ExportTTL = 48 * time.Hour
+
+	// LoginEventRetention adalah masa simpan riwayat login sebelum dihapus task terjadwal
+	LoginEventRetention = 90 * 24 * time.Hour
+
+	// SchedulerRunRetention adalah masa simpan riwayat eksekusi task terjadwal
+	SchedulerRunRetention = 30 * 24 * time.Hour
+
+	// WebhookDeliveryRetention adalah masa simpan log pengiriman webhook
+	WebhookDeliveryRetention = 30 * 24 * time.Hour
+
+	// CacheTTL adalah masa berlaku default entry read-through cache
+	CacheTTL = 5 * time.Minute
+
+	// UserCacheTTL membatasi umur cache user yang dibaca AuthMiddleware di setiap request,
+	// sehingga perubahan yang gagal diinvalidasi tetap terlihat dalam waktu singkat
+	UserCacheTTL = time.Minute
+
+	// OutboxRetention adalah masa simpan event outbox yang sudah dipublikasikan dan
+	// penanda event yang sudah diproses consumer
+	OutboxRetention = 7 * 24 * time.Hour
+
+	// ContractMaxBodySize adalah ukuran maksimal body JSON atau form yang dibaca
+	// validasi kontrak OpenAPI; body file tidak dibaca dan dibatasi handler-nya
+	ContractMaxBodySize = 1 << 20
+
+	// CategoryImportMaxSize adalah ukuran maksimal file import category
+	CategoryImportMaxSize = 10 << 20
+
+	// CategoryImportMaxRows adalah jumlah baris maksimal satu file import category
+	CategoryImportMaxRows = 50000
+
+	// CategoryImportSyncRows adalah jumlah baris maksimal yang diimport langsung di
+	// request; file yang lebih besar diproses worker sebagai job
+	CategoryImportSyncRows = 500
 )
@@ -1,12 +1,191 @@
 package errors
 
-import "errors"
+import (
+	"errors"
+	"net/http"
+)
+
+// AppError adalah error aplikasi dengan kode stabil yang aman ditampilkan ke client.
+// Cause menyimpan error internal dan tidak pernah dikirim ke client.
+type AppError struct {
+	Code    string
+	Status  int
+	Message string
+	Cause   error
+	Details map[string]interface{}
+}
+
+var catalog []*AppError
+
+// New membuat AppError baru
+func New(code string, status int, message string) *AppError {
+	return &AppError{
+		Code:    code,
+		Status:  status,
+		Message: message,
+	}
+}
+
+// define mendaftarkan AppError ke katalog sehingga pesannya dapat diterjemahkan
+func define(code string, status int, message string) *AppError {
+	err := New(code, status, message)
+	catalog = append(catalog, err)
+	return err
+}
+
+// Catalog returns every error defined in this package
+func Catalog() []*AppError {
+	return append([]*AppError(nil), catalog...)
+}
+
+func (e *AppError) Error() string {
+	if e.Cause != nil {
+		return e.Message + ": " + e.Cause.Error()
+	}
+	return e.Message
+}
 
+func (e *AppError) Unwrap() error {
+	return e.Cause
+}
+
+// Is membuat errors.Is mencocokkan AppError berdasarkan kode
+func (e *AppError) Is(target error) bool {
+	t, ok := target.(*AppError)
+	return ok && t.Code == e.Code
+}
+
+// Wrap returns a copy of the error carrying an internal cause
+func (e *AppError) Wrap(cause error) *AppError {
+	wrapped := *e
+	wrapped.Cause = cause
+	return &wrapped
+}
+
+// WithDetails returns a copy of the error carrying public details
+func (e *AppError) WithDetails(details map[string]interface{}) *AppError {
+	detailed := *e
+	detailed.Details = details
+	return &detailed
+}
+
+// As mengambil AppError dari rantai error
+func As(err error) (*AppError, bool) {
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		return appErr, true
+	}
+	return nil, false
+}
+
+// Katalog error. Kode bersifat stabil: client boleh bergantung padanya.
 var (
-	ErrInvalidEmail           = errors.New("invalid email")
-	ErrShortPassword          = errors.New("password must be at least 6 characters")
-	ErrHashingPassword        = errors.New("failed to hash password")
-	ErrInvalidPassword        = errors.New("invalid password")
-	ErrEmailAlreadyRegistered = errors.New("email already registered")
-	ErrInvalidCredentials     = errors.New("invalid email or password")
+	// User
+	ErrInvalidEmail            = define("user.invalid_email", http.StatusBadRequest, "invalid email")
+	ErrShortPassword           = define("user.password_too_short", http.StatusBadRequest, "password must be at least 6 characters")
+	ErrPasswordPolicy          = define("user.password_policy", http.StatusBadRequest, "password does not meet the password policy")
+	ErrHashingPassword         = define("user.password_hash_failed", http.StatusInternalServerError, "failed to hash password")
+	ErrInvalidPassword         = define("user.invalid_password", http.StatusUnauthorized, "invalid password")
+	ErrEmailAlreadyRegistered  = define("user.email_taken", http.StatusConflict, "email already registered")
+	ErrUserNotFound            = define("user.not_found", http.StatusNotFound, "user not found")
+	ErrAccountDisabled         = define("user.disabled", http.StatusForbidden, "account is disabled")
+	ErrLastAdmin               = define("user.last_admin", http.StatusConflict, "cannot demote or disable the last active admin")
+	ErrPasswordResetRequired   = define("user.password_reset_required", http.StatusForbidden, "password reset required: update your password to continue")
+	ErrRegistrationDisabled    = define("user.registration_disabled", http.StatusForbidden, "open registration is disabled, an invite is required")
+	ErrImpersonationNotAllowed = define("user.impersonation_not_allowed", http.StatusForbidden, "this user cannot be impersonated")
+	ErrUserErased              = define("user.erased", http.StatusConflict, "the user's personal data has been erased")
+
+	// Invite
+	ErrInviteNotFound        = define("invite.not_found", http.StatusNotFound, "invite not found")
+	ErrInvalidInvite         = define("invite.invalid", http.StatusBadRequest, "invite is invalid, expired, revoked or already used")
+	ErrInviteInvalidExpiry   = define("invite.invalid_expiry", http.StatusBadRequest, "invite expiry must be in the future")
+	ErrInviteAlreadyAccepted = define("invite.already_accepted", http.StatusConflict, "invite has already been accepted")
+
+	// Category
+	ErrCategoryNameRequired      = define("category.name_required", http.StatusBadRequest, "category name is required")
+	ErrCategoryNotFound          = define("category.not_found", http.StatusNotFound, "category not found")
+	ErrCategorySlugInvalid       = define("category.slug_invalid", http.StatusBadRequest, "category slug must contain a letter or digit")
+	ErrCategoryImportDuplicate   = define("category.import_duplicate_row", http.StatusConflict, "another row in the file matches the same category")
+	ErrCategoryImportAmbiguous   = define("category.import_ambiguous_match", http.StatusConflict, "more than one category matches this row")
+	ErrCategoryImportEmpty       = define("category.import_empty", http.StatusBadRequest, "import file contains no rows")
+	ErrCategoryImportTooLarge    = define("category.import_too_large", http.StatusRequestEntityTooLarge, "import file exceeds the size or row limit")
+	ErrCategoryImportInvalidFile = define("category.import_invalid_file", http.StatusBadRequest, "import file could not be read")
+	ErrCategoryImportNotFound    = define("category.import_not_found", http.StatusNotFound, "import not found")
+
+	// API key
+	ErrInvalidAPIKey      = define("api_key.invalid", http.StatusUnauthorized, "invalid, expired or revoked API key")
+	ErrAPIKeyIPNotAllowed = define("api_key.ip_not_allowed", http.StatusForbidden, "API key is not allowed from this IP address")
+	ErrAPIKeyNotFound     = define("api_key.not_found", http.StatusNotFound, "API key not found")
+	ErrInsufficientScope  = define("api_key.insufficient_scope", http.StatusForbidden, "API key does not have the required scope")
+	ErrSessionRequired    = define("api_key.session_required", http.StatusForbidden, "this endpoint requires a user session, not an API key")
+
+	// Auth
+	ErrInvalidCredentials     = define("auth.invalid_credentials", http.StatusUnauthorized, "invalid email or password")
+	ErrUnauthorized           = define("auth.unauthorized", http.StatusUnauthorized, "unauthorized")
+	ErrMissingAuthorization   = define("auth.missing_authorization", http.StatusUnauthorized, "authorization header is required")
+	ErrInvalidAuthorization   = define("auth.invalid_authorization", http.StatusUnauthorized, "invalid authorization header format")
+	ErrInvalidToken           = define("auth.invalid_token", http.StatusUnauthorized, "invalid token")
+	ErrTokenRevoked           = define("auth.token_revoked", http.StatusUnauthorized, "token has been revoked or expired")
+	ErrAdminRequired          = define("auth.admin_required", http.StatusForbidden, "access denied: admin role required")
+	ErrForbidden              = define("auth.forbidden", http.StatusForbidden, "access denied")
+	ErrImpersonationForbidden = define("auth.impersonation_forbidden", http.StatusForbidden, "this action is not allowed while impersonating a user")
+
+	// OIDC login
+	ErrOIDCProviderNotFound = define("oidc.provider_not_found", http.StatusNotFound, "login provider not found")
+	ErrOIDCInvalidState     = define("oidc.invalid_state", http.StatusBadRequest, "login session expired or invalid, please try again")
+	ErrOIDCDenied           = define("oidc.denied", http.StatusUnauthorized, "login was denied by the provider")
+	ErrOIDCExchangeFailed   = define("oidc.exchange_failed", http.StatusBadGateway, "could not complete login with the provider")
+	ErrOIDCInvalidIDToken   = define("oidc.invalid_id_token", http.StatusUnauthorized, "invalid ID token from the provider")
+	ErrOIDCEmailNotVerified = define("oidc.email_not_verified", http.StatusForbidden, "the provider did not return a verified email address")
+
+	// OAuth provider
+	ErrOAuthUnknownClient       = define("oauth.unknown_client", http.StatusBadRequest, "unknown OAuth client")
+	ErrOAuthInvalidRedirectURI  = define("oauth.invalid_redirect_uri", http.StatusBadRequest, "redirect_uri is not registered for this client")
+	ErrOAuthInvalidClientConfig = define("oauth.invalid_client_config", http.StatusBadRequest, "invalid OAuth client configuration")
+	ErrOAuthClientNotFound      = define("oauth.client_not_found", http.StatusNotFound, "OAuth client not found")
+	ErrOAuthConsentNotFound     = define("oauth.consent_not_found", http.StatusNotFound, "consent not found")
+
+	// Privacy
+	ErrExportNotFound   = define("privacy.export_not_found", http.StatusNotFound, "export not found")
+	ErrExportInProgress = define("privacy.export_in_progress", http.StatusConflict, "an export is already in progress")
+	ErrExportNotReady   = define("privacy.export_not_ready", http.StatusConflict, "export is not ready yet")
+	ErrExportExpired    = define("privacy.export_expired", http.StatusGone, "export has expired, request a new one")
+
+	// Job
+	ErrJobNotFound  = define("job.not_found", http.StatusNotFound, "job not found")
+	ErrJobNotFailed = define("job.not_failed", http.StatusConflict, "only failed jobs can be retried")
+
+	// Scheduler
+	ErrTaskNotFound = define("scheduler.task_not_found", http.StatusNotFound, "scheduled task not found")
+
+	// Webhook
+	ErrWebhookNotFound = define("webhook.not_found", http.StatusNotFound, "webhook not found")
+
+	// Search
+	ErrSearchQueryTooShort = define("search.query_too_short", http.StatusBadRequest, "search query must contain a word of at least 2 characters")
+
+	// Request
+	ErrRouteNotFound         = define("route.not_found", http.StatusNotFound, "route not found")
+	ErrMethodNotAllowed      = define("route.method_not_allowed", http.StatusMethodNotAllowed, "method not allowed")
+	ErrInvalidPayload        = define("request.invalid_payload", http.StatusBadRequest, "invalid request payload")
+	ErrInvalidID             = define("request.invalid_id", http.StatusBadRequest, "invalid id")
+	ErrValidationFailed      = define("request.validation_failed", http.StatusBadRequest, "validation failed")
+	ErrBadRequest            = define("request.bad_request", http.StatusBadRequest, "bad request")
+	ErrUnsupportedMediaType  = define("request.unsupported_media_type", http.StatusUnsupportedMediaType, "unsupported media type")
+	ErrPreconditionFailed    = define("request.precondition_failed", http.StatusPreconditionFailed, "If-Match does not match the current resource version")
+	ErrContractViolation     = define("request.contract_violation", http.StatusBadRequest, "request does not match the API contract")
+	ErrPayloadTooLarge       = define("request.payload_too_large", http.StatusRequestEntityTooLarge, "request payload is too large")
+	ErrPreconditionRequired  = define("request.precondition_required", http.StatusPreconditionRequired, "If-Match header is required")
+	ErrInvalidIdempotencyKey = define("request.invalid_idempotency_key", http.StatusBadRequest, "Idempotency-Key must not be longer than 255 characters")
+	ErrIdempotencyInProgress = define("request.idempotency_in_progress", http.StatusConflict, "a request with this Idempotency-Key is still being processed")
+	ErrIdempotencyKeyReused  = define("request.idempotency_key_reused", http.StatusUnprocessableEntity, "Idempotency-Key was already used for a different request")
+
+	// Resource
+	ErrVersionConflict       = define("resource.version_conflict", http.StatusConflict, "resource has been modified by another request")
+	ErrResourceNotFound      = define("resource.not_found", http.StatusNotFound, "resource not found")
+	ErrResourceAlreadyExists = define("resource.already_exists", http.StatusConflict, "resource already exists")
+
+	// Internal
+	ErrInternal                  = define("internal.error", http.StatusInternalServerError, "internal server error")
+	ErrResponseContractViolation = define("internal.contract_violation", http.StatusInternalServerError, "response does not match the API contract")
 )